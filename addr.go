@@ -3,6 +3,7 @@ package netlink
 import (
 	"fmt"
 	"net"
+	"net/netip"
 	"strings"
 )
 
@@ -42,6 +43,27 @@ func ParseAddr(s string) (*Addr, error) {
 	return &Addr{IPNet: m, Label: label}, nil
 }
 
+// AddrFromPrefix returns an Addr for the local address and prefix length
+// of prefix.
+func AddrFromPrefix(prefix netip.Prefix) *Addr {
+	return &Addr{IPNet: NewIPNetFromPrefix(prefix)}
+}
+
+// Prefix returns the local address and prefix length as a netip.Prefix.
+func (a Addr) Prefix() netip.Prefix {
+	return NetipPrefix(a.IPNet)
+}
+
+// PeerPrefix returns the peer address as a netip.Prefix.
+func (a Addr) PeerPrefix() netip.Prefix {
+	return NetipPrefix(a.Peer)
+}
+
+// BroadcastAddr returns the broadcast address as a netip.Addr.
+func (a Addr) BroadcastAddr() netip.Addr {
+	return NetipAddr(a.Broadcast)
+}
+
 // Equal returns true if both Addrs have the same net.IPNet value.
 func (a Addr) Equal(x Addr) bool {
 	sizea, _ := a.Mask.Size()
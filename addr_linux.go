@@ -1,6 +1,7 @@
 package netlink
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -92,7 +93,9 @@ func (h *Handle) addrHandle(link Link, addr *Addr, req *nl.NetlinkRequest) error
 		msg.Index = uint32(addr.LinkIndex)
 	} else {
 		base := link.Attrs()
-		h.ensureIndex(base)
+		if err := h.ensureLinkIndex(base); err != nil {
+			return err
+		}
 		msg.Index = uint32(base.Index)
 	}
 	mask := addr.Mask
@@ -167,7 +170,7 @@ func (h *Handle) addrHandle(link Link, addr *Addr, req *nl.NetlinkRequest) error
 	// value should be "forever". To compensate for that, only add the attributes if at least one of the values is
 	// non-zero, which means the caller has explicitly set them
 	if addr.ValidLft > 0 || addr.PreferedLft > 0 {
-		cachedata := nl.IfaCacheInfo{unix.IfaCacheinfo{
+		cachedata := nl.IfaCacheInfo{IfaCacheinfo: unix.IfaCacheinfo{
 			Valid:    uint32(addr.ValidLft),
 			Prefered: uint32(addr.PreferedLft),
 		}}
@@ -323,18 +326,24 @@ type AddrUpdate struct {
 	PreferedLft int
 	ValidLft    int
 	NewAddr     bool // true=added false=deleted
+	// NetnsID is the id of the network namespace the update originates
+	// from, see AddrSubscribeOptions.ListenAllNsid, or -1.
+	NetnsID int
+	// Resync marks the updates delimiting a resync, which carry nothing
+	// else.
+	Resync SubscriptionResync
 }
 
 // AddrSubscribe takes a chan down which notifications will be sent
 // when addresses change.  Close the 'done' chan to stop subscription.
 func AddrSubscribe(ch chan<- AddrUpdate, done <-chan struct{}) error {
-	return addrSubscribeAt(netns.None(), netns.None(), ch, done, nil, false, 0, nil, false)
+	return addrSubscribeAt(netns.None(), netns.None(), ch, done, AddrSubscribeOptions{})
 }
 
 // AddrSubscribeAt works like AddrSubscribe plus it allows the caller
 // to choose the network namespace in which to subscribe (ns).
 func AddrSubscribeAt(ns netns.NsHandle, ch chan<- AddrUpdate, done <-chan struct{}) error {
-	return addrSubscribeAt(ns, netns.None(), ch, done, nil, false, 0, nil, false)
+	return addrSubscribeAt(ns, netns.None(), ch, done, AddrSubscribeOptions{})
 }
 
 // AddrSubscribeOptions contains a set of options to use with
@@ -346,6 +355,14 @@ type AddrSubscribeOptions struct {
 	ReceiveBufferSize      int
 	ReceiveBufferForceSize bool
 	ReceiveTimeout         *unix.Timeval
+	// Resync keeps the subscription going when the receive buffer overruns,
+	// as for LinkSubscribeOptions, dumping the existing addresses again.
+	Resync bool
+	// NoENOBUFS sets NETLINK_NO_ENOBUFS on the socket.
+	NoENOBUFS bool
+	// ListenAllNsid sets NETLINK_LISTEN_ALL_NSID on the socket, as for
+	// LinkSubscribeOptions.
+	ListenAllNsid bool
 }
 
 // AddrSubscribeWithOptions work like AddrSubscribe but enable to
@@ -356,100 +373,62 @@ func AddrSubscribeWithOptions(ch chan<- AddrUpdate, done <-chan struct{}, option
 		none := netns.None()
 		options.Namespace = &none
 	}
-	return addrSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback, options.ListExisting,
-		options.ReceiveBufferSize, options.ReceiveTimeout, options.ReceiveBufferForceSize)
+	return addrSubscribeAt(*options.Namespace, netns.None(), ch, done, options)
 }
 
-func addrSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- AddrUpdate, done <-chan struct{}, cberr func(error), listExisting bool,
-	rcvbuf int, rcvTimeout *unix.Timeval, rcvBufForce bool) error {
-	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_ROUTE, unix.RTNLGRP_IPV4_IFADDR, unix.RTNLGRP_IPV6_IFADDR)
-	if err != nil {
+// AddrSubscribeContext works like AddrSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func AddrSubscribeContext(ctx context.Context, ch chan<- AddrUpdate, options AddrSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
-	if rcvTimeout != nil {
-		if err := s.SetReceiveTimeout(rcvTimeout); err != nil {
-			return err
-		}
-	}
-	if rcvbuf != 0 {
-		err = s.SetReceiveBufferSize(rcvbuf, rcvBufForce)
-		if err != nil {
-			return err
-		}
-	}
-	if done != nil {
-		go func() {
-			<-done
-			s.Close()
-		}()
-	}
-	if listExisting {
-		req := pkgHandle.newNetlinkRequest(unix.RTM_GETADDR,
-			unix.NLM_F_DUMP)
-		infmsg := nl.NewIfInfomsg(unix.AF_UNSPEC)
-		req.AddData(infmsg)
-		if err := s.Send(req); err != nil {
-			return err
-		}
-	}
-	go func() {
-		defer close(ch)
-		for {
-			msgs, from, err := s.Receive()
-			if err != nil {
-				if cberr != nil {
-					cberr(fmt.Errorf("Receive failed: %v",
-						err))
-				}
-				return
-			}
-			if from.Pid != nl.PidKernel {
-				if cberr != nil {
-					cberr(fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, nl.PidKernel))
-				}
-				continue
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return AddrSubscribeWithOptions(ch, ctx.Done(), options)
+}
+
+func addrSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- AddrUpdate, done <-chan struct{}, options AddrSubscribeOptions) error {
+	sub := &subscription{
+		dumps: []func() *nl.NetlinkRequest{func() *nl.NetlinkRequest {
+			req := pkgHandle.newNetlinkRequest(unix.RTM_GETADDR, unix.NLM_F_DUMP)
+			req.AddData(nl.NewIfInfomsg(unix.AF_UNSPEC))
+			return req
+		}},
+		handle: func(m syscall.NetlinkMessage, nsid int) error {
+			msgType := m.Header.Type
+			if msgType != unix.RTM_NEWADDR && msgType != unix.RTM_DELADDR {
+				return fmt.Errorf("bad message type: %d", msgType)
 			}
-			for _, m := range msgs {
-				if m.Header.Type == unix.NLMSG_DONE {
-					continue
-				}
-				if m.Header.Type == unix.NLMSG_ERROR {
-					error := int32(native.Uint32(m.Data[0:4]))
-					if error == 0 {
-						continue
-					}
-					if cberr != nil {
-						cberr(fmt.Errorf("error message: %v",
-							syscall.Errno(-error)))
-					}
-					continue
-				}
-				msgType := m.Header.Type
-				if msgType != unix.RTM_NEWADDR && msgType != unix.RTM_DELADDR {
-					if cberr != nil {
-						cberr(fmt.Errorf("bad message type: %d", msgType))
-					}
-					continue
-				}
-
-				addr, _, err := parseAddr(m.Data)
-				if err != nil {
-					if cberr != nil {
-						cberr(fmt.Errorf("could not parse address: %v", err))
-					}
-					continue
-				}
-
-				ch <- AddrUpdate{LinkAddress: *addr.IPNet,
-					LinkIndex:   addr.LinkIndex,
-					NewAddr:     msgType == unix.RTM_NEWADDR,
-					Flags:       addr.Flags,
-					Scope:       addr.Scope,
-					PreferedLft: addr.PreferedLft,
-					ValidLft:    addr.ValidLft}
+
+			addr, _, err := parseAddr(m.Data)
+			if err != nil {
+				return fmt.Errorf("could not parse address: %v", err)
 			}
-		}
-	}()
 
-	return nil
+			ch <- AddrUpdate{LinkAddress: *addr.IPNet,
+				LinkIndex:   addr.LinkIndex,
+				NewAddr:     msgType == unix.RTM_NEWADDR,
+				Flags:       addr.Flags,
+				Scope:       addr.Scope,
+				PreferedLft: addr.PreferedLft,
+				ValidLft:    addr.ValidLft,
+				NetnsID:     nsid}
+			return nil
+		},
+		mark: func(r SubscriptionResync) {
+			ch <- AddrUpdate{NetnsID: nl.NETNSA_NSID_NOT_ASSIGNED, Resync: r}
+		},
+		close: func() { close(ch) },
+	}
+	return sub.start(newNs, curNs, done, subscribeOptions{
+		cberr:         options.ErrorCallback,
+		listExisting:  options.ListExisting,
+		rcvbuf:        options.ReceiveBufferSize,
+		rcvbufForce:   options.ReceiveBufferForceSize,
+		rcvTimeout:    options.ReceiveTimeout,
+		resync:        options.Resync,
+		noENOBUFS:     options.NoENOBUFS,
+		listenAllNsid: options.ListenAllNsid,
+	}, unix.RTNLGRP_IPV4_IFADDR, unix.RTNLGRP_IPV6_IFADDR)
 }
@@ -0,0 +1,83 @@
+package netlink
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vishvananda/netlink/nl"
+)
+
+// Batch queues the rtnetlink modifications made through its handle, like
+// LinkSetUp, AddrAdd, RouteAdd or NeighSet, to send them to the kernel with
+// as few system calls as possible on Flush, rather than doing a round trip
+// per call:
+//
+//	b := netlink.NewBatch()
+//	for i := range routes {
+//		b.Handle().RouteAdd(&routes[i])
+//	}
+//	errs, err := b.Flush()
+//
+// The queued operations return a nil error, their outcome is reported by
+// Flush. Queries, including the lookups some modifications do beforehand,
+// are executed right away, and do not see the effect of the queued
+// modifications. In particular, a link added by the batch has no index
+// until it is flushed: the operations on the link, like LinkSetUp or
+// AddrAdd, and the ones needing its index, like adding the link with a
+// MasterIndex, fail with ErrBatchedLinkIndex. Operations taking an index
+// rather than a Link, like RouteAdd, cannot tell and are queued as is.
+type Batch struct {
+	h     *Handle
+	batch *nl.Batch
+}
+
+// ErrBatchedLinkIndex is returned by the operations queued into a Batch
+// which need the index of a link not known yet, as the batch adds it.
+var ErrBatchedLinkIndex = errors.New("link index unknown until the batch is flushed")
+
+// NewBatch returns a batch of modifications sent with the package handle.
+// See [Handle.NewBatch].
+func NewBatch() *Batch {
+	return pkgHandle.NewBatch()
+}
+
+// NewBatch returns an empty batch of modifications sent on the netlink
+// sockets of h.
+func (h *Handle) NewBatch() *Batch {
+	b := &Batch{
+		batch: &nl.Batch{Sockets: h.sockets},
+	}
+	b.h = h.clone()
+	b.h.batch = b.batch
+	return b
+}
+
+// Handle returns the handle whose modifications are queued into b. It shares
+// the netlink sockets of the handle b was created from, so it must not be
+// closed separately.
+func (b *Batch) Handle() *Handle {
+	return b.h
+}
+
+// Len returns the number of netlink requests queued in b. As most
+// operations queue a single request, recording it before each operation
+// allows to find its error in the result of Flush.
+func (b *Batch) Len() int {
+	return b.batch.Len()
+}
+
+// Flush sends the queued requests and empties b, so that it can be reused.
+// errs holds the error of each request, in the order they were queued,
+// including the extended ack message of the kernel if any. err is set if
+// the requests could not be sent, or their outcome could not be received,
+// in which case it is also the error of every request whose outcome is
+// unknown.
+func (b *Batch) Flush() (errs []error, err error) {
+	return b.batch.FlushContext(b.h.Context())
+}
+
+// FlushContext works like Flush, but stops waiting for the kernel and
+// returns ctx.Err() as soon as ctx is done.
+func (b *Batch) FlushContext(ctx context.Context) (errs []error, err error) {
+	return b.batch.FlushContext(ctx)
+}
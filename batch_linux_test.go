@@ -0,0 +1,178 @@
+package netlink
+
+import (
+	"errors"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestBatchRouteAdd(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	link, err := LinkByName("lo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+
+	// Enough routes to need several sendmsg calls.
+	const count = 3000
+	b := NewBatch()
+	for i := 0; i < count; i++ {
+		dst := &net.IPNet{
+			IP:   net.IPv4(10, byte(i>>8), byte(i), 0),
+			Mask: net.CIDRMask(24, 32),
+		}
+		if err := b.Handle().RouteAdd(&Route{LinkIndex: link.Attrs().Index, Dst: dst}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Adding the first route again fails.
+	failed := b.Len()
+	if err := b.Handle().RouteAdd(&Route{LinkIndex: link.Attrs().Index, Dst: &net.IPNet{
+		IP:   net.IPv4(10, 0, 0, 0),
+		Mask: net.CIDRMask(24, 32),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != count+1 {
+		t.Fatalf("expected %d queued requests, got %d", count+1, b.Len())
+	}
+
+	// Queries are not queued.
+	if _, err := b.Handle().LinkByName("lo"); err != nil {
+		t.Fatal(err)
+	}
+
+	errs, err := b.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != count+1 {
+		t.Fatalf("expected %d errors, got %d", count+1, len(errs))
+	}
+	for i, err := range errs {
+		if i == failed {
+			if !errors.Is(err, syscall.EEXIST) {
+				t.Fatalf("expected EEXIST for request %d, got %v", i, err)
+			}
+		} else if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+	if b.Len() != 0 {
+		t.Fatal("batch not emptied by Flush")
+	}
+
+	routes, err := RouteList(link, FAMILY_V4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != count {
+		t.Fatalf("expected %d routes, got %d", count, len(routes))
+	}
+}
+
+func TestBatchWithHandle(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	h, err := NewHandle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	if err := h.LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "foo"}, PeerName: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := h.LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := h.NewBatch()
+	addr, err := ParseAddr("192.168.42.1/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Handle().AddrAdd(link, addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Handle().LinkSetMTU(link, 1400); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Handle().LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+	errs, err := b.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+
+	link, err = h.LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Attrs().MTU != 1400 || link.Attrs().Flags&net.FlagUp == 0 {
+		t.Fatalf("link not modified by the batch: %+v", link.Attrs())
+	}
+	addrs, err := h.AddrList(link, FAMILY_V4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 1 || !addrs[0].IP.Equal(addr.IP) {
+		t.Fatalf("address not added by the batch: %v", addrs)
+	}
+}
+
+func TestBatchLinkAddMaster(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	b := NewBatch()
+	veth := &Veth{LinkAttrs: LinkAttrs{Name: "foo", MasterIndex: 1}, PeerName: "bar"}
+	if err := b.Handle().LinkAdd(veth); !errors.Is(err, ErrBatchedLinkIndex) {
+		t.Fatalf("expected %v, got %v", ErrBatchedLinkIndex, err)
+	}
+	if b.Len() != 0 {
+		t.Fatal("link with a master queued")
+	}
+
+	veth.MasterIndex = 0
+	if err := b.Handle().LinkAdd(veth); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Handle().LinkSetMasterByIndex(veth, 1); !errors.Is(err, ErrBatchedLinkIndex) {
+		t.Fatalf("expected %v, got %v", ErrBatchedLinkIndex, err)
+	}
+	if err := b.Handle().LinkSetUp(veth); !errors.Is(err, ErrBatchedLinkIndex) {
+		t.Fatalf("expected %v, got %v", ErrBatchedLinkIndex, err)
+	}
+	addr := &Addr{IPNet: &net.IPNet{IP: net.IPv4(192, 0, 2, 1), Mask: net.CIDRMask(24, 32)}}
+	if err := b.Handle().AddrAdd(veth, addr); !errors.Is(err, ErrBatchedLinkIndex) {
+		t.Fatalf("expected %v, got %v", ErrBatchedLinkIndex, err)
+	}
+	if b.Len() != 1 {
+		t.Fatalf("expected 1 queued request, got %d", b.Len())
+	}
+	errs, err := b.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0] != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if veth.Index != 0 {
+		t.Fatalf("unexpected index %d set on the queued link", veth.Index)
+	}
+	if _, err := LinkByName("foo"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -266,7 +266,9 @@ func (h *Handle) BridgeVlanDelRange(link Link, vid, vidEnd uint16, pvid, untagge
 
 func (h *Handle) bridgeVlanModify(cmd int, link Link, vid, vidEnd uint16, tunid, tunidEnd uint32, pvid, untagged, self, master bool) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(cmd, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_BRIDGE)
@@ -330,6 +332,305 @@ func (h *Handle) bridgeVlanModify(cmd int, link Link, vid, vidEnd uint16, tunid,
 	return err
 }
 
+// BridgeVlanSetMsti maps a VLAN of an MST enabled bridge to a multiple
+// spanning tree instance.
+// Equivalent to: `bridge vlan global set dev DEV vid VID msti MSTI`
+func BridgeVlanSetMsti(link Link, vid, msti uint16) error {
+	return pkgHandle.BridgeVlanSetMsti(link, vid, msti)
+}
+
+// BridgeVlanSetMsti maps a VLAN of an MST enabled bridge to a multiple
+// spanning tree instance.
+// Equivalent to: `bridge vlan global set dev DEV vid VID msti MSTI`
+func (h *Handle) BridgeVlanSetMsti(link Link, vid, msti uint16) error {
+	return h.bridgeVlanSetMsti(link, vid, 0, msti)
+}
+
+// BridgeVlanSetMstiRange maps a range of VLANs of an MST enabled bridge to a
+// multiple spanning tree instance.
+// Equivalent to: `bridge vlan global set dev DEV vid VID-VIDEND msti MSTI`
+func BridgeVlanSetMstiRange(link Link, vid, vidEnd, msti uint16) error {
+	return pkgHandle.BridgeVlanSetMstiRange(link, vid, vidEnd, msti)
+}
+
+// BridgeVlanSetMstiRange maps a range of VLANs of an MST enabled bridge to a
+// multiple spanning tree instance.
+// Equivalent to: `bridge vlan global set dev DEV vid VID-VIDEND msti MSTI`
+func (h *Handle) BridgeVlanSetMstiRange(link Link, vid, vidEnd, msti uint16) error {
+	return h.bridgeVlanSetMsti(link, vid, vidEnd, msti)
+}
+
+func (h *Handle) bridgeVlanSetMsti(link Link, vid, vidEnd, msti uint16) error {
+	base := link.Attrs()
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
+	req := h.newNetlinkRequest(nl.RTM_NEWVLAN, unix.NLM_F_ACK)
+
+	msg := nl.NewBrVlanMsg(unix.AF_BRIDGE, base.Index)
+	req.AddData(msg)
+
+	opts := nl.NewRtAttr(unix.NLA_F_NESTED|nl.BRIDGE_VLANDB_GLOBAL_OPTIONS, nil)
+	opts.AddRtAttr(nl.BRIDGE_VLANDB_GOPTS_ID, nl.Uint16Attr(vid))
+	if vidEnd != 0 {
+		opts.AddRtAttr(nl.BRIDGE_VLANDB_GOPTS_RANGE, nl.Uint16Attr(vidEnd))
+	}
+	opts.AddRtAttr(nl.BRIDGE_VLANDB_GOPTS_MSTI, nl.Uint16Attr(msti))
+	req.AddData(opts)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// BridgeVlanMstiList gets a map of VLAN id to multiple spanning tree instance
+// for an MST enabled bridge.
+// Equivalent to: `bridge vlan global show dev DEV`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func BridgeVlanMstiList(link Link) (map[uint16]uint16, error) {
+	return pkgHandle.BridgeVlanMstiList(link)
+}
+
+// BridgeVlanMstiList gets a map of VLAN id to multiple spanning tree instance
+// for an MST enabled bridge.
+// Equivalent to: `bridge vlan global show dev DEV`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) BridgeVlanMstiList(link Link) (map[uint16]uint16, error) {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req := h.newNetlinkRequest(unix.RTM_GETVLAN, unix.NLM_F_DUMP)
+
+	msg := nl.NewBrVlanMsg(unix.AF_BRIDGE, base.Index)
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(nl.BRIDGE_VLANDB_DUMP_FLAGS, nl.Uint32Attr(nl.BRIDGE_VLANDB_DUMPF_GLOBAL)))
+
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, nl.RTM_NEWVLAN)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+
+	ret := make(map[uint16]uint16)
+	for _, m := range msgs {
+		vlanMsg := nl.DeserializeBrVlanMsg(m)
+		if int(vlanMsg.Ifindex) != base.Index {
+			continue
+		}
+
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofBrVlanMsg:])
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.BRIDGE_VLANDB_GLOBAL_OPTIONS {
+				continue
+			}
+			if err := parseBridgeVlanMsti(ret, attr.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ret, executeErr
+}
+
+// parseBridgeVlanMsti adds the MSTI of the vlans of the
+// BRIDGE_VLANDB_GLOBAL_OPTIONS attribute payload b to ret.
+func parseBridgeVlanMsti(ret map[uint16]uint16, b []byte) error {
+	opts, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return fmt.Errorf("failed to parse nested attr %v", err)
+	}
+	var vid, vidEnd, msti uint16
+	var hasMsti bool
+	for _, opt := range opts {
+		switch opt.Attr.Type {
+		case nl.BRIDGE_VLANDB_GOPTS_ID, nl.BRIDGE_VLANDB_GOPTS_RANGE, nl.BRIDGE_VLANDB_GOPTS_MSTI:
+			if len(opt.Value) < 2 {
+				return fmt.Errorf("vlan global option %d too short: %d bytes", opt.Attr.Type, len(opt.Value))
+			}
+		}
+		switch opt.Attr.Type {
+		case nl.BRIDGE_VLANDB_GOPTS_ID:
+			vid = native.Uint16(opt.Value)
+		case nl.BRIDGE_VLANDB_GOPTS_RANGE:
+			vidEnd = native.Uint16(opt.Value)
+		case nl.BRIDGE_VLANDB_GOPTS_MSTI:
+			msti = native.Uint16(opt.Value)
+			hasMsti = true
+		}
+	}
+	if !hasMsti {
+		return nil
+	}
+	if vidEnd < vid {
+		vidEnd = vid
+	}
+	for v := uint32(vid); v <= uint32(vidEnd); v++ {
+		ret[uint16(v)] = msti
+	}
+	return nil
+}
+
+// BridgeMstSet sets the state of a bridge port in a multiple spanning tree
+// instance. The state is one of the nl.BR_STATE_* values.
+// Equivalent to: `bridge mst set dev DEV msti MSTI state STATE`
+func BridgeMstSet(link Link, msti uint16, state uint8) error {
+	return pkgHandle.BridgeMstSet(link, msti, state)
+}
+
+// BridgeMstSet sets the state of a bridge port in a multiple spanning tree
+// instance. The state is one of the nl.BR_STATE_* values.
+// Equivalent to: `bridge mst set dev DEV msti MSTI state STATE`
+func (h *Handle) BridgeMstSet(link Link, msti uint16, state uint8) error {
+	base := link.Attrs()
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
+	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
+
+	msg := nl.NewIfInfomsg(unix.AF_BRIDGE)
+	msg.Index = int32(base.Index)
+	req.AddData(msg)
+
+	br := nl.NewRtAttr(unix.IFLA_AF_SPEC, nil)
+	mst := br.AddRtAttr(unix.NLA_F_NESTED|nl.IFLA_BRIDGE_MST, nil)
+	entry := mst.AddRtAttr(unix.NLA_F_NESTED|nl.IFLA_BRIDGE_MST_ENTRY, nil)
+	entry.AddRtAttr(nl.IFLA_BRIDGE_MST_ENTRY_MSTI, nl.Uint16Attr(msti))
+	entry.AddRtAttr(nl.IFLA_BRIDGE_MST_ENTRY_STATE, nl.Uint8Attr(state))
+	req.AddData(br)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// BridgeMstList gets a map of device id to the port states in each multiple
+// spanning tree instance.
+// Equivalent to: `bridge mst show`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func BridgeMstList() (map[int32][]nl.BridgeMstEntry, error) {
+	return pkgHandle.BridgeMstList()
+}
+
+// BridgeMstList gets a map of device id to the port states in each multiple
+// spanning tree instance.
+// Equivalent to: `bridge mst show`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) BridgeMstList() (map[int32][]nl.BridgeMstEntry, error) {
+	return h.bridgeMstListBy(0)
+}
+
+// BridgeMstShowDev gets the port states of a specific device in each multiple
+// spanning tree instance.
+// Equivalent to: `bridge mst show dev DEV`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func BridgeMstShowDev(link Link) ([]nl.BridgeMstEntry, error) {
+	return pkgHandle.BridgeMstShowDev(link)
+}
+
+// BridgeMstShowDev gets the port states of a specific device in each multiple
+// spanning tree instance.
+// Equivalent to: `bridge mst show dev DEV`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) BridgeMstShowDev(link Link) ([]nl.BridgeMstEntry, error) {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	ret, err := h.bridgeMstListBy(int32(base.Index))
+	if ret == nil {
+		return nil, err
+	}
+	return ret[int32(base.Index)], err
+}
+
+// bridgeMstListBy performs a bridge MST dump and optionally filters by device
+// ifindex. When ifindex is 0, all devices are returned.
+func (h *Handle) bridgeMstListBy(ifindex int32) (map[int32][]nl.BridgeMstEntry, error) {
+	req := h.newNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP)
+	msg := nl.NewIfInfomsg(unix.AF_BRIDGE)
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(unix.IFLA_EXT_MASK, nl.Uint32Attr(uint32(nl.RTEXT_FILTER_MST))))
+
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	ret := make(map[int32][]nl.BridgeMstEntry)
+	for _, m := range msgs {
+		msg := nl.DeserializeIfInfomsg(m)
+		if ifindex != 0 && msg.Index != ifindex {
+			continue
+		}
+
+		attrs, err := nl.ParseRouteAttr(m[msg.Len():])
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			if attr.Attr.Type&nl.NLA_TYPE_MASK != unix.IFLA_AF_SPEC {
+				continue
+			}
+			nestAttrs, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse nested attr %v", err)
+			}
+			for _, nestAttr := range nestAttrs {
+				if nestAttr.Attr.Type&nl.NLA_TYPE_MASK != nl.IFLA_BRIDGE_MST {
+					continue
+				}
+				entries, err := parseBridgeMst(nestAttr.Value)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse mst info %v", err)
+				}
+				ret[msg.Index] = append(ret[msg.Index], entries...)
+			}
+		}
+	}
+	return ret, executeErr
+}
+
+func parseBridgeMst(data []byte) ([]nl.BridgeMstEntry, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	var entries []nl.BridgeMstEntry
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.IFLA_BRIDGE_MST_ENTRY {
+			continue
+		}
+		infos, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		var entry nl.BridgeMstEntry
+		for _, info := range infos {
+			switch info.Attr.Type {
+			case nl.IFLA_BRIDGE_MST_ENTRY_MSTI:
+				if len(info.Value) < 2 {
+					return nil, fmt.Errorf("mst entry msti attribute too short: %d bytes", len(info.Value))
+				}
+				entry.Msti = native.Uint16(info.Value)
+			case nl.IFLA_BRIDGE_MST_ENTRY_STATE:
+				if len(info.Value) < 1 {
+					return nil, fmt.Errorf("mst entry state attribute too short: %d bytes", len(info.Value))
+				}
+				entry.State = info.Value[0]
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
 // BridgeVniAdd adds a new vni filter entry
 // Equivalent to: `bridge vni add dev DEV vni VNI`
 func BridgeVniAdd(link Link, vni uint32) error {
@@ -380,7 +681,9 @@ func (h *Handle) BridgeVniDelRange(link Link, vniStart, vniEnd uint32) error {
 
 func (h *Handle) bridgeVniModify(cmd int, link Link, vniStart, vniEnd uint32) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(cmd, unix.NLM_F_ACK)
 
 	msg := nl.NewTunnelMsg(unix.AF_BRIDGE, base.Index)
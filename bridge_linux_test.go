@@ -457,3 +457,114 @@ func TestBridgeGroupFwdMask(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestBridgeMst(t *testing.T) {
+	minKernelRequired(t, 5, 18)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	vlanFiltering := true
+	mstEnabled := true
+	bridge := &Bridge{
+		LinkAttrs:     LinkAttrs{Name: "foo"},
+		VlanFiltering: &vlanFiltering,
+		MstEnabled:    &mstEnabled,
+	}
+	if err := LinkAdd(bridge); err != nil {
+		t.Fatal(err)
+	}
+	brlink, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enabled := brlink.(*Bridge).MstEnabled; enabled == nil || !*enabled {
+		t.Fatalf("expected mst_enabled to be set on %s", brlink.Attrs().Name)
+	}
+
+	if err := BridgeVlanSetMsti(bridge, 1, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := BridgeVlanAddRange(bridge, 10, 12, false, false, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := BridgeVlanSetMstiRange(bridge, 10, 12, 7); err != nil {
+		t.Fatal(err)
+	}
+	mstis, err := BridgeVlanMstiList(bridge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for vid, want := range map[uint16]uint16{1: 5, 10: 7, 11: 7, 12: 7} {
+		if got, ok := mstis[vid]; !ok || got != want {
+			t.Fatalf("expected vid %d in msti %d, got %v", vid, want, mstis)
+		}
+	}
+
+	dummy := &Dummy{LinkAttrs: LinkAttrs{Name: "dum1", MasterIndex: bridge.Index}}
+	if err := LinkAdd(dummy); err != nil {
+		t.Fatal(err)
+	}
+	if err := BridgeMstSet(dummy, 5, nl.BR_STATE_BLOCKING); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := BridgeMstShowDev(dummy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, entry := range entries {
+		if entry.Msti == 5 {
+			found = true
+			if entry.State != nl.BR_STATE_BLOCKING {
+				t.Fatalf("expected msti 5 to be blocking, got %s", entry.String())
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("msti 5 not found in %v", entries)
+	}
+
+	mstMap, err := BridgeMstList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := mstMap[int32(dummy.Index)]; !ok {
+		t.Fatalf("expected mst entries for %s, got %v", dummy.Name, mstMap)
+	}
+
+	if err := BridgeSetMstEnabled(brlink, false); err == nil {
+		t.Fatal("expected disabling mst to fail while ports have vlans")
+	}
+}
+
+func TestParseBridgeMstTruncated(t *testing.T) {
+	for _, info := range []*nl.RtAttr{
+		nl.NewRtAttr(nl.IFLA_BRIDGE_MST_ENTRY_MSTI, []byte{1}),
+		nl.NewRtAttr(nl.IFLA_BRIDGE_MST_ENTRY_STATE, nil),
+	} {
+		entry := nl.NewRtAttr(nl.IFLA_BRIDGE_MST_ENTRY|int(nl.NLA_F_NESTED), nil)
+		entry.AddChild(info)
+		if _, err := parseBridgeMst(entry.Serialize()); err == nil {
+			t.Fatalf("expected an error parsing a truncated attribute %d", info.Type)
+		}
+	}
+}
+
+func TestParseBridgeVlanMsti(t *testing.T) {
+	var b []byte
+	b = append(b, nl.NewRtAttr(nl.BRIDGE_VLANDB_GOPTS_ID, nl.Uint16Attr(10)).Serialize()...)
+	b = append(b, nl.NewRtAttr(nl.BRIDGE_VLANDB_GOPTS_RANGE, nl.Uint16Attr(12)).Serialize()...)
+	b = append(b, nl.NewRtAttr(nl.BRIDGE_VLANDB_GOPTS_MSTI, nl.Uint16Attr(3)).Serialize()...)
+	ret := make(map[uint16]uint16)
+	if err := parseBridgeVlanMsti(ret, b); err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 3 || ret[10] != 3 || ret[11] != 3 || ret[12] != 3 {
+		t.Fatalf("unexpected vlan mstis: %v", ret)
+	}
+
+	for _, attrType := range []int{nl.BRIDGE_VLANDB_GOPTS_ID, nl.BRIDGE_VLANDB_GOPTS_RANGE, nl.BRIDGE_VLANDB_GOPTS_MSTI} {
+		if err := parseBridgeVlanMsti(ret, nl.NewRtAttr(attrType, []byte{1}).Serialize()); err == nil {
+			t.Fatalf("expected an error parsing a truncated attribute %d", attrType)
+		}
+	}
+}
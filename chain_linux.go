@@ -40,7 +40,9 @@ func (h *Handle) chainModify(cmd, flags int, link Link, chain Chain) error {
 	index := int32(0)
 	if link != nil {
 		base := link.Attrs()
-		h.ensureIndex(base)
+		if err := h.ensureLinkIndex(base); err != nil {
+			return err
+		}
 		index = int32(base.Index)
 	}
 	msg := &nl.TcMsg{
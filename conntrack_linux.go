@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/fs"
 	"net"
+	"net/netip"
 	"time"
 
 	"github.com/vishvananda/netlink/nl"
@@ -25,11 +26,6 @@ const (
 	ConntrackExpectTable = 2
 )
 
-const (
-	// backward compatibility with golang 1.6 which does not have io.SeekCurrent
-	seekCurrent = 1
-)
-
 // InetFamily Family type
 type InetFamily uint8
 
@@ -111,6 +107,86 @@ func (h *Handle) ConntrackTableList(table ConntrackTableType, family InetFamily)
 	return result, executeErr
 }
 
+// ConntrackTableListNetipIter passes each flow of the table to f, as a
+// ConntrackFlowNetip decoded without allocating. Iteration continues until
+// all flows are loaded or f returns false.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func ConntrackTableListNetipIter(table ConntrackTableType, family InetFamily, f func(ConntrackFlowNetip) (cont bool)) error {
+	return pkgHandle.ConntrackTableListNetipIter(table, family, f)
+}
+
+// ConntrackTableListNetipIter passes each flow of the table to f, as a
+// ConntrackFlowNetip decoded without allocating. Iteration continues until
+// all flows are loaded or f returns false.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) ConntrackTableListNetipIter(table ConntrackTableType, family InetFamily, f func(ConntrackFlowNetip) (cont bool)) error {
+	req := h.newConntrackRequest(table, family, nl.IPCTNL_MSG_CT_GET, unix.NLM_F_DUMP)
+	return req.ExecuteIter(unix.NETLINK_NETFILTER, 0, func(m []byte) bool {
+		if len(m) < nl.SizeofNfgenmsg {
+			return true
+		}
+		return f(parseConntrackFlowNetip(m))
+	})
+}
+
+func parseConntrackFlowNetip(m []byte) ConntrackFlowNetip {
+	flow := ConntrackFlowNetip{FamilyType: m[0]}
+	ad := nl.NewAttributeDecoder(m[nl.SizeofNfgenmsg:])
+	for ad.Next() {
+		switch ad.Type() {
+		case nl.CTA_TUPLE_ORIG:
+			flow.Forward = parseIPTupleNetip(ad.Value())
+		case nl.CTA_TUPLE_REPLY:
+			flow.Reverse = parseIPTupleNetip(ad.Value())
+		case nl.CTA_MARK:
+			flow.Mark = ad.BEUint32()
+		case nl.CTA_STATUS:
+			flow.Status = ad.BEUint32()
+		case nl.CTA_TIMEOUT:
+			flow.TimeOut = ad.BEUint32()
+		case nl.CTA_ZONE:
+			flow.Zone = ad.BEUint16()
+		}
+	}
+	return flow
+}
+
+func parseIPTupleNetip(b []byte) IPTupleNetip {
+	var tpl IPTupleNetip
+	ad := nl.NewAttributeDecoder(b)
+	for ad.Next() {
+		switch ad.Type() {
+		case nl.CTA_TUPLE_IP:
+			ip := nl.NewAttributeDecoder(ad.Value())
+			for ip.Next() {
+				switch ip.Type() {
+				case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
+					tpl.Src, _ = netip.AddrFromSlice(ip.Value())
+				case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
+					tpl.Dst, _ = netip.AddrFromSlice(ip.Value())
+				}
+			}
+		case nl.CTA_TUPLE_PROTO:
+			proto := nl.NewAttributeDecoder(ad.Value())
+			for proto.Next() {
+				switch proto.Type() {
+				case nl.CTA_PROTO_NUM:
+					tpl.Protocol = proto.Uint8()
+				case nl.CTA_PROTO_SRC_PORT:
+					tpl.SrcPort = proto.BEUint16()
+				case nl.CTA_PROTO_DST_PORT:
+					tpl.DstPort = proto.BEUint16()
+				}
+			}
+		}
+	}
+	return tpl
+}
+
 // ConntrackTableFlush flushes all the flows of a specified table using the netlink handle passed
 // conntrack -F [table]            Flush table
 // The flush operation applies to all the family types
@@ -287,6 +363,37 @@ type IPTuple struct {
 	SrcPort  uint16
 }
 
+// IPTupleNetip is the netip counterpart of IPTuple, without the counters.
+type IPTupleNetip struct {
+	Protocol uint8
+	Src      netip.Addr
+	Dst      netip.Addr
+	SrcPort  uint16
+	DstPort  uint16
+}
+
+// ConntrackFlowNetip is a compact view of a conntrack flow using netip
+// types, as returned by ConntrackTableListNetipIter.
+type ConntrackFlowNetip struct {
+	FamilyType uint8
+	Forward    IPTupleNetip
+	Reverse    IPTupleNetip
+	Mark       uint32
+	Status     uint32
+	TimeOut    uint32
+	Zone       uint16
+}
+
+// SrcAddr returns the source address of the tuple as a netip.Addr.
+func (t IPTuple) SrcAddr() netip.Addr {
+	return NetipAddr(t.SrcIP)
+}
+
+// DstAddr returns the destination address of the tuple as a netip.Addr.
+func (t IPTuple) DstAddr() netip.Addr {
+	return NetipAddr(t.DstIP)
+}
+
 // toNlData generates the inner fields of a nested tuple netlink datastructure
 // does not generate the "nested"-flagged outer message.
 func (t *IPTuple) toNlData(family uint8) ([]*nl.RtAttr, error) {
@@ -498,234 +605,108 @@ func (s *ConntrackFlow) toNlData() ([]*nl.RtAttr, error) {
 	return payload, nil
 }
 
-// This method parse the ip tuple structure
-// The message structure is the following:
-// <len, [CTA_IP_V4_SRC|CTA_IP_V6_SRC], 16 bytes for the IP>
-// <len, [CTA_IP_V4_DST|CTA_IP_V6_DST], 16 bytes for the IP>
-// <len, NLA_F_NESTED|nl.CTA_TUPLE_PROTO, 1 byte for the protocol, 3 bytes of padding>
-// <len, CTA_PROTO_SRC_PORT, 2 bytes for the source port, 2 bytes of padding>
-// <len, CTA_PROTO_DST_PORT, 2 bytes for the source port, 2 bytes of padding>
-func parseIpTuple(reader *bytes.Reader, tpl *IPTuple) uint8 {
-	for i := 0; i < 2; i++ {
-		_, t, _, v := parseNfAttrTLV(reader)
-		switch t {
-		case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
-			tpl.SrcIP = v
-		case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
-			tpl.DstIP = v
+// parseIpTuple parses the attributes nested in CTA_TUPLE_ORIG or
+// CTA_TUPLE_REPLY into tpl. Only the ports of TCP and UDP are parsed.
+func parseIpTuple(b []byte, tpl *IPTuple) {
+	var srcPort, dstPort uint16
+	ad := nl.NewAttributeDecoder(b)
+	for ad.Next() {
+		switch ad.Type() {
+		case nl.CTA_TUPLE_IP:
+			ad.Nested(func(ip *nl.AttributeDecoder) error {
+				for ip.Next() {
+					switch ip.Type() {
+					case nl.CTA_IP_V4_SRC, nl.CTA_IP_V6_SRC:
+						tpl.SrcIP = ip.IP()
+					case nl.CTA_IP_V4_DST, nl.CTA_IP_V6_DST:
+						tpl.DstIP = ip.IP()
+					}
+				}
+				return nil
+			})
+		case nl.CTA_TUPLE_PROTO:
+			ad.Nested(func(proto *nl.AttributeDecoder) error {
+				for proto.Next() {
+					switch proto.Type() {
+					case nl.CTA_PROTO_NUM:
+						tpl.Protocol = proto.Uint8()
+					case nl.CTA_PROTO_SRC_PORT:
+						srcPort = proto.BEUint16()
+					case nl.CTA_PROTO_DST_PORT:
+						dstPort = proto.BEUint16()
+					}
+				}
+				return nil
+			})
 		}
 	}
-	// Get total length of nested protocol-specific info.
-	_, _, protoInfoTotalLen := parseNfAttrTL(reader)
-	_, t, l, v := parseNfAttrTLV(reader)
-	// Track the number of bytes read.
-	protoInfoBytesRead := uint16(nl.SizeofNfattr) + l
-	if t == nl.CTA_PROTO_NUM {
-		tpl.Protocol = uint8(v[0])
+	if tpl.Protocol == unix.IPPROTO_TCP || tpl.Protocol == unix.IPPROTO_UDP {
+		tpl.SrcPort, tpl.DstPort = srcPort, dstPort
 	}
-	// We only parse TCP & UDP headers. Skip the others.
-	if tpl.Protocol != unix.IPPROTO_TCP && tpl.Protocol != unix.IPPROTO_UDP {
-		// skip the rest
-		bytesRemaining := protoInfoTotalLen - protoInfoBytesRead
-		reader.Seek(int64(bytesRemaining), seekCurrent)
-		return tpl.Protocol
-	}
-	// Skip 3 bytes of padding
-	reader.Seek(3, seekCurrent)
-	protoInfoBytesRead += 3
-	for i := 0; i < 2; i++ {
-		_, t, _ := parseNfAttrTL(reader)
-		protoInfoBytesRead += uint16(nl.SizeofNfattr)
-		switch t {
-		case nl.CTA_PROTO_SRC_PORT:
-			parseBERaw16(reader, &tpl.SrcPort)
-			protoInfoBytesRead += 2
-		case nl.CTA_PROTO_DST_PORT:
-			parseBERaw16(reader, &tpl.DstPort)
-			protoInfoBytesRead += 2
-		}
-		// Skip 2 bytes of padding
-		reader.Seek(2, seekCurrent)
-		protoInfoBytesRead += 2
-	}
-	// Skip any remaining/unknown parts of the message
-	bytesRemaining := protoInfoTotalLen - protoInfoBytesRead
-	reader.Seek(int64(bytesRemaining), seekCurrent)
-
-	return tpl.Protocol
 }
 
-func parseNfAttrTLV(r *bytes.Reader) (isNested bool, attrType, len uint16, value []byte) {
-	isNested, attrType, len = parseNfAttrTL(r)
-
-	value = make([]byte, len)
-	binary.Read(r, binary.BigEndian, &value)
-	return isNested, attrType, len, value
-}
-
-func parseNfAttrTL(r *bytes.Reader) (isNested bool, attrType, len uint16) {
-	binary.Read(r, nl.NativeEndian(), &len)
-	len -= nl.SizeofNfattr
-
-	binary.Read(r, nl.NativeEndian(), &attrType)
-	isNested = (attrType & nl.NLA_F_NESTED) == nl.NLA_F_NESTED
-	attrType = attrType & (nl.NLA_F_NESTED - 1)
-	return isNested, attrType, len
-}
-
-// skipNfAttrValue seeks `r` past attr of length `len`.
-// Maintains buffer alignment.
-// Returns length of the seek performed.
-func skipNfAttrValue(r *bytes.Reader, len uint16) uint16 {
-	len = (len + nl.NLA_ALIGNTO - 1) & ^(nl.NLA_ALIGNTO - 1)
-	r.Seek(int64(len), seekCurrent)
-	return len
-}
-
-func parseBERaw16(r *bytes.Reader, v *uint16) {
-	binary.Read(r, binary.BigEndian, v)
-}
-
-func parseBERaw32(r *bytes.Reader, v *uint32) {
-	binary.Read(r, binary.BigEndian, v)
-}
-
-func parseBERaw64(r *bytes.Reader, v *uint64) {
-	binary.Read(r, binary.BigEndian, v)
-}
-
-func parseRaw32(r *bytes.Reader, v *uint32) {
-	binary.Read(r, nl.NativeEndian(), v)
-}
-
-func parseByteAndPacketCounters(r *bytes.Reader) (bytes, packets uint64) {
-	for i := 0; i < 2; i++ {
-		switch _, t, _ := parseNfAttrTL(r); t {
+func parseByteAndPacketCounters(b []byte) (bytes, packets uint64) {
+	ad := nl.NewAttributeDecoder(b)
+	for ad.Next() {
+		switch ad.Type() {
 		case nl.CTA_COUNTERS_BYTES:
-			parseBERaw64(r, &bytes)
+			bytes = ad.BEUint64()
 		case nl.CTA_COUNTERS_PACKETS:
-			parseBERaw64(r, &packets)
-		default:
-			return
+			packets = ad.BEUint64()
 		}
 	}
 	return
 }
 
 // when the flow is alive, only the timestamp_start is returned in structure
-func parseTimeStamp(r *bytes.Reader, readSize uint16) (tstart, tstop uint64) {
-	var numTimeStamps int
-	oneItem := nl.SizeofNfattr + 8 // 4 bytes attr header + 8 bytes timestamp
-	if readSize == uint16(oneItem) {
-		numTimeStamps = 1
-	} else if readSize == 2*uint16(oneItem) {
-		numTimeStamps = 2
-	} else {
-		return
-	}
-	for i := 0; i < numTimeStamps; i++ {
-		switch _, t, _ := parseNfAttrTL(r); t {
+func parseTimeStamp(b []byte) (tstart, tstop uint64) {
+	ad := nl.NewAttributeDecoder(b)
+	for ad.Next() {
+		switch ad.Type() {
 		case nl.CTA_TIMESTAMP_START:
-			parseBERaw64(r, &tstart)
+			tstart = ad.BEUint64()
 		case nl.CTA_TIMESTAMP_STOP:
-			parseBERaw64(r, &tstop)
-		default:
-			return
+			tstop = ad.BEUint64()
 		}
 	}
 	return
 }
 
-func parseProtoInfoTCPState(r *bytes.Reader) (s uint8) {
-	binary.Read(r, binary.BigEndian, &s)
-	r.Seek(nl.SizeofNfattr-1, seekCurrent)
-	return s
-}
-
-// parseProtoInfoTCP reads the entire nested protoinfo structure, but only parses the state attr.
-func parseProtoInfoTCP(r *bytes.Reader, attrLen uint16) *ProtoInfoTCP {
+// parseProtoInfoTCP parses the nested protoinfo structure, but only the state attr.
+func parseProtoInfoTCP(b []byte) *ProtoInfoTCP {
 	p := new(ProtoInfoTCP)
-	bytesRead := 0
-	for bytesRead < int(attrLen) {
-		_, t, l := parseNfAttrTL(r)
-		bytesRead += nl.SizeofNfattr
-
-		switch t {
-		case nl.CTA_PROTOINFO_TCP_STATE:
-			p.State = parseProtoInfoTCPState(r)
-			bytesRead += nl.SizeofNfattr
-		default:
-			bytesRead += int(skipNfAttrValue(r, l))
+	ad := nl.NewAttributeDecoder(b)
+	for ad.Next() {
+		if ad.Type() == nl.CTA_PROTOINFO_TCP_STATE {
+			p.State = ad.Uint8()
 		}
 	}
-
 	return p
 }
 
-func parseProtoInfo(r *bytes.Reader, attrLen uint16) (p ProtoInfo) {
-	bytesRead := 0
-	for bytesRead < int(attrLen) {
-		_, t, l := parseNfAttrTL(r)
-		bytesRead += nl.SizeofNfattr
-
-		switch t {
+func parseProtoInfo(b []byte) (p ProtoInfo) {
+	ad := nl.NewAttributeDecoder(b)
+	for ad.Next() {
+		switch ad.Type() {
 		case nl.CTA_PROTOINFO_TCP:
-			p = parseProtoInfoTCP(r, l)
-			bytesRead += int(l)
+			p = parseProtoInfoTCP(ad.Value())
 		// No inner fields of DCCP / SCTP currently supported.
 		case nl.CTA_PROTOINFO_DCCP:
 			p = new(ProtoInfoDCCP)
-			skipped := skipNfAttrValue(r, l)
-			bytesRead += int(skipped)
 		case nl.CTA_PROTOINFO_SCTP:
 			p = new(ProtoInfoSCTP)
-			skipped := skipNfAttrValue(r, l)
-			bytesRead += int(skipped)
-		default:
-			skipped := skipNfAttrValue(r, l)
-			bytesRead += int(skipped)
 		}
 	}
-
 	return p
 }
 
-func parseTimeOut(r *bytes.Reader) (ttimeout uint32) {
-	parseBERaw32(r, &ttimeout)
-	return
-}
-
-func parseConntrackStatus(r *bytes.Reader) (status uint32) {
-	parseBERaw32(r, &status)
-	return
-}
-
-func parseConnectionMark(r *bytes.Reader) (mark uint32) {
-	parseBERaw32(r, &mark)
-	return
-}
-
-func parseConnectionLabels(r *bytes.Reader) (label []byte) {
-	label = make([]byte, 16) // netfilter defines 128 bit labels value
-	binary.Read(r, nl.NativeEndian(), &label)
-	return
-}
-
-func parseConnectionZone(r *bytes.Reader) (zone uint16) {
-	parseBERaw16(r, &zone)
-	r.Seek(2, seekCurrent)
-	return
-}
-
 func parseRawData(data []byte) *ConntrackFlow {
 	s := &ConntrackFlow{}
-	// First there is the Nfgenmsg header
-	// consume only the family field
-	reader := bytes.NewReader(data)
-	binary.Read(reader, nl.NativeEndian(), &s.FamilyType)
-
-	// skip rest of the Netfilter header
-	reader.Seek(3, seekCurrent)
+	if len(data) < nl.SizeofNfgenmsg {
+		return s
+	}
+	// First there is the Nfgenmsg header, only its family is used.
+	s.FamilyType = data[0]
 	// The message structure is the following:
 	// <len, NLA_F_NESTED|CTA_TUPLE_ORIG> 4 bytes
 	// <len, NLA_F_NESTED|CTA_TUPLE_IP> 4 bytes
@@ -733,47 +714,33 @@ func parseRawData(data []byte) *ConntrackFlow {
 	// <len, NLA_F_NESTED|CTA_TUPLE_REPLY> 4 bytes
 	// <len, NLA_F_NESTED|CTA_TUPLE_IP> 4 bytes
 	// flow information of the reverse flow
-	for reader.Len() > 0 {
-		if nested, t, l := parseNfAttrTL(reader); nested {
-			switch t {
-			case nl.CTA_TUPLE_ORIG:
-				if nested, t, l = parseNfAttrTL(reader); nested && t == nl.CTA_TUPLE_IP {
-					parseIpTuple(reader, &s.Forward)
-				}
-			case nl.CTA_TUPLE_REPLY:
-				if nested, t, l = parseNfAttrTL(reader); nested && t == nl.CTA_TUPLE_IP {
-					parseIpTuple(reader, &s.Reverse)
-				} else {
-					// Header not recognized skip it
-					skipNfAttrValue(reader, l)
-				}
-			case nl.CTA_COUNTERS_ORIG:
-				s.Forward.Bytes, s.Forward.Packets = parseByteAndPacketCounters(reader)
-			case nl.CTA_COUNTERS_REPLY:
-				s.Reverse.Bytes, s.Reverse.Packets = parseByteAndPacketCounters(reader)
-			case nl.CTA_TIMESTAMP:
-				s.TimeStart, s.TimeStop = parseTimeStamp(reader, l)
-			case nl.CTA_PROTOINFO:
-				s.ProtoInfo = parseProtoInfo(reader, l)
-			default:
-				skipNfAttrValue(reader, l)
-			}
-		} else {
-			switch t {
-			case nl.CTA_MARK:
-				s.Mark = parseConnectionMark(reader)
-			case nl.CTA_LABELS:
-				s.Labels = parseConnectionLabels(reader)
-			case nl.CTA_TIMEOUT:
-				s.TimeOut = parseTimeOut(reader)
-			case nl.CTA_STATUS:
-				s.Status = parseConntrackStatus(reader)
-			case nl.CTA_ID, nl.CTA_USE:
-				skipNfAttrValue(reader, l)
-			case nl.CTA_ZONE:
-				s.Zone = parseConnectionZone(reader)
-			default:
-				skipNfAttrValue(reader, l)
+	ad := nl.NewAttributeDecoder(data[nl.SizeofNfgenmsg:])
+	for ad.Next() {
+		switch ad.Type() {
+		case nl.CTA_TUPLE_ORIG:
+			parseIpTuple(ad.Value(), &s.Forward)
+		case nl.CTA_TUPLE_REPLY:
+			parseIpTuple(ad.Value(), &s.Reverse)
+		case nl.CTA_COUNTERS_ORIG:
+			s.Forward.Bytes, s.Forward.Packets = parseByteAndPacketCounters(ad.Value())
+		case nl.CTA_COUNTERS_REPLY:
+			s.Reverse.Bytes, s.Reverse.Packets = parseByteAndPacketCounters(ad.Value())
+		case nl.CTA_TIMESTAMP:
+			s.TimeStart, s.TimeStop = parseTimeStamp(ad.Value())
+		case nl.CTA_PROTOINFO:
+			s.ProtoInfo = parseProtoInfo(ad.Value())
+		case nl.CTA_MARK:
+			s.Mark = ad.BEUint32()
+		case nl.CTA_LABELS:
+			s.Labels = ad.Bytes()
+		case nl.CTA_TIMEOUT:
+			s.TimeOut = ad.BEUint32()
+		case nl.CTA_STATUS:
+			s.Status = ad.BEUint32()
+		case nl.CTA_ZONE:
+			// Some kernels pad the zone into a 4 bytes value.
+			if v := ad.Value(); len(v) >= 2 {
+				s.Zone = binary.BigEndian.Uint16(v)
 			}
 		}
 	}
@@ -863,6 +830,11 @@ func (f *ConntrackFilter) AddIPNet(tp ConntrackFilterType, ipNet *net.IPNet) err
 	return nil
 }
 
+// AddPrefix adds a netip.Prefix to the conntrack filter
+func (f *ConntrackFilter) AddPrefix(tp ConntrackFilterType, prefix netip.Prefix) error {
+	return f.AddIPNet(tp, NewIPNetFromPrefix(prefix))
+}
+
 // AddIP adds an IP to the conntrack filter
 func (f *ConntrackFilter) AddIP(tp ConntrackFilterType, ip net.IP) error {
 	if ip == nil {
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"fmt"
 	"net"
+	"net/netip"
 	"os"
 	"os/exec"
 	"runtime"
@@ -2067,3 +2068,39 @@ func tuplesEqual(t1, t2 IPTuple) bool {
 
 	return true
 }
+
+func TestParseConntrackFlowNetip(t *testing.T) {
+	b := []byte{unix.AF_INET, 0, 0, 0}
+	orig := nl.NewRtAttr(unix.NLA_F_NESTED|nl.CTA_TUPLE_ORIG, nil)
+	ip := orig.AddRtAttr(unix.NLA_F_NESTED|nl.CTA_TUPLE_IP, nil)
+	ip.AddRtAttr(nl.CTA_IP_V4_SRC, net.ParseIP("10.0.0.1").To4())
+	ip.AddRtAttr(nl.CTA_IP_V4_DST, net.ParseIP("10.0.0.2").To4())
+	proto := orig.AddRtAttr(unix.NLA_F_NESTED|nl.CTA_TUPLE_PROTO, nil)
+	proto.AddRtAttr(nl.CTA_PROTO_NUM, nl.Uint8Attr(unix.IPPROTO_TCP))
+	proto.AddRtAttr(nl.CTA_PROTO_SRC_PORT, nl.BEUint16Attr(1234))
+	proto.AddRtAttr(nl.CTA_PROTO_DST_PORT, nl.BEUint16Attr(80))
+	b = append(b, orig.Serialize()...)
+	b = append(b, nl.NewRtAttr(nl.CTA_MARK, nl.BEUint32Attr(5)).Serialize()...)
+
+	flow := parseConntrackFlowNetip(b)
+	expected := ConntrackFlowNetip{
+		FamilyType: unix.AF_INET,
+		Forward: IPTupleNetip{
+			Protocol: unix.IPPROTO_TCP,
+			Src:      netip.MustParseAddr("10.0.0.1"),
+			Dst:      netip.MustParseAddr("10.0.0.2"),
+			SrcPort:  1234,
+			DstPort:  80,
+		},
+		Mark: 5,
+	}
+	if flow != expected {
+		t.Fatalf("expected %+v, got %+v", expected, flow)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		parseConntrackFlowNetip(b)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocation, got %v", allocs)
+	}
+}
@@ -12,6 +12,9 @@ type InetFamily uint8
 // ConntrackFlow placeholder
 type ConntrackFlow struct{}
 
+// ConntrackFlowNetip placeholder
+type ConntrackFlowNetip struct{}
+
 // CustomConntrackFilter placeholder
 type CustomConntrackFilter struct{}
 
@@ -24,6 +27,12 @@ func ConntrackTableList(table ConntrackTableType, family InetFamily) ([]*Conntra
 	return nil, ErrNotImplemented
 }
 
+// ConntrackTableListNetipIter passes each flow of the table to f, as a
+// ConntrackFlowNetip decoded without allocating.
+func ConntrackTableListNetipIter(table ConntrackTableType, family InetFamily, f func(ConntrackFlowNetip) (cont bool)) error {
+	return ErrNotImplemented
+}
+
 // ConntrackTableFlush flushes all the flows of a specified table
 // conntrack -F [table]            Flush table
 // The flush operation applies to all the family types
@@ -0,0 +1,1772 @@
+package netlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// ethtoolHeaderAttr returns the attribute carrying the request header of
+// cmd, a userspace to kernel message type. It is attribute 1 for every
+// message except the statistics one.
+func ethtoolHeaderAttr(cmd uint8) int {
+	if cmd == nl.ETHTOOL_MSG_STATS_GET {
+		return nl.ETHTOOL_A_STATS_HEADER
+	}
+	return 1
+}
+
+// ethtoolReplyHeaderAttr works like ethtoolHeaderAttr for the kernel to
+// userspace message types of the replies and notifications, which are
+// numbered independently.
+func ethtoolReplyHeaderAttr(cmd uint8) int {
+	if cmd == nl.ETHTOOL_MSG_STATS_GET_REPLY {
+		return nl.ETHTOOL_A_STATS_HEADER
+	}
+	return 1
+}
+
+// EthtoolLinkInfo contains the port settings of a device.
+type EthtoolLinkInfo struct {
+	Port        *uint8
+	PhyAddr     *uint8
+	TpMdix      uint8 // Query only
+	TpMdixCtrl  *uint8
+	Transceiver uint8 // Query only
+}
+
+// EthtoolLinkModes contains the speed, duplex and autonegotiation settings
+// of a device. Ours maps the name of each supported link mode to whether it
+// is advertised. When setting, only the modes present in Ours are changed.
+type EthtoolLinkModes struct {
+	Autoneg          *bool
+	Speed            *uint32 // in Mb/s
+	Duplex           *uint8
+	Lanes            *uint32
+	MasterSlaveCfg   *uint8
+	MasterSlaveState uint8 // Query only
+	Ours             map[string]bool
+	Peer             map[string]bool // Query only
+}
+
+// EthtoolLinkState contains the link state of a device.
+type EthtoolLinkState struct {
+	Link        bool
+	Sqi         uint32
+	SqiMax      uint32
+	ExtState    uint8
+	ExtSubstate uint8
+	ExtDownCnt  uint32
+}
+
+// EthtoolFeatures contains the offload features of a device, indexed by
+// name. Hw holds the features that can be changed, Wanted the ones requested
+// by the user, Active the ones currently enabled and NoChange the ones that
+// cannot be changed at all.
+type EthtoolFeatures struct {
+	Hw       map[string]bool
+	Wanted   map[string]bool
+	Active   map[string]bool
+	NoChange map[string]bool
+}
+
+// EthtoolRings contains the ring sizes of a device.
+type EthtoolRings struct {
+	RxMax           uint32 // Query only
+	RxMiniMax       uint32 // Query only
+	RxJumboMax      uint32 // Query only
+	TxMax           uint32 // Query only
+	TxPushBufLenMax uint32 // Query only
+	Rx              *uint32
+	RxMini          *uint32
+	RxJumbo         *uint32
+	Tx              *uint32
+	RxBufLen        *uint32
+	TcpDataSplit    *uint8
+	CqeSize         *uint32
+	TxPush          *bool
+	RxPush          *bool
+	TxPushBufLen    *uint32
+}
+
+// EthtoolChannels contains the channel counts of a device.
+type EthtoolChannels struct {
+	RxMax         uint32 // Query only
+	TxMax         uint32 // Query only
+	OtherMax      uint32 // Query only
+	CombinedMax   uint32 // Query only
+	RxCount       *uint32
+	TxCount       *uint32
+	OtherCount    *uint32
+	CombinedCount *uint32
+}
+
+// EthtoolCoalesce contains the interrupt coalescing settings of a device.
+// Only the parameters supported by the driver are returned.
+type EthtoolCoalesce struct {
+	RxUsecs            *uint32
+	RxMaxFrames        *uint32
+	RxUsecsIrq         *uint32
+	RxMaxFramesIrq     *uint32
+	TxUsecs            *uint32
+	TxMaxFrames        *uint32
+	TxUsecsIrq         *uint32
+	TxMaxFramesIrq     *uint32
+	StatsBlockUsecs    *uint32
+	UseAdaptiveRx      *bool
+	UseAdaptiveTx      *bool
+	PktRateLow         *uint32
+	RxUsecsLow         *uint32
+	RxMaxFramesLow     *uint32
+	TxUsecsLow         *uint32
+	TxMaxFramesLow     *uint32
+	PktRateHigh        *uint32
+	RxUsecsHigh        *uint32
+	RxMaxFramesHigh    *uint32
+	TxUsecsHigh        *uint32
+	TxMaxFramesHigh    *uint32
+	RateSampleInterval *uint32
+	UseCqeModeTx       *bool
+	UseCqeModeRx       *bool
+	TxAggrMaxBytes     *uint32
+	TxAggrMaxFrames    *uint32
+	TxAggrTimeUsecs    *uint32
+}
+
+// EthtoolPause contains the pause frame settings of a device.
+type EthtoolPause struct {
+	Autoneg *bool
+	Rx      *bool
+	Tx      *bool
+	Stats   *EthtoolPauseStats // Query only
+}
+
+// EthtoolPauseStats contains the pause frame counters of a device.
+type EthtoolPauseStats struct {
+	TxFrames uint64
+	RxFrames uint64
+}
+
+// EthtoolEee contains the Energy Efficient Ethernet settings of a device.
+// ModesOurs maps the name of each supported link mode to whether EEE is
+// advertised for it.
+type EthtoolEee struct {
+	ModesOurs    map[string]bool
+	ModesPeer    map[string]bool // Query only
+	Active       bool            // Query only
+	Enabled      *bool
+	TxLpiEnabled *bool
+	TxLpiTimer   *uint32 // in usecs
+}
+
+// EthtoolUpdate is sent when the ethtool settings of a device change.
+// Value is one of *EthtoolLinkInfo, *EthtoolLinkModes, *EthtoolFeatures,
+// *EthtoolRings, *EthtoolChannels, *EthtoolCoalesce, *EthtoolPause,
+// *EthtoolEee, *EthtoolCableTestResult, *EthtoolCableTestTdrResult or, for
+// private flags, map[string]bool.
+type EthtoolUpdate struct {
+	Command uint8
+	Index   int
+	Name    string
+	Value   interface{}
+}
+
+// EthtoolGetLinkInfo returns the port settings of the link.
+// Equivalent to: `ethtool $link`
+func EthtoolGetLinkInfo(link Link) (*EthtoolLinkInfo, error) {
+	return pkgHandle.EthtoolGetLinkInfo(link)
+}
+
+// EthtoolGetLinkInfo returns the port settings of the link.
+// Equivalent to: `ethtool $link`
+func (h *Handle) EthtoolGetLinkInfo(link Link) (*EthtoolLinkInfo, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_LINKINFO_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolLinkInfo(attrs), nil
+}
+
+// EthtoolSetLinkInfo changes the port settings of the link. Only the
+// non-nil fields are changed.
+// Equivalent to: `ethtool -s $link port $port phyad $phyaddr mdix $mdix`
+func EthtoolSetLinkInfo(link Link, info EthtoolLinkInfo) error {
+	return pkgHandle.EthtoolSetLinkInfo(link, info)
+}
+
+// EthtoolSetLinkInfo changes the port settings of the link. Only the
+// non-nil fields are changed.
+// Equivalent to: `ethtool -s $link port $port phyad $phyaddr mdix $mdix`
+func (h *Handle) EthtoolSetLinkInfo(link Link, info EthtoolLinkInfo) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_LINKINFO_SET, link, 0)
+	if err != nil {
+		return err
+	}
+	if info.Port != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_LINKINFO_PORT, nl.Uint8Attr(*info.Port)))
+	}
+	if info.PhyAddr != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_LINKINFO_PHYADDR, nl.Uint8Attr(*info.PhyAddr)))
+	}
+	if info.TpMdixCtrl != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_LINKINFO_TP_MDIX_CTRL, nl.Uint8Attr(*info.TpMdixCtrl)))
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolGetLinkModes returns the speed, duplex and autonegotiation settings
+// of the link.
+// Equivalent to: `ethtool $link`
+func EthtoolGetLinkModes(link Link) (*EthtoolLinkModes, error) {
+	return pkgHandle.EthtoolGetLinkModes(link)
+}
+
+// EthtoolGetLinkModes returns the speed, duplex and autonegotiation settings
+// of the link.
+// Equivalent to: `ethtool $link`
+func (h *Handle) EthtoolGetLinkModes(link Link) (*EthtoolLinkModes, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_LINKMODES_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolLinkModes(attrs)
+}
+
+// EthtoolSetLinkModes changes the speed, duplex and autonegotiation settings
+// of the link. Only the non-nil fields are changed.
+// Equivalent to: `ethtool -s $link speed $speed duplex $duplex autoneg $autoneg`
+func EthtoolSetLinkModes(link Link, modes EthtoolLinkModes) error {
+	return pkgHandle.EthtoolSetLinkModes(link, modes)
+}
+
+// EthtoolSetLinkModes changes the speed, duplex and autonegotiation settings
+// of the link. Only the non-nil fields are changed.
+// Equivalent to: `ethtool -s $link speed $speed duplex $duplex autoneg $autoneg`
+func (h *Handle) EthtoolSetLinkModes(link Link, modes EthtoolLinkModes) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_LINKMODES_SET, link, 0)
+	if err != nil {
+		return err
+	}
+	if modes.Autoneg != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_AUTONEG, boolAttr(*modes.Autoneg)))
+	}
+	if modes.Ours != nil {
+		req.AddData(encodeEthtoolBitset(nl.ETHTOOL_A_LINKMODES_OURS, modes.Ours))
+	}
+	if modes.Speed != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_SPEED, nl.Uint32Attr(*modes.Speed)))
+	}
+	if modes.Duplex != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_DUPLEX, nl.Uint8Attr(*modes.Duplex)))
+	}
+	if modes.MasterSlaveCfg != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_MASTER_SLAVE_CFG, nl.Uint8Attr(*modes.MasterSlaveCfg)))
+	}
+	if modes.Lanes != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_LINKMODES_LANES, nl.Uint32Attr(*modes.Lanes)))
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolGetLinkState returns the link state of the link.
+// Equivalent to: `ethtool $link`
+func EthtoolGetLinkState(link Link) (*EthtoolLinkState, error) {
+	return pkgHandle.EthtoolGetLinkState(link)
+}
+
+// EthtoolGetLinkState returns the link state of the link.
+// Equivalent to: `ethtool $link`
+func (h *Handle) EthtoolGetLinkState(link Link) (*EthtoolLinkState, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_LINKSTATE_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	state := &EthtoolLinkState{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_LINKSTATE_LINK:
+			state.Link = byteToBool(attr.Value[0])
+		case nl.ETHTOOL_A_LINKSTATE_SQI:
+			state.Sqi = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_LINKSTATE_SQI_MAX:
+			state.SqiMax = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_LINKSTATE_EXT_STATE:
+			state.ExtState = attr.Value[0]
+		case nl.ETHTOOL_A_LINKSTATE_EXT_SUBSTATE:
+			state.ExtSubstate = attr.Value[0]
+		case nl.ETHTOOL_A_LINKSTATE_EXT_DOWN_CNT:
+			state.ExtDownCnt = native.Uint32(attr.Value)
+		}
+	}
+	return state, nil
+}
+
+// EthtoolGetFeatures returns the offload features of the link.
+// Equivalent to: `ethtool -k $link`
+func EthtoolGetFeatures(link Link) (*EthtoolFeatures, error) {
+	return pkgHandle.EthtoolGetFeatures(link)
+}
+
+// EthtoolGetFeatures returns the offload features of the link.
+// Equivalent to: `ethtool -k $link`
+func (h *Handle) EthtoolGetFeatures(link Link) (*EthtoolFeatures, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_FEATURES_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolFeatures(attrs)
+}
+
+// EthtoolSetFeatures enables or disables the given offload features of the
+// link. Features not present in the map are left unchanged.
+// Equivalent to: `ethtool -K $link $feature on|off`
+func EthtoolSetFeatures(link Link, features map[string]bool) error {
+	return pkgHandle.EthtoolSetFeatures(link, features)
+}
+
+// EthtoolSetFeatures enables or disables the given offload features of the
+// link. Features not present in the map are left unchanged.
+// Equivalent to: `ethtool -K $link $feature on|off`
+func (h *Handle) EthtoolSetFeatures(link Link, features map[string]bool) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_FEATURES_SET, link, 0)
+	if err != nil {
+		return err
+	}
+	req.AddData(encodeEthtoolBitset(nl.ETHTOOL_A_FEATURES_WANTED, features))
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolGetPrivFlags returns the driver private flags of the link.
+// Equivalent to: `ethtool --show-priv-flags $link`
+func EthtoolGetPrivFlags(link Link) (map[string]bool, error) {
+	return pkgHandle.EthtoolGetPrivFlags(link)
+}
+
+// EthtoolGetPrivFlags returns the driver private flags of the link.
+// Equivalent to: `ethtool --show-priv-flags $link`
+func (h *Handle) EthtoolGetPrivFlags(link Link) (map[string]bool, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_PRIVFLAGS_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolPrivFlags(attrs)
+}
+
+// EthtoolSetPrivFlags changes the given driver private flags of the link.
+// Flags not present in the map are left unchanged.
+// Equivalent to: `ethtool --set-priv-flags $link $flag on|off`
+func EthtoolSetPrivFlags(link Link, flags map[string]bool) error {
+	return pkgHandle.EthtoolSetPrivFlags(link, flags)
+}
+
+// EthtoolSetPrivFlags changes the given driver private flags of the link.
+// Flags not present in the map are left unchanged.
+// Equivalent to: `ethtool --set-priv-flags $link $flag on|off`
+func (h *Handle) EthtoolSetPrivFlags(link Link, flags map[string]bool) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_PRIVFLAGS_SET, link, 0)
+	if err != nil {
+		return err
+	}
+	req.AddData(encodeEthtoolBitset(nl.ETHTOOL_A_PRIVFLAGS_FLAGS, flags))
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolGetRings returns the ring sizes of the link.
+// Equivalent to: `ethtool -g $link`
+func EthtoolGetRings(link Link) (*EthtoolRings, error) {
+	return pkgHandle.EthtoolGetRings(link)
+}
+
+// EthtoolGetRings returns the ring sizes of the link.
+// Equivalent to: `ethtool -g $link`
+func (h *Handle) EthtoolGetRings(link Link) (*EthtoolRings, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_RINGS_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolRings(attrs), nil
+}
+
+// EthtoolSetRings changes the ring sizes of the link. Only the non-nil
+// fields are changed.
+// Equivalent to: `ethtool -G $link rx $rx tx $tx`
+func EthtoolSetRings(link Link, rings EthtoolRings) error {
+	return pkgHandle.EthtoolSetRings(link, rings)
+}
+
+// EthtoolSetRings changes the ring sizes of the link. Only the non-nil
+// fields are changed.
+// Equivalent to: `ethtool -G $link rx $rx tx $tx`
+func (h *Handle) EthtoolSetRings(link Link, rings EthtoolRings) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_RINGS_SET, link, 0)
+	if err != nil {
+		return err
+	}
+	addEthtoolUint32Attrs(req, rings.uint32Attrs())
+	if rings.TcpDataSplit != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_RINGS_TCP_DATA_SPLIT, nl.Uint8Attr(*rings.TcpDataSplit)))
+	}
+	if rings.TxPush != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_RINGS_TX_PUSH, boolAttr(*rings.TxPush)))
+	}
+	if rings.RxPush != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_RINGS_RX_PUSH, boolAttr(*rings.RxPush)))
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolGetChannels returns the channel counts of the link.
+// Equivalent to: `ethtool -l $link`
+func EthtoolGetChannels(link Link) (*EthtoolChannels, error) {
+	return pkgHandle.EthtoolGetChannels(link)
+}
+
+// EthtoolGetChannels returns the channel counts of the link.
+// Equivalent to: `ethtool -l $link`
+func (h *Handle) EthtoolGetChannels(link Link) (*EthtoolChannels, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_CHANNELS_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolChannels(attrs), nil
+}
+
+// EthtoolSetChannels changes the channel counts of the link. Only the
+// non-nil fields are changed.
+// Equivalent to: `ethtool -L $link rx $rx tx $tx other $other combined $combined`
+func EthtoolSetChannels(link Link, channels EthtoolChannels) error {
+	return pkgHandle.EthtoolSetChannels(link, channels)
+}
+
+// EthtoolSetChannels changes the channel counts of the link. Only the
+// non-nil fields are changed.
+// Equivalent to: `ethtool -L $link rx $rx tx $tx other $other combined $combined`
+func (h *Handle) EthtoolSetChannels(link Link, channels EthtoolChannels) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_CHANNELS_SET, link, 0)
+	if err != nil {
+		return err
+	}
+	addEthtoolUint32Attrs(req, channels.uint32Attrs())
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolGetCoalesce returns the interrupt coalescing settings of the link.
+// Equivalent to: `ethtool -c $link`
+func EthtoolGetCoalesce(link Link) (*EthtoolCoalesce, error) {
+	return pkgHandle.EthtoolGetCoalesce(link)
+}
+
+// EthtoolGetCoalesce returns the interrupt coalescing settings of the link.
+// Equivalent to: `ethtool -c $link`
+func (h *Handle) EthtoolGetCoalesce(link Link) (*EthtoolCoalesce, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_COALESCE_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolCoalesce(attrs), nil
+}
+
+// EthtoolSetCoalesce changes the interrupt coalescing settings of the link.
+// Only the non-nil fields are changed.
+// Equivalent to: `ethtool -C $link rx-usecs $usecs ...`
+func EthtoolSetCoalesce(link Link, coalesce EthtoolCoalesce) error {
+	return pkgHandle.EthtoolSetCoalesce(link, coalesce)
+}
+
+// EthtoolSetCoalesce changes the interrupt coalescing settings of the link.
+// Only the non-nil fields are changed.
+// Equivalent to: `ethtool -C $link rx-usecs $usecs ...`
+func (h *Handle) EthtoolSetCoalesce(link Link, coalesce EthtoolCoalesce) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_COALESCE_SET, link, 0)
+	if err != nil {
+		return err
+	}
+	addEthtoolUint32Attrs(req, coalesce.uint32Attrs())
+	for attrType, value := range coalesce.boolAttrs() {
+		if *value != nil {
+			req.AddData(nl.NewRtAttr(attrType, boolAttr(**value)))
+		}
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolGetPause returns the pause frame settings of the link.
+// Equivalent to: `ethtool -a $link`
+func EthtoolGetPause(link Link) (*EthtoolPause, error) {
+	return pkgHandle.EthtoolGetPause(link)
+}
+
+// EthtoolGetPause returns the pause frame settings of the link.
+// Equivalent to: `ethtool -a $link`
+func (h *Handle) EthtoolGetPause(link Link) (*EthtoolPause, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_PAUSE_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolPause(attrs)
+}
+
+// EthtoolSetPause changes the pause frame settings of the link. Only the
+// non-nil fields are changed.
+// Equivalent to: `ethtool -A $link autoneg $autoneg rx $rx tx $tx`
+func EthtoolSetPause(link Link, pause EthtoolPause) error {
+	return pkgHandle.EthtoolSetPause(link, pause)
+}
+
+// EthtoolSetPause changes the pause frame settings of the link. Only the
+// non-nil fields are changed.
+// Equivalent to: `ethtool -A $link autoneg $autoneg rx $rx tx $tx`
+func (h *Handle) EthtoolSetPause(link Link, pause EthtoolPause) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_PAUSE_SET, link, 0)
+	if err != nil {
+		return err
+	}
+	if pause.Autoneg != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_PAUSE_AUTONEG, boolAttr(*pause.Autoneg)))
+	}
+	if pause.Rx != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_PAUSE_RX, boolAttr(*pause.Rx)))
+	}
+	if pause.Tx != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_PAUSE_TX, boolAttr(*pause.Tx)))
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolGetEee returns the Energy Efficient Ethernet settings of the link.
+// Equivalent to: `ethtool --show-eee $link`
+func EthtoolGetEee(link Link) (*EthtoolEee, error) {
+	return pkgHandle.EthtoolGetEee(link)
+}
+
+// EthtoolGetEee returns the Energy Efficient Ethernet settings of the link.
+// Equivalent to: `ethtool --show-eee $link`
+func (h *Handle) EthtoolGetEee(link Link) (*EthtoolEee, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_EEE_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseEthtoolEee(attrs)
+}
+
+// EthtoolSetEee changes the Energy Efficient Ethernet settings of the link.
+// Only the non-nil fields are changed.
+// Equivalent to: `ethtool --set-eee $link eee on|off tx-lpi on|off tx-timer $timer`
+func EthtoolSetEee(link Link, eee EthtoolEee) error {
+	return pkgHandle.EthtoolSetEee(link, eee)
+}
+
+// EthtoolSetEee changes the Energy Efficient Ethernet settings of the link.
+// Only the non-nil fields are changed.
+// Equivalent to: `ethtool --set-eee $link eee on|off tx-lpi on|off tx-timer $timer`
+func (h *Handle) EthtoolSetEee(link Link, eee EthtoolEee) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_EEE_SET, link, 0)
+	if err != nil {
+		return err
+	}
+	if eee.ModesOurs != nil {
+		req.AddData(encodeEthtoolBitset(nl.ETHTOOL_A_EEE_MODES_OURS, eee.ModesOurs))
+	}
+	if eee.Enabled != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_EEE_ENABLED, boolAttr(*eee.Enabled)))
+	}
+	if eee.TxLpiEnabled != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_EEE_TX_LPI_ENABLED, boolAttr(*eee.TxLpiEnabled)))
+	}
+	if eee.TxLpiTimer != nil {
+		req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_EEE_TX_LPI_TIMER, nl.Uint32Attr(*eee.TxLpiTimer)))
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolGetStringSet returns the strings of the string set id, such as
+// nl.ETH_SS_FEATURES or nl.ETH_SS_LINK_MODES, indexed by bit. If link is
+// nil, the global string set is returned, otherwise the one of the device.
+// Equivalent to: `ethtool --show-strings $link`
+func EthtoolGetStringSet(link Link, id uint32) ([]string, error) {
+	return pkgHandle.EthtoolGetStringSet(link, id)
+}
+
+// EthtoolGetStringSet returns the strings of the string set id, such as
+// nl.ETH_SS_FEATURES or nl.ETH_SS_LINK_MODES, indexed by bit. If link is
+// nil, the global string set is returned, otherwise the one of the device.
+// Equivalent to: `ethtool --show-strings $link`
+func (h *Handle) EthtoolGetStringSet(link Link, id uint32) ([]string, error) {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_STRSET_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	sets := nl.NewRtAttr(nl.ETHTOOL_A_STRSET_STRINGSETS|unix.NLA_F_NESTED, nil)
+	set := sets.AddRtAttr(nl.ETHTOOL_A_STRINGSETS_STRINGSET|unix.NLA_F_NESTED, nil)
+	set.AddRtAttr(nl.ETHTOOL_A_STRINGSET_ID, nl.Uint32Attr(id))
+	req.AddData(sets)
+
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("no reply for ethtool string set %d", id)
+	}
+	attrs, err := nl.ParseRouteAttr(msgs[0][nl.SizeofGenlmsg:])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.ETHTOOL_A_STRSET_STRINGSETS {
+			continue
+		}
+		sets, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, set := range sets {
+			setID, strings, err := parseEthtoolStringSet(set.Value)
+			if err != nil {
+				return nil, err
+			}
+			if setID == id {
+				return strings, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("ethtool string set %d not found", id)
+}
+
+func (h *Handle) newEthtoolRequest(cmd uint8, link Link, flags uint32) (*nl.NetlinkRequest, error) {
+	f, err := h.GenlFamilyGet(nl.ETHTOOL_GENL_NAME)
+	if err != nil {
+		return nil, err
+	}
+	msg := &nl.Genlmsg{
+		Command: cmd,
+		Version: nl.ETHTOOL_GENL_VERSION,
+	}
+	req := h.newNetlinkRequest(int(f.ID), unix.NLM_F_ACK)
+	req.AddData(msg)
+
+	header := nl.NewRtAttr(ethtoolHeaderAttr(cmd)|unix.NLA_F_NESTED, nil)
+	if link != nil {
+		base := link.Attrs()
+		h.ensureIndex(base)
+		header.AddRtAttr(nl.ETHTOOL_A_HEADER_DEV_INDEX, nl.Uint32Attr(uint32(base.Index)))
+	}
+	if flags != 0 {
+		header.AddRtAttr(nl.ETHTOOL_A_HEADER_FLAGS, nl.Uint32Attr(flags))
+	}
+	req.AddData(header)
+	return req, nil
+}
+
+// ethtoolGet sends a get request for the link and returns the attributes
+// of the reply.
+func (h *Handle) ethtoolGet(cmd uint8, link Link, flags uint32) ([]syscall.NetlinkRouteAttr, error) {
+	req, err := h.newEthtoolRequest(cmd, link, flags)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("no reply for ethtool command %d", cmd)
+	}
+	return nl.ParseRouteAttr(msgs[0][nl.SizeofGenlmsg:])
+}
+
+func addEthtoolUint32Attrs(req *nl.NetlinkRequest, values map[int]**uint32) {
+	for attrType, value := range values {
+		if *value != nil {
+			req.AddData(nl.NewRtAttr(attrType, nl.Uint32Attr(**value)))
+		}
+	}
+}
+
+func parseEthtoolUint32Attr(values map[int]**uint32, attr syscall.NetlinkRouteAttr) bool {
+	value, ok := values[int(attr.Attr.Type&nl.NLA_TYPE_MASK)]
+	if !ok {
+		return false
+	}
+	v := native.Uint32(attr.Value)
+	*value = &v
+	return true
+}
+
+func (r *EthtoolRings) uint32Attrs() map[int]**uint32 {
+	return map[int]**uint32{
+		nl.ETHTOOL_A_RINGS_RX:              &r.Rx,
+		nl.ETHTOOL_A_RINGS_RX_MINI:         &r.RxMini,
+		nl.ETHTOOL_A_RINGS_RX_JUMBO:        &r.RxJumbo,
+		nl.ETHTOOL_A_RINGS_TX:              &r.Tx,
+		nl.ETHTOOL_A_RINGS_RX_BUF_LEN:      &r.RxBufLen,
+		nl.ETHTOOL_A_RINGS_CQE_SIZE:        &r.CqeSize,
+		nl.ETHTOOL_A_RINGS_TX_PUSH_BUF_LEN: &r.TxPushBufLen,
+	}
+}
+
+func (c *EthtoolChannels) uint32Attrs() map[int]**uint32 {
+	return map[int]**uint32{
+		nl.ETHTOOL_A_CHANNELS_RX_COUNT:       &c.RxCount,
+		nl.ETHTOOL_A_CHANNELS_TX_COUNT:       &c.TxCount,
+		nl.ETHTOOL_A_CHANNELS_OTHER_COUNT:    &c.OtherCount,
+		nl.ETHTOOL_A_CHANNELS_COMBINED_COUNT: &c.CombinedCount,
+	}
+}
+
+func (c *EthtoolCoalesce) uint32Attrs() map[int]**uint32 {
+	return map[int]**uint32{
+		nl.ETHTOOL_A_COALESCE_RX_USECS:             &c.RxUsecs,
+		nl.ETHTOOL_A_COALESCE_RX_MAX_FRAMES:        &c.RxMaxFrames,
+		nl.ETHTOOL_A_COALESCE_RX_USECS_IRQ:         &c.RxUsecsIrq,
+		nl.ETHTOOL_A_COALESCE_RX_MAX_FRAMES_IRQ:    &c.RxMaxFramesIrq,
+		nl.ETHTOOL_A_COALESCE_TX_USECS:             &c.TxUsecs,
+		nl.ETHTOOL_A_COALESCE_TX_MAX_FRAMES:        &c.TxMaxFrames,
+		nl.ETHTOOL_A_COALESCE_TX_USECS_IRQ:         &c.TxUsecsIrq,
+		nl.ETHTOOL_A_COALESCE_TX_MAX_FRAMES_IRQ:    &c.TxMaxFramesIrq,
+		nl.ETHTOOL_A_COALESCE_STATS_BLOCK_USECS:    &c.StatsBlockUsecs,
+		nl.ETHTOOL_A_COALESCE_PKT_RATE_LOW:         &c.PktRateLow,
+		nl.ETHTOOL_A_COALESCE_RX_USECS_LOW:         &c.RxUsecsLow,
+		nl.ETHTOOL_A_COALESCE_RX_MAX_FRAMES_LOW:    &c.RxMaxFramesLow,
+		nl.ETHTOOL_A_COALESCE_TX_USECS_LOW:         &c.TxUsecsLow,
+		nl.ETHTOOL_A_COALESCE_TX_MAX_FRAMES_LOW:    &c.TxMaxFramesLow,
+		nl.ETHTOOL_A_COALESCE_PKT_RATE_HIGH:        &c.PktRateHigh,
+		nl.ETHTOOL_A_COALESCE_RX_USECS_HIGH:        &c.RxUsecsHigh,
+		nl.ETHTOOL_A_COALESCE_RX_MAX_FRAMES_HIGH:   &c.RxMaxFramesHigh,
+		nl.ETHTOOL_A_COALESCE_TX_USECS_HIGH:        &c.TxUsecsHigh,
+		nl.ETHTOOL_A_COALESCE_TX_MAX_FRAMES_HIGH:   &c.TxMaxFramesHigh,
+		nl.ETHTOOL_A_COALESCE_RATE_SAMPLE_INTERVAL: &c.RateSampleInterval,
+		nl.ETHTOOL_A_COALESCE_TX_AGGR_MAX_BYTES:    &c.TxAggrMaxBytes,
+		nl.ETHTOOL_A_COALESCE_TX_AGGR_MAX_FRAMES:   &c.TxAggrMaxFrames,
+		nl.ETHTOOL_A_COALESCE_TX_AGGR_TIME_USECS:   &c.TxAggrTimeUsecs,
+	}
+}
+
+func (c *EthtoolCoalesce) boolAttrs() map[int]**bool {
+	return map[int]**bool{
+		nl.ETHTOOL_A_COALESCE_USE_ADAPTIVE_RX: &c.UseAdaptiveRx,
+		nl.ETHTOOL_A_COALESCE_USE_ADAPTIVE_TX: &c.UseAdaptiveTx,
+		nl.ETHTOOL_A_COALESCE_USE_CQE_MODE_TX: &c.UseCqeModeTx,
+		nl.ETHTOOL_A_COALESCE_USE_CQE_MODE_RX: &c.UseCqeModeRx,
+	}
+}
+
+// encodeEthtoolBitset encodes bits as a verbose bitset with a mask, so that
+// only the bits present in the map are changed.
+func encodeEthtoolBitset(attrType int, bits map[string]bool) *nl.RtAttr {
+	names := make([]string, 0, len(bits))
+	for name := range bits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bitset := nl.NewRtAttr(attrType|unix.NLA_F_NESTED, nil)
+	list := bitset.AddRtAttr(nl.ETHTOOL_A_BITSET_BITS|unix.NLA_F_NESTED, nil)
+	for _, name := range names {
+		bit := list.AddRtAttr(nl.ETHTOOL_A_BITSET_BITS_BIT|unix.NLA_F_NESTED, nil)
+		bit.AddRtAttr(nl.ETHTOOL_A_BITSET_BIT_NAME, nl.ZeroTerminated(name))
+		if bits[name] {
+			bit.AddRtAttr(nl.ETHTOOL_A_BITSET_BIT_VALUE, []byte{})
+		}
+	}
+	return bitset
+}
+
+// parseEthtoolBitset decodes a verbose bitset. Without a mask, only the set
+// bits are listed; with a mask, the listed bits are the ones in the mask and
+// the value flag tells whether they are set.
+func parseEthtoolBitset(b []byte) (map[string]bool, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	noMask := false
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK == nl.ETHTOOL_A_BITSET_NOMASK {
+			noMask = true
+		}
+	}
+	bits := make(map[string]bool)
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.ETHTOOL_A_BITSET_BITS {
+			continue
+		}
+		list, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, bit := range list {
+			bitAttrs, err := nl.ParseRouteAttr(bit.Value)
+			if err != nil {
+				return nil, err
+			}
+			var name string
+			value := noMask
+			for _, bitAttr := range bitAttrs {
+				switch bitAttr.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.ETHTOOL_A_BITSET_BIT_NAME:
+					name = nl.BytesToString(bitAttr.Value)
+				case nl.ETHTOOL_A_BITSET_BIT_VALUE:
+					value = true
+				}
+			}
+			if name != "" {
+				bits[name] = value
+			}
+		}
+	}
+	return bits, nil
+}
+
+func parseEthtoolStringSet(b []byte) (uint32, []string, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	var id uint32
+	var strings []string
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_STRINGSET_ID:
+			id = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_STRINGSET_COUNT:
+			if count := int(native.Uint32(attr.Value)); count > len(strings) {
+				strings = append(strings, make([]string, count-len(strings))...)
+			}
+		case nl.ETHTOOL_A_STRINGSET_STRINGS:
+			list, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return 0, nil, err
+			}
+			for _, item := range list {
+				itemAttrs, err := nl.ParseRouteAttr(item.Value)
+				if err != nil {
+					return 0, nil, err
+				}
+				var index int
+				var value string
+				for _, itemAttr := range itemAttrs {
+					switch itemAttr.Attr.Type & nl.NLA_TYPE_MASK {
+					case nl.ETHTOOL_A_STRING_INDEX:
+						index = int(native.Uint32(itemAttr.Value))
+					case nl.ETHTOOL_A_STRING_VALUE:
+						value = nl.BytesToString(itemAttr.Value)
+					}
+				}
+				if index >= len(strings) {
+					strings = append(strings, make([]string, index+1-len(strings))...)
+				}
+				strings[index] = value
+			}
+		}
+	}
+	return id, strings, nil
+}
+
+func parseEthtoolLinkInfo(attrs []syscall.NetlinkRouteAttr) *EthtoolLinkInfo {
+	info := &EthtoolLinkInfo{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_LINKINFO_PORT:
+			port := attr.Value[0]
+			info.Port = &port
+		case nl.ETHTOOL_A_LINKINFO_PHYADDR:
+			phyAddr := attr.Value[0]
+			info.PhyAddr = &phyAddr
+		case nl.ETHTOOL_A_LINKINFO_TP_MDIX:
+			info.TpMdix = attr.Value[0]
+		case nl.ETHTOOL_A_LINKINFO_TP_MDIX_CTRL:
+			tpMdixCtrl := attr.Value[0]
+			info.TpMdixCtrl = &tpMdixCtrl
+		case nl.ETHTOOL_A_LINKINFO_TRANSCEIVER:
+			info.Transceiver = attr.Value[0]
+		}
+	}
+	return info
+}
+
+func parseEthtoolLinkModes(attrs []syscall.NetlinkRouteAttr) (*EthtoolLinkModes, error) {
+	modes := &EthtoolLinkModes{}
+	var err error
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_LINKMODES_AUTONEG:
+			autoneg := byteToBool(attr.Value[0])
+			modes.Autoneg = &autoneg
+		case nl.ETHTOOL_A_LINKMODES_OURS:
+			if modes.Ours, err = parseEthtoolBitset(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.ETHTOOL_A_LINKMODES_PEER:
+			if modes.Peer, err = parseEthtoolBitset(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.ETHTOOL_A_LINKMODES_SPEED:
+			speed := native.Uint32(attr.Value)
+			modes.Speed = &speed
+		case nl.ETHTOOL_A_LINKMODES_DUPLEX:
+			duplex := attr.Value[0]
+			modes.Duplex = &duplex
+		case nl.ETHTOOL_A_LINKMODES_MASTER_SLAVE_CFG:
+			cfg := attr.Value[0]
+			modes.MasterSlaveCfg = &cfg
+		case nl.ETHTOOL_A_LINKMODES_MASTER_SLAVE_STATE:
+			modes.MasterSlaveState = attr.Value[0]
+		case nl.ETHTOOL_A_LINKMODES_LANES:
+			lanes := native.Uint32(attr.Value)
+			modes.Lanes = &lanes
+		}
+	}
+	return modes, nil
+}
+
+func parseEthtoolFeatures(attrs []syscall.NetlinkRouteAttr) (*EthtoolFeatures, error) {
+	features := &EthtoolFeatures{}
+	var err error
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_FEATURES_HW:
+			features.Hw, err = parseEthtoolBitset(attr.Value)
+		case nl.ETHTOOL_A_FEATURES_WANTED:
+			features.Wanted, err = parseEthtoolBitset(attr.Value)
+		case nl.ETHTOOL_A_FEATURES_ACTIVE:
+			features.Active, err = parseEthtoolBitset(attr.Value)
+		case nl.ETHTOOL_A_FEATURES_NOCHANGE:
+			features.NoChange, err = parseEthtoolBitset(attr.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return features, nil
+}
+
+func parseEthtoolPrivFlags(attrs []syscall.NetlinkRouteAttr) (map[string]bool, error) {
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK == nl.ETHTOOL_A_PRIVFLAGS_FLAGS {
+			return parseEthtoolBitset(attr.Value)
+		}
+	}
+	return map[string]bool{}, nil
+}
+
+func parseEthtoolRings(attrs []syscall.NetlinkRouteAttr) *EthtoolRings {
+	rings := &EthtoolRings{}
+	values := rings.uint32Attrs()
+	for _, attr := range attrs {
+		if parseEthtoolUint32Attr(values, attr) {
+			continue
+		}
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_RINGS_RX_MAX:
+			rings.RxMax = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_RINGS_RX_MINI_MAX:
+			rings.RxMiniMax = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_RINGS_RX_JUMBO_MAX:
+			rings.RxJumboMax = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_RINGS_TX_MAX:
+			rings.TxMax = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_RINGS_TX_PUSH_BUF_LEN_MAX:
+			rings.TxPushBufLenMax = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_RINGS_TCP_DATA_SPLIT:
+			split := attr.Value[0]
+			rings.TcpDataSplit = &split
+		case nl.ETHTOOL_A_RINGS_TX_PUSH:
+			push := byteToBool(attr.Value[0])
+			rings.TxPush = &push
+		case nl.ETHTOOL_A_RINGS_RX_PUSH:
+			push := byteToBool(attr.Value[0])
+			rings.RxPush = &push
+		}
+	}
+	return rings
+}
+
+func parseEthtoolChannels(attrs []syscall.NetlinkRouteAttr) *EthtoolChannels {
+	channels := &EthtoolChannels{}
+	values := channels.uint32Attrs()
+	for _, attr := range attrs {
+		if parseEthtoolUint32Attr(values, attr) {
+			continue
+		}
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_CHANNELS_RX_MAX:
+			channels.RxMax = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_CHANNELS_TX_MAX:
+			channels.TxMax = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_CHANNELS_OTHER_MAX:
+			channels.OtherMax = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_CHANNELS_COMBINED_MAX:
+			channels.CombinedMax = native.Uint32(attr.Value)
+		}
+	}
+	return channels
+}
+
+func parseEthtoolCoalesce(attrs []syscall.NetlinkRouteAttr) *EthtoolCoalesce {
+	coalesce := &EthtoolCoalesce{}
+	values := coalesce.uint32Attrs()
+	flags := coalesce.boolAttrs()
+	for _, attr := range attrs {
+		if parseEthtoolUint32Attr(values, attr) {
+			continue
+		}
+		if flag, ok := flags[int(attr.Attr.Type&nl.NLA_TYPE_MASK)]; ok {
+			v := byteToBool(attr.Value[0])
+			*flag = &v
+		}
+	}
+	return coalesce
+}
+
+func parseEthtoolPause(attrs []syscall.NetlinkRouteAttr) (*EthtoolPause, error) {
+	pause := &EthtoolPause{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_PAUSE_AUTONEG:
+			autoneg := byteToBool(attr.Value[0])
+			pause.Autoneg = &autoneg
+		case nl.ETHTOOL_A_PAUSE_RX:
+			rx := byteToBool(attr.Value[0])
+			pause.Rx = &rx
+		case nl.ETHTOOL_A_PAUSE_TX:
+			tx := byteToBool(attr.Value[0])
+			pause.Tx = &tx
+		case nl.ETHTOOL_A_PAUSE_STATS:
+			stats, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			pause.Stats = &EthtoolPauseStats{}
+			for _, stat := range stats {
+				switch stat.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.ETHTOOL_A_PAUSE_STAT_TX_FRAMES:
+					pause.Stats.TxFrames = native.Uint64(stat.Value)
+				case nl.ETHTOOL_A_PAUSE_STAT_RX_FRAMES:
+					pause.Stats.RxFrames = native.Uint64(stat.Value)
+				}
+			}
+		}
+	}
+	return pause, nil
+}
+
+func parseEthtoolEee(attrs []syscall.NetlinkRouteAttr) (*EthtoolEee, error) {
+	eee := &EthtoolEee{}
+	var err error
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_EEE_MODES_OURS:
+			if eee.ModesOurs, err = parseEthtoolBitset(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.ETHTOOL_A_EEE_MODES_PEER:
+			if eee.ModesPeer, err = parseEthtoolBitset(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.ETHTOOL_A_EEE_ACTIVE:
+			eee.Active = byteToBool(attr.Value[0])
+		case nl.ETHTOOL_A_EEE_ENABLED:
+			enabled := byteToBool(attr.Value[0])
+			eee.Enabled = &enabled
+		case nl.ETHTOOL_A_EEE_TX_LPI_ENABLED:
+			enabled := byteToBool(attr.Value[0])
+			eee.TxLpiEnabled = &enabled
+		case nl.ETHTOOL_A_EEE_TX_LPI_TIMER:
+			timer := native.Uint32(attr.Value)
+			eee.TxLpiTimer = &timer
+		}
+	}
+	return eee, nil
+}
+
+func parseEthtoolNotification(cmd uint8, m []byte) (*EthtoolUpdate, error) {
+	attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+	if err != nil {
+		return nil, err
+	}
+	update := &EthtoolUpdate{Command: cmd}
+	for _, attr := range attrs {
+		if int(attr.Attr.Type&nl.NLA_TYPE_MASK) != ethtoolReplyHeaderAttr(cmd) {
+			continue
+		}
+		header, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range header {
+			switch a.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.ETHTOOL_A_HEADER_DEV_INDEX:
+				update.Index = int(native.Uint32(a.Value))
+			case nl.ETHTOOL_A_HEADER_DEV_NAME:
+				update.Name = nl.BytesToString(a.Value)
+			}
+		}
+	}
+	switch cmd {
+	case nl.ETHTOOL_MSG_LINKINFO_NTF:
+		update.Value = parseEthtoolLinkInfo(attrs)
+	case nl.ETHTOOL_MSG_LINKMODES_NTF:
+		update.Value, err = parseEthtoolLinkModes(attrs)
+	case nl.ETHTOOL_MSG_FEATURES_NTF:
+		update.Value, err = parseEthtoolFeatures(attrs)
+	case nl.ETHTOOL_MSG_PRIVFLAGS_NTF:
+		update.Value, err = parseEthtoolPrivFlags(attrs)
+	case nl.ETHTOOL_MSG_RINGS_NTF:
+		update.Value = parseEthtoolRings(attrs)
+	case nl.ETHTOOL_MSG_CHANNELS_NTF:
+		update.Value = parseEthtoolChannels(attrs)
+	case nl.ETHTOOL_MSG_COALESCE_NTF:
+		update.Value = parseEthtoolCoalesce(attrs)
+	case nl.ETHTOOL_MSG_PAUSE_NTF:
+		update.Value, err = parseEthtoolPause(attrs)
+	case nl.ETHTOOL_MSG_EEE_NTF:
+		update.Value, err = parseEthtoolEee(attrs)
+	case nl.ETHTOOL_MSG_CABLE_TEST_NTF:
+		update.Value, err = parseEthtoolCableTest(attrs)
+	case nl.ETHTOOL_MSG_CABLE_TEST_TDR_NTF:
+		update.Value, err = parseEthtoolCableTestTdr(attrs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+// EthtoolSubscribe takes a chan down which notifications will be sent
+// when the ethtool settings of a device change. Close the 'done' chan to
+// stop subscription.
+func EthtoolSubscribe(ch chan<- EthtoolUpdate, done <-chan struct{}) error {
+	return ethtoolSubscribeAt(netns.None(), netns.None(), ch, done, nil)
+}
+
+// EthtoolSubscribeOptions contains a set of options to use with
+// EthtoolSubscribeWithOptions.
+type EthtoolSubscribeOptions struct {
+	Namespace     *netns.NsHandle
+	ErrorCallback func(error)
+}
+
+// EthtoolSubscribeWithOptions work like EthtoolSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace can be provided as well as an error callback.
+func EthtoolSubscribeWithOptions(ch chan<- EthtoolUpdate, done <-chan struct{}, options EthtoolSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return ethtoolSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback)
+}
+
+// EthtoolSubscribeContext works like EthtoolSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func EthtoolSubscribeContext(ctx context.Context, ch chan<- EthtoolUpdate, options EthtoolSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return EthtoolSubscribeWithOptions(ch, ctx.Done(), options)
+}
+
+func ethtoolSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- EthtoolUpdate, done <-chan struct{}, cberr func(error)) error {
+	return genlSubscribeGroups(newNs, curNs, nl.ETHTOOL_GENL_NAME, []string{nl.ETHTOOL_MCGRP_MONITOR_NAME}, done, cberr,
+		func(f *GenlFamily, m syscall.NetlinkMessage) error {
+			if len(m.Data) < nl.SizeofGenlmsg {
+				return nil
+			}
+			update, err := parseEthtoolNotification(m.Data[0], m.Data)
+			if err != nil {
+				return fmt.Errorf("could not parse ethtool message: %v", err)
+			}
+			ch <- *update
+			return nil
+		},
+		func() { close(ch) })
+}
+
+// Names of the standard statistics groups, from the ETH_SS_STATS_STD string set.
+var ethtoolStatsGroups = []string{"eth-phy", "eth-mac", "eth-ctrl", "rmon"}
+
+// EthtoolStats contains the standard statistics of a device. A group is nil
+// when the kernel does not report it, and counters not maintained by the
+// driver are zero.
+type EthtoolStats struct {
+	Phy  *EthtoolPhyStats
+	Mac  *EthtoolMacStats
+	Ctrl *EthtoolCtrlStats
+	Rmon *EthtoolRmonStats
+}
+
+// EthtoolPhyStats contains the IEEE 802.3 PHY counters.
+type EthtoolPhyStats struct {
+	SymbolErrorDuringCarrier uint64
+}
+
+// EthtoolMacStats contains the IEEE 802.3 MAC counters.
+type EthtoolMacStats struct {
+	FramesTransmittedOK            uint64
+	SingleCollisionFrames          uint64
+	MultipleCollisionFrames        uint64
+	FramesReceivedOK               uint64
+	FrameCheckSequenceErrors       uint64
+	AlignmentErrors                uint64
+	OctetsTransmittedOK            uint64
+	FramesWithDeferredXmissions    uint64
+	LateCollisions                 uint64
+	FramesAbortedDueToXSColls      uint64
+	FramesLostDueToIntMACXmitError uint64
+	CarrierSenseErrors             uint64
+	OctetsReceivedOK               uint64
+	FramesLostDueToIntMACRcvError  uint64
+	MulticastFramesXmittedOK       uint64
+	BroadcastFramesXmittedOK       uint64
+	FramesWithExcessiveDeferral    uint64
+	MulticastFramesReceivedOK      uint64
+	BroadcastFramesReceivedOK      uint64
+	InRangeLengthErrors            uint64
+	OutOfRangeLengthField          uint64
+	FrameTooLongErrors             uint64
+}
+
+// EthtoolCtrlStats contains the IEEE 802.3 MAC control counters.
+type EthtoolCtrlStats struct {
+	MACControlFramesTransmitted uint64
+	MACControlFramesReceived    uint64
+	UnsupportedOpcodesReceived  uint64
+}
+
+// EthtoolRmonStats contains the RMON (RFC 2819) counters and the packet
+// size histograms.
+type EthtoolRmonStats struct {
+	UndersizePkts uint64
+	OversizePkts  uint64
+	Fragments     uint64
+	Jabbers       uint64
+	RxHist        []EthtoolHistBucket
+	TxHist        []EthtoolHistBucket
+}
+
+// EthtoolHistBucket counts the packets whose size is within [Low, High].
+type EthtoolHistBucket struct {
+	Low   uint32
+	High  uint32
+	Value uint64
+}
+
+// EthtoolTsInfo contains the timestamping capabilities of a device. The
+// maps only hold the supported capabilities. PhcIndex is -1 when the device
+// has no PTP hardware clock.
+type EthtoolTsInfo struct {
+	Timestamping map[string]bool
+	TxTypes      map[string]bool
+	RxFilters    map[string]bool
+	PhcIndex     int
+}
+
+// EthtoolModuleEeprom selects the part of a module EEPROM page to read.
+// Length must not cross a page boundary. When I2CAddress is zero, the
+// default address 0x50 is used.
+type EthtoolModuleEeprom struct {
+	Offset     uint32
+	Length     uint32
+	Page       uint8
+	Bank       uint8
+	I2CAddress uint8
+}
+
+// Default I2C address of the lower memory and of the paged memory of
+// SFP, QSFP and CMIS modules.
+const ethtoolModuleEepromI2CAddress = 0x50
+
+// EthtoolCableTestResult is the result of a cable test, sent down the channel of
+// EthtoolSubscribe once the test started by EthtoolCableTest is done.
+// Results and FaultLengths are only set when Status is
+// nl.ETHTOOL_A_CABLE_TEST_NTF_STATUS_COMPLETED.
+type EthtoolCableTestResult struct {
+	Status       uint8
+	Results      []EthtoolCableResult
+	FaultLengths []EthtoolCableFaultLength
+}
+
+// EthtoolCableResult contains the state of a pair of the cable, one of
+// nl.ETHTOOL_A_CABLE_RESULT_CODE_*.
+type EthtoolCableResult struct {
+	Pair uint8  // nl.ETHTOOL_A_CABLE_PAIR_*
+	Code uint8  // nl.ETHTOOL_A_CABLE_RESULT_CODE_*
+	Src  uint32 // nl.ETHTOOL_A_CABLE_INF_SRC_*, zero if not reported
+}
+
+// EthtoolCableFaultLength contains the distance to the fault on a pair of
+// the cable.
+type EthtoolCableFaultLength struct {
+	Pair uint8  // nl.ETHTOOL_A_CABLE_PAIR_*
+	Cm   uint32 // in centimeters
+	Src  uint32 // nl.ETHTOOL_A_CABLE_INF_SRC_*, zero if not reported
+}
+
+// EthtoolCableTestTdrConfig selects the distances and the pair the raw
+// Time Domain Reflectometry data is collected for. The PHY default is used
+// for nil fields.
+type EthtoolCableTestTdrConfig struct {
+	First *uint32 // in centimeters
+	Last  *uint32 // in centimeters
+	Step  *uint32 // in centimeters
+	Pair  *uint8  // nl.ETHTOOL_A_CABLE_PAIR_*
+}
+
+// EthtoolCableTestTdrResult is the raw Time Domain Reflectometry data of a cable,
+// sent down the channel of EthtoolSubscribe once the test started by
+// EthtoolCableTestTdr is done. Data is in the order reported by the PHY.
+type EthtoolCableTestTdrResult struct {
+	Status uint8
+	Data   []EthtoolCableTdrData
+}
+
+// EthtoolCableTdrData is an entry of the raw TDR data. Exactly one of Step,
+// Amplitude and Pulse is set: a step gives the distances of the amplitudes
+// following it, and a pulse the amplitude of the pulse sent.
+type EthtoolCableTdrData struct {
+	Step      *EthtoolCableTdrStep
+	Amplitude *EthtoolCableTdrAmplitude
+	Pulse     *int16 // in mV
+}
+
+// EthtoolCableTdrStep contains the distances of the amplitudes that follow it.
+type EthtoolCableTdrStep struct {
+	First uint32 // in centimeters
+	Last  uint32 // in centimeters
+	Step  uint32 // in centimeters
+}
+
+// EthtoolCableTdrAmplitude contains the amplitude of the reflection on a
+// pair of the cable.
+type EthtoolCableTdrAmplitude struct {
+	Pair uint8 // nl.ETHTOOL_A_CABLE_PAIR_*
+	Mv   int16
+}
+
+// EthtoolGetStats returns the standard statistics of the link.
+// Equivalent to: `ethtool -S $link --all-groups`
+func EthtoolGetStats(link Link) (*EthtoolStats, error) {
+	return pkgHandle.EthtoolGetStats(link)
+}
+
+// EthtoolGetStats returns the standard statistics of the link.
+// Equivalent to: `ethtool -S $link --all-groups`
+func (h *Handle) EthtoolGetStats(link Link) (*EthtoolStats, error) {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_STATS_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]bool, len(ethtoolStatsGroups))
+	for _, name := range ethtoolStatsGroups {
+		groups[name] = true
+	}
+	req.AddData(encodeEthtoolBitset(nl.ETHTOOL_A_STATS_GROUPS, groups))
+
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, errors.New("no reply for ethtool stats")
+	}
+	attrs, err := nl.ParseRouteAttr(msgs[0][nl.SizeofGenlmsg:])
+	if err != nil {
+		return nil, err
+	}
+	stats := &EthtoolStats{}
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.ETHTOOL_A_STATS_GRP {
+			continue
+		}
+		if err := parseEthtoolStatsGroup(stats, attr.Value); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+// EthtoolGetTsInfo returns the timestamping capabilities of the link.
+// Equivalent to: `ethtool -T $link`
+func EthtoolGetTsInfo(link Link) (*EthtoolTsInfo, error) {
+	return pkgHandle.EthtoolGetTsInfo(link)
+}
+
+// EthtoolGetTsInfo returns the timestamping capabilities of the link.
+// Equivalent to: `ethtool -T $link`
+func (h *Handle) EthtoolGetTsInfo(link Link) (*EthtoolTsInfo, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_TSINFO_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	info := &EthtoolTsInfo{PhcIndex: -1}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_TSINFO_TIMESTAMPING:
+			info.Timestamping, err = parseEthtoolBitset(attr.Value)
+		case nl.ETHTOOL_A_TSINFO_TX_TYPES:
+			info.TxTypes, err = parseEthtoolBitset(attr.Value)
+		case nl.ETHTOOL_A_TSINFO_RX_FILTERS:
+			info.RxFilters, err = parseEthtoolBitset(attr.Value)
+		case nl.ETHTOOL_A_TSINFO_PHC_INDEX:
+			info.PhcIndex = int(int32(native.Uint32(attr.Value)))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+// EthtoolGetModuleEeprom reads a part of a page of the EEPROM of the
+// transceiver module plugged in the link.
+// Equivalent to: `ethtool -m $link offset $offset length $length page $page bank $bank i2c $addr`
+func EthtoolGetModuleEeprom(link Link, eeprom EthtoolModuleEeprom) ([]byte, error) {
+	return pkgHandle.EthtoolGetModuleEeprom(link, eeprom)
+}
+
+// EthtoolGetModuleEeprom reads a part of a page of the EEPROM of the
+// transceiver module plugged in the link.
+// Equivalent to: `ethtool -m $link offset $offset length $length page $page bank $bank i2c $addr`
+func (h *Handle) EthtoolGetModuleEeprom(link Link, eeprom EthtoolModuleEeprom) ([]byte, error) {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_MODULE_EEPROM_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	if eeprom.I2CAddress == 0 {
+		eeprom.I2CAddress = ethtoolModuleEepromI2CAddress
+	}
+	req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_MODULE_EEPROM_OFFSET, nl.Uint32Attr(eeprom.Offset)))
+	req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_MODULE_EEPROM_LENGTH, nl.Uint32Attr(eeprom.Length)))
+	req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_MODULE_EEPROM_PAGE, nl.Uint8Attr(eeprom.Page)))
+	req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_MODULE_EEPROM_BANK, nl.Uint8Attr(eeprom.Bank)))
+	req.AddData(nl.NewRtAttr(nl.ETHTOOL_A_MODULE_EEPROM_I2C_ADDRESS, nl.Uint8Attr(eeprom.I2CAddress)))
+
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, errors.New("no reply for ethtool module eeprom")
+	}
+	attrs, err := nl.ParseRouteAttr(msgs[0][nl.SizeofGenlmsg:])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK == nl.ETHTOOL_A_MODULE_EEPROM_DATA {
+			return attr.Value, nil
+		}
+	}
+	return nil, errors.New("no data in ethtool module eeprom reply")
+}
+
+// EthtoolGetPhcVclocks returns the indexes of the PTP virtual clocks of the
+// link.
+// Equivalent to: `ethtool --show-phc-vclocks $link`
+func EthtoolGetPhcVclocks(link Link) ([]int, error) {
+	return pkgHandle.EthtoolGetPhcVclocks(link)
+}
+
+// EthtoolGetPhcVclocks returns the indexes of the PTP virtual clocks of the
+// link.
+// Equivalent to: `ethtool --show-phc-vclocks $link`
+func (h *Handle) EthtoolGetPhcVclocks(link Link) ([]int, error) {
+	attrs, err := h.ethtoolGet(nl.ETHTOOL_MSG_PHC_VCLOCKS_GET, link, 0)
+	if err != nil {
+		return nil, err
+	}
+	var clocks []int
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.ETHTOOL_A_PHC_VCLOCKS_INDEX {
+			continue
+		}
+		for i := 0; i+4 <= len(attr.Value); i += 4 {
+			clocks = append(clocks, int(int32(native.Uint32(attr.Value[i:]))))
+		}
+	}
+	return clocks, nil
+}
+
+// EthtoolCableTest starts a cable test on the link. Its result is sent to
+// the subscribers of EthtoolSubscribe as an *EthtoolCableTestResult.
+// Equivalent to: `ethtool --cable-test $link`
+func EthtoolCableTest(link Link) error {
+	return pkgHandle.EthtoolCableTest(link)
+}
+
+// EthtoolCableTest starts a cable test on the link. Its result is sent to
+// the subscribers of EthtoolSubscribe as an *EthtoolCableTestResult.
+// Equivalent to: `ethtool --cable-test $link`
+func (h *Handle) EthtoolCableTest(link Link) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_CABLE_TEST_ACT, link, 0)
+	if err != nil {
+		return err
+	}
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// EthtoolCableTestTdr starts collecting the raw Time Domain Reflectometry
+// data of the cable of the link. The data is sent to the subscribers of
+// EthtoolSubscribe as an *EthtoolCableTestTdrResult.
+// Equivalent to: `ethtool --cable-test-tdr $link first $first last $last step $step pair $pair`
+func EthtoolCableTestTdr(link Link, config EthtoolCableTestTdrConfig) error {
+	return pkgHandle.EthtoolCableTestTdr(link, config)
+}
+
+// EthtoolCableTestTdr starts collecting the raw Time Domain Reflectometry
+// data of the cable of the link. The data is sent to the subscribers of
+// EthtoolSubscribe as an *EthtoolCableTestTdrResult.
+// Equivalent to: `ethtool --cable-test-tdr $link first $first last $last step $step pair $pair`
+func (h *Handle) EthtoolCableTestTdr(link Link, config EthtoolCableTestTdrConfig) error {
+	req, err := h.newEthtoolRequest(nl.ETHTOOL_MSG_CABLE_TEST_TDR_ACT, link, 0)
+	if err != nil {
+		return err
+	}
+	cfg := nl.NewRtAttr(nl.ETHTOOL_A_CABLE_TEST_TDR_CFG|unix.NLA_F_NESTED, nil)
+	if config.First != nil {
+		cfg.AddRtAttr(nl.ETHTOOL_A_CABLE_TEST_TDR_CFG_FIRST, nl.Uint32Attr(*config.First))
+	}
+	if config.Last != nil {
+		cfg.AddRtAttr(nl.ETHTOOL_A_CABLE_TEST_TDR_CFG_LAST, nl.Uint32Attr(*config.Last))
+	}
+	if config.Step != nil {
+		cfg.AddRtAttr(nl.ETHTOOL_A_CABLE_TEST_TDR_CFG_STEP, nl.Uint32Attr(*config.Step))
+	}
+	if config.Pair != nil {
+		cfg.AddRtAttr(nl.ETHTOOL_A_CABLE_TEST_TDR_CFG_PAIR, nl.Uint8Attr(*config.Pair))
+	}
+	req.AddData(cfg)
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+func parseEthtoolStatsGroup(stats *EthtoolStats, b []byte) error {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return err
+	}
+	id := -1
+	counters := make(map[int]uint64)
+	var rxHist, txHist []EthtoolHistBucket
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_STATS_GRP_ID:
+			id = int(native.Uint32(attr.Value))
+		case nl.ETHTOOL_A_STATS_GRP_STAT:
+			// Each counter is a u64 whose attribute type is its index
+			stat, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return err
+			}
+			for _, s := range stat {
+				counters[int(s.Attr.Type&nl.NLA_TYPE_MASK)] = native.Uint64(s.Value)
+			}
+		case nl.ETHTOOL_A_STATS_GRP_HIST_RX, nl.ETHTOOL_A_STATS_GRP_HIST_TX:
+			bucket, err := parseEthtoolHistBucket(attr.Value)
+			if err != nil {
+				return err
+			}
+			if attr.Attr.Type&nl.NLA_TYPE_MASK == nl.ETHTOOL_A_STATS_GRP_HIST_RX {
+				rxHist = append(rxHist, bucket)
+			} else {
+				txHist = append(txHist, bucket)
+			}
+		}
+	}
+	switch id {
+	case nl.ETHTOOL_STATS_ETH_PHY:
+		stats.Phy = &EthtoolPhyStats{
+			SymbolErrorDuringCarrier: counters[nl.ETHTOOL_A_STATS_ETH_PHY_5_SYM_ERR],
+		}
+	case nl.ETHTOOL_STATS_ETH_MAC:
+		stats.Mac = &EthtoolMacStats{
+			FramesTransmittedOK:            counters[nl.ETHTOOL_A_STATS_ETH_MAC_2_TX_PKT],
+			SingleCollisionFrames:          counters[nl.ETHTOOL_A_STATS_ETH_MAC_3_SINGLE_COL],
+			MultipleCollisionFrames:        counters[nl.ETHTOOL_A_STATS_ETH_MAC_4_MULTI_COL],
+			FramesReceivedOK:               counters[nl.ETHTOOL_A_STATS_ETH_MAC_5_RX_PKT],
+			FrameCheckSequenceErrors:       counters[nl.ETHTOOL_A_STATS_ETH_MAC_6_FCS_ERR],
+			AlignmentErrors:                counters[nl.ETHTOOL_A_STATS_ETH_MAC_7_ALIGN_ERR],
+			OctetsTransmittedOK:            counters[nl.ETHTOOL_A_STATS_ETH_MAC_8_TX_BYTES],
+			FramesWithDeferredXmissions:    counters[nl.ETHTOOL_A_STATS_ETH_MAC_9_TX_DEFER],
+			LateCollisions:                 counters[nl.ETHTOOL_A_STATS_ETH_MAC_10_LATE_COL],
+			FramesAbortedDueToXSColls:      counters[nl.ETHTOOL_A_STATS_ETH_MAC_11_XS_COL],
+			FramesLostDueToIntMACXmitError: counters[nl.ETHTOOL_A_STATS_ETH_MAC_12_TX_INT_ERR],
+			CarrierSenseErrors:             counters[nl.ETHTOOL_A_STATS_ETH_MAC_13_CS_ERR],
+			OctetsReceivedOK:               counters[nl.ETHTOOL_A_STATS_ETH_MAC_14_RX_BYTES],
+			FramesLostDueToIntMACRcvError:  counters[nl.ETHTOOL_A_STATS_ETH_MAC_15_RX_INT_ERR],
+			MulticastFramesXmittedOK:       counters[nl.ETHTOOL_A_STATS_ETH_MAC_18_TX_MCAST],
+			BroadcastFramesXmittedOK:       counters[nl.ETHTOOL_A_STATS_ETH_MAC_19_TX_BCAST],
+			FramesWithExcessiveDeferral:    counters[nl.ETHTOOL_A_STATS_ETH_MAC_20_XS_DEFER],
+			MulticastFramesReceivedOK:      counters[nl.ETHTOOL_A_STATS_ETH_MAC_21_RX_MCAST],
+			BroadcastFramesReceivedOK:      counters[nl.ETHTOOL_A_STATS_ETH_MAC_22_RX_BCAST],
+			InRangeLengthErrors:            counters[nl.ETHTOOL_A_STATS_ETH_MAC_23_IR_LEN_ERR],
+			OutOfRangeLengthField:          counters[nl.ETHTOOL_A_STATS_ETH_MAC_24_OOR_LEN],
+			FrameTooLongErrors:             counters[nl.ETHTOOL_A_STATS_ETH_MAC_25_TOO_LONG_ERR],
+		}
+	case nl.ETHTOOL_STATS_ETH_CTRL:
+		stats.Ctrl = &EthtoolCtrlStats{
+			MACControlFramesTransmitted: counters[nl.ETHTOOL_A_STATS_ETH_CTRL_3_TX],
+			MACControlFramesReceived:    counters[nl.ETHTOOL_A_STATS_ETH_CTRL_4_RX],
+			UnsupportedOpcodesReceived:  counters[nl.ETHTOOL_A_STATS_ETH_CTRL_5_RX_UNSUP],
+		}
+	case nl.ETHTOOL_STATS_RMON:
+		stats.Rmon = &EthtoolRmonStats{
+			UndersizePkts: counters[nl.ETHTOOL_A_STATS_RMON_UNDERSIZE],
+			OversizePkts:  counters[nl.ETHTOOL_A_STATS_RMON_OVERSIZE],
+			Fragments:     counters[nl.ETHTOOL_A_STATS_RMON_FRAG],
+			Jabbers:       counters[nl.ETHTOOL_A_STATS_RMON_JABBER],
+			RxHist:        rxHist,
+			TxHist:        txHist,
+		}
+	}
+	return nil
+}
+
+func parseEthtoolHistBucket(b []byte) (EthtoolHistBucket, error) {
+	var bucket EthtoolHistBucket
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return bucket, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_STATS_GRP_HIST_BKT_LOW:
+			bucket.Low = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_STATS_GRP_HIST_BKT_HI:
+			bucket.High = native.Uint32(attr.Value)
+		case nl.ETHTOOL_A_STATS_GRP_HIST_VAL:
+			bucket.Value = native.Uint64(attr.Value)
+		}
+	}
+	return bucket, nil
+}
+
+func parseEthtoolCableTest(attrs []syscall.NetlinkRouteAttr) (*EthtoolCableTestResult, error) {
+	test := &EthtoolCableTestResult{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_CABLE_TEST_NTF_STATUS:
+			test.Status = attr.Value[0]
+		case nl.ETHTOOL_A_CABLE_TEST_NTF_NEST:
+			nested, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nested {
+				values, err := nl.ParseRouteAttr(n.Value)
+				if err != nil {
+					return nil, err
+				}
+				switch n.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.ETHTOOL_A_CABLE_NEST_RESULT:
+					var result EthtoolCableResult
+					for _, v := range values {
+						switch v.Attr.Type & nl.NLA_TYPE_MASK {
+						case nl.ETHTOOL_A_CABLE_RESULT_PAIR:
+							result.Pair = v.Value[0]
+						case nl.ETHTOOL_A_CABLE_RESULT_CODE:
+							result.Code = v.Value[0]
+						case nl.ETHTOOL_A_CABLE_RESULT_SRC:
+							result.Src = native.Uint32(v.Value)
+						}
+					}
+					test.Results = append(test.Results, result)
+				case nl.ETHTOOL_A_CABLE_NEST_FAULT_LENGTH:
+					var length EthtoolCableFaultLength
+					for _, v := range values {
+						switch v.Attr.Type & nl.NLA_TYPE_MASK {
+						case nl.ETHTOOL_A_CABLE_FAULT_LENGTH_PAIR:
+							length.Pair = v.Value[0]
+						case nl.ETHTOOL_A_CABLE_FAULT_LENGTH_CM:
+							length.Cm = native.Uint32(v.Value)
+						case nl.ETHTOOL_A_CABLE_FAULT_LENGTH_SRC:
+							length.Src = native.Uint32(v.Value)
+						}
+					}
+					test.FaultLengths = append(test.FaultLengths, length)
+				}
+			}
+		}
+	}
+	return test, nil
+}
+
+func parseEthtoolCableTestTdr(attrs []syscall.NetlinkRouteAttr) (*EthtoolCableTestTdrResult, error) {
+	test := &EthtoolCableTestTdrResult{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.ETHTOOL_A_CABLE_TEST_TDR_NTF_STATUS:
+			test.Status = attr.Value[0]
+		case nl.ETHTOOL_A_CABLE_TEST_TDR_NTF_NEST:
+			nested, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nested {
+				values, err := nl.ParseRouteAttr(n.Value)
+				if err != nil {
+					return nil, err
+				}
+				var data EthtoolCableTdrData
+				switch n.Attr.Type & nl.NLA_TYPE_MASK {
+				case nl.ETHTOOL_A_CABLE_TDR_NEST_STEP:
+					data.Step = &EthtoolCableTdrStep{}
+					for _, v := range values {
+						switch v.Attr.Type & nl.NLA_TYPE_MASK {
+						case nl.ETHTOOL_A_CABLE_STEP_FIRST_DISTANCE:
+							data.Step.First = native.Uint32(v.Value)
+						case nl.ETHTOOL_A_CABLE_STEP_LAST_DISTANCE:
+							data.Step.Last = native.Uint32(v.Value)
+						case nl.ETHTOOL_A_CABLE_STEP_STEP_DISTANCE:
+							data.Step.Step = native.Uint32(v.Value)
+						}
+					}
+				case nl.ETHTOOL_A_CABLE_TDR_NEST_AMPLITUDE:
+					data.Amplitude = &EthtoolCableTdrAmplitude{}
+					for _, v := range values {
+						switch v.Attr.Type & nl.NLA_TYPE_MASK {
+						case nl.ETHTOOL_A_CABLE_AMPLITUDE_PAIR:
+							data.Amplitude.Pair = v.Value[0]
+						case nl.ETHTOOL_A_CABLE_AMPLITUDE_mV:
+							data.Amplitude.Mv = int16(native.Uint16(v.Value))
+						}
+					}
+				case nl.ETHTOOL_A_CABLE_TDR_NEST_PULSE:
+					for _, v := range values {
+						if v.Attr.Type&nl.NLA_TYPE_MASK == nl.ETHTOOL_A_CABLE_PULSE_mV {
+							mv := int16(native.Uint16(v.Value))
+							data.Pulse = &mv
+						}
+					}
+					if data.Pulse == nil {
+						continue
+					}
+				default:
+					continue
+				}
+				test.Data = append(test.Data, data)
+			}
+		}
+	}
+	return test, nil
+}
@@ -0,0 +1,461 @@
+package netlink
+
+import (
+	"errors"
+	"math/rand"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+func setUpEthtoolVeth(t *testing.T) Link {
+	t.Helper()
+	if _, err := GenlFamilyGet(nl.ETHTOOL_GENL_NAME); err != nil {
+		t.Skipf("ethtool netlink not supported: %v", err)
+	}
+	veth := &Veth{LinkAttrs: LinkAttrs{Name: "foo", TxQLen: testTxQLen, MTU: 1400}, PeerName: "bar"}
+	if err := LinkAdd(veth); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return link
+}
+
+func TestEthtoolFeatures(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolVeth(t)
+
+	ch := make(chan EthtoolUpdate, 16)
+	done := make(chan struct{})
+	defer close(done)
+	if err := EthtoolSubscribe(ch, done); err != nil {
+		t.Fatal(err)
+	}
+
+	features, err := EthtoolGetFeatures(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !features.Hw["tx-checksum-ip-generic"] {
+		t.Fatalf("expected tx-checksum-ip-generic to be changeable: %v", features.Hw)
+	}
+
+	if err := EthtoolSetFeatures(link, map[string]bool{"tx-checksum-ip-generic": false}); err != nil {
+		t.Fatal(err)
+	}
+	features, err = EthtoolGetFeatures(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if features.Active["tx-checksum-ip-generic"] {
+		t.Fatal("tx-checksum-ip-generic is still active")
+	}
+
+	timeout := time.After(10 * time.Second)
+	for found := false; !found; {
+		select {
+		case update := <-ch:
+			if update.Command == nl.ETHTOOL_MSG_FEATURES_NTF && update.Index == link.Attrs().Index {
+				if _, ok := update.Value.(*EthtoolFeatures); !ok {
+					t.Fatalf("unexpected update value %T", update.Value)
+				}
+				found = true
+			}
+		case <-timeout:
+			t.Fatal("ethtool features update not received")
+		}
+	}
+}
+
+func TestEthtoolChannels(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolVeth(t)
+
+	channels, err := EthtoolGetChannels(link)
+	if err != nil {
+		t.Skipf("ethtool channels not supported: %v", err)
+	}
+	if channels.RxCount == nil {
+		t.Skipf("rx channels not reported: %+v", channels)
+	}
+
+	rx := channels.RxMax
+	if err := EthtoolSetChannels(link, EthtoolChannels{RxCount: &rx}); err != nil {
+		t.Fatal(err)
+	}
+	channels, err = EthtoolGetChannels(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if channels.RxCount == nil || *channels.RxCount != rx {
+		t.Fatalf("expected %d rx channels, got %+v", rx, channels)
+	}
+}
+
+func TestEthtoolLinkModesAndState(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolVeth(t)
+
+	modes, err := EthtoolGetLinkModes(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modes.Speed == nil || *modes.Speed != 10000 {
+		t.Fatalf("unexpected veth link modes: %+v", modes)
+	}
+	if _, err := EthtoolGetLinkInfo(link); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := EthtoolGetLinkState(link); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEthtoolGetStringSet(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolVeth(t)
+
+	features, err := EthtoolGetStringSet(nil, nl.ETH_SS_FEATURES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, f := range features {
+		if f == "tx-checksum-ip-generic" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("tx-checksum-ip-generic not found in %v", features)
+	}
+
+	stats, err := EthtoolGetStringSet(link, nl.ETH_SS_STATS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) == 0 || stats[0] != "peer_ifindex" {
+		t.Fatalf("unexpected veth stats strings: %v", stats)
+	}
+}
+
+func TestEthtoolGetTsInfoAndStats(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolVeth(t)
+
+	info, err := EthtoolGetTsInfo(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Timestamping["software-transmit"] {
+		t.Fatalf("expected software transmit timestamping: %+v", info)
+	}
+	if info.PhcIndex != -1 {
+		t.Fatalf("expected no PHC for veth, got %d", info.PhcIndex)
+	}
+
+	stats, err := EthtoolGetStats(link)
+	if errors.Is(err, unix.EOPNOTSUPP) {
+		t.Skipf("ethtool stats not supported: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Mac != nil && stats.Mac.FramesTransmittedOK != 0 {
+		t.Fatalf("unexpected MAC stats for veth: %+v", stats.Mac)
+	}
+}
+
+func TestEthtoolModuleEepromAndPhcVclocks(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolVeth(t)
+
+	// veth has no transceiver module, no PHC and no PHY to test the cable of
+	if _, err := EthtoolGetModuleEeprom(link, EthtoolModuleEeprom{Length: 128}); !errors.Is(err, unix.EOPNOTSUPP) {
+		t.Fatalf("expected EOPNOTSUPP reading the module EEPROM of veth, got %v", err)
+	}
+	if err := EthtoolCableTest(link); !errors.Is(err, unix.EOPNOTSUPP) {
+		t.Fatalf("expected EOPNOTSUPP testing the cable of veth, got %v", err)
+	}
+	clocks, err := EthtoolGetPhcVclocks(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clocks) != 0 {
+		t.Fatalf("unexpected PHC vclocks for veth: %v", clocks)
+	}
+}
+
+func ethtoolTestMessage(cmd uint8, attrs ...*nl.RtAttr) []byte {
+	b := (&nl.Genlmsg{Command: cmd, Version: nl.ETHTOOL_GENL_VERSION}).Serialize()
+	for _, attr := range attrs {
+		b = append(b, attr.Serialize()...)
+	}
+	return b
+}
+
+func ethtoolTestHeader(attrType int, index uint32) *nl.RtAttr {
+	header := nl.NewRtAttr(attrType|unix.NLA_F_NESTED, nil)
+	header.AddRtAttr(nl.ETHTOOL_A_HEADER_DEV_INDEX, nl.Uint32Attr(index))
+	return header
+}
+
+func TestParseEthtoolNotificationHeader(t *testing.T) {
+	// The kernel to userspace message types are numbered independently from
+	// the userspace to kernel ones: the module EEPROM reply has the value of
+	// the statistics request, but its header is attribute 1.
+	for _, tc := range []struct {
+		cmd    uint8
+		header int
+	}{
+		{nl.ETHTOOL_MSG_MODULE_EEPROM_GET_REPLY, nl.ETHTOOL_A_MODULE_EEPROM_HEADER},
+		{nl.ETHTOOL_MSG_STATS_GET_REPLY, nl.ETHTOOL_A_STATS_HEADER},
+		{nl.ETHTOOL_MSG_RINGS_NTF, nl.ETHTOOL_A_RINGS_HEADER},
+	} {
+		update, err := parseEthtoolNotification(tc.cmd, ethtoolTestMessage(tc.cmd, ethtoolTestHeader(tc.header, 7)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if update.Index != 7 {
+			t.Fatalf("command %d: expected index 7, got %d", tc.cmd, update.Index)
+		}
+	}
+}
+
+func TestParseEthtoolCableTestNotifications(t *testing.T) {
+	nest := nl.NewRtAttr(nl.ETHTOOL_A_CABLE_TEST_NTF_NEST|unix.NLA_F_NESTED, nil)
+	result := nest.AddRtAttr(nl.ETHTOOL_A_CABLE_NEST_RESULT|unix.NLA_F_NESTED, nil)
+	result.AddRtAttr(nl.ETHTOOL_A_CABLE_RESULT_PAIR, nl.Uint8Attr(nl.ETHTOOL_A_CABLE_PAIR_B))
+	result.AddRtAttr(nl.ETHTOOL_A_CABLE_RESULT_CODE, nl.Uint8Attr(nl.ETHTOOL_A_CABLE_RESULT_CODE_OPEN))
+	length := nest.AddRtAttr(nl.ETHTOOL_A_CABLE_NEST_FAULT_LENGTH|unix.NLA_F_NESTED, nil)
+	length.AddRtAttr(nl.ETHTOOL_A_CABLE_FAULT_LENGTH_PAIR, nl.Uint8Attr(nl.ETHTOOL_A_CABLE_PAIR_B))
+	length.AddRtAttr(nl.ETHTOOL_A_CABLE_FAULT_LENGTH_CM, nl.Uint32Attr(1250))
+	m := ethtoolTestMessage(nl.ETHTOOL_MSG_CABLE_TEST_NTF,
+		ethtoolTestHeader(nl.ETHTOOL_A_CABLE_TEST_NTF_HEADER, 3),
+		nl.NewRtAttr(nl.ETHTOOL_A_CABLE_TEST_NTF_STATUS, nl.Uint8Attr(nl.ETHTOOL_A_CABLE_TEST_NTF_STATUS_COMPLETED)),
+		nest)
+
+	update, err := parseEthtoolNotification(nl.ETHTOOL_MSG_CABLE_TEST_NTF, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test, ok := update.Value.(*EthtoolCableTestResult)
+	if !ok || update.Index != 3 {
+		t.Fatalf("unexpected cable test update: %+v", update)
+	}
+	expected := &EthtoolCableTestResult{
+		Status:       nl.ETHTOOL_A_CABLE_TEST_NTF_STATUS_COMPLETED,
+		Results:      []EthtoolCableResult{{Pair: nl.ETHTOOL_A_CABLE_PAIR_B, Code: nl.ETHTOOL_A_CABLE_RESULT_CODE_OPEN}},
+		FaultLengths: []EthtoolCableFaultLength{{Pair: nl.ETHTOOL_A_CABLE_PAIR_B, Cm: 1250}},
+	}
+	if !reflect.DeepEqual(test, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, test)
+	}
+
+	nest = nl.NewRtAttr(nl.ETHTOOL_A_CABLE_TEST_TDR_NTF_NEST|unix.NLA_F_NESTED, nil)
+	pulse := nest.AddRtAttr(nl.ETHTOOL_A_CABLE_TDR_NEST_PULSE|unix.NLA_F_NESTED, nil)
+	pulse.AddRtAttr(nl.ETHTOOL_A_CABLE_PULSE_mV, nl.Uint16Attr(1000))
+	step := nest.AddRtAttr(nl.ETHTOOL_A_CABLE_TDR_NEST_STEP|unix.NLA_F_NESTED, nil)
+	step.AddRtAttr(nl.ETHTOOL_A_CABLE_STEP_FIRST_DISTANCE, nl.Uint32Attr(100))
+	step.AddRtAttr(nl.ETHTOOL_A_CABLE_STEP_LAST_DISTANCE, nl.Uint32Attr(200))
+	step.AddRtAttr(nl.ETHTOOL_A_CABLE_STEP_STEP_DISTANCE, nl.Uint32Attr(100))
+	amplitude := nest.AddRtAttr(nl.ETHTOOL_A_CABLE_TDR_NEST_AMPLITUDE|unix.NLA_F_NESTED, nil)
+	amplitude.AddRtAttr(nl.ETHTOOL_A_CABLE_AMPLITUDE_PAIR, nl.Uint8Attr(nl.ETHTOOL_A_CABLE_PAIR_A))
+	amplitude.AddRtAttr(nl.ETHTOOL_A_CABLE_AMPLITUDE_mV, nl.Uint16Attr(uint16(0xffff-41)))
+	m = ethtoolTestMessage(nl.ETHTOOL_MSG_CABLE_TEST_TDR_NTF,
+		ethtoolTestHeader(nl.ETHTOOL_A_CABLE_TEST_TDR_NTF_HEADER, 3),
+		nl.NewRtAttr(nl.ETHTOOL_A_CABLE_TEST_TDR_NTF_STATUS, nl.Uint8Attr(nl.ETHTOOL_A_CABLE_TEST_NTF_STATUS_COMPLETED)),
+		nest)
+
+	update, err = parseEthtoolNotification(nl.ETHTOOL_MSG_CABLE_TEST_TDR_NTF, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tdr, ok := update.Value.(*EthtoolCableTestTdrResult)
+	if !ok || update.Index != 3 {
+		t.Fatalf("unexpected cable test TDR update: %+v", update)
+	}
+	mv := int16(1000)
+	expectedTdr := &EthtoolCableTestTdrResult{
+		Status: nl.ETHTOOL_A_CABLE_TEST_NTF_STATUS_COMPLETED,
+		Data: []EthtoolCableTdrData{
+			{Pulse: &mv},
+			{Step: &EthtoolCableTdrStep{First: 100, Last: 200, Step: 100}},
+			{Amplitude: &EthtoolCableTdrAmplitude{Pair: nl.ETHTOOL_A_CABLE_PAIR_A, Mv: -42}},
+		},
+	}
+	if !reflect.DeepEqual(tdr, expectedTdr) {
+		t.Fatalf("expected %+v, got %+v", expectedTdr, tdr)
+	}
+}
+
+// setUpEthtoolNetdevsim creates a netdevsim device in the test namespace and
+// returns its port, as netdevsim implements more ethtool operations than veth.
+func setUpEthtoolNetdevsim(t *testing.T) Link {
+	t.Helper()
+	if _, err := os.Stat("/sys/bus/netdevsim/new_device"); err != nil {
+		t.Skipf("netdevsim not available: %v", err)
+	}
+	if _, err := GenlFamilyGet(nl.ETHTOOL_GENL_NAME); err != nil {
+		t.Skipf("ethtool netlink not supported: %v", err)
+	}
+	id := strconv.Itoa(1000 + rand.Intn(1000))
+	if err := os.WriteFile("/sys/bus/netdevsim/new_device", []byte(id+" 1"), 0200); err != nil {
+		t.Fatalf("can't create netdevsim device %s: %v", id, err)
+	}
+	t.Cleanup(func() {
+		_ = os.WriteFile("/sys/bus/netdevsim/del_device", []byte(id), 0200)
+	})
+	links, err := LinkList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, link := range links {
+		if link.Attrs().Flags&net.FlagLoopback == 0 {
+			return link
+		}
+	}
+	t.Fatalf("port of netdevsim device %s not found", id)
+	return nil
+}
+
+func skipUnlessEthtoolSupported(t *testing.T, err error) {
+	t.Helper()
+	if errors.Is(err, unix.EOPNOTSUPP) {
+		t.Skipf("not supported by the driver: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEthtoolRings(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolNetdevsim(t)
+
+	rings, err := EthtoolGetRings(link)
+	skipUnlessEthtoolSupported(t, err)
+	if rings.Rx == nil || rings.Tx == nil || rings.RxMax < 2 || rings.TxMax < 2 {
+		t.Fatalf("unexpected netdevsim rings: %+v", rings)
+	}
+
+	rx, tx := rings.RxMax/2, rings.TxMax/2
+	if err := EthtoolSetRings(link, EthtoolRings{Rx: &rx, Tx: &tx}); err != nil {
+		t.Fatal(err)
+	}
+	rings, err = EthtoolGetRings(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rings.Rx == nil || *rings.Rx != rx || rings.Tx == nil || *rings.Tx != tx {
+		t.Fatalf("expected %d rx and %d tx descriptors, got %+v", rx, tx, rings)
+	}
+}
+
+func TestEthtoolCoalesce(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolNetdevsim(t)
+
+	_, err := EthtoolGetCoalesce(link)
+	skipUnlessEthtoolSupported(t, err)
+
+	rxUsecs, txFrames, adaptive := uint32(100), uint32(16), true
+	if err := EthtoolSetCoalesce(link, EthtoolCoalesce{
+		RxUsecs:       &rxUsecs,
+		TxMaxFrames:   &txFrames,
+		UseAdaptiveRx: &adaptive,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	coalesce, err := EthtoolGetCoalesce(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if coalesce.RxUsecs == nil || *coalesce.RxUsecs != rxUsecs {
+		t.Fatalf("expected rx-usecs %d, got %+v", rxUsecs, coalesce)
+	}
+	if coalesce.TxMaxFrames == nil || *coalesce.TxMaxFrames != txFrames {
+		t.Fatalf("expected tx-frames %d, got %+v", txFrames, coalesce)
+	}
+	if coalesce.UseAdaptiveRx == nil || !*coalesce.UseAdaptiveRx {
+		t.Fatalf("expected adaptive-rx on, got %+v", coalesce)
+	}
+}
+
+func TestEthtoolPause(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolNetdevsim(t)
+
+	_, err := EthtoolGetPause(link)
+	skipUnlessEthtoolSupported(t, err)
+
+	on, off := true, false
+	if err := EthtoolSetPause(link, EthtoolPause{Autoneg: &off, Rx: &on, Tx: &off}); err != nil {
+		t.Fatal(err)
+	}
+	pause, err := EthtoolGetPause(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pause.Rx == nil || !*pause.Rx || pause.Tx == nil || *pause.Tx {
+		t.Fatalf("expected rx pause on and tx pause off, got %+v", pause)
+	}
+}
+
+func TestEthtoolEee(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolNetdevsim(t)
+
+	eee, err := EthtoolGetEee(link)
+	skipUnlessEthtoolSupported(t, err)
+	if eee.TxLpiEnabled == nil {
+		t.Skipf("tx-lpi not reported: %+v", eee)
+	}
+
+	txLpi := !*eee.TxLpiEnabled
+	if err := EthtoolSetEee(link, EthtoolEee{TxLpiEnabled: &txLpi}); err != nil {
+		t.Fatal(err)
+	}
+	eee, err = EthtoolGetEee(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eee.TxLpiEnabled == nil || *eee.TxLpiEnabled != txLpi {
+		t.Fatalf("expected tx-lpi %v, got %+v", txLpi, eee)
+	}
+}
+
+func TestEthtoolPrivFlags(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	link := setUpEthtoolNetdevsim(t)
+
+	flags, err := EthtoolGetPrivFlags(link)
+	skipUnlessEthtoolSupported(t, err)
+	if len(flags) == 0 {
+		t.Skip("no private flags")
+	}
+
+	var name string
+	for name = range flags {
+		break
+	}
+	value := !flags[name]
+	if err := EthtoolSetPrivFlags(link, map[string]bool{name: value}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := EthtoolGetPrivFlags(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[name] != value {
+		t.Fatalf("expected private flag %q to be %v, got %v", name, value, got)
+	}
+}
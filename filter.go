@@ -316,11 +316,22 @@ const (
 
 type TunnelKeyAction struct {
 	ActionAttrs
-	Action   TunnelKeyAct
-	SrcAddr  net.IP
-	DstAddr  net.IP
-	KeyID    uint32
-	DestPort uint16
+	Action     TunnelKeyAct
+	SrcAddr    net.IP
+	DstAddr    net.IP
+	KeyID      uint32
+	DestPort   uint16
+	ErspanOpts *ErspanOpts
+}
+
+// ErspanOpts are the ERSPAN tunnel metadata options, as set by the
+// tunnel_key action and matched by the flower classifier. Index is used by
+// Version 1, Dir and HwId by Version 2.
+type ErspanOpts struct {
+	Version uint8
+	Index   uint32
+	Dir     uint8
+	HwId    uint8
 }
 
 func (action *TunnelKeyAction) Type() string {
@@ -54,30 +54,35 @@ func (filter *U32) Type() string {
 
 type Flower struct {
 	FilterAttrs
-	ClassId         uint32
-	DestIP          net.IP
-	DestIPMask      net.IPMask
-	SrcIP           net.IP
-	SrcIPMask       net.IPMask
-	EthType         uint16
-	EncDestIP       net.IP
-	EncDestIPMask   net.IPMask
-	EncSrcIP        net.IP
-	EncSrcIPMask    net.IPMask
-	EncDestPort     uint16
-	EncKeyId        uint32
-	SrcMac          net.HardwareAddr
-	DestMac         net.HardwareAddr
-	VlanId          uint16
-	SkipHw          bool
-	SkipSw          bool
-	IPProto         *nl.IPProto
-	DestPort        uint16
-	SrcPort         uint16
-	SrcPortRangeMin uint16
-	SrcPortRangeMax uint16
-	DstPortRangeMin uint16
-	DstPortRangeMax uint16
+	ClassId       uint32
+	DestIP        net.IP
+	DestIPMask    net.IPMask
+	SrcIP         net.IP
+	SrcIPMask     net.IPMask
+	EthType       uint16
+	EncDestIP     net.IP
+	EncDestIPMask net.IPMask
+	EncSrcIP      net.IP
+	EncSrcIPMask  net.IPMask
+	EncDestPort   uint16
+	EncKeyId      uint32
+	// EncErspanOpts matches the ERSPAN options of the tunnel metadata. All
+	// bits of the options set for the Version are matched unless
+	// EncErspanOptsMask is set.
+	EncErspanOpts     *ErspanOpts
+	EncErspanOptsMask *ErspanOpts
+	SrcMac            net.HardwareAddr
+	DestMac           net.HardwareAddr
+	VlanId            uint16
+	SkipHw            bool
+	SkipSw            bool
+	IPProto           *nl.IPProto
+	DestPort          uint16
+	SrcPort           uint16
+	SrcPortRangeMin   uint16
+	SrcPortRangeMax   uint16
+	DstPortRangeMin   uint16
+	DstPortRangeMax   uint16
 
 	Actions []Action
 }
@@ -143,6 +148,18 @@ func (filter *Flower) encode(parent *nl.RtAttr) error {
 	if filter.EncKeyId != 0 {
 		parent.AddRtAttr(nl.TCA_FLOWER_KEY_ENC_KEY_ID, htonl(filter.EncKeyId))
 	}
+	if filter.EncErspanOpts != nil {
+		mask := filter.EncErspanOptsMask
+		if mask == nil {
+			// As iproute2, the version is matched exactly, its value
+			// selecting the other fields to match.
+			mask = &ErspanOpts{Version: filter.EncErspanOpts.Version, Index: 0xffffffff, Dir: 0xff, HwId: 0xff}
+		}
+		opts := parent.AddRtAttr(nl.TCA_FLOWER_KEY_ENC_OPTS|unix.NLA_F_NESTED, nil)
+		encodeErspanOpts(opts, nl.TCA_FLOWER_KEY_ENC_OPTS_ERSPAN, filter.EncErspanOpts, filter.EncErspanOpts.Version)
+		opts = parent.AddRtAttr(nl.TCA_FLOWER_KEY_ENC_OPTS_MASK|unix.NLA_F_NESTED, nil)
+		encodeErspanOpts(opts, nl.TCA_FLOWER_KEY_ENC_OPTS_ERSPAN, mask, filter.EncErspanOpts.Version)
+	}
 	if filter.SrcMac != nil {
 		parent.AddRtAttr(nl.TCA_FLOWER_KEY_ETH_SRC, filter.SrcMac)
 	}
@@ -231,6 +248,10 @@ func (filter *Flower) decode(data []syscall.NetlinkRouteAttr) error {
 			filter.EncDestPort = ntohs(datum.Value)
 		case nl.TCA_FLOWER_KEY_ENC_KEY_ID:
 			filter.EncKeyId = ntohl(datum.Value)
+		case nl.TCA_FLOWER_KEY_ENC_OPTS:
+			filter.EncErspanOpts = decodeErspanOpts(datum.Value, nl.TCA_FLOWER_KEY_ENC_OPTS_ERSPAN)
+		case nl.TCA_FLOWER_KEY_ENC_OPTS_MASK:
+			filter.EncErspanOptsMask = decodeErspanOpts(datum.Value, nl.TCA_FLOWER_KEY_ENC_OPTS_ERSPAN)
 		case nl.TCA_FLOWER_KEY_ETH_SRC:
 			filter.SrcMac = datum.Value
 		case nl.TCA_FLOWER_KEY_ETH_DST:
@@ -715,6 +736,10 @@ func EncodeActions(attr *nl.RtAttr, actions []Action) error {
 				if action.DestPort != 0 {
 					aopts.AddRtAttr(nl.TCA_TUNNEL_KEY_ENC_DST_PORT, htons(action.DestPort))
 				}
+				if action.ErspanOpts != nil {
+					opts := aopts.AddRtAttr(nl.TCA_TUNNEL_KEY_ENC_OPTS|unix.NLA_F_NESTED, nil)
+					encodeErspanOpts(opts, nl.TCA_TUNNEL_KEY_ENC_OPTS_ERSPAN, action.ErspanOpts, action.ErspanOpts.Version)
+				}
 			}
 		case *SkbEditAction:
 			table := attr.AddRtAttr(tabIndex, nil)
@@ -996,6 +1021,8 @@ func parseActions(tables []syscall.NetlinkRouteAttr) ([]Action, error) {
 							action.(*TunnelKeyAction).DstAddr = adatum.Value[:]
 						case nl.TCA_TUNNEL_KEY_ENC_DST_PORT:
 							action.(*TunnelKeyAction).DestPort = ntohs(adatum.Value)
+						case nl.TCA_TUNNEL_KEY_ENC_OPTS:
+							action.(*TunnelKeyAction).ErspanOpts = decodeErspanOpts(adatum.Value, nl.TCA_TUNNEL_KEY_ENC_OPTS_ERSPAN)
 						case nl.TCA_TUNNEL_KEY_TM:
 							tcTs := nl.DeserializeTcf(adatum.Value)
 							actionTimestamp = toTimeStamp(tcTs)
@@ -1315,3 +1342,51 @@ func SerializeRtab(rtab [256]uint32) []byte {
 	_ = binary.Write(&w, native, rtab)
 	return w.Bytes()
 }
+
+// encodeErspanOpts adds the ERSPAN options nested in attrType to parent. The
+// flower and tunnel_key option attributes share the same layout, and only
+// the fields used by version are added.
+func encodeErspanOpts(parent *nl.RtAttr, attrType int, opts *ErspanOpts, version uint8) {
+	erspan := parent.AddRtAttr(attrType|unix.NLA_F_NESTED, nil)
+	erspan.AddRtAttr(nl.TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_VER, nl.Uint8Attr(opts.Version))
+	switch version {
+	case 1:
+		erspan.AddRtAttr(nl.TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_INDEX, htonl(opts.Index))
+	case 2:
+		erspan.AddRtAttr(nl.TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_DIR, nl.Uint8Attr(opts.Dir))
+		erspan.AddRtAttr(nl.TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_HWID, nl.Uint8Attr(opts.HwId))
+	}
+}
+
+// decodeErspanOpts returns the ERSPAN options nested in attrType of the
+// tunnel options in data, or nil if there are none.
+func decodeErspanOpts(data []byte, attrType int) *ErspanOpts {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil
+	}
+	for _, attr := range attrs {
+		if int(attr.Attr.Type&nl.NLA_TYPE_MASK) != attrType {
+			continue
+		}
+		fields, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil
+		}
+		opts := &ErspanOpts{}
+		for _, field := range fields {
+			switch field.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_VER:
+				opts.Version = field.Value[0]
+			case nl.TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_INDEX:
+				opts.Index = ntohl(field.Value[0:4])
+			case nl.TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_DIR:
+				opts.Dir = field.Value[0]
+			case nl.TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_HWID:
+				opts.HwId = field.Value[0]
+			}
+		}
+		return opts
+	}
+	return nil
+}
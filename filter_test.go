@@ -2764,3 +2764,112 @@ func TestFilterSampleAddDel(t *testing.T) {
 		t.Fatal("Failed to remove qdisc")
 	}
 }
+
+func TestFlowerErspanOptsDefaultMask(t *testing.T) {
+	for _, opts := range []ErspanOpts{
+		{Version: 1, Index: 123},
+		{Version: 2, Dir: 1, HwId: 7},
+	} {
+		filter := &Flower{EncErspanOpts: &opts}
+		parent := nl.NewRtAttr(nl.TCA_OPTIONS, nil)
+		if err := filter.encode(parent); err != nil {
+			t.Fatal(err)
+		}
+		attrs, err := nl.ParseRouteAttr(parent.Serialize()[unix.SizeofRtAttr:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range attrs {
+			attrs[i].Attr.Type &= nl.NLA_TYPE_MASK
+		}
+		decoded := &Flower{}
+		if err := decoded.decode(attrs); err != nil {
+			t.Fatal(err)
+		}
+		if decoded.EncErspanOptsMask == nil || decoded.EncErspanOptsMask.Version != opts.Version {
+			t.Fatalf("expected the default mask to match version %d, got %+v", opts.Version, decoded.EncErspanOptsMask)
+		}
+	}
+}
+
+func TestFilterFlowerErspanOptsAddDel(t *testing.T) {
+	minKernelRequired(t, 5, 8)
+	t.Cleanup(setUpNetlinkTest(t))
+	if err := LinkAdd(&Ifb{LinkAttrs{Name: "foo"}}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+
+	qdisc := &Ingress{
+		QdiscAttrs: QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    MakeHandle(0xffff, 0),
+			Parent:    HANDLE_INGRESS,
+		},
+	}
+	if err := QdiscAdd(qdisc); err != nil {
+		t.Fatal(err)
+	}
+
+	tunnelKey := NewTunnelKeyAction()
+	tunnelKey.Action = TCA_TUNNEL_KEY_SET
+	tunnelKey.SrcAddr = net.ParseIP("10.0.0.1")
+	tunnelKey.DstAddr = net.ParseIP("10.0.0.2")
+	tunnelKey.KeyID = 100
+	tunnelKey.ErspanOpts = &ErspanOpts{Version: 2, Dir: 1, HwId: 7}
+
+	filter := &Flower{
+		FilterAttrs: FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    MakeHandle(0xffff, 0),
+			Priority:  1,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		EncDestIP:     net.ParseIP("10.0.0.1"),
+		EncSrcIP:      net.ParseIP("10.0.0.2"),
+		EncKeyId:      100,
+		EncErspanOpts: &ErspanOpts{Version: 1, Index: 123},
+		Actions:       []Action{tunnelKey},
+	}
+	if err := FilterAdd(filter); err != nil {
+		t.Fatal(err)
+	}
+
+	filters, err := FilterList(link, MakeHandle(0xffff, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filters) != 1 {
+		t.Fatal("Failed to add filter")
+	}
+	flower, ok := filters[0].(*Flower)
+	if !ok {
+		t.Fatal("Filter is the wrong type")
+	}
+	if flower.EncErspanOpts == nil || *flower.EncErspanOpts != *filter.EncErspanOpts {
+		t.Fatalf("Flower EncErspanOpts doesn't match: %+v", flower.EncErspanOpts)
+	}
+	if flower.EncErspanOptsMask == nil || *flower.EncErspanOptsMask != (ErspanOpts{Version: 1, Index: 0xffffffff}) {
+		t.Fatalf("Flower EncErspanOptsMask doesn't match: %+v", flower.EncErspanOptsMask)
+	}
+	if len(flower.Actions) != 1 {
+		t.Fatalf("Expected one action, got %d", len(flower.Actions))
+	}
+	action, ok := flower.Actions[0].(*TunnelKeyAction)
+	if !ok {
+		t.Fatal("Action is the wrong type")
+	}
+	if action.ErspanOpts == nil || *action.ErspanOpts != *tunnelKey.ErspanOpts {
+		t.Fatalf("TunnelKeyAction ErspanOpts doesn't match: %+v", action.ErspanOpts)
+	}
+
+	if err := FilterDel(filter); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,11 +1,13 @@
 package netlink
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"syscall"
 
 	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
 	"golang.org/x/sys/unix"
 )
 
@@ -178,3 +180,288 @@ func (h *Handle) GenlFamilyGet(name string) (*GenlFamily, error) {
 func GenlFamilyGet(name string) (*GenlFamily, error) {
 	return pkgHandle.GenlFamilyGet(name)
 }
+
+// GenlMessage is a message of a generic netlink family.
+type GenlMessage struct {
+	Family  uint16
+	Command uint8
+	Version uint8
+	// Header is the family specific header, if the family has one.
+	Header []byte
+	// Attrs holds the serialized attributes of the message.
+	Attrs []byte
+}
+
+// AttributeDecoder returns a decoder of the attributes of m.
+func (m *GenlMessage) AttributeDecoder() *nl.AttributeDecoder {
+	return nl.NewAttributeDecoder(m.Attrs)
+}
+
+func parseGenlMessage(f *GenlFamily, m []byte) (GenlMessage, error) {
+	if len(m) < nl.SizeofGenlmsg {
+		return GenlMessage{}, fmt.Errorf("generic netlink message too short: %d bytes", len(m))
+	}
+	msg := nl.DeserializeGenlmsg(m)
+	hdrLen := nl.SizeofGenlmsg + (int(f.HdrSize)+unix.NLA_ALIGNTO-1)&^(unix.NLA_ALIGNTO-1)
+	if len(m) < hdrLen {
+		return GenlMessage{}, fmt.Errorf("generic netlink message too short for the %s header: %d bytes", f.Name, len(m))
+	}
+	res := GenlMessage{
+		Family:  f.ID,
+		Command: msg.Command,
+		Version: msg.Version,
+		Attrs:   m[hdrLen:],
+	}
+	if f.HdrSize > 0 {
+		res.Header = m[nl.SizeofGenlmsg : nl.SizeofGenlmsg+int(f.HdrSize)]
+	}
+	return res, nil
+}
+
+// GenlExecute sends the command cmd of version version to the generic
+// netlink family named family, with the netlink flags flags, e.g.
+// unix.NLM_F_DUMP, and the serialized attributes attrs, preceded by the
+// family specific header if the family has one. Attributes can be
+// serialized with nl.AttributeEncoder. It returns the messages the family
+// replied with.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func GenlExecute(family string, cmd, version uint8, flags int, attrs []byte) ([]GenlMessage, error) {
+	return pkgHandle.GenlExecute(family, cmd, version, flags, attrs)
+}
+
+// GenlExecute sends the command cmd of version version to the generic
+// netlink family named family, with the netlink flags flags, e.g.
+// unix.NLM_F_DUMP, and the serialized attributes attrs, preceded by the
+// family specific header if the family has one. Attributes can be
+// serialized with nl.AttributeEncoder. It returns the messages the family
+// replied with.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) GenlExecute(family string, cmd, version uint8, flags int, attrs []byte) ([]GenlMessage, error) {
+	f, err := h.GenlFamilyGet(family)
+	if err != nil {
+		return nil, err
+	}
+	req := h.newNetlinkRequest(int(f.ID), flags)
+	req.AddData(&nl.Genlmsg{
+		Command: cmd,
+		Version: version,
+	})
+	if len(attrs) > 0 {
+		req.AddRawData(attrs)
+	}
+	msgs, executeErr := req.Execute(unix.NETLINK_GENERIC, 0)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	res := make([]GenlMessage, 0, len(msgs))
+	for _, m := range msgs {
+		msg, err := parseGenlMessage(f, m)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, msg)
+	}
+	return res, executeErr
+}
+
+// GenlOpPolicy holds the indexes of the attribute policies of the do and
+// dump requests of a command of a generic netlink family. They are nil if
+// the requests are not supported or do not accept attributes.
+type GenlOpPolicy struct {
+	Do   *uint32
+	Dump *uint32
+}
+
+// GenlPolicy describes the attributes accepted by the commands of a generic
+// netlink family.
+type GenlPolicy struct {
+	// Policies maps the index of each attribute policy of the family to
+	// the policies of the attributes it accepts, by attribute type. Nested
+	// attributes refer to the index of their own policy.
+	Policies map[uint32]map[uint16]*nl.PolicyType
+	// Ops maps the commands of the family to their policies. It is only
+	// filled in since Linux 5.10; before that all the commands share the
+	// policy of index 0.
+	Ops map[uint8]GenlOpPolicy
+}
+
+// GenlPolicyGet dumps the attribute policies of the generic netlink family
+// named family, which requires Linux 5.8.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func GenlPolicyGet(family string) (*GenlPolicy, error) {
+	return pkgHandle.GenlPolicyGet(family)
+}
+
+// GenlPolicyGet dumps the attribute policies of the generic netlink family
+// named family, which requires Linux 5.8.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) GenlPolicyGet(family string) (*GenlPolicy, error) {
+	msg := &nl.Genlmsg{
+		Command: nl.GENL_CTRL_CMD_GETPOLICY,
+		Version: nl.GENL_CTRL_VERSION,
+	}
+	req := h.newNetlinkRequest(nl.GENL_ID_CTRL, unix.NLM_F_DUMP)
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(nl.GENL_CTRL_ATTR_FAMILY_NAME, nl.ZeroTerminated(family)))
+	msgs, executeErr := req.Execute(unix.NETLINK_GENERIC, 0)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	policy := &GenlPolicy{
+		Policies: make(map[uint32]map[uint16]*nl.PolicyType),
+		Ops:      make(map[uint8]GenlOpPolicy),
+	}
+	for _, m := range msgs {
+		if len(m) < nl.SizeofGenlmsg {
+			return nil, fmt.Errorf("generic netlink message too short: %d bytes", len(m))
+		}
+		ad := nl.NewAttributeDecoder(m[nl.SizeofGenlmsg:])
+		for ad.Next() {
+			switch ad.Type() {
+			case nl.GENL_CTRL_ATTR_POLICY:
+				ad.Nested(policy.parsePolicies)
+			case nl.GENL_CTRL_ATTR_OP_POLICY:
+				ad.Nested(policy.parseOpPolicies)
+			}
+		}
+		if err := ad.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return policy, executeErr
+}
+
+func (p *GenlPolicy) parsePolicies(ad *nl.AttributeDecoder) error {
+	for ad.Next() {
+		idx := uint32(ad.Type())
+		attrs := p.Policies[idx]
+		if attrs == nil {
+			attrs = make(map[uint16]*nl.PolicyType)
+			p.Policies[idx] = attrs
+		}
+		ad.Nested(func(ad *nl.AttributeDecoder) error {
+			for ad.Next() {
+				pt, err := nl.ParsePolicyType(ad.Bytes())
+				if err != nil {
+					return err
+				}
+				attrs[ad.Type()] = pt
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+func (p *GenlPolicy) parseOpPolicies(ad *nl.AttributeDecoder) error {
+	for ad.Next() {
+		cmd := uint8(ad.Type())
+		var op GenlOpPolicy
+		ad.Nested(func(ad *nl.AttributeDecoder) error {
+			for ad.Next() {
+				switch ad.Type() {
+				case nl.GENL_CTRL_ATTR_POLICY_DO:
+					v := ad.Uint32()
+					op.Do = &v
+				case nl.GENL_CTRL_ATTR_POLICY_DUMP:
+					v := ad.Uint32()
+					op.Dump = &v
+				}
+			}
+			return nil
+		})
+		p.Ops[cmd] = op
+	}
+	return nil
+}
+
+// GenlSubscribe takes a chan down which the messages sent by the generic
+// netlink family named family to its multicast group named group will be
+// sent. Close the 'done' chan to stop subscription.
+func GenlSubscribe(family, group string, ch chan<- GenlMessage, done <-chan struct{}) error {
+	return genlSubscribeAt(netns.None(), netns.None(), family, group, ch, done, nil)
+}
+
+// GenlSubscribeOptions contains a set of options to use with
+// GenlSubscribeWithOptions.
+type GenlSubscribeOptions struct {
+	Namespace     *netns.NsHandle
+	ErrorCallback func(error)
+}
+
+// GenlSubscribeWithOptions work like GenlSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace can be provided as well as an error callback.
+func GenlSubscribeWithOptions(family, group string, ch chan<- GenlMessage, done <-chan struct{}, options GenlSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return genlSubscribeAt(*options.Namespace, netns.None(), family, group, ch, done, options.ErrorCallback)
+}
+
+// GenlSubscribeContext works like GenlSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func GenlSubscribeContext(ctx context.Context, family, group string, ch chan<- GenlMessage, options GenlSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return GenlSubscribeWithOptions(family, group, ch, ctx.Done(), options)
+}
+
+func genlSubscribeAt(newNs, curNs netns.NsHandle, family, group string, ch chan<- GenlMessage, done <-chan struct{}, cberr func(error)) error {
+	return genlSubscribeGroups(newNs, curNs, family, []string{group}, done, cberr,
+		func(f *GenlFamily, m syscall.NetlinkMessage) error {
+			msg, err := parseGenlMessage(f, m.Data)
+			if err != nil {
+				return fmt.Errorf("could not parse %s message: %v", family, err)
+			}
+			ch <- msg
+			return nil
+		},
+		func() { close(ch) })
+}
+
+// genlSubscribeGroups subscribes to the multicast groups named groups of the
+// generic netlink family named family, skipping the ones it does not have,
+// and calls handle with the messages of the family until done is closed.
+// closeCh is called once the subscription stops.
+func genlSubscribeGroups(newNs, curNs netns.NsHandle, family string, groups []string, done <-chan struct{}, cberr func(error), handle func(f *GenlFamily, m syscall.NetlinkMessage) error, closeCh func()) error {
+	f, err := pkgHandle.GenlFamilyGet(family)
+	if err != nil {
+		return err
+	}
+	var ids []uint32
+	for _, g := range f.Groups {
+		for _, name := range groups {
+			if g.Name == name {
+				ids = append(ids, g.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("multicast group %q of generic netlink family %q not found", groups[0], family)
+	}
+	sub := &subscription{
+		protocol: unix.NETLINK_GENERIC,
+		handle: func(m syscall.NetlinkMessage, nsid int) error {
+			if m.Header.Type != f.ID {
+				return nil
+			}
+			return handle(f, m)
+		},
+		close: closeCh,
+	}
+	return sub.start(newNs, curNs, done, subscribeOptions{cberr: cberr}, ids...)
+}
@@ -0,0 +1,140 @@
+package netlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+func TestGenlExecute(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	msgs, err := GenlExecute(nl.GENL_CTRL_NAME, nl.GENL_CTRL_CMD_GETFAMILY, nl.GENL_CTRL_VERSION, unix.NLM_F_DUMP, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, m := range msgs {
+		ad := m.AttributeDecoder()
+		for ad.Next() {
+			if ad.Type() == nl.GENL_CTRL_ATTR_FAMILY_NAME && ad.String() == nl.GENL_CTRL_NAME {
+				found = true
+			}
+		}
+		if err := ad.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !found {
+		t.Fatalf("%s family not found in the dump of %d families", nl.GENL_CTRL_NAME, len(msgs))
+	}
+
+	ae := nl.NewAttributeEncoder()
+	ae.String(nl.GENL_CTRL_ATTR_FAMILY_NAME, nl.GENL_CTRL_NAME)
+	attrs, err := ae.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs, err = GenlExecute(nl.GENL_CTRL_NAME, nl.GENL_CTRL_CMD_GETFAMILY, nl.GENL_CTRL_VERSION, 0, attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(msgs))
+	}
+	var id uint16
+	ad := msgs[0].AttributeDecoder()
+	for ad.Next() {
+		if ad.Type() == nl.GENL_CTRL_ATTR_FAMILY_ID {
+			id = ad.Uint16()
+		}
+	}
+	if err := ad.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if id != nl.GENL_ID_CTRL || msgs[0].Family != nl.GENL_ID_CTRL || msgs[0].Command != nl.GENL_CTRL_CMD_NEWFAMILY {
+		t.Fatalf("unexpected reply %+v with family id %d", msgs[0], id)
+	}
+
+	if _, err := GenlExecute("nonexistent", 1, 1, 0, nil); err == nil {
+		t.Fatal("expected an error for an unknown family")
+	}
+}
+
+func TestGenlPolicyGet(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	minKernelRequired(t, 5, 10)
+
+	policy, err := GenlPolicyGet(nl.GENL_CTRL_NAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op, ok := policy.Ops[nl.GENL_CTRL_CMD_GETFAMILY]
+	if !ok || op.Do == nil {
+		t.Fatalf("expected a policy for the do requests of GETFAMILY, got %+v", policy.Ops)
+	}
+	attrs, ok := policy.Policies[*op.Do]
+	if !ok {
+		t.Fatalf("policy %d not found", *op.Do)
+	}
+	name, ok := attrs[nl.GENL_CTRL_ATTR_FAMILY_NAME]
+	if !ok || name.Type != nl.NL_ATTR_TYPE_NUL_STRING {
+		t.Fatalf("unexpected policy of the family name attribute: %+v", name)
+	}
+	id, ok := attrs[nl.GENL_CTRL_ATTR_FAMILY_ID]
+	if !ok || id.Type != nl.NL_ATTR_TYPE_U16 {
+		t.Fatalf("unexpected policy of the family id attribute: %+v", id)
+	}
+}
+
+func TestGenlSubscribe(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	f, err := GenlFamilyGet(nl.NETDEV_FAMILY_NAME)
+	if err != nil {
+		t.Skipf("netdev genl family not supported: %v", err)
+	}
+
+	if err := GenlSubscribe(nl.NETDEV_FAMILY_NAME, "nonexistent", make(chan GenlMessage), nil); err == nil {
+		t.Fatal("expected an error for an unknown multicast group")
+	}
+
+	ch := make(chan GenlMessage, 16)
+	done := make(chan struct{})
+	if err := GenlSubscribe(nl.NETDEV_FAMILY_NAME, nl.NETDEV_MCGRP_MGMT, ch, done); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "foo"}, PeerName: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for found := false; !found; {
+		select {
+		case m := <-ch:
+			if m.Family != f.ID || m.Command != nl.NETDEV_CMD_DEV_ADD_NTF {
+				continue
+			}
+			ad := m.AttributeDecoder()
+			for ad.Next() {
+				if ad.Type() == nl.NETDEV_A_DEV_IFINDEX && int(ad.Uint32()) == link.Attrs().Index {
+					found = true
+				}
+			}
+			if err := ad.Err(); err != nil {
+				t.Fatal(err)
+			}
+		case <-timeout:
+			t.Fatal("device addition notification not received")
+		}
+	}
+
+	close(done)
+	for range ch {
+	}
+}
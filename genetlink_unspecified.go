@@ -24,3 +24,23 @@ func (h *Handle) GenlFamilyGet(name string) (*GenlFamily, error) {
 func GenlFamilyGet(name string) (*GenlFamily, error) {
 	return nil, ErrNotImplemented
 }
+
+type GenlMessage struct{}
+
+type GenlPolicy struct{}
+
+func (h *Handle) GenlExecute(family string, cmd, version uint8, flags int, attrs []byte) ([]GenlMessage, error) {
+	return nil, ErrNotImplemented
+}
+
+func GenlExecute(family string, cmd, version uint8, flags int, attrs []byte) ([]GenlMessage, error) {
+	return nil, ErrNotImplemented
+}
+
+func (h *Handle) GenlPolicyGet(family string) (*GenlPolicy, error) {
+	return nil, ErrNotImplemented
+}
+
+func GenlPolicyGet(family string) (*GenlPolicy, error) {
+	return nil, ErrNotImplemented
+}
@@ -1,6 +1,7 @@
 package netlink
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -61,6 +62,20 @@ type HandleOptions struct {
 	// NetNS specifies the network namespace to operate on. If not set, the
 	// current network namespace will be used.
 	NetNS *netns.NsHandle
+
+	// Transport, if set, creates the transport the handle exchanges the
+	// messages of each netlink family over, instead of a netlink socket in
+	// NetNS, e.g. to record or replay them in tests with [nl.Recorder] and
+	// [nl.Replayer]. When no families are specified, the handle then also
+	// supports NETLINK_GENERIC. The socket options of the handle do not
+	// apply to transports.
+	Transport func(family int) (nl.Transport, error)
+
+	// WarningCallback, if set, is called with the warnings the kernel
+	// attaches to the acknowledgement of successful requests. This requires
+	// [nl.EnableErrorMessageReporting]. It must not call back into the
+	// netlink API.
+	WarningCallback func(warning *nl.Error)
 }
 
 // Handle is a handle for the netlink requests on a
@@ -70,10 +85,60 @@ type HandleOptions struct {
 type Handle struct {
 	sockets map[int]*nl.SocketHandle
 	options HandleOptions
+	ctx     context.Context
+	batch   *nl.Batch
 
 	lookupByDump atomic.Bool
 }
 
+// WithContext returns a handle which shares the netlink sockets and options
+// of the package handle, and whose requests are bounded by ctx. See
+// [Handle.WithContext].
+func WithContext(ctx context.Context) *Handle {
+	return pkgHandle.WithContext(ctx)
+}
+
+// WithContext returns a shallow copy of h whose requests are bounded by ctx:
+// once ctx is done, any request blocked on the netlink socket is interrupted
+// and returns ctx.Err(). A deadline of ctx takes precedence over a longer
+// socket timeout. The returned handle shares the netlink sockets of h, so it
+// must not be closed separately.
+//
+// For example, to list the links with a per-call deadline:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//	links, err := netlink.WithContext(ctx).LinkList()
+func (h *Handle) WithContext(ctx context.Context) *Handle {
+	if ctx == nil {
+		panic("nil context")
+	}
+	h2 := h.clone()
+	h2.ctx = ctx
+	return h2
+}
+
+// clone returns a shallow copy of h, sharing its netlink sockets.
+func (h *Handle) clone() *Handle {
+	h2 := &Handle{
+		sockets: h.sockets,
+		options: h.options,
+		ctx:     h.ctx,
+		batch:   h.batch,
+	}
+	h2.lookupByDump.Store(h.lookupByDump.Load())
+	return h2
+}
+
+// Context returns the context bounding the requests of h. It is
+// [context.Background] unless h was returned by [Handle.WithContext].
+func (h *Handle) Context() context.Context {
+	if h.ctx != nil {
+		return h.ctx
+	}
+	return context.Background()
+}
+
 // DisableVFInfoCollection configures the handle to skip VF information fetching
 //
 // Deprecated: Use [NewHandleWithOptions] and set
@@ -83,6 +148,16 @@ func (h *Handle) DisableVFInfoCollection() *Handle {
 	return h
 }
 
+// RetryInterrupted configures the handle to retry dump operations that fail
+// with [ErrDumpInterrupted].
+//
+// Deprecated: Use [NewHandleWithOptions] and set
+// [HandleOptions.RetryInterrupted] instead.
+func (h *Handle) RetryInterrupted() *Handle {
+	h.options.RetryInterrupted = true
+	return h
+}
+
 // SetSocketTimeout configures timeout for default netlink sockets
 func SetSocketTimeout(to time.Duration) error {
 	if to < time.Microsecond {
@@ -124,6 +199,9 @@ func (h *Handle) SetSocketTimeout(to time.Duration) error {
 	}
 	tv := unix.NsecToTimeval(to.Nanoseconds())
 	for _, sh := range h.sockets {
+		if sh.Socket == nil {
+			continue
+		}
 		if err := sh.Socket.SetSendTimeout(&tv); err != nil {
 			return err
 		}
@@ -143,6 +221,9 @@ func (h *Handle) SetSocketReceiveBufferSize(size int, force bool) error {
 		opt = unix.SO_RCVBUFFORCE
 	}
 	for _, sh := range h.sockets {
+		if sh.Socket == nil {
+			continue
+		}
 		fd := sh.Socket.GetFd()
 		err := unix.SetsockoptInt(fd, unix.SOL_SOCKET, opt, size)
 		if err != nil {
@@ -156,16 +237,17 @@ func (h *Handle) SetSocketReceiveBufferSize(size int, force bool) error {
 // socket in the netlink handle. The retrieved value should be the
 // double to the one set for SetSocketReceiveBufferSize.
 func (h *Handle) GetSocketReceiveBufferSize() ([]int, error) {
-	results := make([]int, len(h.sockets))
-	i := 0
+	results := make([]int, 0, len(h.sockets))
 	for _, sh := range h.sockets {
+		if sh.Socket == nil {
+			continue
+		}
 		fd := sh.Socket.GetFd()
 		size, err := unix.GetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_RCVBUF)
 		if err != nil {
 			return nil, err
 		}
-		results[i] = size
-		i++
+		results = append(results, size)
 	}
 	return results, nil
 }
@@ -173,6 +255,9 @@ func (h *Handle) GetSocketReceiveBufferSize() ([]int, error) {
 // SetStrictCheck sets the strict check socket option for each socket in the netlink handle. Returns early if any set operation fails
 func (h *Handle) SetStrictCheck(state bool) error {
 	for _, sh := range h.sockets {
+		if sh.Socket == nil {
+			continue
+		}
 		var stateInt int = 0
 		if state {
 			stateInt = 1
@@ -213,6 +298,21 @@ func newHandle(curNs netns.NsHandle, opts HandleOptions, nlFamilies ...int) (*Ha
 		fams = nlFamilies
 	}
 
+	if opts.Transport != nil {
+		if len(nlFamilies) == 0 {
+			fams = append(fams[:len(fams):len(fams)], unix.NETLINK_GENERIC)
+		}
+		for _, f := range fams {
+			t, err := opts.Transport(f)
+			if err != nil {
+				h.Close()
+				return nil, err
+			}
+			h.sockets[f] = &nl.SocketHandle{Transport: t}
+		}
+		return h, nil
+	}
+
 	newNs := netns.None()
 	if opts.NetNS != nil {
 		newNs = *opts.NetNS
@@ -251,7 +351,11 @@ func (h *Handle) Delete() {
 func (h *Handle) newNetlinkRequest(proto, flags int) *nl.NetlinkRequest {
 	// Do this so that package API still use nl package variable nextSeqNr
 	if h.sockets == nil {
-		return nl.NewNetlinkRequest(proto, flags)
+		req := nl.NewNetlinkRequest(proto, flags)
+		req.Context = h.ctx
+		req.Batch = h.batch
+		req.WarningCallback = h.options.WarningCallback
+		return req
 	}
 	return &nl.NetlinkRequest{
 		NlMsghdr: unix.NlMsghdr{
@@ -262,5 +366,8 @@ func (h *Handle) newNetlinkRequest(proto, flags int) *nl.NetlinkRequest {
 		Sockets: h.sockets,
 
 		RetryInterrupted: h.options.RetryInterrupted,
+		Context:          h.ctx,
+		Batch:            h.batch,
+		WarningCallback:  h.options.WarningCallback,
 	}
 }
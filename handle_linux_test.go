@@ -1,6 +1,8 @@
 package netlink
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -34,3 +36,83 @@ func TestConfigureHandle(t *testing.T) {
 	assert.NoError(t, pkgHandle.Close())
 	assert.Error(t, ConfigureHandle(HandleOptions{}))
 }
+
+func TestHandleWithContext(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	h, err := NewHandle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if links, err := h.WithContext(ctx).LinkList(); err != nil || len(links) == 0 {
+		t.Fatalf("LinkList failed: %v %v", links, err)
+	}
+	if _, err := WithContext(ctx).LinkByName("lo"); err != nil {
+		t.Fatal(err)
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := h.WithContext(canceled).LinkList(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if _, err := WithContext(canceled).AddrList(nil, FAMILY_ALL); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	if h.Context() != context.Background() {
+		t.Fatal("expected the original handle to be left unbound")
+	}
+
+	// The sockets shared with the canceled handle remain usable.
+	if _, err := h.LinkList(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLinkSubscribeContext(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan LinkUpdate)
+	errs := make(chan error, 1)
+	if err := LinkSubscribeContext(ctx, ch, LinkSubscribeOptions{
+		ErrorCallback: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "foo"}, PeerName: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	if !expectLinkUpdate(ch, "foo", false) {
+		t.Fatal("Add update not received as expected")
+	}
+
+	cancel()
+	timeout := time.After(5 * time.Second)
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-ch:
+			closed = !ok
+		case <-timeout:
+			t.Fatal("subscription not closed on context cancellation")
+		}
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+
+	if err := LinkSubscribeContext(ctx, ch, LinkSubscribeOptions{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
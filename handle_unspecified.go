@@ -4,6 +4,7 @@
 package netlink
 
 import (
+	"context"
 	"net"
 	"time"
 
@@ -24,6 +25,18 @@ func NewHandleAtFrom(newNs, curNs netns.NsHandle) (*Handle, error) {
 	return nil, ErrNotImplemented
 }
 
+func WithContext(ctx context.Context) *Handle {
+	return &Handle{}
+}
+
+func (h *Handle) WithContext(ctx context.Context) *Handle {
+	return h
+}
+
+func (h *Handle) Context() context.Context {
+	return context.Background()
+}
+
 func (h *Handle) Close() {}
 
 func (h *Handle) Delete() {}
@@ -188,6 +201,26 @@ func (h *Handle) LinkSetIP6AddrGenMode(link Link, mode int) error {
 	return ErrNotImplemented
 }
 
+func (h *Handle) LinkSetIPv6AddrGenMode(link Link, mode int) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetIPv6Token(link Link, token net.IP) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetIPv6StableSecret(link Link, secret net.IP) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) IPv6SetDefaultStableSecret(secret net.IP) error {
+	return ErrNotImplemented
+}
+
+func (h *Handle) LinkSetCanParams(link Link, params CanParams) error {
+	return ErrNotImplemented
+}
+
 func (h *Handle) setProtinfoAttr(link Link, mode bool, attr int) error {
 	return ErrNotImplemented
 }
@@ -61,6 +61,7 @@ type LinkAttrs struct {
 	ParentDev      string
 	ParentDevBus   string
 	Slave          LinkSlave
+	Inet6          *LinkInet6 // Query only
 }
 
 // LinkSlave represents a slave device.
@@ -222,6 +223,29 @@ type LinkStatistics64 struct {
 	TxCompressed      uint64
 }
 
+// LinkInet6 is the IPv6 state of a link, as found in the AF_INET6 section
+// of IFLA_AF_SPEC.
+type LinkInet6 struct {
+	Flags       uint32 // nl.IF_RA_* and related flags
+	AddrGenMode int    // nl.IN6_ADDR_GEN_MODE_*
+	Token       net.IP
+	RaMtu       uint32
+	CacheInfo   *LinkInet6CacheInfo
+
+	stats      []byte
+	icmp6Stats []byte
+}
+
+/*
+Ref: struct ifla_cacheinfo {...}
+*/
+type LinkInet6CacheInfo struct {
+	MaxReasmLen   uint32
+	Tstamp        uint32 // ipv6InterfaceTable updated timestamp, in centiseconds
+	ReachableTime uint32 // in milliseconds
+	RetransTime   uint32 // in milliseconds
+}
+
 type LinkXdp struct {
 	Fd         int
 	Attached   bool
@@ -279,6 +303,7 @@ type Bridge struct {
 	VlanFiltering     *bool
 	VlanDefaultPVID   *uint16
 	GroupFwdMask      *uint16
+	MstEnabled        *bool
 }
 
 func (bridge *Bridge) Attrs() *LinkAttrs {
@@ -1163,6 +1188,45 @@ func (gretap *Gretap) Type() string {
 	return "gretap"
 }
 
+// Erspan devices mirror traffic to a remote collector over GRE, using ERSPAN
+// type II (Version 1) or type III (Version 2) headers. They must specify
+// LocalIP and RemoteIP on create unless FlowBased is set.
+type Erspan struct {
+	LinkAttrs
+	IKey      uint32
+	OKey      uint32
+	Local     net.IP
+	Remote    net.IP
+	PMtuDisc  uint8
+	Ttl       uint8
+	Tos       uint8
+	Link      uint32
+	FlowBased bool
+	Version   uint8
+	Index     uint32 // session index, Version 1 only
+	Dir       ErspanDir
+	HwId      uint16 // hardware ID, Version 2 only
+}
+
+// ErspanDir is the direction of the mirrored traffic, Version 2 only.
+type ErspanDir uint8
+
+const (
+	ERSPAN_DIR_INGRESS ErspanDir = iota
+	ERSPAN_DIR_EGRESS
+)
+
+func (erspan *Erspan) Attrs() *LinkAttrs {
+	return &erspan.LinkAttrs
+}
+
+func (erspan *Erspan) Type() string {
+	if erspan.Local.To4() == nil {
+		return "ip6erspan"
+	}
+	return "erspan"
+}
+
 type Iptun struct {
 	LinkAttrs
 	Ttl        uint8
@@ -1275,6 +1339,7 @@ type Vti struct {
 	Link   uint32
 	Local  net.IP
 	Remote net.IP
+	FwMark uint32
 }
 
 func (vti *Vti) Attrs() *LinkAttrs {
@@ -1432,6 +1497,18 @@ type Can struct {
 	RxError uint16
 
 	RestartMs uint32
+
+	// CAN FD data phase bit timing, if supported by the device.
+	DataBitTiming      *CanBitTiming
+	DataBitTimingConst *CanBitTimingConst
+
+	Termination      uint16   // in Ohm, if supported by the device
+	TerminationConst []uint16 // supported termination values
+	BitRateConst     []uint32 // supported fixed bit rates
+	DataBitRateConst []uint32 // supported fixed data bit rates
+	BitRateMax       uint32
+
+	Stats *CanDeviceStats
 }
 
 func (can *Can) Attrs() *LinkAttrs {
@@ -1442,6 +1519,223 @@ func (can *Can) Type() string {
 	return "can"
 }
 
+// Control mode flags of Can, used in Mask and Flags.
+const (
+	CAN_CTRLMODE_LOOPBACK       = 0x01  // Loopback mode
+	CAN_CTRLMODE_LISTENONLY     = 0x02  // Listen-only mode
+	CAN_CTRLMODE_3_SAMPLES      = 0x04  // Triple sampling mode
+	CAN_CTRLMODE_ONE_SHOT       = 0x08  // One-Shot mode
+	CAN_CTRLMODE_BERR_REPORTING = 0x10  // Bus-error reporting
+	CAN_CTRLMODE_FD             = 0x20  // CAN FD mode
+	CAN_CTRLMODE_PRESUME_ACK    = 0x40  // Ignore missing CAN ACKs
+	CAN_CTRLMODE_FD_NON_ISO     = 0x80  // CAN FD in non-ISO mode
+	CAN_CTRLMODE_CC_LEN8_DLC    = 0x100 // Classic CAN DLC option
+	CAN_CTRLMODE_TDC_AUTO       = 0x200 // CAN transceiver automatically calculates TDCV
+	CAN_CTRLMODE_TDC_MANUAL     = 0x400 // TDCV is manually set up by user
+)
+
+/*
+Ref: struct can_bittiming {...}
+*/
+type CanBitTiming struct {
+	BitRate            uint32
+	SamplePoint        uint32 // in one-tenth of a percent
+	TimeQuanta         uint32 // in nanoseconds
+	PropagationSegment uint32
+	PhaseSegment1      uint32
+	PhaseSegment2      uint32
+	SyncJumpWidth      uint32
+	BitRatePreScaler   uint32
+}
+
+// CanBitTimingConst is the bit timing limits of the CAN controller.
+type CanBitTimingConst struct {
+	Name                string
+	TimeSegment1Min     uint32
+	TimeSegment1Max     uint32
+	TimeSegment2Min     uint32
+	TimeSegment2Max     uint32
+	SyncJumpWidthMax    uint32
+	BitRatePreScalerMin uint32
+	BitRatePreScalerMax uint32
+	BitRatePreScalerInc uint32
+}
+
+/*
+Ref: struct can_device_stats {...}
+*/
+type CanDeviceStats struct {
+	BusError        uint32 // Bus errors
+	ErrorWarning    uint32 // Changes to error warning state
+	ErrorPassive    uint32 // Changes to error passive state
+	BusOff          uint32 // Changes to bus off state
+	ArbitrationLost uint32 // Arbitration lost errors
+	Restarts        uint32 // CAN controller re-starts
+}
+
+// CanParams are the parameters of an existing Can link changed by
+// LinkSetCanParams. Nil and zero values are left unchanged.
+type CanParams struct {
+	BitTiming     *CanBitTiming
+	DataBitTiming *CanBitTiming
+	// CtrlModeMask selects the control mode flags set to CtrlModeFlags.
+	CtrlModeMask  uint32
+	CtrlModeFlags uint32
+	RestartMs     *uint32
+	Termination   *uint16
+}
+
+// Nlmon is a netlink monitor device, used to capture netlink traffic.
+type Nlmon struct {
+	LinkAttrs
+}
+
+func (nlmon *Nlmon) Attrs() *LinkAttrs {
+	return &nlmon.LinkAttrs
+}
+
+func (nlmon *Nlmon) Type() string {
+	return "nlmon"
+}
+
+// AmtMode is the mode of an Amt device.
+type AmtMode uint32
+
+const (
+	AMT_MODE_GATEWAY AmtMode = iota
+	AMT_MODE_RELAY
+)
+
+func (mode AmtMode) String() string {
+	switch mode {
+	case AMT_MODE_GATEWAY:
+		return "gateway"
+	case AMT_MODE_RELAY:
+		return "relay"
+	}
+	return fmt.Sprintf("unknown(%d)", uint32(mode))
+}
+
+// Amt is an Automatic Multicast Tunneling device, see RFC 7450. Amt devices
+// must specify Link and Local on create, and Discovery in gateway mode.
+type Amt struct {
+	LinkAttrs
+	Mode        AmtMode
+	RelayPort   uint16
+	GatewayPort uint16
+	Link        uint32
+	Local       net.IP
+	Remote      net.IP // Query only, the relay found by discovery
+	Discovery   net.IP
+	MaxTunnels  uint32 // relay mode only
+}
+
+func (amt *Amt) Attrs() *LinkAttrs {
+	return &amt.LinkAttrs
+}
+
+func (amt *Amt) Type() string {
+	return "amt"
+}
+
+// BatAdv is a B.A.T.M.A.N. advanced mesh device.
+type BatAdv struct {
+	LinkAttrs
+	RoutingAlgo string // such as "BATMAN_IV" or "BATMAN_V"
+}
+
+func (batadv *BatAdv) Attrs() *LinkAttrs {
+	return &batadv.LinkAttrs
+}
+
+func (batadv *BatAdv) Type() string {
+	return "batadv"
+}
+
+// Flags of an Rmnet device.
+const (
+	RMNET_FLAGS_INGRESS_DEAGGREGATION = 1 << 0
+	RMNET_FLAGS_INGRESS_MAP_COMMANDS  = 1 << 1
+	RMNET_FLAGS_INGRESS_MAP_CKSUMV4   = 1 << 2
+	RMNET_FLAGS_EGRESS_MAP_CKSUMV4    = 1 << 3
+	RMNET_FLAGS_INGRESS_MAP_CKSUMV5   = 1 << 4
+	RMNET_FLAGS_EGRESS_MAP_CKSUMV5    = 1 << 5
+)
+
+// Rmnet is a Qualcomm RmNet multiplexed device. Rmnet devices must specify
+// ParentIndex on create.
+type Rmnet struct {
+	LinkAttrs
+	MuxID uint16
+	Flags uint32 // RMNET_FLAGS_*
+}
+
+func (rmnet *Rmnet) Attrs() *LinkAttrs {
+	return &rmnet.LinkAttrs
+}
+
+func (rmnet *Rmnet) Type() string {
+	return "rmnet"
+}
+
+// VirtWifi is a virtual wireless device on top of an ethernet device.
+// VirtWifi devices must specify ParentIndex on create.
+type VirtWifi struct {
+	LinkAttrs
+}
+
+func (virtwifi *VirtWifi) Attrs() *LinkAttrs {
+	return &virtwifi.LinkAttrs
+}
+
+func (virtwifi *VirtWifi) Type() string {
+	return "virt_wifi"
+}
+
+// Team is a network team device. Its mode and options are configured via
+// the team generic netlink family, see TeamOptionSet.
+type Team struct {
+	LinkAttrs
+}
+
+func (team *Team) Attrs() *LinkAttrs {
+	return &team.LinkAttrs
+}
+
+func (team *Team) Type() string {
+	return "team"
+}
+
+// Vcan is a virtual local CAN interface.
+type Vcan struct {
+	LinkAttrs
+}
+
+func (vcan *Vcan) Attrs() *LinkAttrs {
+	return &vcan.LinkAttrs
+}
+
+func (vcan *Vcan) Type() string {
+	return "vcan"
+}
+
+// Vxcan is a virtual CAN tunnel, a pair of interfaces that forward CAN
+// frames to each other, typically across network namespaces.
+// Vxcan devices must specify PeerName on create.
+type Vxcan struct {
+	LinkAttrs
+	PeerName      string // vxcan on create only
+	PeerNamespace interface{}
+}
+
+func (vxcan *Vxcan) Attrs() *LinkAttrs {
+	return &vxcan.LinkAttrs
+}
+
+func (vxcan *Vxcan) Type() string {
+	return "vxcan"
+}
+
 type IPoIB struct {
 	LinkAttrs
 	Pkey   uint16
@@ -1474,10 +1768,10 @@ func (bareudp *BareUDP) Type() string {
 }
 
 // iproute2 supported devices;
-// vlan | veth | vcan | dummy | ifb | macvlan | macvtap |
+// vlan | veth | vcan | vxcan | dummy | ifb | macvlan | macvtap |
 // bridge | bond | ipoib | ip6tnl | ipip | sit | vxlan |
-// gre | gretap | ip6gre | ip6gretap | vti | vti6 | nlmon |
-// bond_slave | ipvlan | xfrm | bareudp
+// gre | gretap | ip6gre | ip6gretap | erspan | ip6erspan | vti | vti6 | nlmon |
+// bond_slave | ipvlan | xfrm | bareudp | amt | batadv | rmnet | virt_wifi | team
 
 // LinkNotFoundError wraps the various not found errors when
 // getting/reading links. This is intended for better error
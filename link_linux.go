@@ -2,12 +2,14 @@ package netlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"syscall"
@@ -90,9 +92,22 @@ func (h *Handle) ensureIndex(link *LinkAttrs) {
 	}
 }
 
+// ensureLinkIndex works like ensureIndex for the modifications which can be
+// queued into a batch. It returns ErrBatchedLinkIndex if the index is still
+// unknown, as the link is then added by the batch.
+func (h *Handle) ensureLinkIndex(link *LinkAttrs) error {
+	h.ensureIndex(link)
+	if h.batch != nil && link != nil && link.Index == 0 {
+		return ErrBatchedLinkIndex
+	}
+	return nil
+}
+
 func (h *Handle) LinkSetARPOff(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -111,7 +126,9 @@ func LinkSetARPOff(link Link) error {
 
 func (h *Handle) LinkSetARPOn(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -130,7 +147,9 @@ func LinkSetARPOn(link Link) error {
 
 func (h *Handle) SetPromiscOn(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -153,7 +172,9 @@ func LinkSetAllmulticastOn(link Link) error {
 // Equivalent to: `ip link set $link allmulticast on`
 func (h *Handle) LinkSetAllmulticastOn(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -176,7 +197,9 @@ func LinkSetAllmulticastOff(link Link) error {
 // Equivalent to: `ip link set $link allmulticast off`
 func (h *Handle) LinkSetAllmulticastOff(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -198,7 +221,9 @@ func LinkSetMulticastOn(link Link) error {
 // Equivalent to: `ip link set $link multicast on`
 func (h *Handle) LinkSetMulticastOn(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -221,7 +246,9 @@ func LinkSetMulticastOff(link Link) error {
 // Equivalent to: `ip link set $link multicast off`
 func (h *Handle) LinkSetMulticastOff(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -267,7 +294,9 @@ func (h *Handle) MacvlanMACAddrSet(link Link, addrs []net.HardwareAddr) error {
 
 func (h *Handle) macvlanMACAddrChange(link Link, addrs []net.HardwareAddr, mode uint32) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -314,7 +343,9 @@ func LinkSetMacvlanMode(link Link, mode MacvlanMode) error {
 // Equivalent to: `ip link set $link type (macvlan|macvtap) mode $mode
 func (h *Handle) LinkSetMacvlanMode(link Link, mode MacvlanMode) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -363,13 +394,31 @@ func (h *Handle) BridgeSetVlanDefaultPVID(link Link, pvid uint16) error {
 	return h.linkModify(bridge, unix.NLM_F_ACK)
 }
 
+// BridgeSetMstEnabled enables or disables multiple spanning tree mode on the
+// bridge. The kernel refuses to change it while any port has VLANs.
+// Equivalent to: `ip link set $link type bridge mst_enabled $on`
+func BridgeSetMstEnabled(link Link, on bool) error {
+	return pkgHandle.BridgeSetMstEnabled(link, on)
+}
+
+// BridgeSetMstEnabled enables or disables multiple spanning tree mode on the
+// bridge. The kernel refuses to change it while any port has VLANs.
+// Equivalent to: `ip link set $link type bridge mst_enabled $on`
+func (h *Handle) BridgeSetMstEnabled(link Link, on bool) error {
+	bridge := link.(*Bridge)
+	bridge.MstEnabled = &on
+	return h.linkModify(bridge, unix.NLM_F_ACK)
+}
+
 func SetPromiscOn(link Link) error {
 	return pkgHandle.SetPromiscOn(link)
 }
 
 func (h *Handle) SetPromiscOff(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -395,7 +444,9 @@ func LinkSetUp(link Link) error {
 // Equivalent to: `ip link set $link up`
 func (h *Handle) LinkSetUp(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -418,7 +469,9 @@ func LinkSetDown(link Link) error {
 // Equivalent to: `ip link set $link down`
 func (h *Handle) LinkSetDown(link Link) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -440,7 +493,9 @@ func LinkSetMTU(link Link, mtu int) error {
 // Equivalent to: `ip link set $link mtu $mtu`
 func (h *Handle) LinkSetMTU(link Link, mtu int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -467,7 +522,9 @@ func LinkSetName(link Link, name string) error {
 // Equivalent to: `ip link set $link name $name`
 func (h *Handle) LinkSetName(link Link, name string) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -491,7 +548,9 @@ func LinkSetAlias(link Link, name string) error {
 // Equivalent to: `ip link set dev $link alias $name`
 func (h *Handle) LinkSetAlias(link Link, name string) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -515,7 +574,9 @@ func LinkAddAltName(link Link, name string) error {
 // Equivalent to: `ip link property add $link altname $name`
 func (h *Handle) LinkAddAltName(link Link, name string) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_NEWLINKPROP, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -541,7 +602,9 @@ func LinkDelAltName(link Link, name string) error {
 // Equivalent to: `ip link property del $link altname $name`
 func (h *Handle) LinkDelAltName(link Link, name string) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_DELLINKPROP, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -567,7 +630,9 @@ func LinkSetHardwareAddr(link Link, hwaddr net.HardwareAddr) error {
 // Equivalent to: `ip link set $link address $hwaddr`
 func (h *Handle) LinkSetHardwareAddr(link Link, hwaddr net.HardwareAddr) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -591,7 +656,9 @@ func LinkSetVfHardwareAddr(link Link, vf int, hwaddr net.HardwareAddr) error {
 // Equivalent to: `ip link set $link vf $vf mac $hwaddr`
 func (h *Handle) LinkSetVfHardwareAddr(link Link, vf int, hwaddr net.HardwareAddr) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -621,7 +688,9 @@ func LinkSetVfVlan(link Link, vf, vlan int) error {
 // Equivalent to: `ip link set $link vf $vf vlan $vlan`
 func (h *Handle) LinkSetVfVlan(link Link, vf, vlan int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -651,7 +720,9 @@ func LinkSetVfVlanQos(link Link, vf, vlan, qos int) error {
 // Equivalent to: `ip link set $link vf $vf vlan $vlan qos $qos`
 func (h *Handle) LinkSetVfVlanQos(link Link, vf, vlan, qos int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -682,7 +753,9 @@ func LinkSetVfVlanQosProto(link Link, vf, vlan, qos, proto int) error {
 // Equivalent to: `ip link set $link vf $vf vlan $vlan qos $qos proto $proto`
 func (h *Handle) LinkSetVfVlanQosProto(link Link, vf, vlan, qos, proto int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -719,7 +792,9 @@ func LinkSetVfTxRate(link Link, vf, rate int) error {
 // Equivalent to: `ip link set $link vf $vf rate $rate`
 func (h *Handle) LinkSetVfTxRate(link Link, vf, rate int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -749,7 +824,9 @@ func LinkSetVfRate(link Link, vf, minRate, maxRate int) error {
 // Equivalent to: `ip link set $link vf $vf min_tx_rate $min_rate max_tx_rate $max_rate`
 func (h *Handle) LinkSetVfRate(link Link, vf, minRate, maxRate int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -780,7 +857,9 @@ func LinkSetVfState(link Link, vf int, state uint32) error {
 // Equivalent to: `ip link set $link vf $vf state $state`
 func (h *Handle) LinkSetVfState(link Link, vf int, state uint32) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -811,7 +890,9 @@ func LinkSetVfSpoofchk(link Link, vf int, check bool) error {
 func (h *Handle) LinkSetVfSpoofchk(link Link, vf int, check bool) error {
 	var setting uint32
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -845,7 +926,9 @@ func LinkSetVfTrust(link Link, vf int, state bool) error {
 func (h *Handle) LinkSetVfTrust(link Link, vf int, state bool) error {
 	var setting uint32
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -892,7 +975,9 @@ func (h *Handle) LinkSetVfGUID(link Link, vf int, vfGuid net.HardwareAddr, guidT
 	}
 
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -924,7 +1009,9 @@ func (h *Handle) LinkSetMaster(link Link, master Link) error {
 	index := 0
 	if master != nil {
 		masterBase := master.Attrs()
-		h.ensureIndex(masterBase)
+		if err := h.ensureLinkIndex(masterBase); err != nil {
+			return err
+		}
 		index = masterBase.Index
 	}
 	if index <= 0 {
@@ -955,7 +1042,9 @@ func LinkSetMasterByIndex(link Link, masterIndex int) error {
 // Equivalent to: `ip link set $link master $master`
 func (h *Handle) LinkSetMasterByIndex(link Link, masterIndex int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -984,7 +1073,9 @@ func LinkSetNsPid(link Link, nspid int) error {
 // Equivalent to: `ip link set $link netns $pid`
 func (h *Handle) LinkSetNsPid(link Link, nspid int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -1013,7 +1104,9 @@ func LinkSetNsFd(link Link, fd int) error {
 // Similar to: `ip link set $link netns $ns`
 func (h *Handle) LinkSetNsFd(link Link, fd int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -1063,7 +1156,9 @@ func LinkSetGSOMaxSegs(link Link, maxSegs int) error {
 // Equivalent to: `ip link set $link gso_max_segs $maxSegs`
 func (h *Handle) LinkSetGSOMaxSegs(link Link, maxSize int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -1090,7 +1185,9 @@ func LinkSetGSOMaxSize(link Link, maxSize int) error {
 // Equivalent to: `ip link set $link gso_max_size $maxSize`
 func (h *Handle) LinkSetGSOMaxSize(link Link, maxSize int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -1117,7 +1214,9 @@ func LinkSetGROMaxSize(link Link, maxSize int) error {
 // Equivalent to: `ip link set $link gro_max_size $maxSize`
 func (h *Handle) LinkSetGROMaxSize(link Link, maxSize int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -1144,7 +1243,9 @@ func LinkSetGSOIPv4MaxSize(link Link, maxSize int) error {
 // Equivalent to: `ip link set $link gso_ipv4_max_size $maxSize`
 func (h *Handle) LinkSetGSOIPv4MaxSize(link Link, maxSize int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -1171,7 +1272,9 @@ func LinkSetGROIPv4MaxSize(link Link, maxSize int) error {
 // Equivalent to: `ip link set $link gro_ipv4_max_size $maxSize`
 func (h *Handle) LinkSetGROIPv4MaxSize(link Link, maxSize int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -1204,7 +1307,9 @@ func (h *Handle) LinkSetVXLANGroup(link *Vxlan, group net.IP) error {
 	}
 
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	// RTM_SETLINK does not currently support changing the group address
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
@@ -1245,7 +1350,9 @@ func (h *Handle) LinkSetVXLANVtepDevIndex(link *Vxlan, ifIndex int) error {
 	}
 
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	// RTM_SETLINK does not currently support changing the the VTEP device index
 	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
 
@@ -1486,6 +1593,10 @@ func (h *Handle) linkModify(link Link, flags int) error {
 	if base.Name == "" && !isTuntap {
 		return fmt.Errorf("LinkAttrs.Name cannot be empty")
 	}
+	if h.batch != nil && !isTuntap && base.MasterIndex != 0 {
+		// The master is set once the link is added, by its index.
+		return ErrBatchedLinkIndex
+	}
 
 	if isTuntap {
 		if tuntap.Mode < unix.IFF_TUN || tuntap.Mode > unix.IFF_TAP {
@@ -1676,7 +1787,8 @@ func (h *Handle) linkModify(link Link, flags int) error {
 		native.PutUint32(b, uint32(base.ParentIndex))
 		data := nl.NewRtAttr(unix.IFLA_LINK, b)
 		req.AddData(data)
-	} else if link.Type() == "ipvlan" || link.Type() == "ipoib" {
+	} else if link.Type() == "ipvlan" || link.Type() == "ipvtap" || link.Type() == "ipoib" ||
+		link.Type() == "rmnet" || link.Type() == "virt_wifi" {
 		return fmt.Errorf("Can't create %s link without ParentIndex", link.Type())
 	}
 
@@ -1884,6 +1996,35 @@ func (h *Handle) linkModify(link Link, flags int) error {
 				peer.AddRtAttr(unix.IFLA_NET_NS_FD, val)
 			}
 		}
+	case *Amt:
+		addAmtAttrs(link, linkInfo)
+	case *BatAdv:
+		if link.RoutingAlgo != "" {
+			data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+			data.AddRtAttr(nl.IFLA_BATADV_ALGO_NAME, nl.ZeroTerminated(link.RoutingAlgo))
+		}
+	case *Rmnet:
+		data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+		data.AddRtAttr(nl.IFLA_RMNET_MUX_ID, nl.Uint16Attr(link.MuxID))
+		b := make([]byte, nl.SizeofIflaRmnetFlags)
+		native.PutUint32(b, link.Flags)
+		native.PutUint32(b[4:], ^uint32(0))
+		data.AddRtAttr(nl.IFLA_RMNET_FLAGS, b)
+	case *Vxcan:
+		data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+		peer := data.AddRtAttr(nl.VXCAN_INFO_PEER, nil)
+		nl.NewIfInfomsgChild(peer, unix.AF_UNSPEC)
+		peer.AddRtAttr(unix.IFLA_IFNAME, nl.ZeroTerminated(link.PeerName))
+		if link.PeerNamespace != nil {
+			switch ns := link.PeerNamespace.(type) {
+			case NsPid:
+				val := nl.Uint32Attr(uint32(ns))
+				peer.AddRtAttr(unix.IFLA_NET_NS_PID, val)
+			case NsFd:
+				val := nl.Uint32Attr(uint32(ns))
+				peer.AddRtAttr(unix.IFLA_NET_NS_FD, val)
+			}
+		}
 	case *Vxlan:
 		addVxlanAttrs(link, linkInfo)
 	case *Bond:
@@ -1904,6 +2045,8 @@ func (h *Handle) linkModify(link Link, flags int) error {
 		addGeneveAttrs(link, linkInfo)
 	case *Gretap:
 		addGretapAttrs(link, linkInfo)
+	case *Erspan:
+		addErspanAttrs(link, linkInfo)
 	case *Iptun:
 		addIptunAttrs(link, linkInfo)
 	case *Ip6tnl:
@@ -1934,6 +2077,10 @@ func (h *Handle) linkModify(link Link, flags int) error {
 	if err != nil {
 		return err
 	}
+	if h.batch != nil {
+		// The link is only added once the batch is flushed.
+		return nil
+	}
 
 	h.ensureIndex(base)
 
@@ -1958,7 +2105,9 @@ func LinkDel(link Link) error {
 func (h *Handle) LinkDel(link Link) error {
 	base := link.Attrs()
 
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 
 	req := h.newNetlinkRequest(unix.RTM_DELLINK, unix.NLM_F_ACK)
 
@@ -2040,7 +2189,7 @@ func (h *Handle) LinkByName(name string) (Link, error) {
 	req.AddData(nameData)
 
 	link, err := execGetLink(req)
-	if err == unix.EINVAL {
+	if errors.Is(err, unix.EINVAL) {
 		// older kernels don't support looking up via IFLA_IFNAME
 		// so fall back to dumping all links
 		h.lookupByDump.Store(true)
@@ -2084,7 +2233,7 @@ func (h *Handle) LinkByAlias(alias string) (Link, error) {
 	req.AddData(nameData)
 
 	link, err := execGetLink(req)
-	if err == unix.EINVAL {
+	if errors.Is(err, unix.EINVAL) {
 		// older kernels don't support looking up via IFLA_IFALIAS
 		// so fall back to dumping all links
 		h.lookupByDump.Store(true)
@@ -2117,10 +2266,8 @@ func (h *Handle) LinkByIndex(index int) (Link, error) {
 func execGetLink(req *nl.NetlinkRequest) (Link, error) {
 	msgs, err := req.Execute(unix.NETLINK_ROUTE, 0)
 	if err != nil {
-		if errno, ok := err.(syscall.Errno); ok {
-			if errno == unix.ENODEV {
-				return nil, LinkNotFoundError{fmt.Errorf("Link not found")}
-			}
+		if errors.Is(err, unix.ENODEV) {
+			return nil, LinkNotFoundError{fmt.Errorf("Link not found")}
 		}
 		return nil, err
 	}
@@ -2214,6 +2361,8 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 						link = &Gretap{}
 					case "ip6gretap":
 						link = &Gretap{}
+					case "erspan", "ip6erspan":
+						link = &Erspan{}
 					case "ipip":
 						link = &Iptun{}
 					case "ip6tnl":
@@ -2238,6 +2387,22 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 						link = &IPoIB{}
 					case "can":
 						link = &Can{}
+					case "team":
+						link = &Team{}
+					case "nlmon":
+						link = &Nlmon{}
+					case "amt":
+						link = &Amt{}
+					case "batadv":
+						link = &BatAdv{}
+					case "rmnet":
+						link = &Rmnet{}
+					case "virt_wifi":
+						link = &VirtWifi{}
+					case "vcan":
+						link = &Vcan{}
+					case "vxcan":
+						link = &Vxcan{}
 					case "bareudp":
 						link = &BareUDP{}
 					default:
@@ -2271,6 +2436,8 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 						parseGretapData(link, data)
 					case "ip6gretap":
 						parseGretapData(link, data)
+					case "erspan", "ip6erspan":
+						parseErspanData(link, data)
 					case "ipip":
 						parseIptunData(link, data)
 					case "ip6tnl":
@@ -2297,10 +2464,25 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 						parseIPoIBData(link, data)
 					case "can":
 						parseCanData(link, data)
+					case "amt":
+						parseAmtData(link, data)
+					case "batadv":
+						parseBatAdvData(link, data)
+					case "rmnet":
+						parseRmnetData(link, data)
 					case "bareudp":
 						parseBareUDPData(link, data)
 					}
 
+				case nl.IFLA_INFO_XSTATS:
+					switch linkType {
+					case "can":
+						stats := new(CanDeviceStats)
+						if err := binary.Read(bytes.NewBuffer(info.Value), nl.NativeEndian(), stats); err != nil {
+							return nil, err
+						}
+						link.(*Can).Stats = stats
+					}
 				case nl.IFLA_INFO_SLAVE_KIND:
 					slaveType = string(info.Value[:len(info.Value)-1])
 					switch slaveType {
@@ -2438,6 +2620,24 @@ func LinkDeserialize(hdr *unix.NlMsghdr, m []byte) (Link, error) {
 			base.ParentDev = string(attr.Value[:len(attr.Value)-1])
 		case unix.IFLA_PARENT_DEV_BUS_NAME:
 			base.ParentDevBus = string(attr.Value[:len(attr.Value)-1])
+		case unix.IFLA_AF_SPEC:
+			if msg.Family == unix.AF_BRIDGE {
+				break
+			}
+			afs, err := nl.ParseRouteAttr(attr.Value[:])
+			if err != nil {
+				return nil, err
+			}
+			for _, af := range afs {
+				if af.Attr.Type&nl.NLA_TYPE_MASK != unix.AF_INET6 {
+					continue
+				}
+				// A malformed inet6 section leaves Inet6 unset rather than
+				// failing the whole link.
+				if inet6, err := parseLinkInet6(af.Value); err == nil {
+					base.Inet6 = inet6
+				}
+			}
 		case IFLA_HEADROOM:
 			base.Headroom = native.Uint16(attr.Value[0:2])
 		case IFLA_TAILROOM:
@@ -2554,18 +2754,24 @@ type LinkUpdate struct {
 	nl.IfInfomsg
 	Header unix.NlMsghdr
 	Link
+	// NetnsID is the id of the network namespace the update originates
+	// from, see LinkSubscribeOptions.ListenAllNsid, or -1.
+	NetnsID int
+	// Resync marks the updates delimiting a resync, which carry nothing
+	// else.
+	Resync SubscriptionResync
 }
 
 // LinkSubscribe takes a chan down which notifications will be sent
 // when links change.  Close the 'done' chan to stop subscription.
 func LinkSubscribe(ch chan<- LinkUpdate, done <-chan struct{}) error {
-	return linkSubscribeAt(netns.None(), netns.None(), ch, done, nil, false, 0, nil, false)
+	return linkSubscribeAt(netns.None(), netns.None(), ch, done, LinkSubscribeOptions{})
 }
 
 // LinkSubscribeAt works like LinkSubscribe plus it allows the caller
 // to choose the network namespace in which to subscribe (ns).
 func LinkSubscribeAt(ns netns.NsHandle, ch chan<- LinkUpdate, done <-chan struct{}) error {
-	return linkSubscribeAt(ns, netns.None(), ch, done, nil, false, 0, nil, false)
+	return linkSubscribeAt(ns, netns.None(), ch, done, LinkSubscribeOptions{})
 }
 
 // LinkSubscribeOptions contains a set of options to use with
@@ -2577,6 +2783,19 @@ type LinkSubscribeOptions struct {
 	ReceiveBufferSize      int
 	ReceiveBufferForceSize bool
 	ReceiveTimeout         *unix.Timeval
+	// Resync keeps the subscription going when the kernel drops
+	// notifications because the receive buffer overran: the updates marked
+	// ResyncStart and ResyncDone then delimit a new dump of the existing
+	// links. Otherwise the subscription stops, and ErrorCallback is called
+	// with an error wrapping ENOBUFS.
+	Resync bool
+	// NoENOBUFS sets NETLINK_NO_ENOBUFS on the socket, so that overruns are
+	// ignored.
+	NoENOBUFS bool
+	// ListenAllNsid sets NETLINK_LISTEN_ALL_NSID on the socket, to also
+	// receive the notifications of the network namespaces which have an id
+	// in Namespace. Resyncs only dump the links of Namespace.
+	ListenAllNsid bool
 }
 
 // LinkSubscribeWithOptions work like LinkSubscribe but enable to
@@ -2591,92 +2810,53 @@ func LinkSubscribeWithOptions(ch chan<- LinkUpdate, done <-chan struct{}, option
 		none := netns.None()
 		options.Namespace = &none
 	}
-	return linkSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback, options.ListExisting,
-		options.ReceiveBufferSize, options.ReceiveTimeout, options.ReceiveBufferForceSize)
+	return linkSubscribeAt(*options.Namespace, netns.None(), ch, done, options)
 }
 
-func linkSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- LinkUpdate, done <-chan struct{}, cberr func(error), listExisting bool,
-	rcvbuf int, rcvTimeout *unix.Timeval, rcvbufForce bool) error {
-	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_ROUTE, unix.RTNLGRP_LINK)
-	if err != nil {
+// LinkSubscribeContext works like LinkSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func LinkSubscribeContext(ctx context.Context, ch chan<- LinkUpdate, options LinkSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
-	if rcvTimeout != nil {
-		if err := s.SetReceiveTimeout(rcvTimeout); err != nil {
-			return err
-		}
-	}
-	if rcvbuf != 0 {
-		err = s.SetReceiveBufferSize(rcvbuf, rcvbufForce)
-		if err != nil {
-			return err
-		}
-	}
-	if done != nil {
-		go func() {
-			<-done
-			s.Close()
-		}()
-	}
-	if listExisting {
-		req := pkgHandle.newNetlinkRequest(unix.RTM_GETLINK,
-			unix.NLM_F_DUMP)
-		msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
-		req.AddData(msg)
-		if err := s.Send(req); err != nil {
-			return err
-		}
-	}
-	go func() {
-		defer close(ch)
-		for {
-			msgs, from, err := s.Receive()
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return LinkSubscribeWithOptions(ch, ctx.Done(), options)
+}
+
+func linkSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- LinkUpdate, done <-chan struct{}, options LinkSubscribeOptions) error {
+	sub := &subscription{
+		dumps: []func() *nl.NetlinkRequest{func() *nl.NetlinkRequest {
+			req := pkgHandle.newNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP)
+			req.AddData(nl.NewIfInfomsg(unix.AF_UNSPEC))
+			return req
+		}},
+		handle: func(m syscall.NetlinkMessage, nsid int) error {
+			ifmsg := nl.DeserializeIfInfomsg(m.Data)
+			header := unix.NlMsghdr(m.Header)
+			link, err := LinkDeserialize(&header, m.Data)
 			if err != nil {
-				if cberr != nil {
-					cberr(fmt.Errorf("Receive failed: %v",
-						err))
-				}
-				return
-			}
-			if from.Pid != nl.PidKernel {
-				if cberr != nil {
-					cberr(fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, nl.PidKernel))
-				}
-				continue
-			}
-			for _, m := range msgs {
-				if m.Header.Flags&unix.NLM_F_DUMP_INTR != 0 && cberr != nil {
-					cberr(ErrDumpInterrupted)
-				}
-				if m.Header.Type == unix.NLMSG_DONE {
-					continue
-				}
-				if m.Header.Type == unix.NLMSG_ERROR {
-					error := int32(native.Uint32(m.Data[0:4]))
-					if error == 0 {
-						continue
-					}
-					if cberr != nil {
-						cberr(fmt.Errorf("error message: %v",
-							syscall.Errno(-error)))
-					}
-					continue
-				}
-				ifmsg := nl.DeserializeIfInfomsg(m.Data)
-				header := unix.NlMsghdr(m.Header)
-				link, err := LinkDeserialize(&header, m.Data)
-				if err != nil {
-					if cberr != nil {
-						cberr(err)
-					}
-					continue
-				}
-				ch <- LinkUpdate{IfInfomsg: *ifmsg, Header: header, Link: link}
+				return err
 			}
-		}
-	}()
-
-	return nil
+			ch <- LinkUpdate{IfInfomsg: *ifmsg, Header: header, Link: link, NetnsID: nsid}
+			return nil
+		},
+		mark: func(r SubscriptionResync) {
+			ch <- LinkUpdate{NetnsID: nl.NETNSA_NSID_NOT_ASSIGNED, Resync: r}
+		},
+		close: func() { close(ch) },
+	}
+	return sub.start(newNs, curNs, done, subscribeOptions{
+		cberr:         options.ErrorCallback,
+		listExisting:  options.ListExisting,
+		rcvbuf:        options.ReceiveBufferSize,
+		rcvbufForce:   options.ReceiveBufferForceSize,
+		rcvTimeout:    options.ReceiveTimeout,
+		resync:        options.Resync,
+		noENOBUFS:     options.NoENOBUFS,
+		listenAllNsid: options.ListenAllNsid,
+	}, unix.RTNLGRP_LINK)
 }
 
 func LinkSetHairpin(link Link, mode bool) error {
@@ -2779,7 +2959,9 @@ func (h *Handle) LinkSetBrNeighSuppress(link Link, mode bool) error {
 
 func (h *Handle) setProtinfoAttrRawVal(link Link, val []byte, attr int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_BRIDGE)
@@ -2809,7 +2991,9 @@ func LinkSetTxQLen(link Link, qlen int) error {
 // Equivalent to: `ip link set $link txqlen $qlen`
 func (h *Handle) LinkSetTxQLen(link Link, qlen int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -2838,7 +3022,9 @@ func LinkSetGroup(link Link, group int) error {
 // Equivalent to: `ip link set $link group $id`
 func (h *Handle) LinkSetGroup(link Link, group int) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -2857,33 +3043,220 @@ func (h *Handle) LinkSetGroup(link Link, group int) error {
 
 // LinkSetIP6AddrGenMode sets the IPv6 address generation mode of the link device.
 // Equivalent to: `ip link set $link addrgenmode $mode`
+//
+// Deprecated: Use [LinkSetIPv6AddrGenMode] instead.
 func LinkSetIP6AddrGenMode(link Link, mode int) error {
-	return pkgHandle.LinkSetIP6AddrGenMode(link, mode)
+	return pkgHandle.LinkSetIPv6AddrGenMode(link, mode)
 }
 
 // LinkSetIP6AddrGenMode sets the IPv6 address generation mode of the link device.
 // Equivalent to: `ip link set $link addrgenmode $mode`
+//
+// Deprecated: Use [Handle.LinkSetIPv6AddrGenMode] instead.
 func (h *Handle) LinkSetIP6AddrGenMode(link Link, mode int) error {
+	return h.LinkSetIPv6AddrGenMode(link, mode)
+}
+
+// LinkSetIPv6AddrGenMode sets the IPv6 address generation mode of the link
+// device to one of nl.IN6_ADDR_GEN_MODE_*. IN6_ADDR_GEN_MODE_NONE disables
+// the generation of link local and SLAAC addresses, and
+// IN6_ADDR_GEN_MODE_STABLE_PRIVACY requires a stable secret to be set first.
+// Equivalent to: `ip link set $link addrgenmode $mode`
+func LinkSetIPv6AddrGenMode(link Link, mode int) error {
+	return pkgHandle.LinkSetIPv6AddrGenMode(link, mode)
+}
+
+// LinkSetIPv6AddrGenMode sets the IPv6 address generation mode of the link
+// device to one of nl.IN6_ADDR_GEN_MODE_*. IN6_ADDR_GEN_MODE_NONE disables
+// the generation of link local and SLAAC addresses, and
+// IN6_ADDR_GEN_MODE_STABLE_PRIVACY requires a stable secret to be set first.
+// Equivalent to: `ip link set $link addrgenmode $mode`
+func (h *Handle) LinkSetIPv6AddrGenMode(link Link, mode int) error {
+	return h.linkSetInet6Attr(link, unix.IFLA_INET6_ADDR_GEN_MODE, []byte{uint8(mode)})
+}
+
+// LinkSetIPv6Token sets the IPv6 interface identifier used to form SLAAC
+// addresses on the link device. Only the lower 64 bits of token are used.
+// The kernel only accepts a token while router advertisements are accepted
+// on the link.
+// Equivalent to: `ip token set $token dev $link`
+func LinkSetIPv6Token(link Link, token net.IP) error {
+	return pkgHandle.LinkSetIPv6Token(link, token)
+}
+
+// LinkSetIPv6Token sets the IPv6 interface identifier used to form SLAAC
+// addresses on the link device. Only the lower 64 bits of token are used.
+// The kernel only accepts a token while router advertisements are accepted
+// on the link.
+// Equivalent to: `ip token set $token dev $link`
+func (h *Handle) LinkSetIPv6Token(link Link, token net.IP) error {
+	token = token.To16()
+	if token == nil {
+		return fmt.Errorf("invalid IPv6 token")
+	}
+	return h.linkSetInet6Attr(link, unix.IFLA_INET6_TOKEN, []byte(token))
+}
+
+func (h *Handle) linkSetInet6Attr(link Link, attrType int, value []byte) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
 	msg.Index = int32(base.Index)
 	req.AddData(msg)
 
-	b := make([]byte, 1)
-	b[0] = uint8(mode)
-
-	data := nl.NewRtAttr(unix.IFLA_INET6_ADDR_GEN_MODE, b)
-	af := nl.NewRtAttr(unix.AF_INET6, data.Serialize())
-	spec := nl.NewRtAttr(unix.IFLA_AF_SPEC, af.Serialize())
+	spec := nl.NewRtAttr(unix.IFLA_AF_SPEC, nil)
+	af := spec.AddRtAttr(unix.AF_INET6, nil)
+	af.AddRtAttr(attrType, value)
 	req.AddData(spec)
 
 	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
 	return err
 }
 
+// LinkSetIPv6StableSecret sets the secret used to generate stable privacy
+// addresses on the link device. The kernel does not accept the secret over
+// netlink, so it is written to the sysctl of the link.
+// Equivalent to: `sysctl net.ipv6.conf.$link.stable_secret=$secret`
+func LinkSetIPv6StableSecret(link Link, secret net.IP) error {
+	return pkgHandle.LinkSetIPv6StableSecret(link, secret)
+}
+
+// LinkSetIPv6StableSecret sets the secret used to generate stable privacy
+// addresses on the link device. The kernel does not accept the secret over
+// netlink, so it is written to the sysctl of the link, in the network
+// namespace of the handle.
+// Equivalent to: `sysctl net.ipv6.conf.$link.stable_secret=$secret`
+func (h *Handle) LinkSetIPv6StableSecret(link Link, secret net.IP) error {
+	return h.setIPv6StableSecret(link.Attrs().Name, secret)
+}
+
+// IPv6SetDefaultStableSecret sets the secret used to generate stable privacy
+// addresses on links that do not have their own. The kernel does not accept
+// the secret over netlink, so it is written to the default sysctl.
+// Equivalent to: `sysctl net.ipv6.conf.default.stable_secret=$secret`
+func IPv6SetDefaultStableSecret(secret net.IP) error {
+	return pkgHandle.IPv6SetDefaultStableSecret(secret)
+}
+
+// IPv6SetDefaultStableSecret sets the secret used to generate stable privacy
+// addresses on links that do not have their own. The kernel does not accept
+// the secret over netlink, so it is written to the default sysctl, in the
+// network namespace of the handle.
+// Equivalent to: `sysctl net.ipv6.conf.default.stable_secret=$secret`
+func (h *Handle) IPv6SetDefaultStableSecret(secret net.IP) error {
+	return h.setIPv6StableSecret("default", secret)
+}
+
+func (h *Handle) setIPv6StableSecret(name string, secret net.IP) error {
+	if name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("invalid link name %q", name)
+	}
+	secret = secret.To16()
+	if secret == nil || secret.To4() != nil {
+		return fmt.Errorf("invalid IPv6 stable secret")
+	}
+	// The sysctls under /proc/sys/net are the ones of the network namespace
+	// of the thread opening them.
+	ns := netns.None()
+	if h.options.NetNS != nil {
+		ns = *h.options.NetNS
+	}
+	path := filepath.Join("/proc/sys/net/ipv6/conf", name, "stable_secret")
+	return nl.RunInNetns(ns, netns.None(), func() error {
+		return os.WriteFile(path, []byte(secret.String()), 0o600)
+	})
+}
+
+// Stats returns the IPv6 statistics of the link, indexed by
+// nl.IPSTATS_MIB_*. They are decoded on each call.
+func (i *LinkInet6) Stats() []uint64 {
+	return parseUint64Array(i.stats)
+}
+
+// Icmp6Stats returns the ICMPv6 statistics of the link, indexed by
+// nl.ICMP6_MIB_*. They are decoded on each call.
+func (i *LinkInet6) Icmp6Stats() []uint64 {
+	return parseUint64Array(i.icmp6Stats)
+}
+
+// RaReceived reports whether a router advertisement was received on the link.
+func (i *LinkInet6) RaReceived() bool {
+	return i.Flags&nl.IF_RA_RCVD != 0
+}
+
+// Managed reports whether the last router advertisement had the managed
+// address configuration flag set.
+func (i *LinkInet6) Managed() bool {
+	return i.Flags&nl.IF_RA_MANAGED != 0
+}
+
+// OtherConf reports whether the last router advertisement had the other
+// configuration flag set.
+func (i *LinkInet6) OtherConf() bool {
+	return i.Flags&nl.IF_RA_OTHERCONF != 0
+}
+
+// parseLinkInet6 parses the AF_INET6 section of IFLA_AF_SPEC. The statistics
+// are kept undecoded, most callers listing links not needing them.
+func parseLinkInet6(data []byte) (*LinkInet6, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	inet6 := &LinkInet6{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case unix.IFLA_INET6_FLAGS:
+			if len(attr.Value) < 4 {
+				return nil, fmt.Errorf("inet6 flags attribute too short: %d bytes", len(attr.Value))
+			}
+			inet6.Flags = native.Uint32(attr.Value[0:4])
+		case unix.IFLA_INET6_ADDR_GEN_MODE:
+			if len(attr.Value) < 1 {
+				return nil, fmt.Errorf("inet6 addrgenmode attribute too short: %d bytes", len(attr.Value))
+			}
+			inet6.AddrGenMode = int(attr.Value[0])
+		case unix.IFLA_INET6_TOKEN:
+			if len(attr.Value) < net.IPv6len {
+				return nil, fmt.Errorf("inet6 token attribute too short: %d bytes", len(attr.Value))
+			}
+			inet6.Token = make(net.IP, net.IPv6len)
+			copy(inet6.Token, attr.Value)
+		case nl.IFLA_INET6_RA_MTU:
+			if len(attr.Value) < 4 {
+				return nil, fmt.Errorf("inet6 RA MTU attribute too short: %d bytes", len(attr.Value))
+			}
+			inet6.RaMtu = native.Uint32(attr.Value[0:4])
+		case unix.IFLA_INET6_CACHEINFO:
+			if len(attr.Value) < nl.SizeofIflaCacheInfo {
+				continue
+			}
+			cacheInfo := new(LinkInet6CacheInfo)
+			if err := binary.Read(bytes.NewBuffer(attr.Value[:]), nl.NativeEndian(), cacheInfo); err != nil {
+				return nil, err
+			}
+			inet6.CacheInfo = cacheInfo
+		case unix.IFLA_INET6_STATS:
+			inet6.stats = attr.Value
+		case unix.IFLA_INET6_ICMP6STATS:
+			inet6.icmp6Stats = attr.Value
+		}
+	}
+	return inet6, nil
+}
+
+func parseUint64Array(b []byte) []uint64 {
+	res := make([]uint64, len(b)/8)
+	for i := range res {
+		res[i] = native.Uint64(b[i*8 : i*8+8])
+	}
+	return res
+}
+
 func addNetkitAttrs(nk *Netkit, linkInfo *nl.RtAttr, flag int) error {
 	if nk.Mode != NETKIT_MODE_L2 && (nk.LinkAttrs.HardwareAddr != nil || nk.peerLinkAttrs.HardwareAddr != nil) {
 		return fmt.Errorf("netkit only allows setting Ethernet in L2 mode")
@@ -3528,6 +3901,90 @@ func parseGretapData(link Link, data []syscall.NetlinkRouteAttr) {
 	}
 }
 
+func addErspanAttrs(erspan *Erspan, linkInfo *nl.RtAttr) {
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+
+	if erspan.FlowBased {
+		// In flow based mode, the ERSPAN metadata is set per packet
+		data.AddRtAttr(nl.IFLA_GRE_COLLECT_METADATA, []byte{})
+		return
+	}
+
+	if ip := erspan.Local; ip != nil {
+		if ip.To4() != nil {
+			ip = ip.To4()
+		}
+		data.AddRtAttr(nl.IFLA_GRE_LOCAL, []byte(ip))
+	}
+
+	if ip := erspan.Remote; ip != nil {
+		if ip.To4() != nil {
+			ip = ip.To4()
+		}
+		data.AddRtAttr(nl.IFLA_GRE_REMOTE, []byte(ip))
+	}
+
+	// The kernel requires exactly the key and sequence flags for ERSPAN
+	flags := uint16(nl.GRE_KEY | nl.GRE_SEQ)
+	data.AddRtAttr(nl.IFLA_GRE_IKEY, htonl(erspan.IKey))
+	data.AddRtAttr(nl.IFLA_GRE_OKEY, htonl(erspan.OKey))
+	data.AddRtAttr(nl.IFLA_GRE_IFLAGS, htons(flags))
+	data.AddRtAttr(nl.IFLA_GRE_OFLAGS, htons(flags))
+
+	if erspan.Link != 0 {
+		data.AddRtAttr(nl.IFLA_GRE_LINK, nl.Uint32Attr(erspan.Link))
+	}
+
+	data.AddRtAttr(nl.IFLA_GRE_PMTUDISC, nl.Uint8Attr(erspan.PMtuDisc))
+	data.AddRtAttr(nl.IFLA_GRE_TTL, nl.Uint8Attr(erspan.Ttl))
+	data.AddRtAttr(nl.IFLA_GRE_TOS, nl.Uint8Attr(erspan.Tos))
+
+	if erspan.Version != 0 {
+		data.AddRtAttr(nl.IFLA_GRE_ERSPAN_VER, nl.Uint8Attr(erspan.Version))
+	}
+	switch erspan.Version {
+	case 0, 1:
+		data.AddRtAttr(nl.IFLA_GRE_ERSPAN_INDEX, nl.Uint32Attr(erspan.Index))
+	case 2:
+		data.AddRtAttr(nl.IFLA_GRE_ERSPAN_DIR, nl.Uint8Attr(uint8(erspan.Dir)))
+		data.AddRtAttr(nl.IFLA_GRE_ERSPAN_HWID, nl.Uint16Attr(erspan.HwId))
+	}
+}
+
+func parseErspanData(link Link, data []syscall.NetlinkRouteAttr) {
+	erspan := link.(*Erspan)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_GRE_IKEY:
+			erspan.IKey = ntohl(datum.Value[0:4])
+		case nl.IFLA_GRE_OKEY:
+			erspan.OKey = ntohl(datum.Value[0:4])
+		case nl.IFLA_GRE_LOCAL:
+			erspan.Local = net.IP(datum.Value)
+		case nl.IFLA_GRE_REMOTE:
+			erspan.Remote = net.IP(datum.Value)
+		case nl.IFLA_GRE_LINK:
+			erspan.Link = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_GRE_TTL:
+			erspan.Ttl = uint8(datum.Value[0])
+		case nl.IFLA_GRE_TOS:
+			erspan.Tos = uint8(datum.Value[0])
+		case nl.IFLA_GRE_PMTUDISC:
+			erspan.PMtuDisc = uint8(datum.Value[0])
+		case nl.IFLA_GRE_COLLECT_METADATA:
+			erspan.FlowBased = true
+		case nl.IFLA_GRE_ERSPAN_VER:
+			erspan.Version = uint8(datum.Value[0])
+		case nl.IFLA_GRE_ERSPAN_INDEX:
+			erspan.Index = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_GRE_ERSPAN_DIR:
+			erspan.Dir = ErspanDir(datum.Value[0])
+		case nl.IFLA_GRE_ERSPAN_HWID:
+			erspan.HwId = native.Uint16(datum.Value[0:2])
+		}
+	}
+}
+
 func addGretunAttrs(gre *Gretun, linkInfo *nl.RtAttr) {
 	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
 
@@ -3873,6 +4330,10 @@ func addVtiAttrs(vti *Vti, linkInfo *nl.RtAttr) {
 
 	data.AddRtAttr(nl.IFLA_VTI_IKEY, htonl(vti.IKey))
 	data.AddRtAttr(nl.IFLA_VTI_OKEY, htonl(vti.OKey))
+
+	if vti.FwMark != 0 {
+		data.AddRtAttr(nl.IFLA_VTI_FWMARK, nl.Uint32Attr(vti.FwMark))
+	}
 }
 
 func parseVtiData(link Link, data []syscall.NetlinkRouteAttr) {
@@ -3887,6 +4348,79 @@ func parseVtiData(link Link, data []syscall.NetlinkRouteAttr) {
 			vti.IKey = ntohl(datum.Value[0:4])
 		case nl.IFLA_VTI_OKEY:
 			vti.OKey = ntohl(datum.Value[0:4])
+		case nl.IFLA_VTI_LINK:
+			vti.Link = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_VTI_FWMARK:
+			vti.FwMark = native.Uint32(datum.Value[0:4])
+		}
+	}
+}
+
+func addAmtAttrs(amt *Amt, linkInfo *nl.RtAttr) {
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+	data.AddRtAttr(nl.IFLA_AMT_MODE, nl.Uint32Attr(uint32(amt.Mode)))
+	if amt.Link != 0 {
+		data.AddRtAttr(nl.IFLA_AMT_LINK, nl.Uint32Attr(amt.Link))
+	}
+	if ip := amt.Local.To4(); ip != nil {
+		data.AddRtAttr(nl.IFLA_AMT_LOCAL_IP, []byte(ip))
+	}
+	if ip := amt.Discovery.To4(); ip != nil {
+		data.AddRtAttr(nl.IFLA_AMT_DISCOVERY_IP, []byte(ip))
+	}
+	if amt.RelayPort != 0 {
+		data.AddRtAttr(nl.IFLA_AMT_RELAY_PORT, htons(amt.RelayPort))
+	}
+	if amt.GatewayPort != 0 {
+		data.AddRtAttr(nl.IFLA_AMT_GATEWAY_PORT, htons(amt.GatewayPort))
+	}
+	if amt.MaxTunnels != 0 {
+		data.AddRtAttr(nl.IFLA_AMT_MAX_TUNNELS, nl.Uint32Attr(amt.MaxTunnels))
+	}
+}
+
+func parseAmtData(link Link, data []syscall.NetlinkRouteAttr) {
+	amt := link.(*Amt)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_AMT_MODE:
+			amt.Mode = AmtMode(native.Uint32(datum.Value[0:4]))
+		case nl.IFLA_AMT_RELAY_PORT:
+			amt.RelayPort = ntohs(datum.Value[0:2])
+		case nl.IFLA_AMT_GATEWAY_PORT:
+			amt.GatewayPort = ntohs(datum.Value[0:2])
+		case nl.IFLA_AMT_LINK:
+			amt.Link = native.Uint32(datum.Value[0:4])
+		case nl.IFLA_AMT_LOCAL_IP:
+			amt.Local = net.IP(datum.Value[0:4])
+		case nl.IFLA_AMT_REMOTE_IP:
+			amt.Remote = net.IP(datum.Value[0:4])
+		case nl.IFLA_AMT_DISCOVERY_IP:
+			amt.Discovery = net.IP(datum.Value[0:4])
+		case nl.IFLA_AMT_MAX_TUNNELS:
+			amt.MaxTunnels = native.Uint32(datum.Value[0:4])
+		}
+	}
+}
+
+func parseBatAdvData(link Link, data []syscall.NetlinkRouteAttr) {
+	batadv := link.(*BatAdv)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_BATADV_ALGO_NAME:
+			batadv.RoutingAlgo = nl.BytesToString(datum.Value)
+		}
+	}
+}
+
+func parseRmnetData(link Link, data []syscall.NetlinkRouteAttr) {
+	rmnet := link.(*Rmnet)
+	for _, datum := range data {
+		switch datum.Attr.Type {
+		case nl.IFLA_RMNET_MUX_ID:
+			rmnet.MuxID = native.Uint16(datum.Value[0:2])
+		case nl.IFLA_RMNET_FLAGS:
+			rmnet.Flags = native.Uint32(datum.Value[0:4]) & native.Uint32(datum.Value[4:8])
 		}
 	}
 }
@@ -3928,6 +4462,13 @@ func addBridgeAttrs(bridge *Bridge, linkInfo *nl.RtAttr) {
 	if bridge.GroupFwdMask != nil {
 		data.AddRtAttr(nl.IFLA_BR_GROUP_FWD_MASK, nl.Uint16Attr(*bridge.GroupFwdMask))
 	}
+	if bridge.MstEnabled != nil {
+		opt := nl.BrBooloptMulti{Optmask: 1 << nl.BR_BOOLOPT_MST_ENABLE}
+		if *bridge.MstEnabled {
+			opt.Optval = opt.Optmask
+		}
+		data.AddRtAttr(nl.IFLA_BR_MULTI_BOOLOPT, opt.Serialize())
+	}
 }
 
 func parseBridgeData(bridge Link, data []syscall.NetlinkRouteAttr) {
@@ -3952,6 +4493,12 @@ func parseBridgeData(bridge Link, data []syscall.NetlinkRouteAttr) {
 		case nl.IFLA_BR_GROUP_FWD_MASK:
 			mask := native.Uint16(datum.Value[0:2])
 			br.GroupFwdMask = &mask
+		case nl.IFLA_BR_MULTI_BOOLOPT:
+			opt := nl.DeserializeBrBooloptMulti(datum.Value)
+			if opt.Optmask&(1<<nl.BR_BOOLOPT_MST_ENABLE) != 0 {
+				mstEnabled := opt.Optval&(1<<nl.BR_BOOLOPT_MST_ENABLE) != 0
+				br.MstEnabled = &mstEnabled
+			}
 		}
 	}
 }
@@ -4123,7 +4670,9 @@ func LinkDelBondSlave(link Link, master *Bond) error {
 // LinkSetBondSlaveQueueId modify bond slave queue-id.
 func (h *Handle) LinkSetBondSlaveQueueId(link Link, queueId uint16) error {
 	base := link.Attrs()
-	h.ensureIndex(base)
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
 	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
 
 	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
@@ -4290,17 +4839,131 @@ func parseCanData(link Link, data []syscall.NetlinkRouteAttr) {
 		case nl.IFLA_CAN_RESTART_MS:
 			can.RestartMs = native.Uint32(datum.Value)
 		case nl.IFLA_CAN_DATA_BITTIMING_CONST:
+			can.DataBitTimingConst = parseCanBitTimingConst(datum.Value)
 		case nl.IFLA_CAN_RESTART:
 		case nl.IFLA_CAN_DATA_BITTIMING:
+			can.DataBitTiming = parseCanBitTiming(datum.Value)
 		case nl.IFLA_CAN_TERMINATION:
+			can.Termination = native.Uint16(datum.Value)
 		case nl.IFLA_CAN_TERMINATION_CONST:
+			can.TerminationConst = make([]uint16, len(datum.Value)/2)
+			for i := range can.TerminationConst {
+				can.TerminationConst[i] = native.Uint16(datum.Value[i*2:])
+			}
 		case nl.IFLA_CAN_BITRATE_CONST:
+			can.BitRateConst = parseCanBitRates(datum.Value)
 		case nl.IFLA_CAN_DATA_BITRATE_CONST:
+			can.DataBitRateConst = parseCanBitRates(datum.Value)
 		case nl.IFLA_CAN_BITRATE_MAX:
+			can.BitRateMax = native.Uint32(datum.Value)
 		}
 	}
 }
 
+func parseCanBitTiming(b []byte) *CanBitTiming {
+	if len(b) < nl.SizeofCanBittiming {
+		return nil
+	}
+	return &CanBitTiming{
+		BitRate:            native.Uint32(b),
+		SamplePoint:        native.Uint32(b[4:]),
+		TimeQuanta:         native.Uint32(b[8:]),
+		PropagationSegment: native.Uint32(b[12:]),
+		PhaseSegment1:      native.Uint32(b[16:]),
+		PhaseSegment2:      native.Uint32(b[20:]),
+		SyncJumpWidth:      native.Uint32(b[24:]),
+		BitRatePreScaler:   native.Uint32(b[28:]),
+	}
+}
+
+func parseCanBitTimingConst(b []byte) *CanBitTimingConst {
+	if len(b) < nl.SizeofCanBittimingConst {
+		return nil
+	}
+	return &CanBitTimingConst{
+		Name:                nl.BytesToString(b[:16]),
+		TimeSegment1Min:     native.Uint32(b[16:]),
+		TimeSegment1Max:     native.Uint32(b[20:]),
+		TimeSegment2Min:     native.Uint32(b[24:]),
+		TimeSegment2Max:     native.Uint32(b[28:]),
+		SyncJumpWidthMax:    native.Uint32(b[32:]),
+		BitRatePreScalerMin: native.Uint32(b[36:]),
+		BitRatePreScalerMax: native.Uint32(b[40:]),
+		BitRatePreScalerInc: native.Uint32(b[44:]),
+	}
+}
+
+func parseCanBitRates(b []byte) []uint32 {
+	rates := make([]uint32, len(b)/4)
+	for i := range rates {
+		rates[i] = native.Uint32(b[i*4:])
+	}
+	return rates
+}
+
+func (t *CanBitTiming) serialize() []byte {
+	b := make([]byte, nl.SizeofCanBittiming)
+	native.PutUint32(b, t.BitRate)
+	native.PutUint32(b[4:], t.SamplePoint)
+	native.PutUint32(b[8:], t.TimeQuanta)
+	native.PutUint32(b[12:], t.PropagationSegment)
+	native.PutUint32(b[16:], t.PhaseSegment1)
+	native.PutUint32(b[20:], t.PhaseSegment2)
+	native.PutUint32(b[24:], t.SyncJumpWidth)
+	native.PutUint32(b[28:], t.BitRatePreScaler)
+	return b
+}
+
+// LinkSetCanParams changes the bit timing, control mode, restart delay and
+// termination of an existing Can link. The link must be down to change the
+// bit timing or the control mode.
+// Equivalent to: `ip link set $link type can bitrate $bitrate dbitrate $dbitrate fd on ...`
+func LinkSetCanParams(link Link, params CanParams) error {
+	return pkgHandle.LinkSetCanParams(link, params)
+}
+
+// LinkSetCanParams changes the bit timing, control mode, restart delay and
+// termination of an existing Can link. The link must be down to change the
+// bit timing or the control mode.
+// Equivalent to: `ip link set $link type can bitrate $bitrate dbitrate $dbitrate fd on ...`
+func (h *Handle) LinkSetCanParams(link Link, params CanParams) error {
+	base := link.Attrs()
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
+	req := h.newNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
+
+	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
+	msg.Index = int32(base.Index)
+	req.AddData(msg)
+
+	linkInfo := nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	linkInfo.AddRtAttr(nl.IFLA_INFO_KIND, nl.NonZeroTerminated("can"))
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+	if params.BitTiming != nil {
+		data.AddRtAttr(nl.IFLA_CAN_BITTIMING, params.BitTiming.serialize())
+	}
+	if params.DataBitTiming != nil {
+		data.AddRtAttr(nl.IFLA_CAN_DATA_BITTIMING, params.DataBitTiming.serialize())
+	}
+	if params.CtrlModeMask != 0 {
+		b := make([]byte, 8)
+		native.PutUint32(b, params.CtrlModeMask)
+		native.PutUint32(b[4:], params.CtrlModeFlags)
+		data.AddRtAttr(nl.IFLA_CAN_CTRLMODE, b)
+	}
+	if params.RestartMs != nil {
+		data.AddRtAttr(nl.IFLA_CAN_RESTART_MS, nl.Uint32Attr(*params.RestartMs))
+	}
+	if params.Termination != nil {
+		data.AddRtAttr(nl.IFLA_CAN_TERMINATION, nl.Uint16Attr(*params.Termination))
+	}
+	req.AddData(linkInfo)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
 func addIPoIBAttrs(ipoib *IPoIB, linkInfo *nl.RtAttr) {
 	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
 	data.AddRtAttr(nl.IFLA_IPOIB_PKEY, nl.Uint16Attr(uint16(ipoib.Pkey)))
@@ -0,0 +1,461 @@
+package netlink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// Filter mask bits selecting the statistics blocks returned by LinkGetStats
+// and LinkStatsList.
+const (
+	STATS_FILTER_LINK_64             = 1 << (nl.IFLA_STATS_LINK_64 - 1)
+	STATS_FILTER_LINK_XSTATS         = 1 << (nl.IFLA_STATS_LINK_XSTATS - 1)
+	STATS_FILTER_LINK_XSTATS_SLAVE   = 1 << (nl.IFLA_STATS_LINK_XSTATS_SLAVE - 1)
+	STATS_FILTER_LINK_OFFLOAD_XSTATS = 1 << (nl.IFLA_STATS_LINK_OFFLOAD_XSTATS - 1)
+	STATS_FILTER_AF_SPEC             = 1 << (nl.IFLA_STATS_AF_SPEC - 1)
+	STATS_FILTER_ALL                 = 1<<nl.IFLA_STATS_MAX - 1
+)
+
+// LinkStats holds the statistics of a link reported by RTM_GETSTATS. Only the
+// blocks selected by the filter mask and supported by the device are set.
+type LinkStats struct {
+	Index int
+	// Stats64 is the IFLA_STATS_LINK_64 block.
+	Stats64 *LinkStatistics64
+	// Xstats are the driver specific statistics of a bridge or bond master.
+	Xstats *LinkXstats
+	// XstatsSlave are the statistics of a bridge port or bond slave.
+	XstatsSlave *LinkXstats
+	// Offload are the statistics of traffic handled by hardware.
+	Offload *LinkOffloadXstats
+	// Mpls is the MPLS block of IFLA_STATS_AF_SPEC.
+	Mpls *MplsLinkStats
+}
+
+// LinkXstats holds the extended statistics of a link, keyed by the kind of
+// master device that reported them.
+type LinkXstats struct {
+	Bridge *BridgeXstats
+	Bond   *BondXstats
+}
+
+// BridgeXstats holds the statistics of a bridge or bridge port.
+type BridgeXstats struct {
+	Vlans []BridgeVlanXstats
+	Mcast *BridgeMcastStats
+	Stp   *BridgeStpXstats
+}
+
+/*
+Ref: struct bridge_vlan_xstats {...}
+*/
+type BridgeVlanXstats struct {
+	RxBytes   uint64
+	RxPackets uint64
+	TxBytes   uint64
+	TxPackets uint64
+	Vid       uint16
+	Flags     uint16
+	_         uint32
+}
+
+/*
+Ref: struct br_mcast_stats {...}
+The arrays are indexed by nl.BR_MCAST_DIR_RX and nl.BR_MCAST_DIR_TX.
+*/
+type BridgeMcastStats struct {
+	IgmpV1Queries   [nl.BR_MCAST_DIR_SIZE]uint64
+	IgmpV2Queries   [nl.BR_MCAST_DIR_SIZE]uint64
+	IgmpV3Queries   [nl.BR_MCAST_DIR_SIZE]uint64
+	IgmpLeaves      [nl.BR_MCAST_DIR_SIZE]uint64
+	IgmpV1Reports   [nl.BR_MCAST_DIR_SIZE]uint64
+	IgmpV2Reports   [nl.BR_MCAST_DIR_SIZE]uint64
+	IgmpV3Reports   [nl.BR_MCAST_DIR_SIZE]uint64
+	IgmpParseErrors uint64
+	MldV1Queries    [nl.BR_MCAST_DIR_SIZE]uint64
+	MldV2Queries    [nl.BR_MCAST_DIR_SIZE]uint64
+	MldLeaves       [nl.BR_MCAST_DIR_SIZE]uint64
+	MldV1Reports    [nl.BR_MCAST_DIR_SIZE]uint64
+	MldV2Reports    [nl.BR_MCAST_DIR_SIZE]uint64
+	MldParseErrors  uint64
+	McastBytes      [nl.BR_MCAST_DIR_SIZE]uint64
+	McastPackets    [nl.BR_MCAST_DIR_SIZE]uint64
+}
+
+/*
+Ref: struct bridge_stp_xstats {...}
+*/
+type BridgeStpXstats struct {
+	TransitionBlk uint64
+	TransitionFwd uint64
+	RxBpdu        uint64
+	TxBpdu        uint64
+	RxTcn         uint64
+	TxTcn         uint64
+}
+
+// BondXstats holds the statistics of a bond or bond slave.
+type BondXstats struct {
+	Ad3 *Bond3adStats
+}
+
+// Bond3adStats are the 802.3ad LACPDU and marker counters.
+type Bond3adStats struct {
+	LacpduRx        uint64
+	LacpduTx        uint64
+	LacpduUnknownRx uint64
+	LacpduIllegalRx uint64
+	MarkerRx        uint64
+	MarkerTx        uint64
+	MarkerRespRx    uint64
+	MarkerRespTx    uint64
+	MarkerUnknownRx uint64
+}
+
+// LinkOffloadXstats holds the statistics of offloaded traffic.
+type LinkOffloadXstats struct {
+	// CpuHit counts packets that hit the CPU instead of being forwarded in
+	// hardware.
+	CpuHit *LinkStatistics64
+	// L3Stats are the hardware L3 statistics, reported once they have been
+	// enabled with LinkSetOffloadL3Stats and the driver supports them.
+	L3Stats *LinkHwStats64
+	// L3StatsRequested reports whether hardware L3 statistics were requested.
+	L3StatsRequested bool
+	// L3StatsUsed reports whether the driver actually provides them.
+	L3StatsUsed bool
+}
+
+/*
+Ref: struct rtnl_hw_stats64 {...}
+*/
+type LinkHwStats64 struct {
+	RxPackets uint64
+	TxPackets uint64
+	RxBytes   uint64
+	TxBytes   uint64
+	RxErrors  uint64
+	TxErrors  uint64
+	RxDropped uint64
+	TxDropped uint64
+	Multicast uint64
+}
+
+/*
+Ref: struct mpls_link_stats {...}
+*/
+type MplsLinkStats struct {
+	RxPackets uint64
+	TxPackets uint64
+	RxBytes   uint64
+	TxBytes   uint64
+	RxErrors  uint64
+	TxErrors  uint64
+	RxDropped uint64
+	TxDropped uint64
+	RxNoroute uint64
+}
+
+// LinkGetStats gets the statistics blocks selected by filterMask, a
+// combination of STATS_FILTER_* values, for a link.
+// Equivalent to: `ip stats show dev $link`
+func LinkGetStats(link Link, filterMask uint32) (*LinkStats, error) {
+	return pkgHandle.LinkGetStats(link, filterMask)
+}
+
+// LinkGetStats gets the statistics blocks selected by filterMask, a
+// combination of STATS_FILTER_* values, for a link.
+// Equivalent to: `ip stats show dev $link`
+func (h *Handle) LinkGetStats(link Link, filterMask uint32) (*LinkStats, error) {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req := h.newNetlinkRequest(unix.RTM_GETSTATS, 0)
+	req.AddData(nl.NewIfStatsMsg(unix.AF_UNSPEC, base.Index, filterMask))
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWSTATS)
+	if err != nil {
+		if errors.Is(err, unix.ENODEV) {
+			return nil, LinkNotFoundError{fmt.Errorf("Link not found")}
+		}
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, LinkNotFoundError{fmt.Errorf("Link not found")}
+	}
+	return parseLinkStats(msgs[0])
+}
+
+// LinkStatsList gets the statistics blocks selected by filterMask, a
+// combination of STATS_FILTER_* values, for all links. This is considerably
+// cheaper than LinkList when only counters are needed.
+// Equivalent to: `ip stats show`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func LinkStatsList(filterMask uint32) ([]*LinkStats, error) {
+	return pkgHandle.LinkStatsList(filterMask)
+}
+
+// LinkStatsList gets the statistics blocks selected by filterMask, a
+// combination of STATS_FILTER_* values, for all links. This is considerably
+// cheaper than LinkList when only counters are needed.
+// Equivalent to: `ip stats show`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) LinkStatsList(filterMask uint32) ([]*LinkStats, error) {
+	req := h.newNetlinkRequest(unix.RTM_GETSTATS, unix.NLM_F_DUMP)
+	req.AddData(nl.NewIfStatsMsg(unix.AF_UNSPEC, 0, filterMask))
+
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWSTATS)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+
+	res := make([]*LinkStats, 0, len(msgs))
+	for _, m := range msgs {
+		stats, err := parseLinkStats(m)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, stats)
+	}
+	return res, executeErr
+}
+
+// LinkSetOffloadL3Stats enables or disables the collection of hardware L3
+// statistics on a link, reported in LinkOffloadXstats.L3Stats.
+// Equivalent to: `ip stats set dev $link l3_stats { on | off }`
+func LinkSetOffloadL3Stats(link Link, enable bool) error {
+	return pkgHandle.LinkSetOffloadL3Stats(link, enable)
+}
+
+// LinkSetOffloadL3Stats enables or disables the collection of hardware L3
+// statistics on a link, reported in LinkOffloadXstats.L3Stats.
+// Equivalent to: `ip stats set dev $link l3_stats { on | off }`
+func (h *Handle) LinkSetOffloadL3Stats(link Link, enable bool) error {
+	base := link.Attrs()
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
+	req := h.newNetlinkRequest(unix.RTM_SETSTATS, unix.NLM_F_ACK)
+	req.AddData(nl.NewIfStatsMsg(unix.AF_UNSPEC, base.Index, 0))
+	req.AddData(nl.NewRtAttr(nl.IFLA_STATS_SET_OFFLOAD_XSTATS_L3_STATS, boolToByte(enable)))
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+func parseLinkStats(m []byte) (*LinkStats, error) {
+	msg := nl.DeserializeIfStatsMsg(m)
+	stats := &LinkStats{Index: int(msg.Ifindex)}
+
+	attrs, err := nl.ParseRouteAttr(m[nl.SizeofIfStatsMsg:])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.IFLA_STATS_LINK_64:
+			stats.Stats64 = new(LinkStatistics64)
+			if err := binary.Read(bytes.NewBuffer(attr.Value), nl.NativeEndian(), stats.Stats64); err != nil {
+				return nil, err
+			}
+		case nl.IFLA_STATS_LINK_XSTATS:
+			if stats.Xstats, err = parseLinkXstats(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.IFLA_STATS_LINK_XSTATS_SLAVE:
+			if stats.XstatsSlave, err = parseLinkXstats(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.IFLA_STATS_LINK_OFFLOAD_XSTATS:
+			if stats.Offload, err = parseLinkOffloadXstats(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.IFLA_STATS_AF_SPEC:
+			if stats.Mpls, err = parseStatsAfSpec(attr.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stats, nil
+}
+
+func parseLinkXstats(data []byte) (*LinkXstats, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	xstats := &LinkXstats{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.LINK_XSTATS_TYPE_BRIDGE:
+			if xstats.Bridge, err = parseBridgeXstats(attr.Value); err != nil {
+				return nil, err
+			}
+		case nl.LINK_XSTATS_TYPE_BOND:
+			if xstats.Bond, err = parseBondXstats(attr.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return xstats, nil
+}
+
+func parseBridgeXstats(data []byte) (*BridgeXstats, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	xstats := &BridgeXstats{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.BRIDGE_XSTATS_VLAN:
+			var vlan BridgeVlanXstats
+			if err := binary.Read(bytes.NewBuffer(attr.Value), nl.NativeEndian(), &vlan); err != nil {
+				return nil, err
+			}
+			xstats.Vlans = append(xstats.Vlans, vlan)
+		case nl.BRIDGE_XSTATS_MCAST:
+			xstats.Mcast = new(BridgeMcastStats)
+			if err := binary.Read(bytes.NewBuffer(attr.Value), nl.NativeEndian(), xstats.Mcast); err != nil {
+				return nil, err
+			}
+		case nl.BRIDGE_XSTATS_STP:
+			xstats.Stp = new(BridgeStpXstats)
+			if err := binary.Read(bytes.NewBuffer(attr.Value), nl.NativeEndian(), xstats.Stp); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return xstats, nil
+}
+
+func parseBondXstats(data []byte) (*BondXstats, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	xstats := &BondXstats{}
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != nl.BOND_XSTATS_3AD {
+			continue
+		}
+		stats, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		xstats.Ad3 = parseBond3adStats(stats)
+	}
+	return xstats, nil
+}
+
+func parseBond3adStats(data []syscall.NetlinkRouteAttr) *Bond3adStats {
+	stats := &Bond3adStats{}
+	for _, datum := range data {
+		if len(datum.Value) < 8 {
+			continue
+		}
+		value := native.Uint64(datum.Value[0:8])
+		switch datum.Attr.Type {
+		case nl.BOND_3AD_STAT_LACPDU_RX:
+			stats.LacpduRx = value
+		case nl.BOND_3AD_STAT_LACPDU_TX:
+			stats.LacpduTx = value
+		case nl.BOND_3AD_STAT_LACPDU_UNKNOWN_RX:
+			stats.LacpduUnknownRx = value
+		case nl.BOND_3AD_STAT_LACPDU_ILLEGAL_RX:
+			stats.LacpduIllegalRx = value
+		case nl.BOND_3AD_STAT_MARKER_RX:
+			stats.MarkerRx = value
+		case nl.BOND_3AD_STAT_MARKER_TX:
+			stats.MarkerTx = value
+		case nl.BOND_3AD_STAT_MARKER_RESP_RX:
+			stats.MarkerRespRx = value
+		case nl.BOND_3AD_STAT_MARKER_RESP_TX:
+			stats.MarkerRespTx = value
+		case nl.BOND_3AD_STAT_MARKER_UNKNOWN_RX:
+			stats.MarkerUnknownRx = value
+		}
+	}
+	return stats
+}
+
+func parseLinkOffloadXstats(data []byte) (*LinkOffloadXstats, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	xstats := &LinkOffloadXstats{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.IFLA_OFFLOAD_XSTATS_CPU_HIT:
+			xstats.CpuHit = new(LinkStatistics64)
+			if err := binary.Read(bytes.NewBuffer(attr.Value), nl.NativeEndian(), xstats.CpuHit); err != nil {
+				return nil, err
+			}
+		case nl.IFLA_OFFLOAD_XSTATS_L3_STATS:
+			xstats.L3Stats = new(LinkHwStats64)
+			if err := binary.Read(bytes.NewBuffer(attr.Value), nl.NativeEndian(), xstats.L3Stats); err != nil {
+				return nil, err
+			}
+		case nl.IFLA_OFFLOAD_XSTATS_HW_S_INFO:
+			infos, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, info := range infos {
+				if info.Attr.Type&nl.NLA_TYPE_MASK != nl.IFLA_OFFLOAD_XSTATS_L3_STATS {
+					continue
+				}
+				l3Info, err := nl.ParseRouteAttr(info.Value)
+				if err != nil {
+					return nil, err
+				}
+				for _, datum := range l3Info {
+					switch datum.Attr.Type {
+					case nl.IFLA_OFFLOAD_XSTATS_HW_S_INFO_REQUEST:
+						xstats.L3StatsRequested = byteToBool(datum.Value[0])
+					case nl.IFLA_OFFLOAD_XSTATS_HW_S_INFO_USED:
+						xstats.L3StatsUsed = byteToBool(datum.Value[0])
+					}
+				}
+			}
+		}
+	}
+	return xstats, nil
+}
+
+func parseStatsAfSpec(data []byte) (*MplsLinkStats, error) {
+	attrs, err := nl.ParseRouteAttr(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type&nl.NLA_TYPE_MASK != unix.AF_MPLS {
+			continue
+		}
+		mplsAttrs, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, err
+		}
+		for _, mplsAttr := range mplsAttrs {
+			if mplsAttr.Attr.Type&nl.NLA_TYPE_MASK != nl.MPLS_STATS_LINK {
+				continue
+			}
+			stats := new(MplsLinkStats)
+			if err := binary.Read(bytes.NewBuffer(mplsAttr.Value), nl.NativeEndian(), stats); err != nil {
+				return nil, err
+			}
+			return stats, nil
+		}
+	}
+	return nil, nil
+}
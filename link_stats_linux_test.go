@@ -0,0 +1,79 @@
+package netlink
+
+import (
+	"testing"
+)
+
+func TestLinkGetStats(t *testing.T) {
+	minKernelRequired(t, 4, 7)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	vethLink := &Veth{LinkAttrs: LinkAttrs{Name: "v0"}, PeerName: "v1"}
+	if err := LinkAdd(vethLink); err != nil {
+		t.Fatal(err)
+	}
+	veth0, err := LinkByName("v0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := LinkGetStats(veth0, STATS_FILTER_LINK_64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Index != veth0.Attrs().Index {
+		t.Fatalf("expected stats for index %d, got %d", veth0.Attrs().Index, stats.Index)
+	}
+	if stats.Stats64 == nil {
+		t.Fatal("expected IFLA_STATS_LINK_64 to be reported")
+	}
+	if stats.Xstats != nil || stats.XstatsSlave != nil || stats.Offload != nil || stats.Mpls != nil {
+		t.Fatalf("unexpected statistics blocks outside of the filter mask: %+v", stats)
+	}
+
+	list, err := LinkStatsList(STATS_FILTER_LINK_64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, s := range list {
+		if s.Index == veth0.Attrs().Index {
+			found = true
+			if s.Stats64 == nil {
+				t.Fatal("expected IFLA_STATS_LINK_64 to be reported")
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("link %s not found in the stats dump", veth0.Attrs().Name)
+	}
+
+	if _, err := LinkGetStats(&Dummy{LinkAttrs{Index: 1 << 30}}, STATS_FILTER_LINK_64); err == nil {
+		t.Fatal("expected an error for a non existent link")
+	}
+}
+
+func TestLinkGetStatsBridgeXstats(t *testing.T) {
+	minKernelRequired(t, 4, 20)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	bridge := &Bridge{LinkAttrs: LinkAttrs{Name: "foo"}}
+	if err := LinkAdd(bridge); err != nil {
+		t.Fatal(err)
+	}
+	vethLink := &Veth{LinkAttrs: LinkAttrs{Name: "v0", MasterIndex: bridge.Index}, PeerName: "v1"}
+	if err := LinkAdd(vethLink); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := LinkGetStats(vethLink, STATS_FILTER_LINK_XSTATS_SLAVE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.XstatsSlave == nil || stats.XstatsSlave.Bridge == nil {
+		t.Fatalf("expected bridge port xstats, got %+v", stats)
+	}
+	if stats.XstatsSlave.Bridge.Stp == nil {
+		t.Fatalf("expected bridge port STP xstats, got %+v", stats.XstatsSlave.Bridge)
+	}
+}
@@ -371,6 +371,14 @@ func testLinkAddDel(t *testing.T, link Link) {
 		compareGretap(t, gretap, other)
 	}
 
+	if erspan, ok := link.(*Erspan); ok {
+		other, ok := result.(*Erspan)
+		if !ok {
+			t.Fatal("Result of create is not an Erspan")
+		}
+		compareErspan(t, erspan, other)
+	}
+
 	if gretun, ok := link.(*Gretun); ok {
 		other, ok := result.(*Gretun)
 		if !ok {
@@ -403,6 +411,40 @@ func testLinkAddDel(t *testing.T, link Link) {
 		compareBareUDP(t, bareudp, other)
 	}
 
+	if amt, ok := link.(*Amt); ok {
+		other, ok := result.(*Amt)
+		if !ok {
+			t.Fatal("Result of create is not an Amt")
+		}
+		compareAmt(t, amt, other)
+	}
+
+	if batadv, ok := link.(*BatAdv); ok {
+		other, ok := result.(*BatAdv)
+		if !ok {
+			t.Fatal("Result of create is not a BatAdv")
+		}
+		if batadv.RoutingAlgo != "" && batadv.RoutingAlgo != other.RoutingAlgo {
+			t.Fatalf("BatAdv.RoutingAlgo is %s, should be %s", other.RoutingAlgo, batadv.RoutingAlgo)
+		}
+	}
+
+	if _, ok := link.(*Nlmon); ok {
+		if _, ok := result.(*Nlmon); !ok {
+			t.Fatal("Result of create is not a Nlmon")
+		}
+	}
+
+	if vti, ok := link.(*Vti); ok {
+		other, ok := result.(*Vti)
+		if !ok {
+			t.Fatal("Result of create is not a Vti")
+		}
+		if vti.FwMark != other.FwMark {
+			t.Fatalf("Vti.FwMark is %d, should be %d", other.FwMark, vti.FwMark)
+		}
+	}
+
 	if err = LinkDel(link); err != nil {
 		t.Fatal(err)
 	}
@@ -527,6 +569,70 @@ func compareGretap(t *testing.T, expected, actual *Gretap) {
 	}
 }
 
+func compareErspan(t *testing.T, expected, actual *Erspan) {
+	if actual.IKey != expected.IKey {
+		t.Fatal("Erspan.IKey doesn't match")
+	}
+
+	if actual.OKey != expected.OKey {
+		t.Fatal("Erspan.OKey doesn't match")
+	}
+
+	if expected.Local != nil && !actual.Local.Equal(expected.Local) {
+		t.Fatal("Erspan.Local doesn't match")
+	}
+
+	if expected.Remote != nil && !actual.Remote.Equal(expected.Remote) {
+		t.Fatal("Erspan.Remote doesn't match")
+	}
+
+	if actual.FlowBased != expected.FlowBased {
+		t.Fatal("Erspan.FlowBased doesn't match")
+	}
+
+	if expected.Version != 0 && actual.Version != expected.Version {
+		t.Fatal("Erspan.Version doesn't match")
+	}
+
+	if actual.Index != expected.Index {
+		t.Fatal("Erspan.Index doesn't match")
+	}
+
+	if actual.Dir != expected.Dir {
+		t.Fatal("Erspan.Dir doesn't match")
+	}
+
+	if actual.HwId != expected.HwId {
+		t.Fatal("Erspan.HwId doesn't match")
+	}
+}
+
+func compareAmt(t *testing.T, expected, actual *Amt) {
+	if actual.Mode != expected.Mode {
+		t.Fatal("Amt.Mode doesn't match")
+	}
+
+	if actual.Link != expected.Link {
+		t.Fatal("Amt.Link doesn't match")
+	}
+
+	if !actual.Local.Equal(expected.Local) {
+		t.Fatal("Amt.Local doesn't match")
+	}
+
+	if expected.Discovery != nil && !actual.Discovery.Equal(expected.Discovery) {
+		t.Fatal("Amt.Discovery doesn't match")
+	}
+
+	if expected.RelayPort != 0 && actual.RelayPort != expected.RelayPort {
+		t.Fatal("Amt.RelayPort doesn't match")
+	}
+
+	if expected.GatewayPort != 0 && actual.GatewayPort != expected.GatewayPort {
+		t.Fatal("Amt.GatewayPort doesn't match")
+	}
+}
+
 func compareGretun(t *testing.T, expected, actual *Gretun) {
 	if actual.Link != expected.Link {
 		t.Fatal("Gretun.Link doesn't match")
@@ -865,6 +971,30 @@ func TestLinkAddDelGretap(t *testing.T) {
 		Remote:    net.ParseIP("2001:db8:ef33::2")})
 }
 
+func TestLinkAddDelErspan(t *testing.T) {
+	minKernelRequired(t, 4, 16)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	testLinkAddDel(t, &Erspan{
+		LinkAttrs: LinkAttrs{Name: "foo4"},
+		IKey:      0x10,
+		OKey:      0x10,
+		Local:     net.IPv4(127, 0, 0, 1),
+		Remote:    net.IPv4(127, 0, 0, 2),
+		Version:   1,
+		Index:     123})
+
+	testLinkAddDel(t, &Erspan{
+		LinkAttrs: LinkAttrs{Name: "foo6"},
+		IKey:      0x20,
+		OKey:      0x20,
+		Local:     net.ParseIP("2001:db8:abcd::1"),
+		Remote:    net.ParseIP("2001:db8:ef33::2"),
+		Version:   2,
+		Dir:       ERSPAN_DIR_EGRESS,
+		HwId:      7})
+}
+
 func TestLinkAddDelGretun(t *testing.T) {
 	t.Cleanup(setUpNetlinkTest(t))
 
@@ -2841,6 +2971,53 @@ func TestLinkAddDelVti(t *testing.T) {
 		Remote:    net.IPv6loopback})
 }
 
+func TestLinkAddDelVtiFwMark(t *testing.T) {
+	minKernelRequired(t, 4, 19)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	testLinkAddDel(t, &Vti{
+		LinkAttrs: LinkAttrs{Name: "vtibar"},
+		IKey:      0x101,
+		OKey:      0x101,
+		Local:     net.IPv6loopback,
+		Remote:    net.IPv6loopback,
+		FwMark:    0x42})
+}
+
+func TestLinkAddDelNlmon(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	testLinkAddDel(t, &Nlmon{LinkAttrs: LinkAttrs{Name: "nlmon0"}})
+}
+
+func TestLinkAddDelBatAdv(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	testLinkAddDel(t, &BatAdv{LinkAttrs: LinkAttrs{Name: "bat0"}, RoutingAlgo: "BATMAN_IV"})
+}
+
+func TestLinkAddDelAmt(t *testing.T) {
+	minKernelRequired(t, 5, 16)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "v0"}, PeerName: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := LinkByName("v0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testLinkAddDel(t, &Amt{
+		LinkAttrs:   LinkAttrs{Name: "amt0"},
+		Mode:        AMT_MODE_GATEWAY,
+		Link:        uint32(parent.Attrs().Index),
+		Local:       net.IPv4(10, 0, 0, 1),
+		Discovery:   net.IPv4(10, 0, 0, 2),
+		RelayPort:   2268,
+		GatewayPort: 2268})
+}
+
 func TestLinkSetGSOMaxSize(t *testing.T) {
 	minKernelRequired(t, 5, 19)
 	t.Cleanup(setUpNetlinkTest(t))
@@ -4020,3 +4197,190 @@ func TestLinkSetMacvlanMode(t *testing.T) {
 	testMacvlanMode(macvtap, MACVLAN_MODE_SOURCE)
 	testMacvlanMode(macvtap, MACVLAN_MODE_BRIDGE)
 }
+
+func TestLinkSetIPv6AddrGenModeAndToken(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "v0"}, PeerName: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("v0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Attrs().Inet6 == nil {
+		t.Skip("IPv6 not available")
+	}
+	if link.Attrs().Inet6.CacheInfo == nil {
+		t.Fatal("expected IFLA_INET6_CACHEINFO to be reported")
+	}
+	if len(link.Attrs().Inet6.Stats()) <= nl.IPSTATS_MIB_INPKTS ||
+		len(link.Attrs().Inet6.Icmp6Stats()) <= nl.ICMP6_MIB_INMSGS {
+		t.Fatal("expected IPv6 and ICMPv6 statistics to be reported")
+	}
+
+	if err := LinkSetIPv6AddrGenMode(link, nl.IN6_ADDR_GEN_MODE_NONE); err != nil {
+		t.Fatal(err)
+	}
+	token := net.ParseIP("::1:2:3:4")
+	if err := LinkSetIPv6Token(link, token); err != nil {
+		t.Fatal(err)
+	}
+
+	link, err = LinkByName("v0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Attrs().Inet6.AddrGenMode != nl.IN6_ADDR_GEN_MODE_NONE {
+		t.Fatalf("expected addrgenmode none, got %d", link.Attrs().Inet6.AddrGenMode)
+	}
+	if !link.Attrs().Inet6.Token.Equal(token) {
+		t.Fatalf("expected token %s, got %s", token, link.Attrs().Inet6.Token)
+	}
+
+	if err := LinkSetIPv6StableSecret(link, net.ParseIP("2001:db8::1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetIPv6AddrGenMode(link, nl.IN6_ADDR_GEN_MODE_STABLE_PRIVACY); err != nil {
+		t.Fatal(err)
+	}
+	link, err = LinkByName("v0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.Attrs().Inet6.AddrGenMode != nl.IN6_ADDR_GEN_MODE_STABLE_PRIVACY {
+		t.Fatalf("expected addrgenmode stable_secret, got %d", link.Attrs().Inet6.AddrGenMode)
+	}
+}
+
+func TestHandleIPv6SetDefaultStableSecret(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	origns, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer origns.Close()
+	ns, err := netns.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+	if err := netns.Set(origns); err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewHandleAt(ns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	secret := net.ParseIP("2001:db8::2")
+	if err := h.IPv6SetDefaultStableSecret(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	const path = "/proc/sys/net/ipv6/conf/default/stable_secret"
+	var got []byte
+	if err := nl.RunInNetns(ns, origns, func() error {
+		got, err = os.ReadFile(path)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if ip := net.ParseIP(strings.TrimSpace(string(got))); !ip.Equal(secret) {
+		t.Fatalf("expected stable secret %s in the handle netns, got %q", secret, got)
+	}
+	// The secret is unset, and unreadable, in the netns of the caller.
+	if got, err := os.ReadFile(path); err == nil && net.ParseIP(strings.TrimSpace(string(got))).Equal(secret) {
+		t.Fatal("stable secret set in the caller netns")
+	}
+}
+
+func TestParseLinkInet6Truncated(t *testing.T) {
+	for _, attrType := range []int{unix.IFLA_INET6_FLAGS, unix.IFLA_INET6_ADDR_GEN_MODE, unix.IFLA_INET6_TOKEN, nl.IFLA_INET6_RA_MTU} {
+		if _, err := parseLinkInet6(nl.NewRtAttr(attrType, nil).Serialize()); err == nil {
+			t.Fatalf("expected an error for empty inet6 attribute %d", attrType)
+		}
+	}
+
+	token := net.ParseIP("::1:2:3:4")
+	data := nl.NewRtAttr(unix.IFLA_INET6_TOKEN, token).Serialize()
+	inet6, err := parseLinkInet6(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[unix.SizeofRtAttr] = 0xff
+	if !inet6.Token.Equal(token) {
+		t.Fatalf("token aliases the message buffer: %s", inet6.Token)
+	}
+}
+
+func TestLinkAddDelVcan(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	testLinkAddDel(t, &Vcan{LinkAttrs: LinkAttrs{Name: "foo"}})
+}
+
+func TestLinkAddDelVxcan(t *testing.T) {
+	minKernelRequired(t, 4, 12)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	if err := LinkAdd(&Vxcan{LinkAttrs: LinkAttrs{Name: "foo"}, PeerName: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := link.(*Vxcan); !ok {
+		t.Fatalf("unexpected link type %T", link)
+	}
+	peer, err := LinkByName("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := peer.(*Vxcan); !ok {
+		t.Fatalf("unexpected peer link type %T", peer)
+	}
+	if err := LinkDel(link); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LinkByName("bar"); err == nil {
+		t.Fatal("peer link not removed")
+	}
+}
+
+func TestParseCanData(t *testing.T) {
+	timing := &CanBitTiming{BitRate: 2000000, SamplePoint: 750, TimeQuanta: 25,
+		PropagationSegment: 7, PhaseSegment1: 7, PhaseSegment2: 5, SyncJumpWidth: 1, BitRatePreScaler: 1}
+	ctrlmode := make([]byte, 8)
+	native.PutUint32(ctrlmode, CAN_CTRLMODE_FD|CAN_CTRLMODE_LOOPBACK)
+	native.PutUint32(ctrlmode[4:], CAN_CTRLMODE_FD)
+	termConst := make([]byte, 4)
+	native.PutUint16(termConst, 0)
+	native.PutUint16(termConst[2:], 120)
+
+	data := []syscall.NetlinkRouteAttr{
+		{Attr: syscall.RtAttr{Type: nl.IFLA_CAN_DATA_BITTIMING}, Value: timing.serialize()},
+		{Attr: syscall.RtAttr{Type: nl.IFLA_CAN_CTRLMODE}, Value: ctrlmode},
+		{Attr: syscall.RtAttr{Type: nl.IFLA_CAN_TERMINATION}, Value: nl.Uint16Attr(120)},
+		{Attr: syscall.RtAttr{Type: nl.IFLA_CAN_TERMINATION_CONST}, Value: termConst},
+		{Attr: syscall.RtAttr{Type: nl.IFLA_CAN_BITRATE_MAX}, Value: nl.Uint32Attr(8000000)},
+	}
+	can := &Can{}
+	parseCanData(can, data)
+
+	if can.DataBitTiming == nil || *can.DataBitTiming != *timing {
+		t.Fatalf("unexpected data bit timing %+v", can.DataBitTiming)
+	}
+	if can.Flags&CAN_CTRLMODE_FD == 0 || can.Flags&CAN_CTRLMODE_LOOPBACK != 0 {
+		t.Fatalf("unexpected control mode flags %#x", can.Flags)
+	}
+	if can.Termination != 120 || !reflect.DeepEqual(can.TerminationConst, []uint16{0, 120}) {
+		t.Fatalf("unexpected termination %d %v", can.Termination, can.TerminationConst)
+	}
+	if can.BitRateMax != 8000000 {
+		t.Fatalf("unexpected maximum bit rate %d", can.BitRateMax)
+	}
+}
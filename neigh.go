@@ -3,6 +3,7 @@ package netlink
 import (
 	"fmt"
 	"net"
+	"net/netip"
 )
 
 // Neigh represents a link layer neighbor from netlink.
@@ -34,8 +35,24 @@ func (neigh *Neigh) String() string {
 	return fmt.Sprintf("%s %s", neigh.IP, neigh.HardwareAddr)
 }
 
+// IPAddr returns the IP address of the neighbor as a netip.Addr.
+func (neigh Neigh) IPAddr() netip.Addr {
+	return NetipAddr(neigh.IP)
+}
+
+// SetIPAddr sets the IP address of the neighbor.
+func (neigh *Neigh) SetIPAddr(addr netip.Addr) {
+	neigh.IP = netIP(addr)
+}
+
 // NeighUpdate is sent when a neighbor changes - type is RTM_NEWNEIGH or RTM_DELNEIGH.
 type NeighUpdate struct {
 	Type uint16
 	Neigh
+	// NetnsID is the id of the network namespace the update originates
+	// from, see NeighSubscribeOptions.ListenAllNsid, or -1.
+	NetnsID int
+	// Resync marks the updates delimiting a resync, which carry nothing
+	// else.
+	Resync SubscriptionResync
 }
@@ -1,8 +1,8 @@
 package netlink
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net"
 	"syscall"
 	"unsafe"
@@ -362,13 +362,13 @@ func NeighDeserialize(m []byte) (*Neigh, error) {
 // NeighSubscribe takes a chan down which notifications will be sent
 // when neighbors are added or deleted. Close the 'done' chan to stop subscription.
 func NeighSubscribe(ch chan<- NeighUpdate, done <-chan struct{}) error {
-	return neighSubscribeAt(netns.None(), netns.None(), ch, done, nil, false, 0, nil, false)
+	return neighSubscribeAt(netns.None(), netns.None(), ch, done, NeighSubscribeOptions{})
 }
 
 // NeighSubscribeAt works like NeighSubscribe plus it allows the caller
 // to choose the network namespace in which to subscribe (ns).
 func NeighSubscribeAt(ns netns.NsHandle, ch chan<- NeighUpdate, done <-chan struct{}) error {
-	return neighSubscribeAt(ns, netns.None(), ch, done, nil, false, 0, nil, false)
+	return neighSubscribeAt(ns, netns.None(), ch, done, NeighSubscribeOptions{})
 }
 
 // NeighSubscribeOptions contains a set of options to use with
@@ -382,6 +382,14 @@ type NeighSubscribeOptions struct {
 	ReceiveBufferSize      int
 	ReceiveBufferForceSize bool
 	ReceiveTimeout         *unix.Timeval
+	// Resync keeps the subscription going when the receive buffer overruns,
+	// as for LinkSubscribeOptions, dumping the existing neighbors again.
+	Resync bool
+	// NoENOBUFS sets NETLINK_NO_ENOBUFS on the socket.
+	NoENOBUFS bool
+	// ListenAllNsid sets NETLINK_LISTEN_ALL_NSID on the socket, as for
+	// LinkSubscribeOptions.
+	ListenAllNsid bool
 }
 
 // NeighSubscribeWithOptions work like NeighSubscribe but enable to
@@ -396,104 +404,53 @@ func NeighSubscribeWithOptions(ch chan<- NeighUpdate, done <-chan struct{}, opti
 		none := netns.None()
 		options.Namespace = &none
 	}
-	return neighSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback, options.ListExisting,
-		options.ReceiveBufferSize, options.ReceiveTimeout, options.ReceiveBufferForceSize)
+	return neighSubscribeAt(*options.Namespace, netns.None(), ch, done, options)
 }
 
-func neighSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- NeighUpdate, done <-chan struct{}, cberr func(error), listExisting bool,
-	rcvbuf int, rcvTimeout *unix.Timeval, rcvbufForce bool) error {
-	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_ROUTE, unix.RTNLGRP_NEIGH)
-	makeRequest := func(family int) error {
-		req := pkgHandle.newNetlinkRequest(unix.RTM_GETNEIGH, unix.NLM_F_DUMP)
-		ndmsg := &Ndmsg{Family: uint8(family)}
-		req.AddData(ndmsg)
-		if err := s.Send(req); err != nil {
-			return err
-		}
-		return nil
-	}
-	if err != nil {
+// NeighSubscribeContext works like NeighSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func NeighSubscribeContext(ctx context.Context, ch chan<- NeighUpdate, options NeighSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
-	if rcvTimeout != nil {
-		if err := s.SetReceiveTimeout(rcvTimeout); err != nil {
-			return err
-		}
-	}
-	if rcvbuf != 0 {
-		err = s.SetReceiveBufferSize(rcvbuf, rcvbufForce)
-		if err != nil {
-			return err
-		}
-	}
-	if done != nil {
-		go func() {
-			<-done
-			s.Close()
-		}()
-	}
-	if listExisting {
-		if err := makeRequest(unix.AF_UNSPEC); err != nil {
-			return err
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return NeighSubscribeWithOptions(ch, ctx.Done(), options)
+}
+
+func neighSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- NeighUpdate, done <-chan struct{}, options NeighSubscribeOptions) error {
+	dump := func(family int) func() *nl.NetlinkRequest {
+		return func() *nl.NetlinkRequest {
+			req := pkgHandle.newNetlinkRequest(unix.RTM_GETNEIGH, unix.NLM_F_DUMP)
+			req.AddData(&Ndmsg{Family: uint8(family)})
+			return req
 		}
-		// We have to wait for NLMSG_DONE before making AF_BRIDGE request
 	}
-	go func() {
-		defer close(ch)
-		for {
-			msgs, from, err := s.Receive()
+	sub := &subscription{
+		// The AF_BRIDGE neighbors are not part of the AF_UNSPEC dump.
+		dumps: []func() *nl.NetlinkRequest{dump(unix.AF_UNSPEC), dump(unix.AF_BRIDGE)},
+		handle: func(m syscall.NetlinkMessage, nsid int) error {
+			neigh, err := NeighDeserialize(m.Data)
 			if err != nil {
-				if cberr != nil {
-					cberr(err)
-				}
-				return
-			}
-			if from.Pid != nl.PidKernel {
-				if cberr != nil {
-					cberr(fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, nl.PidKernel))
-				}
-				continue
+				return err
 			}
-			for _, m := range msgs {
-				if m.Header.Flags&unix.NLM_F_DUMP_INTR != 0 && cberr != nil {
-					cberr(ErrDumpInterrupted)
-				}
-				if m.Header.Type == unix.NLMSG_DONE {
-					if listExisting {
-						// This will be called after handling AF_UNSPEC
-						// list request, we have to wait for NLMSG_DONE
-						// before making another request
-						if err := makeRequest(unix.AF_BRIDGE); err != nil {
-							if cberr != nil {
-								cberr(err)
-							}
-							return
-						}
-						listExisting = false
-					}
-					continue
-				}
-				if m.Header.Type == unix.NLMSG_ERROR {
-					nError := int32(native.Uint32(m.Data[0:4]))
-					if nError == 0 {
-						continue
-					}
-					if cberr != nil {
-						cberr(syscall.Errno(-nError))
-					}
-					return
-				}
-				neigh, err := NeighDeserialize(m.Data)
-				if err != nil {
-					if cberr != nil {
-						cberr(err)
-					}
-					return
-				}
-				ch <- NeighUpdate{Type: m.Header.Type, Neigh: *neigh}
-			}
-		}
-	}()
-
-	return nil
+			ch <- NeighUpdate{Type: m.Header.Type, Neigh: *neigh, NetnsID: nsid}
+			return nil
+		},
+		mark: func(r SubscriptionResync) {
+			ch <- NeighUpdate{NetnsID: nl.NETNSA_NSID_NOT_ASSIGNED, Resync: r}
+		},
+		close: func() { close(ch) },
+	}
+	return sub.start(newNs, curNs, done, subscribeOptions{
+		cberr:         options.ErrorCallback,
+		listExisting:  options.ListExisting,
+		rcvbuf:        options.ReceiveBufferSize,
+		rcvbufForce:   options.ReceiveBufferForceSize,
+		rcvTimeout:    options.ReceiveTimeout,
+		resync:        options.Resync,
+		noENOBUFS:     options.NoENOBUFS,
+		listenAllNsid: options.ListenAllNsid,
+	}, unix.RTNLGRP_NEIGH)
 }
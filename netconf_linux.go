@@ -0,0 +1,341 @@
+package netlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// Special interface indexes for Netconf, selecting the "all" and "default"
+// configuration instead of a specific device.
+const (
+	NETCONF_IFINDEX_ALL     = nl.NETCONFA_IFINDEX_ALL
+	NETCONF_IFINDEX_DEFAULT = nl.NETCONFA_IFINDEX_DEFAULT
+)
+
+// Netconf is the per device network configuration of an address family, as
+// reported by RTM_GETNETCONF. Values the family does not support are nil.
+type Netconf struct {
+	Family                   int
+	Ifindex                  int
+	Forwarding               *bool
+	RpFilter                 *int32
+	McForwarding             *bool
+	ProxyNeigh               *bool
+	IgnoreRoutesWithLinkdown *bool
+	Input                    *bool
+	BcForwarding             *bool
+}
+
+// NetconfUpdate is sent when the network configuration of a device changes.
+type NetconfUpdate struct {
+	Netconf
+	// Type is either RTM_NEWNETCONF or RTM_DELNETCONF.
+	Type uint16
+}
+
+// NetconfGet gets the network configuration of family for the device with
+// the given ifindex, or NETCONF_IFINDEX_ALL / NETCONF_IFINDEX_DEFAULT.
+// Equivalent to: `ip -f $family netconf show dev $link`
+func NetconfGet(family, ifindex int) (*Netconf, error) {
+	return pkgHandle.NetconfGet(family, ifindex)
+}
+
+// NetconfGet gets the network configuration of family for the device with
+// the given ifindex, or NETCONF_IFINDEX_ALL / NETCONF_IFINDEX_DEFAULT.
+// Equivalent to: `ip -f $family netconf show dev $link`
+func (h *Handle) NetconfGet(family, ifindex int) (*Netconf, error) {
+	req := h.newNetlinkRequest(unix.RTM_GETNETCONF, 0)
+	req.AddData(nl.NewNetconfMsg(family))
+	req.AddData(nl.NewRtAttr(nl.NETCONFA_IFINDEX, nl.Uint32Attr(uint32(int32(ifindex)))))
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWNETCONF)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, fmt.Errorf("no netconf returned for ifindex %d", ifindex)
+	}
+	return parseNetconf(msgs[0])
+}
+
+// NetconfList gets the network configuration of all devices for family,
+// which may be FAMILY_ALL, FAMILY_V4, FAMILY_V6 or FAMILY_MPLS.
+// Equivalent to: `ip -f $family netconf show`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func NetconfList(family int) ([]Netconf, error) {
+	return pkgHandle.NetconfList(family)
+}
+
+// NetconfList gets the network configuration of all devices for family,
+// which may be FAMILY_ALL, FAMILY_V4, FAMILY_V6 or FAMILY_MPLS.
+// Equivalent to: `ip -f $family netconf show`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) NetconfList(family int) ([]Netconf, error) {
+	req := h.newNetlinkRequest(unix.RTM_GETNETCONF, unix.NLM_F_DUMP)
+	req.AddData(nl.NewNetconfMsg(family))
+
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWNETCONF)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+
+	res := make([]Netconf, 0, len(msgs))
+	for _, m := range msgs {
+		conf, err := parseNetconf(m)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *conf)
+	}
+	return res, executeErr
+}
+
+func parseNetconf(m []byte) (*Netconf, error) {
+	msg := nl.DeserializeNetconfMsg(m)
+	conf := &Netconf{Family: int(msg.Family)}
+
+	attrs, err := nl.ParseRouteAttr(m[msg.Len():])
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range attrs {
+		if len(attr.Value) < 4 {
+			continue
+		}
+		value := int32(native.Uint32(attr.Value[0:4]))
+		enabled := value != 0
+		switch attr.Attr.Type {
+		case nl.NETCONFA_IFINDEX:
+			conf.Ifindex = int(value)
+		case nl.NETCONFA_FORWARDING:
+			conf.Forwarding = &enabled
+		case nl.NETCONFA_RP_FILTER:
+			conf.RpFilter = &value
+		case nl.NETCONFA_MC_FORWARDING:
+			conf.McForwarding = &enabled
+		case nl.NETCONFA_PROXY_NEIGH:
+			conf.ProxyNeigh = &enabled
+		case nl.NETCONFA_IGNORE_ROUTES_WITH_LINKDOWN:
+			conf.IgnoreRoutesWithLinkdown = &enabled
+		case nl.NETCONFA_INPUT:
+			conf.Input = &enabled
+		case nl.NETCONFA_BC_FORWARDING:
+			conf.BcForwarding = &enabled
+		}
+	}
+	return conf, nil
+}
+
+// NetconfSubscribe takes a chan down which notifications will be sent
+// when the IPv4, IPv6 or MPLS network configuration of a device changes.
+// Close the 'done' chan to stop subscription.
+func NetconfSubscribe(ch chan<- NetconfUpdate, done <-chan struct{}) error {
+	return netconfSubscribeAt(netns.None(), netns.None(), ch, done, nil, false)
+}
+
+// NetconfSubscribeOptions contains a set of options to use with
+// NetconfSubscribeWithOptions.
+type NetconfSubscribeOptions struct {
+	Namespace     *netns.NsHandle
+	ErrorCallback func(error)
+	ListExisting  bool
+}
+
+// NetconfSubscribeWithOptions work like NetconfSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace can be provided as well as an error callback.
+func NetconfSubscribeWithOptions(ch chan<- NetconfUpdate, done <-chan struct{}, options NetconfSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return netconfSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback, options.ListExisting)
+}
+
+// NetconfSubscribeContext works like NetconfSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func NetconfSubscribeContext(ctx context.Context, ch chan<- NetconfUpdate, options NetconfSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return NetconfSubscribeWithOptions(ch, ctx.Done(), options)
+}
+
+func netconfSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- NetconfUpdate, done <-chan struct{}, cberr func(error), listExisting bool) error {
+	sub := &subscription{
+		dumps: []func() *nl.NetlinkRequest{func() *nl.NetlinkRequest {
+			req := pkgHandle.newNetlinkRequest(unix.RTM_GETNETCONF, unix.NLM_F_DUMP)
+			req.AddData(nl.NewNetconfMsg(unix.AF_UNSPEC))
+			return req
+		}},
+		handle: func(m syscall.NetlinkMessage, nsid int) error {
+			msgType := m.Header.Type
+			if msgType != unix.RTM_NEWNETCONF && msgType != unix.RTM_DELNETCONF {
+				return fmt.Errorf("bad message type: %d", msgType)
+			}
+			conf, err := parseNetconf(m.Data)
+			if err != nil {
+				return fmt.Errorf("could not parse netconf: %v", err)
+			}
+			ch <- NetconfUpdate{Netconf: *conf, Type: msgType}
+			return nil
+		},
+		close: func() { close(ch) },
+	}
+	return sub.start(newNs, curNs, done, subscribeOptions{
+		cberr:        cberr,
+		listExisting: listExisting,
+	}, unix.RTNLGRP_IPV4_NETCONF, unix.RTNLGRP_IPV6_NETCONF, unix.RTNLGRP_MPLS_NETCONF)
+}
+
+// LinkSetIPv4DevConf sets IPv4 per device configuration values of the link,
+// keyed by nl.IPV4_DEVCONF_* id. Unlike the sysctl interface, this only
+// updates the values and does not trigger side effects such as the ones of
+// changing forwarding.
+// Equivalent to: `sysctl net.ipv4.conf.$link.$name=$value`
+func LinkSetIPv4DevConf(link Link, conf map[int]uint32) error {
+	return pkgHandle.LinkSetIPv4DevConf(link, conf)
+}
+
+// LinkSetIPv4DevConf sets IPv4 per device configuration values of the link,
+// keyed by nl.IPV4_DEVCONF_* id. Unlike the sysctl interface, this only
+// updates the values and does not trigger side effects such as the ones of
+// changing forwarding.
+// Equivalent to: `sysctl net.ipv4.conf.$link.$name=$value`
+func (h *Handle) LinkSetIPv4DevConf(link Link, conf map[int]uint32) error {
+	base := link.Attrs()
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
+	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
+
+	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
+	msg.Index = int32(base.Index)
+	req.AddData(msg)
+
+	spec := nl.NewRtAttr(unix.IFLA_AF_SPEC, nil)
+	inet := spec.AddRtAttr(unix.AF_INET, nil)
+	devconf := inet.AddRtAttr(nl.IFLA_INET_CONF, nil)
+	for id, value := range conf {
+		if id <= 0 || id > nl.IPV4_DEVCONF_MAX {
+			return fmt.Errorf("invalid IPv4 devconf id %d", id)
+		}
+		devconf.AddRtAttr(id, nl.Uint32Attr(value))
+	}
+	req.AddData(spec)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+// LinkGetIPv4DevConf gets the IPv4 per device configuration of the link,
+// keyed by nl.IPV4_DEVCONF_* id.
+// Equivalent to: `sysctl net.ipv4.conf.$link`
+func LinkGetIPv4DevConf(link Link) (map[int]uint32, error) {
+	return pkgHandle.LinkGetIPv4DevConf(link)
+}
+
+// LinkGetIPv4DevConf gets the IPv4 per device configuration of the link,
+// keyed by nl.IPV4_DEVCONF_* id.
+// Equivalent to: `sysctl net.ipv4.conf.$link`
+func (h *Handle) LinkGetIPv4DevConf(link Link) (map[int]uint32, error) {
+	data, err := h.linkGetAfSpec(link, unix.AF_INET, nl.IFLA_INET_CONF)
+	if err != nil {
+		return nil, err
+	}
+	// Unlike in RTM_SETLINK, the kernel reports the values as an array in
+	// which the value of id is found at index id-1.
+	conf := make(map[int]uint32, len(data)/4)
+	for i := 0; i+4 <= len(data); i += 4 {
+		conf[i/4+1] = native.Uint32(data[i : i+4])
+	}
+	return conf, nil
+}
+
+// LinkGetIPv6DevConf gets the IPv6 per device configuration of the link,
+// indexed by nl.DEVCONF_* id. The kernel does not accept these values in
+// RTM_SETLINK, so they are read-only over netlink.
+// Equivalent to: `sysctl net.ipv6.conf.$link`
+func LinkGetIPv6DevConf(link Link) ([]int32, error) {
+	return pkgHandle.LinkGetIPv6DevConf(link)
+}
+
+// LinkGetIPv6DevConf gets the IPv6 per device configuration of the link,
+// indexed by nl.DEVCONF_* id. The kernel does not accept these values in
+// RTM_SETLINK, so they are read-only over netlink.
+// Equivalent to: `sysctl net.ipv6.conf.$link`
+func (h *Handle) LinkGetIPv6DevConf(link Link) ([]int32, error) {
+	data, err := h.linkGetAfSpec(link, unix.AF_INET6, unix.IFLA_INET6_CONF)
+	if err != nil {
+		return nil, err
+	}
+	conf := make([]int32, len(data)/4)
+	for i := range conf {
+		conf[i] = int32(native.Uint32(data[i*4 : i*4+4]))
+	}
+	return conf, nil
+}
+
+// linkGetAfSpec returns the value of the attribute attrType nested in the
+// family section of the IFLA_AF_SPEC of the link.
+func (h *Handle) linkGetAfSpec(link Link, family, attrType int) ([]byte, error) {
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req := h.newNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_ACK)
+
+	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
+	msg.Index = int32(base.Index)
+	req.AddData(msg)
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK)
+	if err != nil {
+		if errors.Is(err, unix.ENODEV) {
+			return nil, LinkNotFoundError{fmt.Errorf("Link not found")}
+		}
+		return nil, err
+	}
+	for _, m := range msgs {
+		ifmsg := nl.DeserializeIfInfomsg(m)
+		attrs, err := nl.ParseRouteAttr(m[ifmsg.Len():])
+		if err != nil {
+			return nil, err
+		}
+		for _, attr := range attrs {
+			if attr.Attr.Type&nl.NLA_TYPE_MASK != unix.IFLA_AF_SPEC {
+				continue
+			}
+			afs, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, af := range afs {
+				if int(af.Attr.Type&nl.NLA_TYPE_MASK) != family {
+					continue
+				}
+				infos, err := nl.ParseRouteAttr(af.Value)
+				if err != nil {
+					return nil, err
+				}
+				for _, info := range infos {
+					if int(info.Attr.Type&nl.NLA_TYPE_MASK) == attrType {
+						return info.Value, nil
+					}
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("no address family %d configuration for link %s", family, base.Name)
+}
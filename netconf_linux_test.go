@@ -0,0 +1,130 @@
+package netlink
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+func TestNetconfGetAndSetIPv4DevConf(t *testing.T) {
+	minKernelRequired(t, 4, 4)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "v0"}, PeerName: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("v0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LinkSetIPv4DevConf(link, map[int]uint32{nl.IPV4_DEVCONF_RP_FILTER: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	conf, err := NetconfGet(FAMILY_V4, link.Attrs().Index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Ifindex != link.Attrs().Index {
+		t.Fatalf("expected netconf for index %d, got %d", link.Attrs().Index, conf.Ifindex)
+	}
+	if conf.RpFilter == nil || *conf.RpFilter != 2 {
+		t.Fatalf("expected rp_filter 2, got %v", conf.RpFilter)
+	}
+
+	devconf, err := LinkGetIPv4DevConf(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if devconf[nl.IPV4_DEVCONF_RP_FILTER] != 2 {
+		t.Fatalf("expected rp_filter 2 in devconf, got %d", devconf[nl.IPV4_DEVCONF_RP_FILTER])
+	}
+
+	if _, err := NetconfGet(FAMILY_V4, NETCONF_IFINDEX_ALL); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := NetconfList(FAMILY_V4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, c := range list {
+		if c.Ifindex == link.Attrs().Index {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("link not found in netconf dump")
+	}
+}
+
+func TestLinkGetIPv6DevConf(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "v0"}, PeerName: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("v0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf, err := LinkGetIPv6DevConf(link)
+	if err != nil {
+		t.Skipf("IPv6 not available: %v", err)
+	}
+	if len(conf) <= nl.DEVCONF_HOPLIMIT || conf[nl.DEVCONF_HOPLIMIT] == 0 {
+		t.Fatalf("expected a non zero hop limit, got %v", conf)
+	}
+}
+
+func TestNetconfSubscribeWithOptions(t *testing.T) {
+	minKernelRequired(t, 4, 4)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "v0"}, PeerName: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("v0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetIPv4DevConf(link, map[int]uint32{nl.IPV4_DEVCONF_RP_FILTER: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan NetconfUpdate)
+	done := make(chan struct{})
+	defer close(done)
+	ns, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+	if err := NetconfSubscribeWithOptions(ch, done, NetconfSubscribeOptions{
+		Namespace: &ns,
+		ErrorCallback: func(err error) {
+			t.Log(err)
+		},
+		ListExisting: true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case update := <-ch:
+			if update.Type == unix.RTM_NEWNETCONF && update.Family == FAMILY_V4 &&
+				update.Ifindex == link.Attrs().Index && update.RpFilter != nil && *update.RpFilter == 1 {
+				return
+			}
+		case <-timeout:
+			t.Fatal("existing netconf not listed")
+		}
+	}
+}
@@ -0,0 +1,712 @@
+package netlink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// Netdev contains the XDP and AF_XDP capabilities of a device. The feature
+// fields are bitmasks of the nl.NETDEV_XDP_ACT_*, nl.NETDEV_XDP_RX_METADATA_*
+// and nl.NETDEV_XSK_FLAGS_* flags.
+type Netdev struct {
+	Ifindex               int
+	XdpFeatures           uint64
+	XdpZcMaxSegs          uint32
+	XdpRxMetadataFeatures uint64
+	XskFeatures           uint64
+}
+
+// NetdevQueue is a queue of a device and the NAPI instance serving it.
+type NetdevQueue struct {
+	Ifindex int
+	ID      uint32
+	Type    NetdevQueueType
+	NapiID  uint32
+	Dmabuf  uint32
+	Xsk     bool // an AF_XDP socket is bound to the queue
+}
+
+// NetdevNapi is a NAPI instance of a device.
+type NetdevNapi struct {
+	Ifindex           int
+	ID                uint32
+	Irq               uint32
+	Pid               uint32 // PID of the NAPI thread, if threaded
+	DeferHardIrqs     uint32
+	GroFlushTimeout   uint64 // in ns
+	IrqSuspendTimeout uint64 // in ns
+	Threaded          bool
+}
+
+// NetdevPagePool is a page pool of a device. Ifindex is zero once the device
+// has been removed, at which point DetachTime holds when it was.
+type NetdevPagePool struct {
+	ID          uint64
+	Ifindex     int
+	NapiID      uint64
+	Inflight    uint64 // number of pages in use
+	InflightMem uint64 // bytes in use
+	DetachTime  uint64 // in seconds since boot
+	Dmabuf      uint32
+}
+
+// NetdevPagePoolStat contains the allocation and recycling counters of a
+// page pool.
+type NetdevPagePoolStat struct {
+	ID                    uint64
+	Ifindex               int
+	AllocFast             uint64
+	AllocSlow             uint64
+	AllocSlowHighOrder    uint64
+	AllocEmpty            uint64
+	AllocRefill           uint64
+	AllocWaive            uint64
+	RecycleCached         uint64
+	RecycleCacheFull      uint64
+	RecycleRing           uint64
+	RecycleRingFull       uint64
+	RecycleReleasedRefcnt uint64
+}
+
+// NetdevUpdate is sent when a device or a page pool is added, changed or
+// deleted. Command is one of the nl.NETDEV_CMD_*_NTF commands, and either
+// Dev or PagePool is set accordingly.
+type NetdevUpdate struct {
+	Command  uint8
+	Dev      *Netdev
+	PagePool *NetdevPagePool
+}
+
+// NetdevQueueType is the type of a queue of a device.
+type NetdevQueueType uint32
+
+const (
+	NETDEV_QUEUE_TYPE_RX NetdevQueueType = nl.NETDEV_QUEUE_TYPE_RX
+	NETDEV_QUEUE_TYPE_TX NetdevQueueType = nl.NETDEV_QUEUE_TYPE_TX
+)
+
+func (t NetdevQueueType) String() string {
+	switch t {
+	case NETDEV_QUEUE_TYPE_RX:
+		return "rx"
+	case NETDEV_QUEUE_TYPE_TX:
+		return "tx"
+	}
+	return fmt.Sprintf("unknown(%d)", uint32(t))
+}
+
+// NetdevQstats contains the queue statistics of a device, either summed over
+// all its queues or, when PerQueue is set, for the queue identified by
+// QueueType and QueueID. Counters not maintained by the driver are zero.
+type NetdevQstats struct {
+	Ifindex   int
+	PerQueue  bool
+	QueueType NetdevQueueType
+	QueueID   uint32
+
+	RxPackets          uint64
+	RxBytes            uint64
+	TxPackets          uint64
+	TxBytes            uint64
+	RxAllocFail        uint64
+	RxHwDrops          uint64
+	RxHwDropOverruns   uint64
+	RxCsumComplete     uint64
+	RxCsumUnnecessary  uint64
+	RxCsumNone         uint64
+	RxCsumBad          uint64
+	RxHwGroPackets     uint64
+	RxHwGroBytes       uint64
+	RxHwGroWirePackets uint64
+	RxHwGroWireBytes   uint64
+	RxHwDropRatelimits uint64
+	TxHwDrops          uint64
+	TxHwDropErrors     uint64
+	TxCsumNone         uint64
+	TxNeedsCsum        uint64
+	TxHwGsoPackets     uint64
+	TxHwGsoBytes       uint64
+	TxHwGsoWirePackets uint64
+	TxHwGsoWireBytes   uint64
+	TxHwDropRatelimits uint64
+	TxStop             uint64
+	TxWake             uint64
+}
+
+// NetdevQstatsList returns the queue statistics of the link, or of all the
+// devices supporting them when link is nil. When perQueue is set, one entry
+// is returned per queue instead of one per device.
+// Equivalent to: `ynl --family netdev --dump qstats-get`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func NetdevQstatsList(link Link, perQueue bool) ([]NetdevQstats, error) {
+	return pkgHandle.NetdevQstatsList(link, perQueue)
+}
+
+// NetdevQstatsList returns the queue statistics of the link, or of all the
+// devices supporting them when link is nil. When perQueue is set, one entry
+// is returned per queue instead of one per device.
+// Equivalent to: `ynl --family netdev --dump qstats-get`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) NetdevQstatsList(link Link, perQueue bool) ([]NetdevQstats, error) {
+	req, err := h.newNetdevRequest(nl.NETDEV_CMD_QSTATS_GET, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		base := link.Attrs()
+		h.ensureIndex(base)
+		req.AddData(nl.NewRtAttr(nl.NETDEV_A_QSTATS_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	}
+	if perQueue {
+		req.AddData(nl.NewRtAttr(nl.NETDEV_A_QSTATS_SCOPE, nl.Uint32Attr(nl.NETDEV_QSTATS_SCOPE_QUEUE)))
+	}
+
+	msgs, executeErr := req.Execute(unix.NETLINK_GENERIC, 0)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	var res []NetdevQstats
+	for _, m := range msgs {
+		stats, err := parseNetdevQstats(m)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, stats)
+	}
+	return res, executeErr
+}
+
+// NetdevGet returns the XDP and AF_XDP capabilities of the link.
+// Equivalent to: `ynl --family netdev --do dev-get --json '{"ifindex": $index}'`
+func NetdevGet(link Link) (*Netdev, error) {
+	return pkgHandle.NetdevGet(link)
+}
+
+// NetdevGet returns the XDP and AF_XDP capabilities of the link.
+// Equivalent to: `ynl --family netdev --do dev-get --json '{"ifindex": $index}'`
+func (h *Handle) NetdevGet(link Link) (*Netdev, error) {
+	req, err := h.newNetdevRequest(nl.NETDEV_CMD_DEV_GET, unix.NLM_F_ACK)
+	if err != nil {
+		return nil, err
+	}
+	base := link.Attrs()
+	h.ensureIndex(base)
+	req.AddData(nl.NewRtAttr(nl.NETDEV_A_DEV_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, errors.New("no reply for netdev dev-get")
+	}
+	return parseNetdev(msgs[0])
+}
+
+// NetdevList returns the XDP and AF_XDP capabilities of all the devices.
+// Equivalent to: `ynl --family netdev --dump dev-get`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func NetdevList() ([]Netdev, error) {
+	return pkgHandle.NetdevList()
+}
+
+// NetdevList returns the XDP and AF_XDP capabilities of all the devices.
+// Equivalent to: `ynl --family netdev --dump dev-get`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) NetdevList() ([]Netdev, error) {
+	req, err := h.newNetdevRequest(nl.NETDEV_CMD_DEV_GET, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	msgs, executeErr := req.Execute(unix.NETLINK_GENERIC, 0)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	var res []Netdev
+	for _, m := range msgs {
+		dev, err := parseNetdev(m)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *dev)
+	}
+	return res, executeErr
+}
+
+// NetdevQueueList returns the queues of the link, or of all the devices
+// when link is nil.
+// Equivalent to: `ynl --family netdev --dump queue-get --json '{"ifindex": $index}'`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func NetdevQueueList(link Link) ([]NetdevQueue, error) {
+	return pkgHandle.NetdevQueueList(link)
+}
+
+// NetdevQueueList returns the queues of the link, or of all the devices
+// when link is nil.
+// Equivalent to: `ynl --family netdev --dump queue-get --json '{"ifindex": $index}'`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) NetdevQueueList(link Link) ([]NetdevQueue, error) {
+	req, err := h.newNetdevRequest(nl.NETDEV_CMD_QUEUE_GET, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		base := link.Attrs()
+		h.ensureIndex(base)
+		req.AddData(nl.NewRtAttr(nl.NETDEV_A_QUEUE_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	}
+	msgs, executeErr := req.Execute(unix.NETLINK_GENERIC, 0)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	var res []NetdevQueue
+	for _, m := range msgs {
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+		var queue NetdevQueue
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NETDEV_A_QUEUE_ID:
+				queue.ID = native.Uint32(attr.Value)
+			case nl.NETDEV_A_QUEUE_IFINDEX:
+				queue.Ifindex = int(native.Uint32(attr.Value))
+			case nl.NETDEV_A_QUEUE_TYPE:
+				queue.Type = NetdevQueueType(native.Uint32(attr.Value))
+			case nl.NETDEV_A_QUEUE_NAPI_ID:
+				queue.NapiID = native.Uint32(attr.Value)
+			case nl.NETDEV_A_QUEUE_DMABUF:
+				queue.Dmabuf = native.Uint32(attr.Value)
+			case nl.NETDEV_A_QUEUE_XSK:
+				queue.Xsk = true
+			}
+		}
+		res = append(res, queue)
+	}
+	return res, executeErr
+}
+
+// NetdevNapiList returns the NAPI instances of the link, or of all the
+// devices when link is nil.
+// Equivalent to: `ynl --family netdev --dump napi-get --json '{"ifindex": $index}'`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func NetdevNapiList(link Link) ([]NetdevNapi, error) {
+	return pkgHandle.NetdevNapiList(link)
+}
+
+// NetdevNapiList returns the NAPI instances of the link, or of all the
+// devices when link is nil.
+// Equivalent to: `ynl --family netdev --dump napi-get --json '{"ifindex": $index}'`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) NetdevNapiList(link Link) ([]NetdevNapi, error) {
+	req, err := h.newNetdevRequest(nl.NETDEV_CMD_NAPI_GET, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		base := link.Attrs()
+		h.ensureIndex(base)
+		req.AddData(nl.NewRtAttr(nl.NETDEV_A_NAPI_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	}
+	msgs, executeErr := req.Execute(unix.NETLINK_GENERIC, 0)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	var res []NetdevNapi
+	for _, m := range msgs {
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+		var napi NetdevNapi
+		for _, attr := range attrs {
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NETDEV_A_NAPI_IFINDEX:
+				napi.Ifindex = int(native.Uint32(attr.Value))
+			case nl.NETDEV_A_NAPI_ID:
+				napi.ID = native.Uint32(attr.Value)
+			case nl.NETDEV_A_NAPI_IRQ:
+				napi.Irq = native.Uint32(attr.Value)
+			case nl.NETDEV_A_NAPI_PID:
+				napi.Pid = native.Uint32(attr.Value)
+			case nl.NETDEV_A_NAPI_DEFER_HARD_IRQS:
+				napi.DeferHardIrqs = native.Uint32(attr.Value)
+			case nl.NETDEV_A_NAPI_GRO_FLUSH_TIMEOUT:
+				napi.GroFlushTimeout = netdevUint(attr.Value)
+			case nl.NETDEV_A_NAPI_IRQ_SUSPEND_TIMEOUT:
+				napi.IrqSuspendTimeout = netdevUint(attr.Value)
+			case nl.NETDEV_A_NAPI_THREADED:
+				napi.Threaded = native.Uint32(attr.Value) == nl.NETDEV_NAPI_THREADED_ENABLED
+			}
+		}
+		res = append(res, napi)
+	}
+	return res, executeErr
+}
+
+// NetdevPagePoolList returns the page pools of the link, or of all the
+// devices when link is nil.
+// Equivalent to: `ynl --family netdev --dump page-pool-get`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func NetdevPagePoolList(link Link) ([]NetdevPagePool, error) {
+	return pkgHandle.NetdevPagePoolList(link)
+}
+
+// NetdevPagePoolList returns the page pools of the link, or of all the
+// devices when link is nil.
+// Equivalent to: `ynl --family netdev --dump page-pool-get`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) NetdevPagePoolList(link Link) ([]NetdevPagePool, error) {
+	req, err := h.newNetdevRequest(nl.NETDEV_CMD_PAGE_POOL_GET, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	ifindex := 0
+	if link != nil {
+		base := link.Attrs()
+		h.ensureIndex(base)
+		ifindex = base.Index
+	}
+	msgs, executeErr := req.Execute(unix.NETLINK_GENERIC, 0)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	var res []NetdevPagePool
+	for _, m := range msgs {
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+		pool := parseNetdevPagePool(attrs)
+		// The kernel does not filter page pool dumps by device
+		if ifindex != 0 && pool.Ifindex != ifindex {
+			continue
+		}
+		res = append(res, *pool)
+	}
+	return res, executeErr
+}
+
+// NetdevPagePoolStats returns the counters of the page pools of the link,
+// or of all the devices when link is nil.
+// Equivalent to: `ynl --family netdev --dump page-pool-stats-get`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func NetdevPagePoolStats(link Link) ([]NetdevPagePoolStat, error) {
+	return pkgHandle.NetdevPagePoolStats(link)
+}
+
+// NetdevPagePoolStats returns the counters of the page pools of the link,
+// or of all the devices when link is nil.
+// Equivalent to: `ynl --family netdev --dump page-pool-stats-get`
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) NetdevPagePoolStats(link Link) ([]NetdevPagePoolStat, error) {
+	req, err := h.newNetdevRequest(nl.NETDEV_CMD_PAGE_POOL_STATS_GET, unix.NLM_F_DUMP)
+	if err != nil {
+		return nil, err
+	}
+	ifindex := 0
+	if link != nil {
+		base := link.Attrs()
+		h.ensureIndex(base)
+		ifindex = base.Index
+	}
+	msgs, executeErr := req.Execute(unix.NETLINK_GENERIC, 0)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	var res []NetdevPagePoolStat
+	for _, m := range msgs {
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+		var stat NetdevPagePoolStat
+		for _, attr := range attrs {
+			v := netdevUint(attr.Value)
+			switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+			case nl.NETDEV_A_PAGE_POOL_STATS_INFO:
+				info, err := nl.ParseRouteAttr(attr.Value)
+				if err != nil {
+					return nil, err
+				}
+				pool := parseNetdevPagePool(info)
+				stat.ID = pool.ID
+				stat.Ifindex = pool.Ifindex
+			case nl.NETDEV_A_PAGE_POOL_STATS_ALLOC_FAST:
+				stat.AllocFast = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_ALLOC_SLOW:
+				stat.AllocSlow = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_ALLOC_SLOW_HIGH_ORDER:
+				stat.AllocSlowHighOrder = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_ALLOC_EMPTY:
+				stat.AllocEmpty = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_ALLOC_REFILL:
+				stat.AllocRefill = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_ALLOC_WAIVE:
+				stat.AllocWaive = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_RECYCLE_CACHED:
+				stat.RecycleCached = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_RECYCLE_CACHE_FULL:
+				stat.RecycleCacheFull = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_RECYCLE_RING:
+				stat.RecycleRing = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_RECYCLE_RING_FULL:
+				stat.RecycleRingFull = v
+			case nl.NETDEV_A_PAGE_POOL_STATS_RECYCLE_RELEASED_REFCNT:
+				stat.RecycleReleasedRefcnt = v
+			}
+		}
+		if ifindex != 0 && stat.Ifindex != ifindex {
+			continue
+		}
+		res = append(res, stat)
+	}
+	return res, executeErr
+}
+
+func (h *Handle) newNetdevRequest(cmd uint8, flags int) (*nl.NetlinkRequest, error) {
+	f, err := h.GenlFamilyGet(nl.NETDEV_FAMILY_NAME)
+	if err != nil {
+		return nil, err
+	}
+	msg := &nl.Genlmsg{
+		Command: cmd,
+		Version: nl.NETDEV_FAMILY_VERSION,
+	}
+	req := h.newNetlinkRequest(int(f.ID), flags)
+	req.AddData(msg)
+	return req, nil
+}
+
+// netdevUint decodes a variable width unsigned integer, which the kernel
+// sends as a u32 when the value fits and as a u64 otherwise.
+func netdevUint(b []byte) uint64 {
+	if len(b) >= 8 {
+		return native.Uint64(b)
+	}
+	if len(b) >= 4 {
+		return uint64(native.Uint32(b))
+	}
+	return 0
+}
+
+func parseNetdevQstats(m []byte) (NetdevQstats, error) {
+	var stats NetdevQstats
+	attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+	if err != nil {
+		return stats, err
+	}
+	for _, attr := range attrs {
+		v := netdevUint(attr.Value)
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NETDEV_A_QSTATS_IFINDEX:
+			stats.Ifindex = int(v)
+		case nl.NETDEV_A_QSTATS_QUEUE_TYPE:
+			stats.PerQueue = true
+			stats.QueueType = NetdevQueueType(v)
+		case nl.NETDEV_A_QSTATS_QUEUE_ID:
+			stats.PerQueue = true
+			stats.QueueID = uint32(v)
+		case nl.NETDEV_A_QSTATS_RX_PACKETS:
+			stats.RxPackets = v
+		case nl.NETDEV_A_QSTATS_RX_BYTES:
+			stats.RxBytes = v
+		case nl.NETDEV_A_QSTATS_TX_PACKETS:
+			stats.TxPackets = v
+		case nl.NETDEV_A_QSTATS_TX_BYTES:
+			stats.TxBytes = v
+		case nl.NETDEV_A_QSTATS_RX_ALLOC_FAIL:
+			stats.RxAllocFail = v
+		case nl.NETDEV_A_QSTATS_RX_HW_DROPS:
+			stats.RxHwDrops = v
+		case nl.NETDEV_A_QSTATS_RX_HW_DROP_OVERRUNS:
+			stats.RxHwDropOverruns = v
+		case nl.NETDEV_A_QSTATS_RX_CSUM_COMPLETE:
+			stats.RxCsumComplete = v
+		case nl.NETDEV_A_QSTATS_RX_CSUM_UNNECESSARY:
+			stats.RxCsumUnnecessary = v
+		case nl.NETDEV_A_QSTATS_RX_CSUM_NONE:
+			stats.RxCsumNone = v
+		case nl.NETDEV_A_QSTATS_RX_CSUM_BAD:
+			stats.RxCsumBad = v
+		case nl.NETDEV_A_QSTATS_RX_HW_GRO_PACKETS:
+			stats.RxHwGroPackets = v
+		case nl.NETDEV_A_QSTATS_RX_HW_GRO_BYTES:
+			stats.RxHwGroBytes = v
+		case nl.NETDEV_A_QSTATS_RX_HW_GRO_WIRE_PACKETS:
+			stats.RxHwGroWirePackets = v
+		case nl.NETDEV_A_QSTATS_RX_HW_GRO_WIRE_BYTES:
+			stats.RxHwGroWireBytes = v
+		case nl.NETDEV_A_QSTATS_RX_HW_DROP_RATELIMITS:
+			stats.RxHwDropRatelimits = v
+		case nl.NETDEV_A_QSTATS_TX_HW_DROPS:
+			stats.TxHwDrops = v
+		case nl.NETDEV_A_QSTATS_TX_HW_DROP_ERRORS:
+			stats.TxHwDropErrors = v
+		case nl.NETDEV_A_QSTATS_TX_CSUM_NONE:
+			stats.TxCsumNone = v
+		case nl.NETDEV_A_QSTATS_TX_NEEDS_CSUM:
+			stats.TxNeedsCsum = v
+		case nl.NETDEV_A_QSTATS_TX_HW_GSO_PACKETS:
+			stats.TxHwGsoPackets = v
+		case nl.NETDEV_A_QSTATS_TX_HW_GSO_BYTES:
+			stats.TxHwGsoBytes = v
+		case nl.NETDEV_A_QSTATS_TX_HW_GSO_WIRE_PACKETS:
+			stats.TxHwGsoWirePackets = v
+		case nl.NETDEV_A_QSTATS_TX_HW_GSO_WIRE_BYTES:
+			stats.TxHwGsoWireBytes = v
+		case nl.NETDEV_A_QSTATS_TX_HW_DROP_RATELIMITS:
+			stats.TxHwDropRatelimits = v
+		case nl.NETDEV_A_QSTATS_TX_STOP:
+			stats.TxStop = v
+		case nl.NETDEV_A_QSTATS_TX_WAKE:
+			stats.TxWake = v
+		}
+	}
+	return stats, nil
+}
+
+func parseNetdev(m []byte) (*Netdev, error) {
+	attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+	if err != nil {
+		return nil, err
+	}
+	dev := &Netdev{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NETDEV_A_DEV_IFINDEX:
+			dev.Ifindex = int(native.Uint32(attr.Value))
+		case nl.NETDEV_A_DEV_XDP_FEATURES:
+			dev.XdpFeatures = netdevUint(attr.Value)
+		case nl.NETDEV_A_DEV_XDP_ZC_MAX_SEGS:
+			dev.XdpZcMaxSegs = native.Uint32(attr.Value)
+		case nl.NETDEV_A_DEV_XDP_RX_METADATA_FEATURES:
+			dev.XdpRxMetadataFeatures = netdevUint(attr.Value)
+		case nl.NETDEV_A_DEV_XSK_FEATURES:
+			dev.XskFeatures = netdevUint(attr.Value)
+		}
+	}
+	return dev, nil
+}
+
+func parseNetdevPagePool(attrs []syscall.NetlinkRouteAttr) *NetdevPagePool {
+	pool := &NetdevPagePool{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.NETDEV_A_PAGE_POOL_ID:
+			pool.ID = netdevUint(attr.Value)
+		case nl.NETDEV_A_PAGE_POOL_IFINDEX:
+			pool.Ifindex = int(native.Uint32(attr.Value))
+		case nl.NETDEV_A_PAGE_POOL_NAPI_ID:
+			pool.NapiID = netdevUint(attr.Value)
+		case nl.NETDEV_A_PAGE_POOL_INFLIGHT:
+			pool.Inflight = netdevUint(attr.Value)
+		case nl.NETDEV_A_PAGE_POOL_INFLIGHT_MEM:
+			pool.InflightMem = netdevUint(attr.Value)
+		case nl.NETDEV_A_PAGE_POOL_DETACH_TIME:
+			pool.DetachTime = netdevUint(attr.Value)
+		case nl.NETDEV_A_PAGE_POOL_DMABUF:
+			pool.Dmabuf = native.Uint32(attr.Value)
+		}
+	}
+	return pool
+}
+
+// NetdevSubscribe takes a chan down which notifications will be sent
+// when devices or page pools are added, changed or deleted. Close the
+// 'done' chan to stop subscription.
+func NetdevSubscribe(ch chan<- NetdevUpdate, done <-chan struct{}) error {
+	return netdevSubscribeAt(netns.None(), netns.None(), ch, done, nil)
+}
+
+// NetdevSubscribeOptions contains a set of options to use with
+// NetdevSubscribeWithOptions.
+type NetdevSubscribeOptions struct {
+	Namespace     *netns.NsHandle
+	ErrorCallback func(error)
+}
+
+// NetdevSubscribeWithOptions work like NetdevSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace can be provided as well as an error callback.
+func NetdevSubscribeWithOptions(ch chan<- NetdevUpdate, done <-chan struct{}, options NetdevSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return netdevSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback)
+}
+
+// NetdevSubscribeContext works like NetdevSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func NetdevSubscribeContext(ctx context.Context, ch chan<- NetdevUpdate, options NetdevSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return NetdevSubscribeWithOptions(ch, ctx.Done(), options)
+}
+
+func netdevSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- NetdevUpdate, done <-chan struct{}, cberr func(error)) error {
+	// The page-pool group only exists since Linux 6.8
+	groups := []string{nl.NETDEV_MCGRP_MGMT, nl.NETDEV_MCGRP_PAGE_POOL}
+	return genlSubscribeGroups(newNs, curNs, nl.NETDEV_FAMILY_NAME, groups, done, cberr,
+		func(f *GenlFamily, m syscall.NetlinkMessage) error {
+			if len(m.Data) < nl.SizeofGenlmsg {
+				return nil
+			}
+			var err error
+			update := NetdevUpdate{Command: m.Data[0]}
+			switch update.Command {
+			case nl.NETDEV_CMD_DEV_ADD_NTF, nl.NETDEV_CMD_DEV_DEL_NTF, nl.NETDEV_CMD_DEV_CHANGE_NTF:
+				update.Dev, err = parseNetdev(m.Data)
+			case nl.NETDEV_CMD_PAGE_POOL_ADD_NTF, nl.NETDEV_CMD_PAGE_POOL_DEL_NTF, nl.NETDEV_CMD_PAGE_POOL_CHANGE_NTF:
+				var attrs []syscall.NetlinkRouteAttr
+				attrs, err = nl.ParseRouteAttr(m.Data[nl.SizeofGenlmsg:])
+				if err == nil {
+					update.PagePool = parseNetdevPagePool(attrs)
+				}
+			default:
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("could not parse netdev message: %v", err)
+			}
+			ch <- update
+			return nil
+		},
+		func() { close(ch) })
+}
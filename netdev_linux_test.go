@@ -0,0 +1,161 @@
+package netlink
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+func TestNetdevQstatsList(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	if _, err := GenlFamilyGet(nl.NETDEV_FAMILY_NAME); err != nil {
+		t.Skipf("netdev genl family not supported: %v", err)
+	}
+
+	stats, err := NetdevQstatsList(nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range stats {
+		if s.PerQueue {
+			t.Fatalf("unexpected per-queue stats: %+v", s)
+		}
+	}
+	stats, err = NetdevQstatsList(nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range stats {
+		if !s.PerQueue {
+			t.Fatalf("expected per-queue stats: %+v", s)
+		}
+	}
+
+	// netdevsim is the only virtual device reporting queue statistics
+	link := setUpEthtoolNetdevsim(t)
+	index := link.Attrs().Index
+	stats, err = NetdevQstatsList(link, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || stats[0].Ifindex != index || stats[0].PerQueue {
+		t.Fatalf("expected the device stats of %d, got %+v", index, stats)
+	}
+	device := stats[0]
+
+	queues, err := NetdevQstatsList(link, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queues) == 0 {
+		t.Fatal("no per-queue stats")
+	}
+	seen := make(map[NetdevQueueType]map[uint32]bool)
+	var rxPackets, txPackets uint64
+	for _, q := range queues {
+		if q.Ifindex != index || !q.PerQueue {
+			t.Fatalf("expected per-queue stats of %d, got %+v", index, q)
+		}
+		if q.QueueType != NETDEV_QUEUE_TYPE_RX && q.QueueType != NETDEV_QUEUE_TYPE_TX {
+			t.Fatalf("unexpected queue type: %+v", q)
+		}
+		if seen[q.QueueType] == nil {
+			seen[q.QueueType] = make(map[uint32]bool)
+		}
+		if seen[q.QueueType][q.QueueID] {
+			t.Fatalf("duplicate %s queue %d", q.QueueType, q.QueueID)
+		}
+		seen[q.QueueType][q.QueueID] = true
+		rxPackets += q.RxPackets
+		txPackets += q.TxPackets
+	}
+	// The device stats add the ones of the queues to the ones kept outside
+	// of them
+	if device.RxPackets < rxPackets || device.TxPackets < txPackets {
+		t.Fatalf("device stats %+v lower than the sum of the queue ones: rx %d, tx %d", device, rxPackets, txPackets)
+	}
+}
+
+func TestNetdevGetAndSubscribe(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	if _, err := GenlFamilyGet(nl.NETDEV_FAMILY_NAME); err != nil {
+		t.Skipf("netdev genl family not supported: %v", err)
+	}
+
+	ch := make(chan NetdevUpdate, 16)
+	done := make(chan struct{})
+	defer close(done)
+	if err := NetdevSubscribe(ch, done); err != nil {
+		t.Fatal(err)
+	}
+
+	veth := &Veth{LinkAttrs: LinkAttrs{Name: "foo"}, PeerName: "bar"}
+	if err := LinkAdd(veth); err != nil {
+		t.Fatal(err)
+	}
+	link, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dev, err := NetdevGet(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev.Ifindex != link.Attrs().Index {
+		t.Fatalf("expected ifindex %d, got %d", link.Attrs().Index, dev.Ifindex)
+	}
+	if dev.XdpFeatures&nl.NETDEV_XDP_ACT_BASIC == 0 {
+		t.Fatalf("expected basic XDP support on veth, got %#x", dev.XdpFeatures)
+	}
+
+	devs, err := NetdevList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, d := range devs {
+		if d.Ifindex == link.Attrs().Index {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("veth not found in netdev list")
+	}
+
+	queues, err := NetdevQueueList(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range queues {
+		if q.Ifindex != link.Attrs().Index {
+			t.Fatalf("unexpected queue of another device: %+v", q)
+		}
+	}
+	if _, err := NetdevNapiList(link); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NetdevPagePoolList(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	timeout := time.After(10 * time.Second)
+	for found = false; !found; {
+		select {
+		case update := <-ch:
+			if update.Command == nl.NETDEV_CMD_DEV_ADD_NTF && update.Dev != nil && update.Dev.Ifindex == link.Attrs().Index {
+				found = true
+			}
+		case <-timeout:
+			t.Fatal("netdev add notification not received")
+		}
+	}
+
+	// Page pool stats require CONFIG_PAGE_POOL_STATS
+	if _, err := NetdevPagePoolStats(nil); err != nil && !errors.Is(err, unix.EOPNOTSUPP) {
+		t.Fatal(err)
+	}
+}
@@ -11,6 +11,7 @@ package netlink
 import (
 	"errors"
 	"net"
+	"net/netip"
 )
 
 var (
@@ -38,3 +39,54 @@ func NewIPNet(ip net.IP) *net.IPNet {
 	}
 	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
 }
+
+// NetipAddr converts ip to a netip.Addr without allocating. IPv4 addresses
+// in their 16-byte form are unmapped. It returns the zero Addr if ip is
+// nil or invalid.
+func NetipAddr(ip net.IP) netip.Addr {
+	if ip4 := ip.To4(); ip4 != nil {
+		return netip.AddrFrom4([4]byte(ip4))
+	}
+	addr, _ := netip.AddrFromSlice(ip)
+	return addr
+}
+
+// NetipPrefix converts ipNet to a netip.Prefix without allocating. Unlike
+// netip.Prefix.Masked, the host bits of the address are kept, as netlink
+// addresses carry them. It returns the zero Prefix if ipNet is nil or its
+// mask is not canonical.
+func NetipPrefix(ipNet *net.IPNet) netip.Prefix {
+	if ipNet == nil {
+		return netip.Prefix{}
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits == 0 {
+		return netip.Prefix{}
+	}
+	addr := NetipAddr(ipNet.IP)
+	if addr.Is4() && bits == 8*net.IPv6len {
+		ones -= 8 * (net.IPv6len - net.IPv4len)
+	}
+	return netip.PrefixFrom(addr, ones)
+}
+
+// NewIPNetFromPrefix converts prefix to a net.IPNet. It returns nil if
+// prefix is invalid.
+func NewIPNetFromPrefix(prefix netip.Prefix) *net.IPNet {
+	if !prefix.IsValid() {
+		return nil
+	}
+	addr := prefix.Addr()
+	return &net.IPNet{
+		IP:   addr.AsSlice(),
+		Mask: net.CIDRMask(prefix.Bits(), addr.BitLen()),
+	}
+}
+
+// netIP converts addr to a net.IP. It returns nil if addr is invalid.
+func netIP(addr netip.Addr) net.IP {
+	if !addr.IsValid() {
+		return nil
+	}
+	return addr.AsSlice()
+}
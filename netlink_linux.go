@@ -1,6 +1,10 @@
 package netlink
 
-import "github.com/vishvananda/netlink/nl"
+import (
+	"context"
+
+	"github.com/vishvananda/netlink/nl"
+)
 
 // Family type definitions
 const (
@@ -12,3 +16,18 @@ const (
 
 // ErrDumpInterrupted is an alias for [nl.ErrDumpInterrupted].
 var ErrDumpInterrupted = nl.ErrDumpInterrupted
+
+// contextErrorCallback wraps the error callback of a subscription that lasts
+// until ctx is done, so that the receive failure caused by closing its socket
+// is reported as ctx.Err().
+func contextErrorCallback(ctx context.Context, cberr func(error)) func(error) {
+	if cberr == nil {
+		return nil
+	}
+	return func(err error) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		cberr(err)
+	}
+}
@@ -149,6 +149,26 @@ func LinkSetIP6AddrGenMode(link Link, mode int) error {
 	return ErrNotImplemented
 }
 
+func LinkSetIPv6AddrGenMode(link Link, mode int) error {
+	return ErrNotImplemented
+}
+
+func LinkSetIPv6Token(link Link, token net.IP) error {
+	return ErrNotImplemented
+}
+
+func LinkSetIPv6StableSecret(link Link, secret net.IP) error {
+	return ErrNotImplemented
+}
+
+func IPv6SetDefaultStableSecret(secret net.IP) error {
+	return ErrNotImplemented
+}
+
+func LinkSetCanParams(link Link, params CanParams) error {
+	return ErrNotImplemented
+}
+
 func LinkAdd(link Link) error {
 	return ErrNotImplemented
 }
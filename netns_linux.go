@@ -13,18 +13,22 @@ package netlink
 // in to that namespace.
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"syscall"
 
 	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
 	"golang.org/x/sys/unix"
 )
 
 // These can be replaced by the values from sys/unix when it is next released.
 const (
-	_ = iota
-	NETNSA_NSID
-	NETNSA_PID
-	NETNSA_FD
+	NETNSA_NSID = nl.NETNSA_NSID
+	NETNSA_PID  = nl.NETNSA_PID
+	NETNSA_FD   = nl.NETNSA_FD
 )
 
 // GetNetNsIdByPid looks up the network namespace ID for a given pid (really thread id).
@@ -139,3 +143,262 @@ func (h *Handle) setNetNsId(attrType int, val uint32, newnsid uint32) error {
 	_, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWNSID)
 	return err
 }
+
+// NetNsId is a network namespace id assigned in a network namespace.
+type NetNsId struct {
+	// NsID is the id of a peer network namespace in the namespace whose
+	// ids are listed.
+	NsID int
+	// CurrentNsID is the id of the same peer namespace in the current
+	// namespace, when the ids of another namespace are listed with
+	// NetNsIdListByTarget, or -1.
+	CurrentNsID int
+}
+
+// NetNsIdList lists the ids assigned in the current network namespace.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func NetNsIdList() ([]NetNsId, error) {
+	return pkgHandle.NetNsIdList()
+}
+
+// NetNsIdList lists the ids assigned in the network namespace of h.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) NetNsIdList() ([]NetNsId, error) {
+	return h.netNsIdList(nil)
+}
+
+// NetNsIdListByTarget lists the ids assigned in the network namespace whose
+// id in the current one is targetNsid, along with the ids of the same peer
+// namespaces in the current one. As the kernel only considers the target
+// of the requests it checks strictly, it fails unless run on a Handle with
+// strict checking enabled, see [Handle.SetStrictCheck].
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func NetNsIdListByTarget(targetNsid int) ([]NetNsId, error) {
+	return pkgHandle.NetNsIdListByTarget(targetNsid)
+}
+
+// NetNsIdListByTarget lists the ids assigned in the network namespace whose
+// id in the one of h is targetNsid, along with the ids of the same peer
+// namespaces in the one of h. As the kernel only considers the target of
+// the requests it checks strictly, h must have strict checking enabled,
+// see [Handle.SetStrictCheck].
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) NetNsIdListByTarget(targetNsid int) ([]NetNsId, error) {
+	return h.netNsIdList(&targetNsid)
+}
+
+func (h *Handle) netNsIdList(targetNsid *int) ([]NetNsId, error) {
+	req := h.newNetlinkRequest(unix.RTM_GETNSID, unix.NLM_F_DUMP)
+	req.AddData(nl.NewRtGenMsg())
+	if targetNsid != nil {
+		req.AddData(nl.NewRtAttr(nl.NETNSA_TARGET_NSID, nl.Uint32Attr(uint32(*targetNsid))))
+	}
+
+	msgs, executeErr := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWNSID)
+	if executeErr != nil && !errors.Is(executeErr, ErrDumpInterrupted) {
+		return nil, executeErr
+	}
+	res := make([]NetNsId, 0, len(msgs))
+	for _, m := range msgs {
+		id, hasCurrent, err := parseNetNsId(m)
+		if err != nil {
+			return nil, err
+		}
+		if targetNsid != nil && !hasCurrent {
+			// The kernel adds it to every id of a target namespace.
+			return nil, errors.New("target network namespace ignored, strict checking is required")
+		}
+		res = append(res, id)
+	}
+	return res, executeErr
+}
+
+// parseNetNsId parses the RTM_NEWNSID or RTM_DELNSID message m. hasCurrent
+// reports whether it holds the nl.NETNSA_CURRENT_NSID attribute.
+func parseNetNsId(m []byte) (id NetNsId, hasCurrent bool, err error) {
+	id = NetNsId{
+		NsID:        nl.NETNSA_NSID_NOT_ASSIGNED,
+		CurrentNsID: nl.NETNSA_NSID_NOT_ASSIGNED,
+	}
+	if len(m) < nl.NewRtGenMsg().Len() {
+		return id, false, fmt.Errorf("netns id message too short: %d bytes", len(m))
+	}
+	ad := nl.NewAttributeDecoder(m[nl.NewRtGenMsg().Len():])
+	for ad.Next() {
+		switch ad.Type() {
+		case nl.NETNSA_NSID:
+			id.NsID = int(ad.Int32())
+		case nl.NETNSA_CURRENT_NSID:
+			id.CurrentNsID = int(ad.Int32())
+			hasCurrent = true
+		}
+	}
+	return id, hasCurrent, ad.Err()
+}
+
+// NetNsIdUpdate is sent when an id is assigned to a peer network namespace,
+// with Type RTM_NEWNSID, or when the peer namespace is destroyed, with Type
+// RTM_DELNSID.
+type NetNsIdUpdate struct {
+	Type uint16
+	NetNsId
+	// Resync marks the updates delimiting a resync, which carry nothing
+	// else.
+	Resync SubscriptionResync
+}
+
+// NetNsIdSubscribe takes a chan down which notifications will be sent
+// when network namespace ids are assigned or their namespaces destroyed.
+// Close the 'done' chan to stop subscription.
+func NetNsIdSubscribe(ch chan<- NetNsIdUpdate, done <-chan struct{}) error {
+	return netNsIdSubscribeAt(netns.None(), netns.None(), ch, done, NetNsIdSubscribeOptions{})
+}
+
+// NetNsIdSubscribeOptions contains a set of options to use with
+// NetNsIdSubscribeWithOptions.
+type NetNsIdSubscribeOptions struct {
+	Namespace              *netns.NsHandle
+	ErrorCallback          func(error)
+	ListExisting           bool
+	ReceiveBufferSize      int
+	ReceiveBufferForceSize bool
+	ReceiveTimeout         *unix.Timeval
+	// Resync keeps the subscription going when the receive buffer overruns,
+	// as for LinkSubscribeOptions, dumping the existing ids again.
+	Resync bool
+	// NoENOBUFS sets NETLINK_NO_ENOBUFS on the socket.
+	NoENOBUFS bool
+}
+
+// NetNsIdSubscribeWithOptions work like NetNsIdSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace can be provided as well as an error callback.
+func NetNsIdSubscribeWithOptions(ch chan<- NetNsIdUpdate, done <-chan struct{}, options NetNsIdSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return netNsIdSubscribeAt(*options.Namespace, netns.None(), ch, done, options)
+}
+
+// NetNsIdSubscribeContext works like NetNsIdSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func NetNsIdSubscribeContext(ctx context.Context, ch chan<- NetNsIdUpdate, options NetNsIdSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return NetNsIdSubscribeWithOptions(ch, ctx.Done(), options)
+}
+
+func netNsIdSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- NetNsIdUpdate, done <-chan struct{}, options NetNsIdSubscribeOptions) error {
+	sub := &subscription{
+		dumps: []func() *nl.NetlinkRequest{func() *nl.NetlinkRequest {
+			req := pkgHandle.newNetlinkRequest(unix.RTM_GETNSID, unix.NLM_F_DUMP)
+			req.AddData(nl.NewRtGenMsg())
+			return req
+		}},
+		handle: func(m syscall.NetlinkMessage, nsid int) error {
+			if m.Header.Type != unix.RTM_NEWNSID && m.Header.Type != unix.RTM_DELNSID {
+				return fmt.Errorf("bad message type: %d", m.Header.Type)
+			}
+			id, _, err := parseNetNsId(m.Data)
+			if err != nil {
+				return err
+			}
+			ch <- NetNsIdUpdate{Type: m.Header.Type, NetNsId: id}
+			return nil
+		},
+		mark: func(r SubscriptionResync) {
+			ch <- NetNsIdUpdate{
+				NetNsId: NetNsId{
+					NsID:        nl.NETNSA_NSID_NOT_ASSIGNED,
+					CurrentNsID: nl.NETNSA_NSID_NOT_ASSIGNED,
+				},
+				Resync: r,
+			}
+		},
+		close: func() { close(ch) },
+	}
+	return sub.start(newNs, curNs, done, subscribeOptions{
+		cberr:        options.ErrorCallback,
+		listExisting: options.ListExisting,
+		rcvbuf:       options.ReceiveBufferSize,
+		rcvbufForce:  options.ReceiveBufferForceSize,
+		rcvTimeout:   options.ReceiveTimeout,
+		resync:       options.Resync,
+		noENOBUFS:    options.NoENOBUFS,
+	}, unix.RTNLGRP_NSID)
+}
+
+// NetNsIdMap maps the ids of network namespaces back to their handles, to
+// tell where the updates of the subscriptions listening to all the
+// namespaces, with their ListenAllNsid option, originate from. The ids are
+// relative to the network namespace of the Handle the map was created
+// with, which must be the one of the subscriptions. It is safe for
+// concurrent use.
+type NetNsIdMap struct {
+	h   *Handle
+	mu  sync.RWMutex
+	ids map[int]netns.NsHandle
+}
+
+// NewNetNsIdMap returns an empty NetNsIdMap for the ids in the current
+// network namespace.
+func NewNetNsIdMap() *NetNsIdMap {
+	return pkgHandle.NewNetNsIdMap()
+}
+
+// NewNetNsIdMap returns an empty NetNsIdMap for the ids in the network
+// namespace of h.
+func (h *Handle) NewNetNsIdMap() *NetNsIdMap {
+	return &NetNsIdMap{h: h, ids: make(map[int]netns.NsHandle)}
+}
+
+// Add adds ns to m and returns its id. If ns has no id yet, the kernel is
+// asked to assign one, as the notifications of the namespaces without one
+// are not received by the subscriptions listening to all the namespaces.
+// m does not own ns: it must remain open until removed from m.
+func (m *NetNsIdMap) Add(ns netns.NsHandle) (int, error) {
+	nsid, err := m.h.GetNetNsIdByFd(int(ns))
+	if err != nil {
+		return 0, err
+	}
+	if nsid == nl.NETNSA_NSID_NOT_ASSIGNED {
+		if err := m.h.SetNetNsIdByFd(int(ns), nl.NETNSA_NSID_NOT_ASSIGNED); err != nil {
+			return 0, fmt.Errorf("could not assign a network namespace id: %w", err)
+		}
+		if nsid, err = m.h.GetNetNsIdByFd(int(ns)); err != nil {
+			return 0, err
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ids[nsid] = ns
+	return nsid, nil
+}
+
+// Remove removes the network namespace of id nsid from m.
+func (m *NetNsIdMap) Remove(nsid int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.ids, nsid)
+}
+
+// Lookup returns the network namespace of id nsid, if it was added to m.
+func (m *NetNsIdMap) Lookup(nsid int) (netns.NsHandle, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ns, ok := m.ids[nsid]
+	return ns, ok
+}
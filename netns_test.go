@@ -8,8 +8,11 @@ import (
 	"runtime"
 	"syscall"
 	"testing"
+	"time"
 
+	"github.com/vishvananda/netlink/nl"
 	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
 )
 
 // TestNetNsIdByFd tests setting and getting the network namespace ID
@@ -78,3 +81,251 @@ func TestNetNsIdByPid(t *testing.T) {
 		t.Errorf("GetNetNsIdByPid returned %d, want %d", haveID, wantID)
 	}
 }
+
+func TestNetNsIdMap(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ns, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+	var peers []netns.NsHandle
+	for i := 0; i < 2; i++ {
+		peer, err := netns.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer peer.Close()
+		peers = append(peers, peer)
+	}
+	if err := netns.Set(ns); err != nil {
+		t.Fatal(err)
+	}
+	// The first namespace has an id, the second one gets one assigned.
+	if err := SetNetNsIdByFd(int(peers[0]), 7); err != nil {
+		t.Fatal(err)
+	}
+	m := NewNetNsIdMap()
+	for i, peer := range peers {
+		nsid, err := m.Add(peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 && nsid != 7 {
+			t.Fatalf("expected netns id 7, got %d", nsid)
+		}
+		if nsid < 0 {
+			t.Fatalf("expected an assigned netns id, got %d", nsid)
+		}
+	}
+
+	ch := make(chan LinkUpdate)
+	done := make(chan struct{})
+	defer close(done)
+	if err := LinkSubscribeWithOptions(ch, done, LinkSubscribeOptions{ListenAllNsid: true}); err != nil {
+		t.Fatal(err)
+	}
+	for i, peer := range peers {
+		h, err := NewHandleAt(peer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer h.Close()
+		name := []string{"foo", "bar"}[i]
+		if err := h.LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: name}, PeerName: name + "-peer"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := map[string]netns.NsHandle{"foo": peers[0], "bar": peers[1]}
+	timeout := time.After(time.Minute)
+	for len(expected) > 0 {
+		select {
+		case update := <-ch:
+			peer, ok := expected[update.Link.Attrs().Name]
+			if !ok {
+				continue
+			}
+			ns, ok := m.Lookup(update.NetnsID)
+			if !ok || !ns.Equal(peer) {
+				t.Fatalf("netns id %d of %s not mapped to its namespace", update.NetnsID, update.Link.Attrs().Name)
+			}
+			delete(expected, update.Link.Attrs().Name)
+		case <-timeout:
+			t.Fatalf("updates not received: %v", expected)
+		}
+	}
+
+	m.Remove(7)
+	if _, ok := m.Lookup(7); ok {
+		t.Fatal("expected the namespace to be removed")
+	}
+}
+
+func TestNetNsIdList(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ns, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+	var peers []netns.NsHandle
+	for i := 0; i < 2; i++ {
+		peer, err := netns.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer peer.Close()
+		peers = append(peers, peer)
+	}
+	if err := netns.Set(ns); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetNetNsIdByFd(int(peers[0]), 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetNetNsIdByFd(int(peers[1]), 9); err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewHandleAt(peers[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	if err := h.SetNetNsIdByFd(int(peers[1]), 3); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := NetNsIdList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []NetNsId{{NsID: 7, CurrentNsID: -1}, {NsID: 9, CurrentNsID: -1}}
+	if len(ids) != len(expected) || ids[0] != expected[0] || ids[1] != expected[1] {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+
+	if _, err := NetNsIdListByTarget(7); err == nil {
+		t.Fatal("expected an error without strict checking")
+	}
+	strict, err := NewHandle(unix.NETLINK_ROUTE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer strict.Close()
+	if err := strict.SetStrictCheck(true); err != nil {
+		t.Fatal(err)
+	}
+	ids, err = strict.NetNsIdListByTarget(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != (NetNsId{NsID: 3, CurrentNsID: 9}) {
+		t.Fatalf("unexpected ids of the target namespace: %v", ids)
+	}
+}
+
+func TestNetNsIdSubscribe(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ns, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+	peer, err := netns.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := netns.Set(ns); err != nil {
+		peer.Close()
+		t.Fatal(err)
+	}
+
+	ch := make(chan NetNsIdUpdate)
+	done := make(chan struct{})
+	defer close(done)
+	if err := NetNsIdSubscribe(ch, done); err != nil {
+		peer.Close()
+		t.Fatal(err)
+	}
+	if err := SetNetNsIdByFd(int(peer), 7); err != nil {
+		peer.Close()
+		t.Fatal(err)
+	}
+	peer.Close()
+
+	// The namespace is destroyed asynchronously once closed.
+	expected := []uint16{unix.RTM_NEWNSID, unix.RTM_DELNSID}
+	timeout := time.After(time.Minute)
+	for len(expected) > 0 {
+		select {
+		case update := <-ch:
+			if update.Type != expected[0] || update.NsID != 7 {
+				t.Fatalf("unexpected update %+v", update)
+			}
+			expected = expected[1:]
+		case <-timeout:
+			t.Fatalf("updates not received: %v", expected)
+		}
+	}
+}
+
+func TestNetNsIdSubscribeResync(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ns, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+
+	ch := make(chan NetNsIdUpdate)
+	done := make(chan struct{})
+	defer close(done)
+	if err := NetNsIdSubscribeWithOptions(ch, done, NetNsIdSubscribeOptions{
+		ReceiveBufferSize: 1,
+		Resync:            true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Assign ids, not consuming the updates, until the receive buffer of
+	// the subscription overruns.
+	for i := 0; i < 64; i++ {
+		peer, err := netns.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := netns.Set(ns); err != nil {
+			peer.Close()
+			t.Fatal(err)
+		}
+		err = SetNetNsIdByFd(int(peer), 100+i)
+		peer.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The markers delimiting the resync carry no namespace id.
+	timeout := time.After(time.Minute)
+	for _, resync := range []SubscriptionResync{ResyncStart, ResyncDone} {
+		for received := false; !received; {
+			select {
+			case update := <-ch:
+				if update.Resync == ResyncNone {
+					continue
+				}
+				if update.Resync != resync || update.NsID != nl.NETNSA_NSID_NOT_ASSIGNED ||
+					update.CurrentNsID != nl.NETNSA_NSID_NOT_ASSIGNED {
+					t.Fatalf("unexpected update %+v", update)
+				}
+				received = true
+			case <-timeout:
+				t.Fatalf("resync marker %s not received", resync)
+			}
+		}
+	}
+}
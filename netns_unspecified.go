@@ -18,3 +18,13 @@ func GetNetNsIdByFd(fd int) (int, error) {
 func SetNetNsIdByFd(fd, nsid int) error {
 	return ErrNotImplemented
 }
+
+type NetNsId struct{}
+
+func NetNsIdList() ([]NetNsId, error) {
+	return nil, ErrNotImplemented
+}
+
+func NetNsIdListByTarget(targetNsid int) ([]NetNsId, error) {
+	return nil, ErrNotImplemented
+}
@@ -3,6 +3,7 @@ package netlink
 import (
 	"fmt"
 	"net"
+	"net/netip"
 	"strconv"
 	"strings"
 )
@@ -15,6 +16,16 @@ type Nexthop struct {
 	Protocol  RouteProtocol
 }
 
+// GatewayAddr returns the gateway of the nexthop as a netip.Addr.
+func (h Nexthop) GatewayAddr() netip.Addr {
+	return NetipAddr(h.Gateway)
+}
+
+// SetGatewayAddr sets the gateway of the nexthop.
+func (h *Nexthop) SetGatewayAddr(addr netip.Addr) {
+	h.Gateway = netIP(addr)
+}
+
 func (h *Nexthop) String() string {
 	elems := []string{
 		"ID: " + strconv.FormatUint(uint64(h.ID), 10),
@@ -0,0 +1,416 @@
+package nl
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// AttributeDecoder iterates over a list of netlink attributes, and decodes
+// their values with typed getters:
+//
+//	ad := nl.NewAttributeDecoder(b)
+//	for ad.Next() {
+//		switch ad.Type() {
+//		case nl.IFLA_MTU:
+//			mtu = ad.Uint32()
+//		case nl.IFLA_IFNAME:
+//			name = ad.String()
+//		}
+//	}
+//	if err := ad.Err(); err != nil {
+//		return err
+//	}
+//
+// Every read is bounds checked. The first error met, either a truncated
+// attribute or a value of an unexpected length, stops the iteration and is
+// returned by Err; the getters then return zero values.
+type AttributeDecoder struct {
+	// ByteOrder is the byte order of the integer values which are not
+	// flagged with NLA_F_NET_BYTEORDER. It defaults to the native one.
+	ByteOrder binary.ByteOrder
+
+	b     []byte
+	typ   uint16
+	value []byte
+	err   error
+}
+
+// NewAttributeDecoder returns an AttributeDecoder over the attributes in b.
+func NewAttributeDecoder(b []byte) *AttributeDecoder {
+	return &AttributeDecoder{ByteOrder: NativeEndian(), b: b}
+}
+
+// Next advances to the next attribute. It returns false once there are no
+// attributes left, or an error occurred.
+func (ad *AttributeDecoder) Next() bool {
+	if ad.err != nil || len(ad.b) == 0 {
+		return false
+	}
+	if len(ad.b) < unix.SizeofRtAttr {
+		ad.err = fmt.Errorf("netlink attribute truncated: %d bytes left", len(ad.b))
+		return false
+	}
+	length := int(NativeEndian().Uint16(ad.b[0:2]))
+	typ := NativeEndian().Uint16(ad.b[2:4])
+	if length < unix.SizeofRtAttr || length > len(ad.b) {
+		ad.err = fmt.Errorf("netlink attribute %d has invalid length %d, %d bytes left", typ&NLA_TYPE_MASK, length, len(ad.b))
+		return false
+	}
+	ad.typ = typ
+	ad.value = ad.b[unix.SizeofRtAttr:length]
+	ad.b = ad.b[min(rtaAlignOf(length), len(ad.b)):]
+	return true
+}
+
+// Err returns the first error met by ad, if any.
+func (ad *AttributeDecoder) Err() error {
+	return ad.err
+}
+
+// Type returns the type of the current attribute, without its flags.
+func (ad *AttributeDecoder) Type() uint16 {
+	return ad.typ & NLA_TYPE_MASK
+}
+
+// Flags returns the NLA_F_NESTED and NLA_F_NET_BYTEORDER flags of the
+// current attribute. Not all the nested attributes are flagged as such.
+func (ad *AttributeDecoder) Flags() uint16 {
+	return ad.typ &^ NLA_TYPE_MASK
+}
+
+// Len returns the length of the value of the current attribute.
+func (ad *AttributeDecoder) Len() int {
+	return len(ad.value)
+}
+
+// Value returns the value of the current attribute, without copying it: it
+// aliases the decoded buffer. It allows to walk nested attributes without
+// allocating, with a decoder over the value.
+func (ad *AttributeDecoder) Value() []byte {
+	if ad.err != nil {
+		return nil
+	}
+	return ad.value
+}
+
+// fixed returns the value of the current attribute if it is n bytes long,
+// and records an error otherwise.
+func (ad *AttributeDecoder) fixed(kind string, n int) []byte {
+	if ad.err != nil {
+		return nil
+	}
+	if len(ad.value) != n {
+		ad.err = fmt.Errorf("netlink attribute %d: %s needs %d bytes, got %d", ad.Type(), kind, n, len(ad.value))
+		return nil
+	}
+	return ad.value
+}
+
+// byteOrder returns the byte order of the current attribute.
+func (ad *AttributeDecoder) byteOrder() binary.ByteOrder {
+	if ad.typ&NLA_F_NET_BYTEORDER != 0 {
+		return binary.BigEndian
+	}
+	return ad.ByteOrder
+}
+
+// Uint8 decodes the current attribute as an uint8.
+func (ad *AttributeDecoder) Uint8() uint8 {
+	b := ad.fixed("uint8", 1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// Uint16 decodes the current attribute as an uint16, in network byte order
+// if it is flagged with NLA_F_NET_BYTEORDER.
+func (ad *AttributeDecoder) Uint16() uint16 {
+	b := ad.fixed("uint16", 2)
+	if b == nil {
+		return 0
+	}
+	return ad.byteOrder().Uint16(b)
+}
+
+// Uint32 decodes the current attribute as an uint32, in network byte order
+// if it is flagged with NLA_F_NET_BYTEORDER.
+func (ad *AttributeDecoder) Uint32() uint32 {
+	b := ad.fixed("uint32", 4)
+	if b == nil {
+		return 0
+	}
+	return ad.byteOrder().Uint32(b)
+}
+
+// Uint64 decodes the current attribute as an uint64, in network byte order
+// if it is flagged with NLA_F_NET_BYTEORDER.
+func (ad *AttributeDecoder) Uint64() uint64 {
+	b := ad.fixed("uint64", 8)
+	if b == nil {
+		return 0
+	}
+	return ad.byteOrder().Uint64(b)
+}
+
+// BEUint16 decodes the current attribute as a big endian uint16.
+func (ad *AttributeDecoder) BEUint16() uint16 {
+	b := ad.fixed("uint16", 2)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+// BEUint32 decodes the current attribute as a big endian uint32.
+func (ad *AttributeDecoder) BEUint32() uint32 {
+	b := ad.fixed("uint32", 4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+// BEUint64 decodes the current attribute as a big endian uint64.
+func (ad *AttributeDecoder) BEUint64() uint64 {
+	b := ad.fixed("uint64", 8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// Int32 decodes the current attribute as an int32.
+func (ad *AttributeDecoder) Int32() int32 {
+	return int32(ad.Uint32())
+}
+
+// Int64 decodes the current attribute as an int64.
+func (ad *AttributeDecoder) Int64() int64 {
+	return int64(ad.Uint64())
+}
+
+// Flag reports whether the current attribute has no value, as flag
+// attributes, which are set by their mere presence.
+func (ad *AttributeDecoder) Flag() bool {
+	ad.fixed("flag", 0)
+	return ad.err == nil
+}
+
+// String decodes the current attribute as a string, up to its first NUL
+// byte.
+func (ad *AttributeDecoder) String() string {
+	if ad.err != nil {
+		return ""
+	}
+	return unix.ByteSliceToString(ad.value)
+}
+
+// Bytes returns a copy of the value of the current attribute.
+func (ad *AttributeDecoder) Bytes() []byte {
+	if ad.err != nil {
+		return nil
+	}
+	return append([]byte(nil), ad.value...)
+}
+
+// IP decodes the current attribute as an IPv4 or IPv6 address.
+func (ad *AttributeDecoder) IP() net.IP {
+	if ad.err != nil {
+		return nil
+	}
+	if len(ad.value) != net.IPv4len && len(ad.value) != net.IPv6len {
+		ad.err = fmt.Errorf("netlink attribute %d: IP address needs %d or %d bytes, got %d", ad.Type(), net.IPv4len, net.IPv6len, len(ad.value))
+		return nil
+	}
+	return net.IP(ad.Bytes())
+}
+
+// HardwareAddr decodes the current attribute as a hardware address.
+func (ad *AttributeDecoder) HardwareAddr() net.HardwareAddr {
+	return net.HardwareAddr(ad.Bytes())
+}
+
+// Nested decodes the current attribute as a list of attributes, with fn. An
+// error returned by fn, or met by the decoder passed to it, becomes the
+// error of ad.
+func (ad *AttributeDecoder) Nested(fn func(nad *AttributeDecoder) error) {
+	if ad.err != nil {
+		return
+	}
+	nad := &AttributeDecoder{ByteOrder: ad.ByteOrder, b: ad.value}
+	if err := fn(nad); err != nil {
+		ad.err = err
+		return
+	}
+	if nad.err != nil {
+		ad.err = nad.err
+	}
+}
+
+// AttributeEncoder serializes a list of netlink attributes:
+//
+//	ae := nl.NewAttributeEncoder()
+//	ae.Uint32(nl.IFLA_MTU, 1500)
+//	ae.String(nl.IFLA_IFNAME, "foo")
+//	b, err := ae.Encode()
+//
+// The first error met, a value too long for an attribute, is returned by
+// Encode.
+type AttributeEncoder struct {
+	// ByteOrder is the byte order of the integer values. It defaults to the
+	// native one.
+	ByteOrder binary.ByteOrder
+
+	b   []byte
+	err error
+}
+
+// NewAttributeEncoder returns an empty AttributeEncoder.
+func NewAttributeEncoder() *AttributeEncoder {
+	return &AttributeEncoder{ByteOrder: NativeEndian()}
+}
+
+// Encode returns the serialized attributes.
+func (ae *AttributeEncoder) Encode() ([]byte, error) {
+	if ae.err != nil {
+		return nil, ae.err
+	}
+	return ae.b, nil
+}
+
+// Bytes adds an attribute of type typ with value v. The type may carry the
+// NLA_F_NESTED and NLA_F_NET_BYTEORDER flags.
+func (ae *AttributeEncoder) Bytes(typ uint16, v []byte) {
+	if ae.err != nil {
+		return
+	}
+	length := unix.SizeofRtAttr + len(v)
+	if length > math.MaxUint16 {
+		ae.err = fmt.Errorf("netlink attribute %d: value of %d bytes too long", typ&NLA_TYPE_MASK, len(v))
+		return
+	}
+	var hdr [unix.SizeofRtAttr]byte
+	NativeEndian().PutUint16(hdr[0:2], uint16(length))
+	NativeEndian().PutUint16(hdr[2:4], typ)
+	ae.b = append(ae.b, hdr[:]...)
+	ae.b = append(ae.b, v...)
+	ae.b = append(ae.b, make([]byte, rtaAlignOf(length)-length)...)
+}
+
+// Flag adds an attribute of type typ without value if v is true.
+func (ae *AttributeEncoder) Flag(typ uint16, v bool) {
+	if v {
+		ae.Bytes(typ, nil)
+	}
+}
+
+// Uint8 adds an uint8 attribute.
+func (ae *AttributeEncoder) Uint8(typ uint16, v uint8) {
+	ae.Bytes(typ, []byte{v})
+}
+
+// Uint16 adds an uint16 attribute, in network byte order if typ is flagged
+// with NLA_F_NET_BYTEORDER.
+func (ae *AttributeEncoder) Uint16(typ uint16, v uint16) {
+	b := make([]byte, 2)
+	ae.byteOrder(typ).PutUint16(b, v)
+	ae.Bytes(typ, b)
+}
+
+// Uint32 adds an uint32 attribute, in network byte order if typ is flagged
+// with NLA_F_NET_BYTEORDER.
+func (ae *AttributeEncoder) Uint32(typ uint16, v uint32) {
+	b := make([]byte, 4)
+	ae.byteOrder(typ).PutUint32(b, v)
+	ae.Bytes(typ, b)
+}
+
+// Uint64 adds an uint64 attribute, in network byte order if typ is flagged
+// with NLA_F_NET_BYTEORDER.
+func (ae *AttributeEncoder) Uint64(typ uint16, v uint64) {
+	b := make([]byte, 8)
+	ae.byteOrder(typ).PutUint64(b, v)
+	ae.Bytes(typ, b)
+}
+
+// BEUint16 adds a big endian uint16 attribute.
+func (ae *AttributeEncoder) BEUint16(typ uint16, v uint16) {
+	ae.Bytes(typ, binary.BigEndian.AppendUint16(nil, v))
+}
+
+// BEUint32 adds a big endian uint32 attribute.
+func (ae *AttributeEncoder) BEUint32(typ uint16, v uint32) {
+	ae.Bytes(typ, binary.BigEndian.AppendUint32(nil, v))
+}
+
+// BEUint64 adds a big endian uint64 attribute.
+func (ae *AttributeEncoder) BEUint64(typ uint16, v uint64) {
+	ae.Bytes(typ, binary.BigEndian.AppendUint64(nil, v))
+}
+
+// Int32 adds an int32 attribute.
+func (ae *AttributeEncoder) Int32(typ uint16, v int32) {
+	ae.Uint32(typ, uint32(v))
+}
+
+// Int64 adds an int64 attribute.
+func (ae *AttributeEncoder) Int64(typ uint16, v int64) {
+	ae.Uint64(typ, uint64(v))
+}
+
+// String adds a NUL terminated string attribute.
+func (ae *AttributeEncoder) String(typ uint16, v string) {
+	ae.Bytes(typ, ZeroTerminated(v))
+}
+
+// IP adds an IP address attribute, in its 4 bytes form for IPv4 addresses.
+func (ae *AttributeEncoder) IP(typ uint16, v net.IP) {
+	if ip4 := v.To4(); ip4 != nil {
+		ae.Bytes(typ, ip4)
+		return
+	}
+	if ae.err == nil && len(v) != net.IPv6len {
+		ae.err = fmt.Errorf("netlink attribute %d: invalid IP address %v", typ&NLA_TYPE_MASK, v)
+		return
+	}
+	ae.Bytes(typ, v)
+}
+
+// HardwareAddr adds a hardware address attribute.
+func (ae *AttributeEncoder) HardwareAddr(typ uint16, v net.HardwareAddr) {
+	ae.Bytes(typ, v)
+}
+
+// Nested adds an attribute of type typ, flagged with NLA_F_NESTED, holding
+// the attributes encoded by fn. An error returned by fn, or met by the
+// encoder passed to it, becomes the error of ae.
+func (ae *AttributeEncoder) Nested(typ uint16, fn func(nae *AttributeEncoder) error) {
+	if ae.err != nil {
+		return
+	}
+	nae := &AttributeEncoder{ByteOrder: ae.ByteOrder}
+	if err := fn(nae); err != nil {
+		ae.err = err
+		return
+	}
+	b, err := nae.Encode()
+	if err != nil {
+		ae.err = err
+		return
+	}
+	ae.Bytes(typ|NLA_F_NESTED, b)
+}
+
+// byteOrder returns the byte order of the integer values of attributes of
+// type typ.
+func (ae *AttributeEncoder) byteOrder(typ uint16) binary.ByteOrder {
+	if typ&NLA_F_NET_BYTEORDER != 0 {
+		return binary.BigEndian
+	}
+	return ae.ByteOrder
+}
@@ -0,0 +1,173 @@
+package nl
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestAttributeEncoderDecoder(t *testing.T) {
+	mac, _ := net.ParseMAC("aa:bb:cc:dd:ee:ff")
+	ae := NewAttributeEncoder()
+	ae.String(unix.IFLA_IFNAME, "foo")
+	ae.Uint32(unix.IFLA_MTU, 1500)
+	ae.HardwareAddr(unix.IFLA_ADDRESS, mac)
+	ae.Nested(unix.IFLA_LINKINFO, func(nae *AttributeEncoder) error {
+		nae.String(IFLA_INFO_KIND, "vxlan")
+		nae.Nested(IFLA_INFO_DATA, func(nae *AttributeEncoder) error {
+			nae.IP(IFLA_VXLAN_GROUP, net.IPv4(239, 1, 1, 1))
+			nae.BEUint16(IFLA_VXLAN_PORT, 4789)
+			nae.Uint8(IFLA_VXLAN_TTL, 64)
+			nae.Uint64(100|NLA_F_NET_BYTEORDER, 0x0102030405060708)
+			nae.Flag(101, true)
+			return nil
+		})
+		return nil
+	})
+	b, err := ae.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The encoding matches the one of RtAttr.
+	linkInfo := NewRtAttr(unix.IFLA_LINKINFO|int(NLA_F_NESTED), nil)
+	linkInfo.AddRtAttr(IFLA_INFO_KIND, ZeroTerminated("vxlan"))
+	data := linkInfo.AddRtAttr(IFLA_INFO_DATA|int(NLA_F_NESTED), nil)
+	data.AddRtAttr(IFLA_VXLAN_GROUP, net.IPv4(239, 1, 1, 1).To4())
+	data.AddRtAttr(IFLA_VXLAN_PORT, BEUint16Attr(4789))
+	data.AddRtAttr(IFLA_VXLAN_TTL, Uint8Attr(64))
+	data.AddRtAttr(100|int(NLA_F_NET_BYTEORDER), BEUint64Attr(0x0102030405060708))
+	data.AddRtAttr(101, nil)
+	var expected []byte
+	expected = append(expected, NewRtAttr(unix.IFLA_IFNAME, ZeroTerminated("foo")).Serialize()...)
+	expected = append(expected, NewRtAttr(unix.IFLA_MTU, Uint32Attr(1500)).Serialize()...)
+	expected = append(expected, NewRtAttr(unix.IFLA_ADDRESS, mac).Serialize()...)
+	expected = append(expected, linkInfo.Serialize()...)
+	if !bytes.Equal(b, expected) {
+		t.Fatalf("encoded %v, expected %v", b, expected)
+	}
+
+	var (
+		name, kind string
+		mtu        uint32
+		addr       net.HardwareAddr
+		group      net.IP
+		port       uint16
+		ttl        uint8
+		be         uint64
+		flag       bool
+		flags      uint16
+	)
+	ad := NewAttributeDecoder(b)
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.IFLA_IFNAME:
+			name = ad.String()
+		case unix.IFLA_MTU:
+			mtu = ad.Uint32()
+		case unix.IFLA_ADDRESS:
+			addr = ad.HardwareAddr()
+		case unix.IFLA_LINKINFO:
+			flags = ad.Flags()
+			ad.Nested(func(nad *AttributeDecoder) error {
+				for nad.Next() {
+					switch nad.Type() {
+					case IFLA_INFO_KIND:
+						kind = nad.String()
+					case IFLA_INFO_DATA:
+						nad.Nested(func(nad *AttributeDecoder) error {
+							for nad.Next() {
+								switch nad.Type() {
+								case IFLA_VXLAN_GROUP:
+									group = nad.IP()
+								case IFLA_VXLAN_PORT:
+									port = nad.BEUint16()
+								case IFLA_VXLAN_TTL:
+									ttl = nad.Uint8()
+								case 100:
+									be = nad.Uint64()
+								case 101:
+									flag = nad.Flag()
+								}
+							}
+							return nil
+						})
+					}
+				}
+				return nil
+			})
+		}
+	}
+	if err := ad.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if name != "foo" || mtu != 1500 || addr.String() != mac.String() || kind != "vxlan" ||
+		!group.Equal(net.IPv4(239, 1, 1, 1)) || port != 4789 || ttl != 64 ||
+		be != 0x0102030405060708 || !flag || flags != NLA_F_NESTED {
+		t.Fatalf("unexpected decoded values: %q %d %v %q %v %d %d %#x %v %#x", name, mtu, addr, kind, group, port, ttl, be, flag, flags)
+	}
+}
+
+func TestAttributeDecoderErrors(t *testing.T) {
+	valid := NewRtAttr(unix.IFLA_MTU, Uint32Attr(1500)).Serialize()
+	for _, tt := range []struct {
+		name string
+		b    []byte
+		get  func(ad *AttributeDecoder)
+	}{
+		{"truncated header", append(valid, 8, 0), nil},
+		{"truncated value", append(valid, 8, 0, 1, 0, 0), nil},
+		{"invalid length", append(valid, 2, 0, 1, 0), nil},
+		{"short value", valid, func(ad *AttributeDecoder) { ad.Uint64() }},
+		{"long value", valid, func(ad *AttributeDecoder) { ad.Uint16() }},
+		{"invalid IP", NewRtAttr(unix.IFA_ADDRESS, []byte{1, 2, 3}).Serialize(), func(ad *AttributeDecoder) { ad.IP() }},
+		{"invalid nested", valid, func(ad *AttributeDecoder) {
+			ad.Nested(func(nad *AttributeDecoder) error {
+				for nad.Next() {
+				}
+				return nil
+			})
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := NewAttributeDecoder(tt.b)
+			n := 0
+			for ad.Next() {
+				n++
+				if tt.get != nil {
+					tt.get(ad)
+				}
+			}
+			if ad.Err() == nil {
+				t.Fatal("expected an error")
+			}
+			if n != 1 {
+				t.Fatalf("expected 1 attribute before the error, got %d", n)
+			}
+			if ad.Uint32() != 0 {
+				t.Fatal("expected the getters to return zero values after an error")
+			}
+		})
+	}
+
+	if _, err := ParsePolicyType(NewRtAttr(NL_POLICY_TYPE_ATTR_MASK, Uint32Attr(1)).Serialize()); err == nil {
+		t.Fatal("expected an error parsing a truncated policy")
+	}
+}
+
+func TestAttributeEncoderErrors(t *testing.T) {
+	ae := NewAttributeEncoder()
+	ae.Bytes(1, make([]byte, 1<<16))
+	ae.Uint32(2, 1)
+	if b, err := ae.Encode(); err == nil || b != nil {
+		t.Fatalf("expected an error encoding a too long value, got %v", b)
+	}
+
+	ae = NewAttributeEncoder()
+	ae.IP(1, net.IP{1, 2, 3})
+	if _, err := ae.Encode(); err == nil {
+		t.Fatal("expected an error encoding an invalid IP address")
+	}
+}
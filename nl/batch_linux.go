@@ -0,0 +1,250 @@
+package nl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"golang.org/x/sys/unix"
+)
+
+// BATCH_SEND_SIZE bounds the size of the sendmsg calls of a Batch. It stays
+// well below the default netlink socket send buffer size.
+const BATCH_SEND_SIZE = 65536
+
+// ErrBatchAborted is the error of the requests of an nfnetlink transaction
+// which were processed successfully by the kernel, but rolled back because
+// another request of the same transaction failed.
+var ErrBatchAborted = errors.New("netlink batch aborted")
+
+// Batch queues netlink requests to send them to the kernel with as few
+// sendmsg calls as possible, and collects the outcome of each of them.
+//
+// Only the last request of each sendmsg call asks for an acknowledgement,
+// the kernel reports the others only if they fail. As it processes the
+// messages of a sendmsg call in order, the acknowledgement of the last one
+// confirms all the others. Requests are sent in the order they were queued
+// for each netlink protocol.
+type Batch struct {
+	// Sockets, if set, are used to send the requests of the matching
+	// protocols, as for [NetlinkRequest]. Dedicated sockets are used
+	// otherwise.
+	Sockets map[int]*SocketHandle
+
+	// NfnlSubsys, if set, wraps the NETLINK_NETFILTER requests between
+	// NFNL_MSG_BATCH_BEGIN and NFNL_MSG_BATCH_END messages for this nfnetlink
+	// subsystem, e.g. NFNL_SUBSYS_NFTABLES, so that the kernel applies them
+	// as a single transaction. They are then all sent with a single sendmsg
+	// call, and each of them is acknowledged.
+	NfnlSubsys uint16
+
+	entries []batchEntry
+}
+
+type batchEntry struct {
+	sockType int
+	req      *NetlinkRequest
+}
+
+// Add queues req to be sent on a socket of type sockType on the next Flush.
+// The batch owns req from then on: its sequence number and NLM_F_ACK flag
+// are overwritten when it is sent.
+func (b *Batch) Add(sockType int, req *NetlinkRequest) {
+	b.entries = append(b.entries, batchEntry{sockType: sockType, req: req})
+}
+
+// Len returns the number of requests queued in b.
+func (b *Batch) Len() int {
+	return len(b.entries)
+}
+
+// Flush sends the queued requests and empties b. errs holds the error of
+// each request, in the order they were queued. err is set if the requests
+// could not be sent, or their outcome could not be received, in which case
+// it is also the error of every request whose outcome is unknown.
+func (b *Batch) Flush() (errs []error, err error) {
+	return b.FlushContext(context.Background())
+}
+
+// FlushContext works like Flush, but stops waiting for the kernel and
+// returns ctx.Err() as soon as ctx is done.
+func (b *Batch) FlushContext(ctx context.Context) (errs []error, err error) {
+	entries := b.entries
+	b.entries = nil
+
+	var sockTypes []int
+	indexes := make(map[int][]int)
+	for i, e := range entries {
+		if _, ok := indexes[e.sockType]; !ok {
+			sockTypes = append(sockTypes, e.sockType)
+		}
+		indexes[e.sockType] = append(indexes[e.sockType], i)
+	}
+
+	errs = make([]error, len(entries))
+	for _, sockType := range sockTypes {
+		if flushErr := b.flush(ctx, sockType, entries, indexes[sockType], errs); flushErr != nil && err == nil {
+			err = flushErr
+		}
+	}
+	return errs, err
+}
+
+// flush sends the requests of entries at indexes on a socket of type
+// sockType, and sets their errors in errs.
+func (b *Batch) flush(ctx context.Context, sockType int, entries []batchEntry, indexes []int, errs []error) error {
+	t, sh, release, err := openTransport(b.Sockets, sockType)
+	if err != nil {
+		for _, i := range indexes {
+			errs[i] = err
+		}
+		return err
+	}
+	defer release()
+	nextSeq := func() uint32 {
+		if sh != nil {
+			return atomic.AddUint32(&sh.Seq, 1)
+		}
+		return atomic.AddUint32(&nextSeqNr, 1)
+	}
+
+	transaction := sockType == unix.NETLINK_NETFILTER && b.NfnlSubsys != 0
+	for start := 0; start < len(indexes); {
+		var (
+			buf      []byte
+			last     int
+			beginSeq uint32
+			seqs     = make(map[uint32]int)
+			msgs     = make(map[uint32][]byte)
+			awaited  = make(map[uint32]bool)
+		)
+		if transaction {
+			beginSeq = nextSeq()
+			buf = append(buf, b.nfnlBatchMessage(NFNL_MSG_BATCH_BEGIN, beginSeq)...)
+		}
+		end := start
+		for ; end < len(indexes); end++ {
+			req := entries[indexes[end]].req
+			req.Seq = nextSeq()
+			if transaction {
+				req.Flags |= unix.NLM_F_ACK
+				awaited[req.Seq] = true
+			} else {
+				req.Flags &^= unix.NLM_F_ACK
+			}
+			msg := req.Serialize()
+			if !transaction && end > start && len(buf)+len(msg) > BATCH_SEND_SIZE {
+				break
+			}
+			seqs[req.Seq] = indexes[end]
+			msgs[req.Seq] = msg
+			last = len(buf)
+			buf = append(buf, msg...)
+		}
+		if transaction {
+			buf = append(buf, b.nfnlBatchMessage(NFNL_MSG_BATCH_END, nextSeq())...)
+		} else {
+			// Ask for the acknowledgement of the last request only.
+			lastReq := entries[indexes[end-1]].req
+			lastReq.Flags |= unix.NLM_F_ACK
+			NativeEndian().PutUint16(buf[last+6:last+8], lastReq.Flags)
+			awaited[lastReq.Seq] = true
+		}
+
+		err := b.exchange(ctx, t, buf, beginSeq, entries, seqs, msgs, awaited, errs)
+		if err != nil {
+			// The outcome of the requests which did not fail is unknown.
+			for _, i := range seqs {
+				if errs[i] == nil {
+					errs[i] = err
+				}
+			}
+			for _, i := range indexes[end:] {
+				errs[i] = err
+			}
+			return err
+		}
+		if transaction {
+			for _, i := range seqs {
+				if errs[i] != nil {
+					for _, j := range seqs {
+						if errs[j] == nil {
+							errs[j] = ErrBatchAborted
+						}
+					}
+					break
+				}
+			}
+		}
+		start = end
+	}
+	return nil
+}
+
+// exchange sends buf on t and receives the errors of the requests whose
+// sequence numbers are the keys of seqs, until all the awaited ones are
+// acknowledged. msgs holds the serialized requests by sequence number.
+func (b *Batch) exchange(ctx context.Context, t Transport, buf []byte, beginSeq uint32, entries []batchEntry, seqs map[uint32]int, msgs map[uint32][]byte, awaited map[uint32]bool, errs []error) error {
+	if err := t.Send(ctx, buf); err != nil {
+		return err
+	}
+	pid, err := t.Pid()
+	if err != nil {
+		return err
+	}
+	for len(awaited) > 0 {
+		replies, from, err := t.Receive(ctx)
+		if err != nil {
+			return err
+		}
+		if from.Pid != PidKernel {
+			return fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, PidKernel)
+		}
+		for _, m := range replies {
+			if m.Header.Pid != pid || m.Header.Type != unix.NLMSG_ERROR {
+				continue
+			}
+			if beginSeq != 0 && m.Header.Seq == beginSeq {
+				// The whole transaction was rejected.
+				if err, _ := netlinkMessageError(m, nil, 0); err != nil {
+					for _, i := range seqs {
+						errs[i] = err
+					}
+					return nil
+				}
+				continue
+			}
+			i, ok := seqs[m.Header.Seq]
+			if !ok {
+				continue
+			}
+			req := entries[i].req
+			err, warning := netlinkMessageError(m, msgs[m.Header.Seq], req.attrsOffset())
+			if warning != nil && req.WarningCallback != nil {
+				req.WarningCallback(warning)
+			}
+			errs[i] = err
+			delete(awaited, m.Header.Seq)
+		}
+	}
+	return nil
+}
+
+// nfnlBatchMessage returns a serialized NFNL_MSG_BATCH_BEGIN or
+// NFNL_MSG_BATCH_END message for the nfnetlink subsystem of b.
+func (b *Batch) nfnlBatchMessage(typ uint16, seq uint32) []byte {
+	req := &NetlinkRequest{
+		NlMsghdr: unix.NlMsghdr{
+			Type:  typ,
+			Flags: unix.NLM_F_REQUEST,
+			Seq:   seq,
+		},
+	}
+	req.AddData(&Nfgenmsg{
+		NfgenFamily: unix.AF_UNSPEC,
+		Version:     NFNETLINK_V0,
+		ResId:       Swap16(b.NfnlSubsys),
+	})
+	return req.Serialize()
+}
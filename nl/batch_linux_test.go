@@ -0,0 +1,93 @@
+package nl
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+func setUpBatchTestNetns(t *testing.T) {
+	t.Helper()
+	if os.Getuid() != 0 {
+		t.Skip("Test requires root privileges.")
+	}
+	runtime.LockOSThread()
+	origns, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns, err := netns.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		ns.Close()
+		netns.Set(origns)
+		origns.Close()
+		runtime.UnlockOSThread()
+	})
+}
+
+func newNftTableRequest(name string) *NetlinkRequest {
+	req := NewNetlinkRequest(NFNL_SUBSYS_NFTABLES<<8, unix.NLM_F_CREATE|unix.NLM_F_EXCL)
+	req.AddData(&Nfgenmsg{NfgenFamily: unix.AF_INET, Version: NFNETLINK_V0})
+	req.AddData(NewRtAttr(1, ZeroTerminated(name))) // NFTA_TABLE_NAME
+	return req
+}
+
+func TestBatchLinkSet(t *testing.T) {
+	setUpBatchTestNetns(t)
+
+	b := &Batch{}
+	for _, index := range []int32{1, 1000, 1} { // lo, missing, lo
+		req := NewNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
+		msg := NewIfInfomsg(unix.AF_UNSPEC)
+		msg.Index = index
+		req.AddData(msg)
+		req.AddData(NewRtAttr(unix.IFLA_MTU, Uint32Attr(1400)))
+		b.Add(unix.NETLINK_ROUTE, req)
+	}
+	errs, err := b.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 3 || errs[0] != nil || !errors.Is(errs[1], syscall.ENODEV) || errs[2] != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
+
+func TestBatchNfnlTransaction(t *testing.T) {
+	setUpBatchTestNetns(t)
+
+	b := &Batch{NfnlSubsys: NFNL_SUBSYS_NFTABLES}
+	b.Add(unix.NETLINK_NETFILTER, newNftTableRequest("foo"))
+	b.Add(unix.NETLINK_NETFILTER, newNftTableRequest("foo"))
+	errs, err := b.Flush()
+	if err != nil {
+		t.Skipf("nf_tables not supported: %v", err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errors.Is(errs[0], syscall.EOPNOTSUPP) || errors.Is(errs[0], syscall.EPROTONOSUPPORT) {
+		t.Skipf("nf_tables not supported: %v", errs[0])
+	}
+	if !errors.Is(errs[0], ErrBatchAborted) || !errors.Is(errs[1], syscall.EEXIST) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	b.Add(unix.NETLINK_NETFILTER, newNftTableRequest("foo"))
+	b.Add(unix.NETLINK_NETFILTER, newNftTableRequest("bar"))
+	errs, err = b.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 || errs[0] != nil || errs[1] != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
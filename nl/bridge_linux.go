@@ -8,6 +8,8 @@ import (
 const (
 	SizeofBridgeVlanInfo = 0x04
 	SizeofTunnelMsg      = 0x08
+	SizeofBrVlanMsg      = 0x08
+	SizeofBrBooloptMulti = 0x08
 )
 
 /* Bridge Flags */
@@ -21,6 +23,7 @@ const (
  *     [IFLA_BRIDGE_FLAGS]
  *     [IFLA_BRIDGE_MODE]
  *     [IFLA_BRIDGE_VLAN_INFO]
+ *     [IFLA_BRIDGE_MST]
  * }
  */
 const (
@@ -28,6 +31,38 @@ const (
 	IFLA_BRIDGE_MODE
 	IFLA_BRIDGE_VLAN_INFO
 	IFLA_BRIDGE_VLAN_TUNNEL_INFO
+	IFLA_BRIDGE_MRP
+	IFLA_BRIDGE_CFM
+	IFLA_BRIDGE_MST
+)
+
+/* Bridge multiple spanning tree nested attributes
+ * [IFLA_BRIDGE_MST] = {
+ *     [IFLA_BRIDGE_MST_ENTRY] = {
+ *         [IFLA_BRIDGE_MST_ENTRY_MSTI]
+ *         [IFLA_BRIDGE_MST_ENTRY_STATE]
+ *     },
+ *     ...
+ * }
+ */
+const (
+	IFLA_BRIDGE_MST_UNSPEC = iota
+	IFLA_BRIDGE_MST_ENTRY
+)
+
+const (
+	IFLA_BRIDGE_MST_ENTRY_UNSPEC = iota
+	IFLA_BRIDGE_MST_ENTRY_MSTI
+	IFLA_BRIDGE_MST_ENTRY_STATE
+)
+
+/* Bridge port STP states */
+const (
+	BR_STATE_DISABLED = iota
+	BR_STATE_LISTENING
+	BR_STATE_LEARNING
+	BR_STATE_FORWARDING
+	BR_STATE_BLOCKING
 )
 
 const (
@@ -80,13 +115,127 @@ func (b *BridgeVlanInfo) String() string {
 	return fmt.Sprintf("%+v", *b)
 }
 
+// BridgeMstEntry is the state of a bridge port in a multiple spanning tree
+// instance.
+type BridgeMstEntry struct {
+	Msti  uint16
+	State uint8
+}
+
+func (b *BridgeMstEntry) String() string {
+	return fmt.Sprintf("%+v", *b)
+}
+
 /* New extended info filters for IFLA_EXT_MASK */
 const (
 	RTEXT_FILTER_VF = 1 << iota
 	RTEXT_FILTER_BRVLAN
 	RTEXT_FILTER_BRVLAN_COMPRESSED
+	RTEXT_FILTER_SKIP_STATS
+	RTEXT_FILTER_MRP
+	RTEXT_FILTER_CFM_CONFIG
+	RTEXT_FILTER_CFM_STATUS
+	RTEXT_FILTER_MST
 )
 
+/* Bridge boolean options, used with IFLA_BR_MULTI_BOOLOPT */
+const (
+	BR_BOOLOPT_NO_LL_LEARN = iota
+	BR_BOOLOPT_MCAST_VLAN_SNOOPING
+	BR_BOOLOPT_MST_ENABLE
+)
+
+//	struct br_boolopt_multi {
+//	  __u32 optval;
+//	  __u32 optmask;
+//	};
+type BrBooloptMulti struct {
+	Optval  uint32
+	Optmask uint32
+}
+
+func (b *BrBooloptMulti) Serialize() []byte {
+	return (*(*[SizeofBrBooloptMulti]byte)(unsafe.Pointer(b)))[:]
+}
+
+func DeserializeBrBooloptMulti(b []byte) *BrBooloptMulti {
+	return (*BrBooloptMulti)(unsafe.Pointer(&b[0:SizeofBrBooloptMulti][0]))
+}
+
+// RTM_NEWVLAN is misspelled as RTM_NEWNVLAN in golang.org/x/sys/unix.
+const RTM_NEWVLAN = 0x70
+
+/* Bridge VLAN database attributes, used with RTM_{NEW,DEL,GET}VLAN */
+const (
+	BRIDGE_VLANDB_UNSPEC = iota
+	BRIDGE_VLANDB_ENTRY
+	BRIDGE_VLANDB_GLOBAL_OPTIONS
+)
+
+const (
+	BRIDGE_VLANDB_DUMP_UNSPEC = iota
+	BRIDGE_VLANDB_DUMP_FLAGS
+)
+
+const (
+	BRIDGE_VLANDB_DUMPF_STATS = 1 << iota
+	BRIDGE_VLANDB_DUMPF_GLOBAL
+)
+
+const (
+	BRIDGE_VLANDB_GOPTS_UNSPEC = iota
+	BRIDGE_VLANDB_GOPTS_ID
+	BRIDGE_VLANDB_GOPTS_RANGE
+	BRIDGE_VLANDB_GOPTS_MCAST_SNOOPING
+	BRIDGE_VLANDB_GOPTS_MCAST_IGMP_VERSION
+	BRIDGE_VLANDB_GOPTS_MCAST_MLD_VERSION
+	BRIDGE_VLANDB_GOPTS_MCAST_LAST_MEMBER_CNT
+	BRIDGE_VLANDB_GOPTS_MCAST_STARTUP_QUERY_CNT
+	BRIDGE_VLANDB_GOPTS_MCAST_LAST_MEMBER_INTVL
+	BRIDGE_VLANDB_GOPTS_PAD
+	BRIDGE_VLANDB_GOPTS_MCAST_MEMBERSHIP_INTVL
+	BRIDGE_VLANDB_GOPTS_MCAST_QUERIER_INTVL
+	BRIDGE_VLANDB_GOPTS_MCAST_QUERY_INTVL
+	BRIDGE_VLANDB_GOPTS_MCAST_QUERY_RESPONSE_INTVL
+	BRIDGE_VLANDB_GOPTS_MCAST_STARTUP_QUERY_INTVL
+	BRIDGE_VLANDB_GOPTS_MCAST_QUERIER
+	BRIDGE_VLANDB_GOPTS_MCAST_ROUTER_PORTS
+	BRIDGE_VLANDB_GOPTS_MCAST_QUERIER_STATE
+	BRIDGE_VLANDB_GOPTS_MSTI
+)
+
+//	struct br_vlan_msg {
+//	  __u8 family;
+//	  __u8 reserved1;
+//	  __u16 reserved2;
+//	  __u32 ifindex;
+//	};
+type BrVlanMsg struct {
+	Family    uint8
+	Reserved1 uint8
+	Reserved2 uint16
+	Ifindex   uint32
+}
+
+func NewBrVlanMsg(family uint8, ifindex int) *BrVlanMsg {
+	return &BrVlanMsg{
+		Family:  family,
+		Ifindex: uint32(ifindex),
+	}
+}
+
+func (msg *BrVlanMsg) Len() int {
+	return SizeofBrVlanMsg
+}
+
+func (msg *BrVlanMsg) Serialize() []byte {
+	return (*(*[SizeofBrVlanMsg]byte)(unsafe.Pointer(msg)))[:]
+}
+
+func DeserializeBrVlanMsg(b []byte) *BrVlanMsg {
+	return (*BrVlanMsg)(unsafe.Pointer(&b[0:SizeofBrVlanMsg][0]))
+}
+
 /* VXLAN VNI filter attributes */
 const (
 	VXLAN_VNIFILTER_UNSPEC = iota
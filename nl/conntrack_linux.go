@@ -73,6 +73,43 @@ const (
 	NFNETLINK_V0 = 0
 )
 
+// #define NFNL_SUBSYS_NONE 		0
+// #define NFNL_SUBSYS_CTNETLINK		1
+// #define NFNL_SUBSYS_CTNETLINK_EXP	2
+// #define NFNL_SUBSYS_QUEUE		3
+// #define NFNL_SUBSYS_ULOG		4
+// #define NFNL_SUBSYS_OSF		5
+// #define NFNL_SUBSYS_IPSET		6
+// #define NFNL_SUBSYS_ACCT		7
+// #define NFNL_SUBSYS_CTNETLINK_TIMEOUT	8
+// #define NFNL_SUBSYS_CTHELPER		9
+// #define NFNL_SUBSYS_NFTABLES		10
+// #define NFNL_SUBSYS_NFT_COMPAT		11
+// #define NFNL_SUBSYS_HOOK		12
+const (
+	NFNL_SUBSYS_NONE              = 0
+	NFNL_SUBSYS_CTNETLINK         = 1
+	NFNL_SUBSYS_CTNETLINK_EXP     = 2
+	NFNL_SUBSYS_QUEUE             = 3
+	NFNL_SUBSYS_ULOG              = 4
+	NFNL_SUBSYS_OSF               = 5
+	NFNL_SUBSYS_IPSET             = 6
+	NFNL_SUBSYS_ACCT              = 7
+	NFNL_SUBSYS_CTNETLINK_TIMEOUT = 8
+	NFNL_SUBSYS_CTHELPER          = 9
+	NFNL_SUBSYS_NFTABLES          = 10
+	NFNL_SUBSYS_NFT_COMPAT        = 11
+	NFNL_SUBSYS_HOOK              = 12
+)
+
+// Reserved control nfnetlink messages
+// #define NFNL_MSG_BATCH_BEGIN		NLMSG_MIN_TYPE
+// #define NFNL_MSG_BATCH_END		NLMSG_MIN_TYPE+1
+const (
+	NFNL_MSG_BATCH_BEGIN = 0x10
+	NFNL_MSG_BATCH_END   = 0x11
+)
+
 const (
 	NLA_F_NESTED        uint16 = (1 << 15) // #define NLA_F_NESTED (1 << 15)
 	NLA_F_NET_BYTEORDER uint16 = (1 << 14) // #define NLA_F_NESTED (1 << 14)
@@ -0,0 +1,334 @@
+package nl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// enum netlink_attribute_type
+const (
+	NL_ATTR_TYPE_INVALID = iota
+	NL_ATTR_TYPE_FLAG
+	NL_ATTR_TYPE_U8
+	NL_ATTR_TYPE_U16
+	NL_ATTR_TYPE_U32
+	NL_ATTR_TYPE_U64
+	NL_ATTR_TYPE_S8
+	NL_ATTR_TYPE_S16
+	NL_ATTR_TYPE_S32
+	NL_ATTR_TYPE_S64
+	NL_ATTR_TYPE_BINARY
+	NL_ATTR_TYPE_STRING
+	NL_ATTR_TYPE_NUL_STRING
+	NL_ATTR_TYPE_NESTED
+	NL_ATTR_TYPE_NESTED_ARRAY
+	NL_ATTR_TYPE_BITFIELD32
+	NL_ATTR_TYPE_SINT
+	NL_ATTR_TYPE_UINT
+)
+
+// enum netlink_policy_type_attr
+const (
+	NL_POLICY_TYPE_ATTR_UNSPEC = iota
+	NL_POLICY_TYPE_ATTR_TYPE
+	NL_POLICY_TYPE_ATTR_MIN_VALUE_S
+	NL_POLICY_TYPE_ATTR_MAX_VALUE_S
+	NL_POLICY_TYPE_ATTR_MIN_VALUE_U
+	NL_POLICY_TYPE_ATTR_MAX_VALUE_U
+	NL_POLICY_TYPE_ATTR_MIN_LENGTH
+	NL_POLICY_TYPE_ATTR_MAX_LENGTH
+	NL_POLICY_TYPE_ATTR_POLICY_IDX
+	NL_POLICY_TYPE_ATTR_POLICY_MAXTYPE
+	NL_POLICY_TYPE_ATTR_BITFIELD32_MASK
+	NL_POLICY_TYPE_ATTR_PAD
+	NL_POLICY_TYPE_ATTR_MASK
+)
+
+// Error is an error reported by the kernel in answer to a netlink request,
+// along with its extended ack attributes. Use errors.As to retrieve it, and
+// errors.Is to compare its errno.
+//
+// Requests return an *Error only when the kernel attached extended ack
+// attributes to the error, and the bare errno otherwise, so that comparing
+// errors to errno values keeps working.
+type Error struct {
+	// Errno is the error number, or 0 for the warning attached to the
+	// acknowledgement of a successful request.
+	Errno syscall.Errno
+	// Msg is the extended ack message (NLMSGERR_ATTR_MSG).
+	Msg string
+	// Offset is the offset of the offending attribute in the request
+	// (NLMSGERR_ATTR_OFFS), or 0 if it was not reported.
+	Offset uint32
+	// AttrPath is the path of attribute types leading to the offending
+	// attribute in the request, outermost first, resolved from Offset.
+	AttrPath []uint16
+	// MissingType is the type of the missing attribute
+	// (NLMSGERR_ATTR_MISS_TYPE), or 0 if it was not reported.
+	MissingType uint16
+	// MissingNest is the offset of the nest missing the attribute in the
+	// request (NLMSGERR_ATTR_MISS_NEST), or 0 if the attribute is missing
+	// at the top level.
+	MissingNest uint32
+	// MissingNestPath is the path of attribute types leading to the nest
+	// missing the attribute, resolved from MissingNest.
+	MissingNestPath []uint16
+	// Policy is the policy the offending attribute does not comply with
+	// (NLMSGERR_ATTR_POLICY).
+	Policy *PolicyType
+	// Cookie is the opaque cookie returned by the kernel
+	// (NLMSGERR_ATTR_COOKIE).
+	Cookie []byte
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Errno != 0 {
+		b.WriteString(e.Errno.Error())
+	} else {
+		b.WriteString("netlink warning")
+	}
+	if e.Msg != "" {
+		b.WriteString(": ")
+		b.WriteString(e.Msg)
+	}
+	if len(e.AttrPath) > 0 {
+		fmt.Fprintf(&b, " (attribute %s)", formatAttrPath(e.AttrPath))
+	} else if e.Offset != 0 {
+		fmt.Fprintf(&b, " (attribute at offset %d)", e.Offset)
+	}
+	if e.MissingType != 0 {
+		fmt.Fprintf(&b, " (missing attribute %d", e.MissingType)
+		if len(e.MissingNestPath) > 0 {
+			fmt.Fprintf(&b, " in %s", formatAttrPath(e.MissingNestPath))
+		}
+		b.WriteString(")")
+	}
+	return b.String()
+}
+
+// Unwrap returns the errno of e.
+func (e *Error) Unwrap() error {
+	if e.Errno == 0 {
+		return nil
+	}
+	return e.Errno
+}
+
+func formatAttrPath(path []uint16) string {
+	s := make([]string, len(path))
+	for i, t := range path {
+		s[i] = strconv.Itoa(int(t))
+	}
+	return strings.Join(s, "/")
+}
+
+// PolicyType is the validation policy of a netlink attribute, as reported
+// in extended acks and by the policy dump of generic netlink families. The
+// fields which do not apply to the attribute type are nil.
+type PolicyType struct {
+	Type           uint32 // NL_ATTR_TYPE_*
+	MinValueS      *int64
+	MaxValueS      *int64
+	MinValueU      *uint64
+	MaxValueU      *uint64
+	MinLength      *uint32
+	MaxLength      *uint32
+	PolicyIdx      *uint32
+	PolicyMaxType  *uint32
+	Bitfield32Mask *uint32
+	Mask           *uint64
+}
+
+// ParsePolicyType parses the NL_POLICY_TYPE_ATTR_* attributes in b.
+func ParsePolicyType(b []byte) (*PolicyType, error) {
+	p := &PolicyType{}
+	ad := NewAttributeDecoder(b)
+	for ad.Next() {
+		switch ad.Type() {
+		case NL_POLICY_TYPE_ATTR_TYPE:
+			p.Type = ad.Uint32()
+		case NL_POLICY_TYPE_ATTR_MIN_VALUE_S:
+			v := ad.Int64()
+			p.MinValueS = &v
+		case NL_POLICY_TYPE_ATTR_MAX_VALUE_S:
+			v := ad.Int64()
+			p.MaxValueS = &v
+		case NL_POLICY_TYPE_ATTR_MIN_VALUE_U:
+			v := ad.Uint64()
+			p.MinValueU = &v
+		case NL_POLICY_TYPE_ATTR_MAX_VALUE_U:
+			v := ad.Uint64()
+			p.MaxValueU = &v
+		case NL_POLICY_TYPE_ATTR_MIN_LENGTH:
+			v := ad.Uint32()
+			p.MinLength = &v
+		case NL_POLICY_TYPE_ATTR_MAX_LENGTH:
+			v := ad.Uint32()
+			p.MaxLength = &v
+		case NL_POLICY_TYPE_ATTR_POLICY_IDX:
+			v := ad.Uint32()
+			p.PolicyIdx = &v
+		case NL_POLICY_TYPE_ATTR_POLICY_MAXTYPE:
+			v := ad.Uint32()
+			p.PolicyMaxType = &v
+		case NL_POLICY_TYPE_ATTR_BITFIELD32_MASK:
+			v := ad.Uint32()
+			p.Bitfield32Mask = &v
+		case NL_POLICY_TYPE_ATTR_MASK:
+			v := ad.Uint64()
+			p.Mask = &v
+		}
+	}
+	if err := ad.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// netlinkMessageError returns the error carried by m, a NLMSG_ERROR or
+// NLMSG_DONE message answering request, whose attributes start at offset
+// attrs. For the acknowledgement of a successful request, it returns a nil
+// error, and the warning attached to it by the kernel if any.
+func netlinkMessageError(m syscall.NetlinkMessage, request []byte, attrs int) (err error, warning *Error) {
+	native := NativeEndian()
+	errno := int32(native.Uint32(m.Data[0:4]))
+
+	unreadData := m.Data[4:]
+	if m.Header.Type == unix.NLMSG_ERROR {
+		if m.Header.Flags&unix.NLM_F_CAPPED != 0 {
+			// The request payload is capped, just skip the nlmsghdr
+			unreadData = unreadData[syscall.SizeofNlMsghdr:]
+		} else {
+			// Skip the entire request message
+			echoReqH := (*syscall.NlMsghdr)(unsafe.Pointer(&unreadData[0]))
+			unreadData = unreadData[nlmAlignOf(int(echoReqH.Len)):]
+		}
+	}
+
+	if m.Header.Flags&unix.NLM_F_ACK_TLVS == 0 || len(unreadData) < syscall.SizeofRtAttr {
+		if errno == 0 {
+			return nil, nil
+		}
+		return syscall.Errno(-errno), nil
+	}
+
+	e := &Error{Errno: syscall.Errno(-errno)}
+	// Annotate the error using nlmsgerr attributes.
+	for len(unreadData) >= syscall.SizeofRtAttr {
+		attr := (*syscall.RtAttr)(unsafe.Pointer(&unreadData[0]))
+		if int(attr.Len) < syscall.SizeofRtAttr || int(attr.Len) > len(unreadData) {
+			break
+		}
+		attrData := unreadData[syscall.SizeofRtAttr:attr.Len]
+
+		switch attr.Type & NLA_TYPE_MASK {
+		case NLMSGERR_ATTR_MSG:
+			e.Msg = unix.ByteSliceToString(attrData)
+		case NLMSGERR_ATTR_OFFS:
+			if len(attrData) < 4 {
+				break
+			}
+			e.Offset = native.Uint32(attrData)
+			e.AttrPath = attrPath(request, attrs, int(e.Offset))
+		case NLMSGERR_ATTR_COOKIE:
+			e.Cookie = append([]byte(nil), attrData...)
+		case NLMSGERR_ATTR_POLICY:
+			e.Policy, _ = ParsePolicyType(attrData)
+		case NLMSGERR_ATTR_MISS_TYPE:
+			if len(attrData) < 4 {
+				break
+			}
+			e.MissingType = uint16(native.Uint32(attrData))
+		case NLMSGERR_ATTR_MISS_NEST:
+			if len(attrData) < 4 {
+				break
+			}
+			e.MissingNest = native.Uint32(attrData)
+			e.MissingNestPath = attrPath(request, attrs, int(e.MissingNest))
+		}
+
+		unreadData = unreadData[rtaAlignOf(int(attr.Len)):]
+	}
+
+	if errno == 0 {
+		return nil, e
+	}
+	return e, nil
+}
+
+// attrPath returns the types of the attributes leading to the attribute at
+// offset in the serialized request msg, whose attributes start at offset
+// attrs. Attributes whose payload is itself a valid list of attributes are
+// considered nested. It returns nil if offset is not the one of an
+// attribute.
+func attrPath(msg []byte, attrs, offset int) []uint16 {
+	if attrs <= 0 || offset < attrs || offset >= len(msg) || !validAttrs(msg[attrs:]) {
+		return nil
+	}
+	native := NativeEndian()
+	var path []uint16
+	b, pos := msg[attrs:], attrs
+	for len(b) >= unix.SizeofRtAttr {
+		l := int(native.Uint16(b[0:2]))
+		if l < unix.SizeofRtAttr || l > len(b) {
+			return nil
+		}
+		aligned := rtaAlignOf(l)
+		if offset >= pos+aligned {
+			if aligned >= len(b) {
+				return nil
+			}
+			b, pos = b[aligned:], pos+aligned
+			continue
+		}
+		path = append(path, native.Uint16(b[2:4])&NLA_TYPE_MASK)
+		if offset == pos {
+			return path
+		}
+		// The offset points inside this attribute, walk its payload.
+		if offset < pos+unix.SizeofRtAttr || !validAttrs(b[unix.SizeofRtAttr:l]) {
+			return nil
+		}
+		b, pos = b[unix.SizeofRtAttr:l], pos+unix.SizeofRtAttr
+	}
+	return nil
+}
+
+// validAttrs reports whether b is a well formed list of attributes.
+func validAttrs(b []byte) bool {
+	native := NativeEndian()
+	for len(b) > 0 {
+		if len(b) < unix.SizeofRtAttr {
+			return false
+		}
+		l := int(native.Uint16(b[0:2]))
+		if l < unix.SizeofRtAttr || l > len(b) {
+			return false
+		}
+		if rtaAlignOf(l) >= len(b) {
+			return true
+		}
+		b = b[rtaAlignOf(l):]
+	}
+	return true
+}
+
+// attrsOffset returns the offset of the attributes in the serialized req,
+// that is the size of the netlink and family headers.
+func (req *NetlinkRequest) attrsOffset() int {
+	offset := unix.SizeofNlMsghdr
+	for _, data := range req.Data {
+		switch data.(type) {
+		case *RtAttr, *Uint32Attribute:
+			return offset
+		}
+		offset += data.Len()
+	}
+	return offset
+}
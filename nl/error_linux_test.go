@@ -0,0 +1,151 @@
+package nl
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestNetlinkMessageError(t *testing.T) {
+	req := NewNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
+	req.AddData(NewIfInfomsg(unix.AF_UNSPEC))
+	req.AddData(NewRtAttr(unix.IFLA_IFNAME, ZeroTerminated("foo")))
+	linkInfo := NewRtAttr(unix.IFLA_LINKINFO, nil)
+	linkInfo.AddRtAttr(IFLA_INFO_KIND, NonZeroTerminated("veth"))
+	data := linkInfo.AddRtAttr(IFLA_INFO_DATA, nil)
+	data.AddRtAttr(VETH_INFO_PEER, nil)
+	req.AddData(linkInfo)
+	msg := req.Serialize()
+
+	// IFLA_LINKINFO follows the nlmsghdr, the ifinfomsg and IFLA_IFNAME.
+	linkInfoOffset := unix.SizeofNlMsghdr + unix.SizeofIfInfomsg + 8
+	// VETH_INFO_PEER follows the IFLA_LINKINFO header, IFLA_INFO_KIND and
+	// the IFLA_INFO_DATA header.
+	peerOffset := linkInfoOffset + 4 + 8 + 4
+	if path := attrPath(msg, req.attrsOffset(), peerOffset); len(path) != 3 ||
+		path[0] != unix.IFLA_LINKINFO || path[1] != IFLA_INFO_DATA || path[2] != VETH_INFO_PEER {
+		t.Fatalf("unexpected path to VETH_INFO_PEER: %v", path)
+	}
+
+	policy := NewRtAttr(NLMSGERR_ATTR_POLICY, nil)
+	policy.AddRtAttr(NL_POLICY_TYPE_ATTR_TYPE, Uint32Attr(NL_ATTR_TYPE_U32))
+	policy.AddRtAttr(NL_POLICY_TYPE_ATTR_MAX_VALUE_U, Uint64Attr(42))
+	b := Uint32Attr(^uint32(unix.EINVAL) + 1)
+	b = append(b, msg[:unix.SizeofNlMsghdr]...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_MSG, ZeroTerminated("bad peer")).Serialize()...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_OFFS, Uint32Attr(uint32(peerOffset))).Serialize()...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_MISS_TYPE, Uint32Attr(unix.IFLA_MTU)).Serialize()...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_MISS_NEST, Uint32Attr(uint32(linkInfoOffset))).Serialize()...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_COOKIE, []byte{1, 2}).Serialize()...)
+	b = append(b, policy.Serialize()...)
+	m := syscall.NetlinkMessage{
+		Header: syscall.NlMsghdr{
+			Type:  unix.NLMSG_ERROR,
+			Flags: unix.NLM_F_CAPPED | unix.NLM_F_ACK_TLVS,
+		},
+		Data: b,
+	}
+
+	err, warning := netlinkMessageError(m, msg, req.attrsOffset())
+	if warning != nil {
+		t.Fatalf("unexpected warning: %v", warning)
+	}
+	if !errors.Is(err, unix.EINVAL) {
+		t.Fatalf("expected EINVAL, got %v", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expected an *Error, got %T", err)
+	}
+	if e.Msg != "bad peer" || e.Offset != uint32(peerOffset) || len(e.AttrPath) != 3 ||
+		e.MissingType != unix.IFLA_MTU || len(e.MissingNestPath) != 1 || e.MissingNestPath[0] != unix.IFLA_LINKINFO ||
+		len(e.Cookie) != 2 {
+		t.Fatalf("unexpected error: %+v", e)
+	}
+	if e.Policy == nil || e.Policy.Type != NL_ATTR_TYPE_U32 || e.Policy.MaxValueU == nil || *e.Policy.MaxValueU != 42 ||
+		e.Policy.MinValueU != nil {
+		t.Fatalf("unexpected policy: %+v", e.Policy)
+	}
+	expected := "invalid argument: bad peer (attribute 18/2/1) (missing attribute 4 in 18)"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+
+	// Warnings come with a successful acknowledgement.
+	b = Uint32Attr(0)
+	b = append(b, msg[:unix.SizeofNlMsghdr]...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_MSG, ZeroTerminated("deprecated")).Serialize()...)
+	m.Data = b
+	err, warning = netlinkMessageError(m, msg, req.attrsOffset())
+	if err != nil || warning == nil || warning.Msg != "deprecated" {
+		t.Fatalf("unexpected acknowledgement: %v %+v", err, warning)
+	}
+
+	// Without extended ack attributes, the bare errno is returned.
+	m.Header.Flags = unix.NLM_F_CAPPED
+	m.Data = append(Uint32Attr(^uint32(unix.ENODEV)+1), msg[:unix.SizeofNlMsghdr]...)
+	if err, _ := netlinkMessageError(m, msg, req.attrsOffset()); err != unix.ENODEV {
+		t.Fatalf("expected ENODEV, got %#v", err)
+	}
+}
+
+func TestNetlinkMessageErrorShortAttrs(t *testing.T) {
+	req := NewNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_ACK)
+	req.AddData(NewIfInfomsg(unix.AF_UNSPEC))
+	msg := req.Serialize()
+
+	// Attributes too short for their type are ignored.
+	b := Uint32Attr(^uint32(unix.EINVAL) + 1)
+	b = append(b, msg[:unix.SizeofNlMsghdr]...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_OFFS, nil).Serialize()...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_MISS_TYPE, []byte{1, 2}).Serialize()...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_MISS_NEST, []byte{1}).Serialize()...)
+	b = append(b, NewRtAttr(NLMSGERR_ATTR_MSG, ZeroTerminated("bad")).Serialize()...)
+	m := syscall.NetlinkMessage{
+		Header: syscall.NlMsghdr{
+			Type:  unix.NLMSG_ERROR,
+			Flags: unix.NLM_F_CAPPED | unix.NLM_F_ACK_TLVS,
+		},
+		Data: b,
+	}
+
+	err, _ := netlinkMessageError(m, msg, req.attrsOffset())
+	var e *Error
+	if !errors.As(err, &e) || e.Errno != unix.EINVAL {
+		t.Fatalf("expected an EINVAL *Error, got %#v", err)
+	}
+	if e.Msg != "bad" || e.Offset != 0 || e.AttrPath != nil || e.MissingType != 0 || e.MissingNest != 0 {
+		t.Fatalf("unexpected error: %+v", e)
+	}
+}
+
+func TestExecuteExtAck(t *testing.T) {
+	setUpBatchTestNetns(t)
+	defer func(enabled bool) {
+		EnableErrorMessageReporting = enabled
+	}(EnableErrorMessageReporting)
+	EnableErrorMessageReporting = true
+
+	// IFLA_MTU is too short for its u32 policy.
+	req := NewNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
+	msg := NewIfInfomsg(unix.AF_UNSPEC)
+	msg.Index = 1
+	req.AddData(msg)
+	req.AddData(NewRtAttr(unix.IFLA_MTU, Uint16Attr(1400)))
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	if !errors.Is(err, unix.ERANGE) {
+		t.Fatalf("expected ERANGE, got %v", err)
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Skipf("extended ack not supported: %v", err)
+	}
+	if len(e.AttrPath) != 1 || e.AttrPath[0] != unix.IFLA_MTU {
+		t.Fatalf("expected IFLA_MTU to be reported, got %+v", e)
+	}
+	if e.Policy == nil || e.Policy.Type != NL_ATTR_TYPE_U32 {
+		t.Fatalf("expected u32 policy to be reported, got %+v", e.Policy)
+	}
+}
@@ -0,0 +1,588 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/ethtool_netlink.h
+
+const (
+	ETHTOOL_GENL_NAME          = "ethtool"
+	ETHTOOL_GENL_VERSION       = 1
+	ETHTOOL_MCGRP_MONITOR_NAME = "monitor"
+)
+
+/* message types - userspace to kernel */
+const (
+	ETHTOOL_MSG_USER_NONE = iota
+	ETHTOOL_MSG_STRSET_GET
+	ETHTOOL_MSG_LINKINFO_GET
+	ETHTOOL_MSG_LINKINFO_SET
+	ETHTOOL_MSG_LINKMODES_GET
+	ETHTOOL_MSG_LINKMODES_SET
+	ETHTOOL_MSG_LINKSTATE_GET
+	ETHTOOL_MSG_DEBUG_GET
+	ETHTOOL_MSG_DEBUG_SET
+	ETHTOOL_MSG_WOL_GET
+	ETHTOOL_MSG_WOL_SET
+	ETHTOOL_MSG_FEATURES_GET
+	ETHTOOL_MSG_FEATURES_SET
+	ETHTOOL_MSG_PRIVFLAGS_GET
+	ETHTOOL_MSG_PRIVFLAGS_SET
+	ETHTOOL_MSG_RINGS_GET
+	ETHTOOL_MSG_RINGS_SET
+	ETHTOOL_MSG_CHANNELS_GET
+	ETHTOOL_MSG_CHANNELS_SET
+	ETHTOOL_MSG_COALESCE_GET
+	ETHTOOL_MSG_COALESCE_SET
+	ETHTOOL_MSG_PAUSE_GET
+	ETHTOOL_MSG_PAUSE_SET
+	ETHTOOL_MSG_EEE_GET
+	ETHTOOL_MSG_EEE_SET
+	ETHTOOL_MSG_TSINFO_GET
+	ETHTOOL_MSG_CABLE_TEST_ACT
+	ETHTOOL_MSG_CABLE_TEST_TDR_ACT
+	ETHTOOL_MSG_TUNNEL_INFO_GET
+	ETHTOOL_MSG_FEC_GET
+	ETHTOOL_MSG_FEC_SET
+	ETHTOOL_MSG_MODULE_EEPROM_GET
+	ETHTOOL_MSG_STATS_GET
+	ETHTOOL_MSG_PHC_VCLOCKS_GET
+	ETHTOOL_MSG_MODULE_GET
+	ETHTOOL_MSG_MODULE_SET
+)
+
+/* message types - kernel to userspace */
+const (
+	ETHTOOL_MSG_KERNEL_NONE = iota
+	ETHTOOL_MSG_STRSET_GET_REPLY
+	ETHTOOL_MSG_LINKINFO_GET_REPLY
+	ETHTOOL_MSG_LINKINFO_NTF
+	ETHTOOL_MSG_LINKMODES_GET_REPLY
+	ETHTOOL_MSG_LINKMODES_NTF
+	ETHTOOL_MSG_LINKSTATE_GET_REPLY
+	ETHTOOL_MSG_DEBUG_GET_REPLY
+	ETHTOOL_MSG_DEBUG_NTF
+	ETHTOOL_MSG_WOL_GET_REPLY
+	ETHTOOL_MSG_WOL_NTF
+	ETHTOOL_MSG_FEATURES_GET_REPLY
+	ETHTOOL_MSG_FEATURES_SET_REPLY
+	ETHTOOL_MSG_FEATURES_NTF
+	ETHTOOL_MSG_PRIVFLAGS_GET_REPLY
+	ETHTOOL_MSG_PRIVFLAGS_NTF
+	ETHTOOL_MSG_RINGS_GET_REPLY
+	ETHTOOL_MSG_RINGS_NTF
+	ETHTOOL_MSG_CHANNELS_GET_REPLY
+	ETHTOOL_MSG_CHANNELS_NTF
+	ETHTOOL_MSG_COALESCE_GET_REPLY
+	ETHTOOL_MSG_COALESCE_NTF
+	ETHTOOL_MSG_PAUSE_GET_REPLY
+	ETHTOOL_MSG_PAUSE_NTF
+	ETHTOOL_MSG_EEE_GET_REPLY
+	ETHTOOL_MSG_EEE_NTF
+	ETHTOOL_MSG_TSINFO_GET_REPLY
+	ETHTOOL_MSG_CABLE_TEST_NTF
+	ETHTOOL_MSG_CABLE_TEST_TDR_NTF
+	ETHTOOL_MSG_TUNNEL_INFO_GET_REPLY
+	ETHTOOL_MSG_FEC_GET_REPLY
+	ETHTOOL_MSG_FEC_NTF
+	ETHTOOL_MSG_MODULE_EEPROM_GET_REPLY
+	ETHTOOL_MSG_STATS_GET_REPLY
+	ETHTOOL_MSG_PHC_VCLOCKS_GET_REPLY
+	ETHTOOL_MSG_MODULE_GET_REPLY
+	ETHTOOL_MSG_MODULE_NTF
+)
+
+/* request header */
+const (
+	ETHTOOL_FLAG_COMPACT_BITSETS = 1 << 0 /* use compact bitsets in reply */
+	ETHTOOL_FLAG_OMIT_REPLY      = 1 << 1 /* provide optional reply for SET or ACT requests */
+	ETHTOOL_FLAG_STATS           = 1 << 2 /* request statistics, if supported by the driver */
+)
+
+const (
+	ETHTOOL_A_HEADER_UNSPEC    = iota
+	ETHTOOL_A_HEADER_DEV_INDEX /* u32 */
+	ETHTOOL_A_HEADER_DEV_NAME  /* string */
+	ETHTOOL_A_HEADER_FLAGS     /* u32 - ETHTOOL_FLAG_* */
+)
+
+/* bit sets */
+const (
+	ETHTOOL_A_BITSET_BIT_UNSPEC = iota
+	ETHTOOL_A_BITSET_BIT_INDEX  /* u32 */
+	ETHTOOL_A_BITSET_BIT_NAME   /* string */
+	ETHTOOL_A_BITSET_BIT_VALUE  /* flag */
+)
+
+const (
+	ETHTOOL_A_BITSET_BITS_UNSPEC = iota
+	ETHTOOL_A_BITSET_BITS_BIT    /* nest - _A_BITSET_BIT_* */
+)
+
+const (
+	ETHTOOL_A_BITSET_UNSPEC = iota
+	ETHTOOL_A_BITSET_NOMASK /* flag */
+	ETHTOOL_A_BITSET_SIZE   /* u32 */
+	ETHTOOL_A_BITSET_BITS   /* nest - _A_BITSET_BITS_* */
+	ETHTOOL_A_BITSET_VALUE  /* binary */
+	ETHTOOL_A_BITSET_MASK   /* binary */
+)
+
+/* string sets */
+const (
+	ETHTOOL_A_STRING_UNSPEC = iota
+	ETHTOOL_A_STRING_INDEX  /* u32 */
+	ETHTOOL_A_STRING_VALUE  /* string */
+)
+
+const (
+	ETHTOOL_A_STRINGS_UNSPEC = iota
+	ETHTOOL_A_STRINGS_STRING /* nest - _A_STRINGS_* */
+)
+
+const (
+	ETHTOOL_A_STRINGSET_UNSPEC  = iota
+	ETHTOOL_A_STRINGSET_ID      /* u32 */
+	ETHTOOL_A_STRINGSET_COUNT   /* u32 */
+	ETHTOOL_A_STRINGSET_STRINGS /* nest - _A_STRINGS_* */
+)
+
+const (
+	ETHTOOL_A_STRINGSETS_UNSPEC    = iota
+	ETHTOOL_A_STRINGSETS_STRINGSET /* nest - _A_STRINGSET_* */
+)
+
+const (
+	ETHTOOL_A_STRSET_UNSPEC      = iota
+	ETHTOOL_A_STRSET_HEADER      /* nest - _A_HEADER_* */
+	ETHTOOL_A_STRSET_STRINGSETS  /* nest - _A_STRINGSETS_* */
+	ETHTOOL_A_STRSET_COUNTS_ONLY /* flag */
+)
+
+// String set IDs, from include/uapi/linux/ethtool.h
+const (
+	ETH_SS_TEST = iota
+	ETH_SS_STATS
+	ETH_SS_PRIV_FLAGS
+	ETH_SS_NTUPLE_FILTERS
+	ETH_SS_FEATURES
+	ETH_SS_RSS_HASH_FUNCS
+	ETH_SS_TUNABLES
+	ETH_SS_PHY_STATS
+	ETH_SS_PHY_TUNABLES
+	ETH_SS_LINK_MODES
+	ETH_SS_MSG_CLASSES
+	ETH_SS_WOL_MODES
+	ETH_SS_SOF_TIMESTAMPING
+	ETH_SS_TS_TX_TYPES
+	ETH_SS_TS_RX_FILTERS
+	ETH_SS_UDP_TUNNEL_TYPES
+	ETH_SS_STATS_STD
+	ETH_SS_STATS_ETH_PHY
+	ETH_SS_STATS_ETH_MAC
+	ETH_SS_STATS_ETH_CTRL
+	ETH_SS_STATS_RMON
+)
+
+/* LINKINFO */
+const (
+	ETHTOOL_A_LINKINFO_UNSPEC       = iota
+	ETHTOOL_A_LINKINFO_HEADER       /* nest - _A_HEADER_* */
+	ETHTOOL_A_LINKINFO_PORT         /* u8 */
+	ETHTOOL_A_LINKINFO_PHYADDR      /* u8 */
+	ETHTOOL_A_LINKINFO_TP_MDIX      /* u8 */
+	ETHTOOL_A_LINKINFO_TP_MDIX_CTRL /* u8 */
+	ETHTOOL_A_LINKINFO_TRANSCEIVER  /* u8 */
+)
+
+/* LINKMODES */
+const (
+	ETHTOOL_A_LINKMODES_UNSPEC             = iota
+	ETHTOOL_A_LINKMODES_HEADER             /* nest - _A_HEADER_* */
+	ETHTOOL_A_LINKMODES_AUTONEG            /* u8 */
+	ETHTOOL_A_LINKMODES_OURS               /* bitset */
+	ETHTOOL_A_LINKMODES_PEER               /* bitset */
+	ETHTOOL_A_LINKMODES_SPEED              /* u32 */
+	ETHTOOL_A_LINKMODES_DUPLEX             /* u8 */
+	ETHTOOL_A_LINKMODES_MASTER_SLAVE_CFG   /* u8 */
+	ETHTOOL_A_LINKMODES_MASTER_SLAVE_STATE /* u8 */
+	ETHTOOL_A_LINKMODES_LANES              /* u32 */
+	ETHTOOL_A_LINKMODES_RATE_MATCHING      /* u8 */
+)
+
+/* LINKSTATE */
+const (
+	ETHTOOL_A_LINKSTATE_UNSPEC       = iota
+	ETHTOOL_A_LINKSTATE_HEADER       /* nest - _A_HEADER_* */
+	ETHTOOL_A_LINKSTATE_LINK         /* u8 */
+	ETHTOOL_A_LINKSTATE_SQI          /* u32 */
+	ETHTOOL_A_LINKSTATE_SQI_MAX      /* u32 */
+	ETHTOOL_A_LINKSTATE_EXT_STATE    /* u8 */
+	ETHTOOL_A_LINKSTATE_EXT_SUBSTATE /* u8 */
+	ETHTOOL_A_LINKSTATE_EXT_DOWN_CNT /* u32 */
+)
+
+/* FEATURES */
+const (
+	ETHTOOL_A_FEATURES_UNSPEC   = iota
+	ETHTOOL_A_FEATURES_HEADER   /* nest - _A_HEADER_* */
+	ETHTOOL_A_FEATURES_HW       /* bitset */
+	ETHTOOL_A_FEATURES_WANTED   /* bitset */
+	ETHTOOL_A_FEATURES_ACTIVE   /* bitset */
+	ETHTOOL_A_FEATURES_NOCHANGE /* bitset */
+)
+
+/* PRIVFLAGS */
+const (
+	ETHTOOL_A_PRIVFLAGS_UNSPEC = iota
+	ETHTOOL_A_PRIVFLAGS_HEADER /* nest - _A_HEADER_* */
+	ETHTOOL_A_PRIVFLAGS_FLAGS  /* bitset */
+)
+
+/* RINGS */
+const (
+	ETHTOOL_A_RINGS_UNSPEC              = iota
+	ETHTOOL_A_RINGS_HEADER              /* nest - _A_HEADER_* */
+	ETHTOOL_A_RINGS_RX_MAX              /* u32 */
+	ETHTOOL_A_RINGS_RX_MINI_MAX         /* u32 */
+	ETHTOOL_A_RINGS_RX_JUMBO_MAX        /* u32 */
+	ETHTOOL_A_RINGS_TX_MAX              /* u32 */
+	ETHTOOL_A_RINGS_RX                  /* u32 */
+	ETHTOOL_A_RINGS_RX_MINI             /* u32 */
+	ETHTOOL_A_RINGS_RX_JUMBO            /* u32 */
+	ETHTOOL_A_RINGS_TX                  /* u32 */
+	ETHTOOL_A_RINGS_RX_BUF_LEN          /* u32 */
+	ETHTOOL_A_RINGS_TCP_DATA_SPLIT      /* u8 */
+	ETHTOOL_A_RINGS_CQE_SIZE            /* u32 */
+	ETHTOOL_A_RINGS_TX_PUSH             /* u8 */
+	ETHTOOL_A_RINGS_RX_PUSH             /* u8 */
+	ETHTOOL_A_RINGS_TX_PUSH_BUF_LEN     /* u32 */
+	ETHTOOL_A_RINGS_TX_PUSH_BUF_LEN_MAX /* u32 */
+)
+
+/* CHANNELS */
+const (
+	ETHTOOL_A_CHANNELS_UNSPEC         = iota
+	ETHTOOL_A_CHANNELS_HEADER         /* nest - _A_HEADER_* */
+	ETHTOOL_A_CHANNELS_RX_MAX         /* u32 */
+	ETHTOOL_A_CHANNELS_TX_MAX         /* u32 */
+	ETHTOOL_A_CHANNELS_OTHER_MAX      /* u32 */
+	ETHTOOL_A_CHANNELS_COMBINED_MAX   /* u32 */
+	ETHTOOL_A_CHANNELS_RX_COUNT       /* u32 */
+	ETHTOOL_A_CHANNELS_TX_COUNT       /* u32 */
+	ETHTOOL_A_CHANNELS_OTHER_COUNT    /* u32 */
+	ETHTOOL_A_CHANNELS_COMBINED_COUNT /* u32 */
+)
+
+/* COALESCE */
+const (
+	ETHTOOL_A_COALESCE_UNSPEC               = iota
+	ETHTOOL_A_COALESCE_HEADER               /* nest - _A_HEADER_* */
+	ETHTOOL_A_COALESCE_RX_USECS             /* u32 */
+	ETHTOOL_A_COALESCE_RX_MAX_FRAMES        /* u32 */
+	ETHTOOL_A_COALESCE_RX_USECS_IRQ         /* u32 */
+	ETHTOOL_A_COALESCE_RX_MAX_FRAMES_IRQ    /* u32 */
+	ETHTOOL_A_COALESCE_TX_USECS             /* u32 */
+	ETHTOOL_A_COALESCE_TX_MAX_FRAMES        /* u32 */
+	ETHTOOL_A_COALESCE_TX_USECS_IRQ         /* u32 */
+	ETHTOOL_A_COALESCE_TX_MAX_FRAMES_IRQ    /* u32 */
+	ETHTOOL_A_COALESCE_STATS_BLOCK_USECS    /* u32 */
+	ETHTOOL_A_COALESCE_USE_ADAPTIVE_RX      /* u8 */
+	ETHTOOL_A_COALESCE_USE_ADAPTIVE_TX      /* u8 */
+	ETHTOOL_A_COALESCE_PKT_RATE_LOW         /* u32 */
+	ETHTOOL_A_COALESCE_RX_USECS_LOW         /* u32 */
+	ETHTOOL_A_COALESCE_RX_MAX_FRAMES_LOW    /* u32 */
+	ETHTOOL_A_COALESCE_TX_USECS_LOW         /* u32 */
+	ETHTOOL_A_COALESCE_TX_MAX_FRAMES_LOW    /* u32 */
+	ETHTOOL_A_COALESCE_PKT_RATE_HIGH        /* u32 */
+	ETHTOOL_A_COALESCE_RX_USECS_HIGH        /* u32 */
+	ETHTOOL_A_COALESCE_RX_MAX_FRAMES_HIGH   /* u32 */
+	ETHTOOL_A_COALESCE_TX_USECS_HIGH        /* u32 */
+	ETHTOOL_A_COALESCE_TX_MAX_FRAMES_HIGH   /* u32 */
+	ETHTOOL_A_COALESCE_RATE_SAMPLE_INTERVAL /* u32 */
+	ETHTOOL_A_COALESCE_USE_CQE_MODE_TX      /* u8 */
+	ETHTOOL_A_COALESCE_USE_CQE_MODE_RX      /* u8 */
+	ETHTOOL_A_COALESCE_TX_AGGR_MAX_BYTES    /* u32 */
+	ETHTOOL_A_COALESCE_TX_AGGR_MAX_FRAMES   /* u32 */
+	ETHTOOL_A_COALESCE_TX_AGGR_TIME_USECS   /* u32 */
+)
+
+/* PAUSE */
+const (
+	ETHTOOL_A_PAUSE_UNSPEC  = iota
+	ETHTOOL_A_PAUSE_HEADER  /* nest - _A_HEADER_* */
+	ETHTOOL_A_PAUSE_AUTONEG /* u8 */
+	ETHTOOL_A_PAUSE_RX      /* u8 */
+	ETHTOOL_A_PAUSE_TX      /* u8 */
+	ETHTOOL_A_PAUSE_STATS   /* nest - _PAUSE_STAT_* */
+)
+
+const (
+	ETHTOOL_A_PAUSE_STAT_UNSPEC = iota
+	ETHTOOL_A_PAUSE_STAT_PAD
+	ETHTOOL_A_PAUSE_STAT_TX_FRAMES /* u64 */
+	ETHTOOL_A_PAUSE_STAT_RX_FRAMES /* u64 */
+)
+
+/* EEE */
+const (
+	ETHTOOL_A_EEE_UNSPEC         = iota
+	ETHTOOL_A_EEE_HEADER         /* nest - _A_HEADER_* */
+	ETHTOOL_A_EEE_MODES_OURS     /* bitset */
+	ETHTOOL_A_EEE_MODES_PEER     /* bitset */
+	ETHTOOL_A_EEE_ACTIVE         /* u8 */
+	ETHTOOL_A_EEE_ENABLED        /* u8 */
+	ETHTOOL_A_EEE_TX_LPI_ENABLED /* u8 */
+	ETHTOOL_A_EEE_TX_LPI_TIMER   /* u32 */
+)
+
+/* TSINFO */
+const (
+	ETHTOOL_A_TSINFO_UNSPEC       = iota
+	ETHTOOL_A_TSINFO_HEADER       /* nest - _A_HEADER_* */
+	ETHTOOL_A_TSINFO_TIMESTAMPING /* bitset */
+	ETHTOOL_A_TSINFO_TX_TYPES     /* bitset */
+	ETHTOOL_A_TSINFO_RX_FILTERS   /* bitset */
+	ETHTOOL_A_TSINFO_PHC_INDEX    /* u32 */
+	ETHTOOL_A_TSINFO_STATS        /* nest - _A_TS_STAT */
+)
+
+const (
+	ETHTOOL_A_TS_STAT_UNSPEC  = iota
+	ETHTOOL_A_TS_STAT_TX_PKTS /* uint */
+	ETHTOOL_A_TS_STAT_TX_LOST /* uint */
+	ETHTOOL_A_TS_STAT_TX_ERR  /* uint */
+)
+
+/* CABLE TEST */
+const (
+	ETHTOOL_A_CABLE_TEST_UNSPEC = iota
+	ETHTOOL_A_CABLE_TEST_HEADER /* nest - _A_HEADER_* */
+)
+
+/* CABLE TEST NOTIFY */
+const (
+	ETHTOOL_A_CABLE_RESULT_CODE_UNSPEC = iota
+	ETHTOOL_A_CABLE_RESULT_CODE_OK
+	ETHTOOL_A_CABLE_RESULT_CODE_OPEN
+	ETHTOOL_A_CABLE_RESULT_CODE_SAME_SHORT
+	ETHTOOL_A_CABLE_RESULT_CODE_CROSS_SHORT
+	ETHTOOL_A_CABLE_RESULT_CODE_IMPEDANCE_MISMATCH
+	ETHTOOL_A_CABLE_RESULT_CODE_NOISE
+	ETHTOOL_A_CABLE_RESULT_CODE_RESOLUTION_NOT_POSSIBLE
+)
+
+const (
+	ETHTOOL_A_CABLE_PAIR_A = iota
+	ETHTOOL_A_CABLE_PAIR_B
+	ETHTOOL_A_CABLE_PAIR_C
+	ETHTOOL_A_CABLE_PAIR_D
+)
+
+const (
+	ETHTOOL_A_CABLE_INF_SRC_UNSPEC = iota
+	ETHTOOL_A_CABLE_INF_SRC_TDR
+	ETHTOOL_A_CABLE_INF_SRC_ALCD
+)
+
+const (
+	ETHTOOL_A_CABLE_RESULT_UNSPEC = iota
+	ETHTOOL_A_CABLE_RESULT_PAIR   /* u8 ETHTOOL_A_CABLE_PAIR_ */
+	ETHTOOL_A_CABLE_RESULT_CODE   /* u8 ETHTOOL_A_CABLE_RESULT_CODE_ */
+	ETHTOOL_A_CABLE_RESULT_SRC    /* u32 ETHTOOL_A_CABLE_INF_SRC_ */
+)
+
+const (
+	ETHTOOL_A_CABLE_FAULT_LENGTH_UNSPEC = iota
+	ETHTOOL_A_CABLE_FAULT_LENGTH_PAIR   /* u8 ETHTOOL_A_CABLE_PAIR_ */
+	ETHTOOL_A_CABLE_FAULT_LENGTH_CM     /* u32 */
+	ETHTOOL_A_CABLE_FAULT_LENGTH_SRC    /* u32 ETHTOOL_A_CABLE_INF_SRC_ */
+)
+
+const (
+	ETHTOOL_A_CABLE_TEST_NTF_STATUS_UNSPEC = iota
+	ETHTOOL_A_CABLE_TEST_NTF_STATUS_STARTED
+	ETHTOOL_A_CABLE_TEST_NTF_STATUS_COMPLETED
+)
+
+const (
+	ETHTOOL_A_CABLE_NEST_UNSPEC       = iota
+	ETHTOOL_A_CABLE_NEST_RESULT       /* nest - ETHTOOL_A_CABLE_RESULT_ */
+	ETHTOOL_A_CABLE_NEST_FAULT_LENGTH /* nest - ETHTOOL_A_CABLE_FAULT_LENGTH_ */
+)
+
+const (
+	ETHTOOL_A_CABLE_TEST_NTF_UNSPEC = iota
+	ETHTOOL_A_CABLE_TEST_NTF_HEADER /* nest - ETHTOOL_A_HEADER_* */
+	ETHTOOL_A_CABLE_TEST_NTF_STATUS /* u8 - _STARTED/_COMPLETE */
+	ETHTOOL_A_CABLE_TEST_NTF_NEST   /* nest - of results: */
+)
+
+/* CABLE TEST TDR */
+const (
+	ETHTOOL_A_CABLE_TEST_TDR_CFG_UNSPEC = iota
+	ETHTOOL_A_CABLE_TEST_TDR_CFG_FIRST  /* u32 */
+	ETHTOOL_A_CABLE_TEST_TDR_CFG_LAST   /* u32 */
+	ETHTOOL_A_CABLE_TEST_TDR_CFG_STEP   /* u32 */
+	ETHTOOL_A_CABLE_TEST_TDR_CFG_PAIR   /* u8 */
+)
+
+const (
+	ETHTOOL_A_CABLE_TEST_TDR_UNSPEC = iota
+	ETHTOOL_A_CABLE_TEST_TDR_HEADER /* nest - _A_HEADER_* */
+	ETHTOOL_A_CABLE_TEST_TDR_CFG    /* nest - *_TDR_CFG_* */
+)
+
+/* CABLE TEST TDR NOTIFY */
+const (
+	ETHTOOL_A_CABLE_AMPLITUDE_UNSPEC = iota
+	ETHTOOL_A_CABLE_AMPLITUDE_PAIR   /* u8 */
+	ETHTOOL_A_CABLE_AMPLITUDE_mV     /* s16 */
+)
+
+const (
+	ETHTOOL_A_CABLE_PULSE_UNSPEC = iota
+	ETHTOOL_A_CABLE_PULSE_mV     /* s16 */
+)
+
+const (
+	ETHTOOL_A_CABLE_STEP_UNSPEC         = iota
+	ETHTOOL_A_CABLE_STEP_FIRST_DISTANCE /* u32 */
+	ETHTOOL_A_CABLE_STEP_LAST_DISTANCE  /* u32 */
+	ETHTOOL_A_CABLE_STEP_STEP_DISTANCE  /* u32 */
+)
+
+const (
+	ETHTOOL_A_CABLE_TDR_NEST_UNSPEC    = iota
+	ETHTOOL_A_CABLE_TDR_NEST_STEP      /* nest - ETHTOOL_A_CABLE_STEP */
+	ETHTOOL_A_CABLE_TDR_NEST_AMPLITUDE /* nest - ETHTOOL_A_CABLE_AMPLITUDE_ */
+	ETHTOOL_A_CABLE_TDR_NEST_PULSE     /* nest - ETHTOOL_A_CABLE_PULSE_ */
+)
+
+const (
+	ETHTOOL_A_CABLE_TEST_TDR_NTF_UNSPEC = iota
+	ETHTOOL_A_CABLE_TEST_TDR_NTF_HEADER /* nest - ETHTOOL_A_HEADER_* */
+	ETHTOOL_A_CABLE_TEST_TDR_NTF_STATUS /* u8 - _STARTED/_COMPLETE */
+	ETHTOOL_A_CABLE_TEST_TDR_NTF_NEST   /* nest - of results: */
+)
+
+/* MODULE EEPROM */
+const (
+	ETHTOOL_A_MODULE_EEPROM_UNSPEC      = iota
+	ETHTOOL_A_MODULE_EEPROM_HEADER      /* nest - _A_HEADER_* */
+	ETHTOOL_A_MODULE_EEPROM_OFFSET      /* u32 */
+	ETHTOOL_A_MODULE_EEPROM_LENGTH      /* u32 */
+	ETHTOOL_A_MODULE_EEPROM_PAGE        /* u8 */
+	ETHTOOL_A_MODULE_EEPROM_BANK        /* u8 */
+	ETHTOOL_A_MODULE_EEPROM_I2C_ADDRESS /* u8 */
+	ETHTOOL_A_MODULE_EEPROM_DATA        /* binary */
+)
+
+/* PHC VCLOCKS */
+const (
+	ETHTOOL_A_PHC_VCLOCKS_UNSPEC = iota
+	ETHTOOL_A_PHC_VCLOCKS_HEADER /* nest - _A_HEADER_* */
+	ETHTOOL_A_PHC_VCLOCKS_NUM    /* u32 */
+	ETHTOOL_A_PHC_VCLOCKS_INDEX  /* array, s32 */
+)
+
+/* STATS */
+const (
+	ETHTOOL_A_STATS_UNSPEC = iota
+	ETHTOOL_A_STATS_PAD
+	ETHTOOL_A_STATS_HEADER /* nest - _A_HEADER_* */
+	ETHTOOL_A_STATS_GROUPS /* bitset */
+	ETHTOOL_A_STATS_GRP    /* nest - _A_STATS_GRP_* */
+	ETHTOOL_A_STATS_SRC    /* u32 */
+)
+
+const (
+	ETHTOOL_STATS_ETH_PHY = iota
+	ETHTOOL_STATS_ETH_MAC
+	ETHTOOL_STATS_ETH_CTRL
+	ETHTOOL_STATS_RMON
+)
+
+const (
+	ETHTOOL_A_STATS_GRP_UNSPEC = iota
+	ETHTOOL_A_STATS_GRP_PAD
+	ETHTOOL_A_STATS_GRP_ID    /* u32 */
+	ETHTOOL_A_STATS_GRP_SS_ID /* u32 */
+	ETHTOOL_A_STATS_GRP_STAT  /* nest */
+
+	ETHTOOL_A_STATS_GRP_HIST_RX      /* nest */
+	ETHTOOL_A_STATS_GRP_HIST_TX      /* nest */
+	ETHTOOL_A_STATS_GRP_HIST_BKT_LOW /* u32 */
+	ETHTOOL_A_STATS_GRP_HIST_BKT_HI  /* u32 */
+	ETHTOOL_A_STATS_GRP_HIST_VAL     /* u64 */
+)
+
+/* 30.3.2.1.5 aSymbolErrorDuringCarrier */
+const (
+	ETHTOOL_A_STATS_ETH_PHY_5_SYM_ERR = iota
+)
+
+const (
+	/* 30.3.1.1.2 aFramesTransmittedOK */
+	ETHTOOL_A_STATS_ETH_MAC_2_TX_PKT = iota
+	/* 30.3.1.1.3 aSingleCollisionFrames */
+	ETHTOOL_A_STATS_ETH_MAC_3_SINGLE_COL
+	/* 30.3.1.1.4 aMultipleCollisionFrames */
+	ETHTOOL_A_STATS_ETH_MAC_4_MULTI_COL
+	/* 30.3.1.1.5 aFramesReceivedOK */
+	ETHTOOL_A_STATS_ETH_MAC_5_RX_PKT
+	/* 30.3.1.1.6 aFrameCheckSequenceErrors */
+	ETHTOOL_A_STATS_ETH_MAC_6_FCS_ERR
+	/* 30.3.1.1.7 aAlignmentErrors */
+	ETHTOOL_A_STATS_ETH_MAC_7_ALIGN_ERR
+	/* 30.3.1.1.8 aOctetsTransmittedOK */
+	ETHTOOL_A_STATS_ETH_MAC_8_TX_BYTES
+	/* 30.3.1.1.9 aFramesWithDeferredXmissions */
+	ETHTOOL_A_STATS_ETH_MAC_9_TX_DEFER
+	/* 30.3.1.1.10 aLateCollisions */
+	ETHTOOL_A_STATS_ETH_MAC_10_LATE_COL
+	/* 30.3.1.1.11 aFramesAbortedDueToXSColls */
+	ETHTOOL_A_STATS_ETH_MAC_11_XS_COL
+	/* 30.3.1.1.12 aFramesLostDueToIntMACXmitError */
+	ETHTOOL_A_STATS_ETH_MAC_12_TX_INT_ERR
+	/* 30.3.1.1.13 aCarrierSenseErrors */
+	ETHTOOL_A_STATS_ETH_MAC_13_CS_ERR
+	/* 30.3.1.1.14 aOctetsReceivedOK */
+	ETHTOOL_A_STATS_ETH_MAC_14_RX_BYTES
+	/* 30.3.1.1.15 aFramesLostDueToIntMACRcvError */
+	ETHTOOL_A_STATS_ETH_MAC_15_RX_INT_ERR
+	/* 30.3.1.1.18 aMulticastFramesXmittedOK */
+	ETHTOOL_A_STATS_ETH_MAC_18_TX_MCAST
+	/* 30.3.1.1.19 aBroadcastFramesXmittedOK */
+	ETHTOOL_A_STATS_ETH_MAC_19_TX_BCAST
+	/* 30.3.1.1.20 aFramesWithExcessiveDeferral */
+	ETHTOOL_A_STATS_ETH_MAC_20_XS_DEFER
+	/* 30.3.1.1.21 aMulticastFramesReceivedOK */
+	ETHTOOL_A_STATS_ETH_MAC_21_RX_MCAST
+	/* 30.3.1.1.22 aBroadcastFramesReceivedOK */
+	ETHTOOL_A_STATS_ETH_MAC_22_RX_BCAST
+	/* 30.3.1.1.23 aInRangeLengthErrors */
+	ETHTOOL_A_STATS_ETH_MAC_23_IR_LEN_ERR
+	/* 30.3.1.1.24 aOutOfRangeLengthField */
+	ETHTOOL_A_STATS_ETH_MAC_24_OOR_LEN
+	/* 30.3.1.1.25 aFrameTooLongErrors */
+	ETHTOOL_A_STATS_ETH_MAC_25_TOO_LONG_ERR
+)
+
+const (
+	/* 30.3.3.3 aMACControlFramesTransmitted */
+	ETHTOOL_A_STATS_ETH_CTRL_3_TX = iota
+	/* 30.3.3.4 aMACControlFramesReceived */
+	ETHTOOL_A_STATS_ETH_CTRL_4_RX
+	/* 30.3.3.5 aUnsupportedOpcodesReceived */
+	ETHTOOL_A_STATS_ETH_CTRL_5_RX_UNSUP
+)
+
+const (
+	/* etherStatsUndersizePkts */
+	ETHTOOL_A_STATS_RMON_UNDERSIZE = iota
+	/* etherStatsOversizePkts */
+	ETHTOOL_A_STATS_RMON_OVERSIZE
+	/* etherStatsFragments */
+	ETHTOOL_A_STATS_RMON_FRAG
+	/* etherStatsJabbers */
+	ETHTOOL_A_STATS_RMON_JABBER
+)
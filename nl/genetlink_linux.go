@@ -13,7 +13,9 @@ const (
 )
 
 const (
+	GENL_CTRL_CMD_NEWFAMILY = 1
 	GENL_CTRL_CMD_GETFAMILY = 3
+	GENL_CTRL_CMD_GETPOLICY = 10
 )
 
 const (
@@ -25,6 +27,9 @@ const (
 	GENL_CTRL_ATTR_MAXATTR
 	GENL_CTRL_ATTR_OPS
 	GENL_CTRL_ATTR_MCAST_GROUPS
+	GENL_CTRL_ATTR_POLICY
+	GENL_CTRL_ATTR_OP_POLICY
+	GENL_CTRL_ATTR_OP
 )
 
 const (
@@ -33,6 +38,12 @@ const (
 	GENL_CTRL_ATTR_OP_FLAGS
 )
 
+const (
+	GENL_CTRL_ATTR_POLICY_UNSPEC = iota
+	GENL_CTRL_ATTR_POLICY_DO
+	GENL_CTRL_ATTR_POLICY_DUMP
+)
+
 const (
 	GENL_ADMIN_PERM = 1 << iota
 	GENL_CMD_CAP_DO
@@ -88,3 +99,15 @@ func DeserializeGenlmsg(b []byte) *Genlmsg {
 func (msg *Genlmsg) Serialize() []byte {
 	return (*(*[SizeofGenlmsg]byte)(unsafe.Pointer(msg)))[:]
 }
+
+// NewGenlRequest returns a request for the command cmd of version version
+// of the generic netlink family whose id is family. Its attributes are to
+// be added with AddData or AddRawData.
+func NewGenlRequest(family uint16, cmd, version uint8, flags int) *NetlinkRequest {
+	req := NewNetlinkRequest(int(family), flags)
+	req.AddData(&Genlmsg{
+		Command: cmd,
+		Version: version,
+	})
+	return req
+}
@@ -153,7 +153,9 @@ const (
 	IFLA_BRPORT_MAB
 	IFLA_BRPORT_MCAST_N_GROUPS
 	IFLA_BRPORT_MCAST_MAX_GROUPS
-	IFLA_BRPORT_MAX = IFLA_BRPORT_MCAST_MAX_GROUPS
+	IFLA_BRPORT_NEIGH_VLAN_SUPPRESS
+	IFLA_BRPORT_BACKUP_NHID
+	IFLA_BRPORT_MAX = IFLA_BRPORT_BACKUP_NHID
 )
 
 const (
@@ -284,7 +286,12 @@ const (
 	IFLA_GRE_ENCAP_DPORT
 	IFLA_GRE_COLLECT_METADATA
 	IFLA_GRE_IGNORE_DF
-	IFLA_GRE_MAX = IFLA_GRE_COLLECT_METADATA
+	IFLA_GRE_FWMARK
+	IFLA_GRE_ERSPAN_INDEX
+	IFLA_GRE_ERSPAN_VER
+	IFLA_GRE_ERSPAN_DIR
+	IFLA_GRE_ERSPAN_HWID
+	IFLA_GRE_MAX = IFLA_GRE_ERSPAN_HWID
 )
 
 const (
@@ -717,7 +724,8 @@ const (
 	IFLA_VTI_OKEY
 	IFLA_VTI_LOCAL
 	IFLA_VTI_REMOTE
-	IFLA_VTI_MAX = IFLA_VTI_REMOTE
+	IFLA_VTI_FWMARK
+	IFLA_VTI_MAX = IFLA_VTI_FWMARK
 )
 
 const (
@@ -771,7 +779,12 @@ const (
 	IFLA_BR_MCAST_STATS_ENABLED
 	IFLA_BR_MCAST_IGMP_VERSION
 	IFLA_BR_MCAST_MLD_VERSION
-	IFLA_BR_MAX = IFLA_BR_MCAST_MLD_VERSION
+	IFLA_BR_VLAN_STATS_PER_PORT
+	IFLA_BR_MULTI_BOOLOPT
+	IFLA_BR_MCAST_QUERIER_STATE
+	IFLA_BR_FDB_N_LEARNED
+	IFLA_BR_FDB_MAX_LEARNED
+	IFLA_BR_MAX = IFLA_BR_FDB_MAX_LEARNED
 )
 
 const (
@@ -837,6 +850,18 @@ const (
 	IFLA_CAN_MAX = IFLA_CAN_BITRATE_MAX
 )
 
+const (
+	VXCAN_INFO_UNSPEC = iota
+	VXCAN_INFO_PEER
+	VXCAN_INFO_MAX = VXCAN_INFO_PEER
+)
+
+const (
+	SizeofCanBittiming      = 0x20
+	SizeofCanBittimingConst = 0x30
+	SizeofCanDeviceStats    = 0x18
+)
+
 const (
 	IFLA_BAREUDP_UNSPEC = iota
 	IFLA_BAREUDP_PORT
@@ -852,3 +877,110 @@ const (
 	IN6_ADDR_GEN_MODE_STABLE_PRIVACY
 	IN6_ADDR_GEN_MODE_RANDOM
 )
+
+// IFLA_INET6_RA_MTU is not defined in golang.org/x/sys/unix yet.
+const IFLA_INET6_RA_MTU = 0x9
+
+/* IPv6 device flags, reported in IFLA_INET6_FLAGS */
+const (
+	IF_RS_SENT      = 0x10
+	IF_RA_RCVD      = 0x20
+	IF_RA_MANAGED   = 0x40
+	IF_RA_OTHERCONF = 0x80
+	IF_READY        = 0x80000000
+)
+
+//	struct ifla_cacheinfo {
+//	  __u32 max_reasm_len;
+//	  __u32 tstamp;
+//	  __u32 reachable_time;
+//	  __u32 retrans_time;
+//	};
+const SizeofIflaCacheInfo = 0x10
+
+// IPv6 statistics, an array in IFLA_INET6_STATS indexed by id. The first
+// element holds the number of statistics reported.
+const (
+	IPSTATS_MIB_NUM = iota
+	IPSTATS_MIB_INPKTS
+	IPSTATS_MIB_INOCTETS
+	IPSTATS_MIB_INDELIVERS
+	IPSTATS_MIB_OUTFORWDATAGRAMS
+	IPSTATS_MIB_OUTREQUESTS
+	IPSTATS_MIB_OUTOCTETS
+	IPSTATS_MIB_INHDRERRORS
+	IPSTATS_MIB_INTOOBIGERRORS
+	IPSTATS_MIB_INNOROUTES
+	IPSTATS_MIB_INADDRERRORS
+	IPSTATS_MIB_INUNKNOWNPROTOS
+	IPSTATS_MIB_INTRUNCATEDPKTS
+	IPSTATS_MIB_INDISCARDS
+	IPSTATS_MIB_OUTDISCARDS
+	IPSTATS_MIB_OUTNOROUTES
+	IPSTATS_MIB_REASMTIMEOUT
+	IPSTATS_MIB_REASMREQDS
+	IPSTATS_MIB_REASMOKS
+	IPSTATS_MIB_REASMFAILS
+	IPSTATS_MIB_FRAGOKS
+	IPSTATS_MIB_FRAGFAILS
+	IPSTATS_MIB_FRAGCREATES
+	IPSTATS_MIB_INMCASTPKTS
+	IPSTATS_MIB_OUTMCASTPKTS
+	IPSTATS_MIB_INBCASTPKTS
+	IPSTATS_MIB_OUTBCASTPKTS
+	IPSTATS_MIB_INMCASTOCTETS
+	IPSTATS_MIB_OUTMCASTOCTETS
+	IPSTATS_MIB_INBCASTOCTETS
+	IPSTATS_MIB_OUTBCASTOCTETS
+	IPSTATS_MIB_CSUMERRORS
+	IPSTATS_MIB_NOECTPKTS
+	IPSTATS_MIB_ECT1PKTS
+	IPSTATS_MIB_ECT0PKTS
+	IPSTATS_MIB_CEPKTS
+	IPSTATS_MIB_REASM_OVERLAPS
+	IPSTATS_MIB_OUTPKTS
+)
+
+// ICMPv6 statistics, an array in IFLA_INET6_ICMP6STATS indexed by id. The
+// first element holds the number of statistics reported.
+const (
+	ICMP6_MIB_NUM = iota
+	ICMP6_MIB_INMSGS
+	ICMP6_MIB_INERRORS
+	ICMP6_MIB_OUTMSGS
+	ICMP6_MIB_OUTERRORS
+	ICMP6_MIB_CSUMERRORS
+	ICMP6_MIB_RATELIMITHOST
+)
+
+const (
+	IFLA_AMT_UNSPEC = iota
+	IFLA_AMT_MODE
+	IFLA_AMT_RELAY_PORT
+	IFLA_AMT_GATEWAY_PORT
+	IFLA_AMT_LINK
+	IFLA_AMT_LOCAL_IP
+	IFLA_AMT_REMOTE_IP
+	IFLA_AMT_DISCOVERY_IP
+	IFLA_AMT_MAX_TUNNELS
+	IFLA_AMT_MAX = IFLA_AMT_MAX_TUNNELS
+)
+
+const (
+	IFLA_BATADV_UNSPEC = iota
+	IFLA_BATADV_ALGO_NAME
+	IFLA_BATADV_MAX = IFLA_BATADV_ALGO_NAME
+)
+
+const (
+	IFLA_RMNET_UNSPEC = iota
+	IFLA_RMNET_MUX_ID
+	IFLA_RMNET_FLAGS
+	IFLA_RMNET_MAX = IFLA_RMNET_FLAGS
+)
+
+//	struct ifla_rmnet_flags {
+//	  __u32 flags;
+//	  __u32 mask;
+//	};
+const SizeofIflaRmnetFlags = 0x8
@@ -0,0 +1,130 @@
+package nl
+
+import (
+	"unsafe"
+)
+
+const (
+	SizeofIfStatsMsg = 0x0c
+)
+
+/* Link statistics attributes, used with RTM_{NEW,GET,SET}STATS
+ * [IFLA_STATS_LINK_64]
+ * [IFLA_STATS_LINK_XSTATS] = {
+ *     [LINK_XSTATS_TYPE_BRIDGE]
+ *     [LINK_XSTATS_TYPE_BOND]
+ * }
+ * [IFLA_STATS_LINK_XSTATS_SLAVE]
+ * [IFLA_STATS_LINK_OFFLOAD_XSTATS]
+ * [IFLA_STATS_AF_SPEC] = {
+ *     [AF_MPLS] = { [MPLS_STATS_LINK] }
+ * }
+ */
+const (
+	IFLA_STATS_UNSPEC = iota
+	IFLA_STATS_LINK_64
+	IFLA_STATS_LINK_XSTATS
+	IFLA_STATS_LINK_XSTATS_SLAVE
+	IFLA_STATS_LINK_OFFLOAD_XSTATS
+	IFLA_STATS_AF_SPEC
+	IFLA_STATS_MAX = IFLA_STATS_AF_SPEC
+)
+
+const (
+	IFLA_STATS_GETSET_UNSPEC = iota
+	IFLA_STATS_GET_FILTERS
+	IFLA_STATS_SET_OFFLOAD_XSTATS_L3_STATS
+)
+
+const (
+	LINK_XSTATS_TYPE_UNSPEC = iota
+	LINK_XSTATS_TYPE_BRIDGE
+	LINK_XSTATS_TYPE_BOND
+)
+
+const (
+	IFLA_OFFLOAD_XSTATS_UNSPEC = iota
+	IFLA_OFFLOAD_XSTATS_CPU_HIT
+	IFLA_OFFLOAD_XSTATS_HW_S_INFO
+	IFLA_OFFLOAD_XSTATS_L3_STATS
+)
+
+const (
+	IFLA_OFFLOAD_XSTATS_HW_S_INFO_UNSPEC = iota
+	IFLA_OFFLOAD_XSTATS_HW_S_INFO_REQUEST
+	IFLA_OFFLOAD_XSTATS_HW_S_INFO_USED
+)
+
+const (
+	BRIDGE_XSTATS_UNSPEC = iota
+	BRIDGE_XSTATS_VLAN
+	BRIDGE_XSTATS_MCAST
+	BRIDGE_XSTATS_PAD
+	BRIDGE_XSTATS_STP
+)
+
+const (
+	BR_MCAST_DIR_RX = iota
+	BR_MCAST_DIR_TX
+	BR_MCAST_DIR_SIZE
+)
+
+const (
+	BOND_XSTATS_UNSPEC = iota
+	BOND_XSTATS_3AD
+	BOND_XSTATS_PAD
+)
+
+// Unlike most netlink attributes, the 802.3ad statistics start at type 0.
+const (
+	BOND_3AD_STAT_LACPDU_RX = iota
+	BOND_3AD_STAT_LACPDU_TX
+	BOND_3AD_STAT_LACPDU_UNKNOWN_RX
+	BOND_3AD_STAT_LACPDU_ILLEGAL_RX
+	BOND_3AD_STAT_MARKER_RX
+	BOND_3AD_STAT_MARKER_TX
+	BOND_3AD_STAT_MARKER_RESP_RX
+	BOND_3AD_STAT_MARKER_RESP_TX
+	BOND_3AD_STAT_MARKER_UNKNOWN_RX
+	BOND_3AD_STAT_PAD
+)
+
+const (
+	MPLS_STATS_UNSPEC = iota
+	MPLS_STATS_LINK
+)
+
+//	struct if_stats_msg {
+//	  __u8  family;
+//	  __u8  pad1;
+//	  __u16 pad2;
+//	  __u32 ifindex;
+//	  __u32 filter_mask;
+//	};
+type IfStatsMsg struct {
+	Family     uint8
+	Pad1       uint8
+	Pad2       uint16
+	Ifindex    uint32
+	FilterMask uint32
+}
+
+func NewIfStatsMsg(family uint8, ifindex int, filterMask uint32) *IfStatsMsg {
+	return &IfStatsMsg{
+		Family:     family,
+		Ifindex:    uint32(ifindex),
+		FilterMask: filterMask,
+	}
+}
+
+func (msg *IfStatsMsg) Len() int {
+	return SizeofIfStatsMsg
+}
+
+func (msg *IfStatsMsg) Serialize() []byte {
+	return (*(*[SizeofIfStatsMsg]byte)(unsafe.Pointer(msg)))[:]
+}
+
+func DeserializeIfStatsMsg(b []byte) *IfStatsMsg {
+	return (*IfStatsMsg)(unsafe.Pointer(&b[0:SizeofIfStatsMsg][0]))
+}
@@ -0,0 +1,164 @@
+package nl
+
+import (
+	"unsafe"
+)
+
+const (
+	SizeofNetconfMsg = 0x04
+)
+
+/* Netconf attributes, used with RTM_{NEW,DEL,GET}NETCONF */
+const (
+	NETCONFA_UNSPEC = iota
+	NETCONFA_IFINDEX
+	NETCONFA_FORWARDING
+	NETCONFA_RP_FILTER
+	NETCONFA_MC_FORWARDING
+	NETCONFA_PROXY_NEIGH
+	NETCONFA_IGNORE_ROUTES_WITH_LINKDOWN
+	NETCONFA_INPUT
+	NETCONFA_BC_FORWARDING
+	NETCONFA_MAX = NETCONFA_BC_FORWARDING
+)
+
+const (
+	NETCONFA_IFINDEX_ALL     = -1
+	NETCONFA_IFINDEX_DEFAULT = -2
+)
+
+//	struct netconfmsg {
+//	  __u8 ncm_family;
+//	};
+//
+// The kernel aligns the attributes that follow to 4 bytes, so the padding is
+// part of the message.
+type NetconfMsg struct {
+	Family uint8
+	_      [3]uint8
+}
+
+func NewNetconfMsg(family int) *NetconfMsg {
+	return &NetconfMsg{
+		Family: uint8(family),
+	}
+}
+
+func (msg *NetconfMsg) Len() int {
+	return SizeofNetconfMsg
+}
+
+func (msg *NetconfMsg) Serialize() []byte {
+	return (*(*[SizeofNetconfMsg]byte)(unsafe.Pointer(msg)))[:]
+}
+
+func DeserializeNetconfMsg(b []byte) *NetconfMsg {
+	return (*NetconfMsg)(unsafe.Pointer(&b[0:SizeofNetconfMsg][0]))
+}
+
+/* AF_INET attributes nested in IFLA_AF_SPEC */
+const (
+	IFLA_INET_UNSPEC = iota
+	IFLA_INET_CONF
+)
+
+/* IPv4 per device configuration, nested in IFLA_INET_CONF by index */
+const (
+	IPV4_DEVCONF_FORWARDING = iota + 1
+	IPV4_DEVCONF_MC_FORWARDING
+	IPV4_DEVCONF_PROXY_ARP
+	IPV4_DEVCONF_ACCEPT_REDIRECTS
+	IPV4_DEVCONF_SECURE_REDIRECTS
+	IPV4_DEVCONF_SEND_REDIRECTS
+	IPV4_DEVCONF_SHARED_MEDIA
+	IPV4_DEVCONF_RP_FILTER
+	IPV4_DEVCONF_ACCEPT_SOURCE_ROUTE
+	IPV4_DEVCONF_BOOTP_RELAY
+	IPV4_DEVCONF_LOG_MARTIANS
+	IPV4_DEVCONF_TAG
+	IPV4_DEVCONF_ARPFILTER
+	IPV4_DEVCONF_MEDIUM_ID
+	IPV4_DEVCONF_NOXFRM
+	IPV4_DEVCONF_NOPOLICY
+	IPV4_DEVCONF_FORCE_IGMP_VERSION
+	IPV4_DEVCONF_ARP_ANNOUNCE
+	IPV4_DEVCONF_ARP_IGNORE
+	IPV4_DEVCONF_PROMOTE_SECONDARIES
+	IPV4_DEVCONF_ARP_ACCEPT
+	IPV4_DEVCONF_ARP_NOTIFY
+	IPV4_DEVCONF_ACCEPT_LOCAL
+	IPV4_DEVCONF_SRC_VMARK
+	IPV4_DEVCONF_PROXY_ARP_PVLAN
+	IPV4_DEVCONF_ROUTE_LOCALNET
+	IPV4_DEVCONF_IGMPV2_UNSOLICITED_REPORT_INTERVAL
+	IPV4_DEVCONF_IGMPV3_UNSOLICITED_REPORT_INTERVAL
+	IPV4_DEVCONF_IGNORE_ROUTES_WITH_LINKDOWN
+	IPV4_DEVCONF_DROP_UNICAST_IN_L2_MULTICAST
+	IPV4_DEVCONF_DROP_GRATUITOUS_ARP
+	IPV4_DEVCONF_BC_FORWARDING
+	IPV4_DEVCONF_ARP_EVICT_NOCARRIER
+	IPV4_DEVCONF_MAX = IPV4_DEVCONF_ARP_EVICT_NOCARRIER
+)
+
+/* IPv6 per device configuration, an array in IFLA_INET6_CONF indexed by id */
+const (
+	DEVCONF_FORWARDING = iota
+	DEVCONF_HOPLIMIT
+	DEVCONF_MTU6
+	DEVCONF_ACCEPT_RA
+	DEVCONF_ACCEPT_REDIRECTS
+	DEVCONF_AUTOCONF
+	DEVCONF_DAD_TRANSMITS
+	DEVCONF_RTR_SOLICITS
+	DEVCONF_RTR_SOLICIT_INTERVAL
+	DEVCONF_RTR_SOLICIT_DELAY
+	DEVCONF_USE_TEMPADDR
+	DEVCONF_TEMP_VALID_LFT
+	DEVCONF_TEMP_PREFERED_LFT
+	DEVCONF_REGEN_MAX_RETRY
+	DEVCONF_MAX_DESYNC_FACTOR
+	DEVCONF_MAX_ADDRESSES
+	DEVCONF_FORCE_MLD_VERSION
+	DEVCONF_ACCEPT_RA_DEFRTR
+	DEVCONF_ACCEPT_RA_PINFO
+	DEVCONF_ACCEPT_RA_RTR_PREF
+	DEVCONF_RTR_PROBE_INTERVAL
+	DEVCONF_ACCEPT_RA_RT_INFO_MAX_PLEN
+	DEVCONF_PROXY_NDP
+	DEVCONF_OPTIMISTIC_DAD
+	DEVCONF_ACCEPT_SOURCE_ROUTE
+	DEVCONF_MC_FORWARDING
+	DEVCONF_DISABLE_IPV6
+	DEVCONF_ACCEPT_DAD
+	DEVCONF_FORCE_TLLAO
+	DEVCONF_NDISC_NOTIFY
+	DEVCONF_MLDV1_UNSOLICITED_REPORT_INTERVAL
+	DEVCONF_MLDV2_UNSOLICITED_REPORT_INTERVAL
+	DEVCONF_SUPPRESS_FRAG_NDISC
+	DEVCONF_ACCEPT_RA_FROM_LOCAL
+	DEVCONF_USE_OPTIMISTIC
+	DEVCONF_ACCEPT_RA_MTU
+	DEVCONF_STABLE_SECRET
+	DEVCONF_USE_OIF_ADDRS_ONLY
+	DEVCONF_ACCEPT_RA_MIN_HOP_LIMIT
+	DEVCONF_IGNORE_ROUTES_WITH_LINKDOWN
+	DEVCONF_DROP_UNICAST_IN_L2_MULTICAST
+	DEVCONF_DROP_UNSOLICITED_NA
+	DEVCONF_KEEP_ADDR_ON_DOWN
+	DEVCONF_RTR_SOLICIT_MAX_INTERVAL
+	DEVCONF_SEG6_ENABLED
+	DEVCONF_SEG6_REQUIRE_HMAC
+	DEVCONF_ENHANCED_DAD
+	DEVCONF_ADDR_GEN_MODE
+	DEVCONF_DISABLE_POLICY
+	DEVCONF_ACCEPT_RA_RT_INFO_MIN_PLEN
+	DEVCONF_NDISC_TCLASS
+	DEVCONF_RPL_SEG_ENABLED
+	DEVCONF_RA_DEFRTR_METRIC
+	DEVCONF_IOAM6_ENABLED
+	DEVCONF_IOAM6_ID
+	DEVCONF_IOAM6_ID_WIDE
+	DEVCONF_NDISC_EVICT_NOCARRIER
+	DEVCONF_ACCEPT_UNTRACKED_NA
+	DEVCONF_ACCEPT_RA_MIN_LFT
+)
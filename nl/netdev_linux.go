@@ -0,0 +1,162 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/netdev.h
+
+const (
+	NETDEV_FAMILY_NAME    = "netdev"
+	NETDEV_FAMILY_VERSION = 1
+)
+
+const (
+	NETDEV_XDP_ACT_BASIC        = 1 << 0 /* XDP features set supported by all drivers (XDP_ABORTED, XDP_DROP, XDP_PASS, XDP_TX) */
+	NETDEV_XDP_ACT_REDIRECT     = 1 << 1 /* The netdev supports XDP_REDIRECT */
+	NETDEV_XDP_ACT_NDO_XMIT     = 1 << 2 /* This feature informs if netdev implements ndo_xdp_xmit callback */
+	NETDEV_XDP_ACT_XSK_ZEROCOPY = 1 << 3 /* This feature informs if netdev supports AF_XDP in zero copy mode */
+	NETDEV_XDP_ACT_HW_OFFLOAD   = 1 << 4 /* This feature informs if netdev supports XDP hw offloading */
+	NETDEV_XDP_ACT_RX_SG        = 1 << 5 /* This feature informs if netdev implements non-linear XDP buffer support in the driver napi callback */
+	NETDEV_XDP_ACT_NDO_XMIT_SG  = 1 << 6 /* This feature informs if netdev implements non-linear XDP buffer support in ndo_xdp_xmit callback */
+)
+
+const (
+	NETDEV_XDP_RX_METADATA_TIMESTAMP = 1 << 0 /* Device is capable of exposing receive HW timestamp via bpf_xdp_metadata_rx_timestamp() */
+	NETDEV_XDP_RX_METADATA_HASH      = 1 << 1 /* Device is capable of exposing receive packet hash via bpf_xdp_metadata_rx_hash() */
+	NETDEV_XDP_RX_METADATA_VLAN_TAG  = 1 << 2 /* Device is capable of exposing receive packet VLAN tag via bpf_xdp_metadata_rx_vlan_tag() */
+)
+
+const (
+	NETDEV_XSK_FLAGS_TX_TIMESTAMP = 1 << 0 /* HW timestamping egress packets is supported by the driver */
+	NETDEV_XSK_FLAGS_TX_CHECKSUM  = 1 << 1 /* L3 checksum HW offload is supported by the driver */
+)
+
+const (
+	NETDEV_QUEUE_TYPE_RX = iota
+	NETDEV_QUEUE_TYPE_TX
+)
+
+const (
+	NETDEV_QSTATS_SCOPE_QUEUE = 1
+)
+
+const (
+	NETDEV_NAPI_THREADED_DISABLED = iota
+	NETDEV_NAPI_THREADED_ENABLED
+)
+
+const (
+	NETDEV_A_DEV_IFINDEX = iota + 1
+	NETDEV_A_DEV_PAD
+	NETDEV_A_DEV_XDP_FEATURES
+	NETDEV_A_DEV_XDP_ZC_MAX_SEGS
+	NETDEV_A_DEV_XDP_RX_METADATA_FEATURES
+	NETDEV_A_DEV_XSK_FEATURES
+)
+
+const (
+	NETDEV_A_PAGE_POOL_ID = iota + 1
+	NETDEV_A_PAGE_POOL_IFINDEX
+	NETDEV_A_PAGE_POOL_NAPI_ID
+	NETDEV_A_PAGE_POOL_INFLIGHT
+	NETDEV_A_PAGE_POOL_INFLIGHT_MEM
+	NETDEV_A_PAGE_POOL_DETACH_TIME
+	NETDEV_A_PAGE_POOL_DMABUF
+	NETDEV_A_PAGE_POOL_IO_URING
+)
+
+const (
+	NETDEV_A_PAGE_POOL_STATS_INFO = 1
+)
+
+const (
+	NETDEV_A_PAGE_POOL_STATS_ALLOC_FAST = iota + 8
+	NETDEV_A_PAGE_POOL_STATS_ALLOC_SLOW
+	NETDEV_A_PAGE_POOL_STATS_ALLOC_SLOW_HIGH_ORDER
+	NETDEV_A_PAGE_POOL_STATS_ALLOC_EMPTY
+	NETDEV_A_PAGE_POOL_STATS_ALLOC_REFILL
+	NETDEV_A_PAGE_POOL_STATS_ALLOC_WAIVE
+	NETDEV_A_PAGE_POOL_STATS_RECYCLE_CACHED
+	NETDEV_A_PAGE_POOL_STATS_RECYCLE_CACHE_FULL
+	NETDEV_A_PAGE_POOL_STATS_RECYCLE_RING
+	NETDEV_A_PAGE_POOL_STATS_RECYCLE_RING_FULL
+	NETDEV_A_PAGE_POOL_STATS_RECYCLE_RELEASED_REFCNT
+)
+
+const (
+	NETDEV_A_NAPI_IFINDEX = iota + 1
+	NETDEV_A_NAPI_ID
+	NETDEV_A_NAPI_IRQ
+	NETDEV_A_NAPI_PID
+	NETDEV_A_NAPI_DEFER_HARD_IRQS
+	NETDEV_A_NAPI_GRO_FLUSH_TIMEOUT
+	NETDEV_A_NAPI_IRQ_SUSPEND_TIMEOUT
+	NETDEV_A_NAPI_THREADED
+)
+
+const (
+	NETDEV_A_QUEUE_ID = iota + 1
+	NETDEV_A_QUEUE_IFINDEX
+	NETDEV_A_QUEUE_TYPE
+	NETDEV_A_QUEUE_NAPI_ID
+	NETDEV_A_QUEUE_DMABUF
+	NETDEV_A_QUEUE_IO_URING
+	NETDEV_A_QUEUE_XSK
+)
+
+const (
+	NETDEV_A_QSTATS_IFINDEX = iota + 1
+	NETDEV_A_QSTATS_QUEUE_TYPE
+	NETDEV_A_QSTATS_QUEUE_ID
+	NETDEV_A_QSTATS_SCOPE
+)
+
+const (
+	NETDEV_A_QSTATS_RX_PACKETS = iota + 8
+	NETDEV_A_QSTATS_RX_BYTES
+	NETDEV_A_QSTATS_TX_PACKETS
+	NETDEV_A_QSTATS_TX_BYTES
+	NETDEV_A_QSTATS_RX_ALLOC_FAIL
+	NETDEV_A_QSTATS_RX_HW_DROPS
+	NETDEV_A_QSTATS_RX_HW_DROP_OVERRUNS
+	NETDEV_A_QSTATS_RX_CSUM_COMPLETE
+	NETDEV_A_QSTATS_RX_CSUM_UNNECESSARY
+	NETDEV_A_QSTATS_RX_CSUM_NONE
+	NETDEV_A_QSTATS_RX_CSUM_BAD
+	NETDEV_A_QSTATS_RX_HW_GRO_PACKETS
+	NETDEV_A_QSTATS_RX_HW_GRO_BYTES
+	NETDEV_A_QSTATS_RX_HW_GRO_WIRE_PACKETS
+	NETDEV_A_QSTATS_RX_HW_GRO_WIRE_BYTES
+	NETDEV_A_QSTATS_RX_HW_DROP_RATELIMITS
+	NETDEV_A_QSTATS_TX_HW_DROPS
+	NETDEV_A_QSTATS_TX_HW_DROP_ERRORS
+	NETDEV_A_QSTATS_TX_CSUM_NONE
+	NETDEV_A_QSTATS_TX_NEEDS_CSUM
+	NETDEV_A_QSTATS_TX_HW_GSO_PACKETS
+	NETDEV_A_QSTATS_TX_HW_GSO_BYTES
+	NETDEV_A_QSTATS_TX_HW_GSO_WIRE_PACKETS
+	NETDEV_A_QSTATS_TX_HW_GSO_WIRE_BYTES
+	NETDEV_A_QSTATS_TX_HW_DROP_RATELIMITS
+	NETDEV_A_QSTATS_TX_STOP
+	NETDEV_A_QSTATS_TX_WAKE
+)
+
+const (
+	NETDEV_CMD_DEV_GET = iota + 1
+	NETDEV_CMD_DEV_ADD_NTF
+	NETDEV_CMD_DEV_DEL_NTF
+	NETDEV_CMD_DEV_CHANGE_NTF
+	NETDEV_CMD_PAGE_POOL_GET
+	NETDEV_CMD_PAGE_POOL_ADD_NTF
+	NETDEV_CMD_PAGE_POOL_DEL_NTF
+	NETDEV_CMD_PAGE_POOL_CHANGE_NTF
+	NETDEV_CMD_PAGE_POOL_STATS_GET
+	NETDEV_CMD_QUEUE_GET
+	NETDEV_CMD_NAPI_GET
+	NETDEV_CMD_QSTATS_GET
+	NETDEV_CMD_BIND_RX
+	NETDEV_CMD_NAPI_SET
+)
+
+const (
+	NETDEV_MCGRP_MGMT      = "mgmt"
+	NETDEV_MCGRP_PAGE_POOL = "page-pool"
+)
@@ -3,6 +3,7 @@ package nl
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -33,6 +34,18 @@ const (
 	// Kernel netlink pid
 	PidKernel     uint32 = 0
 	SizeofCnMsgOp        = 0x18
+	// Network namespace id of the namespaces without one
+	NETNSA_NSID_NOT_ASSIGNED = -1
+)
+
+// Attributes of the RTM_*NSID messages.
+const (
+	NETNSA_NONE = iota
+	NETNSA_NSID
+	NETNSA_PID
+	NETNSA_FD
+	NETNSA_TARGET_NSID
+	NETNSA_CURRENT_NSID
 )
 
 // SupportedNlFamilies contains the list of netlink families this netlink package supports
@@ -101,11 +114,13 @@ func Swap32(i uint32) uint32 {
 }
 
 const (
-	NLMSGERR_ATTR_UNUSED = 0
-	NLMSGERR_ATTR_MSG    = 1
-	NLMSGERR_ATTR_OFFS   = 2
-	NLMSGERR_ATTR_COOKIE = 3
-	NLMSGERR_ATTR_POLICY = 4
+	NLMSGERR_ATTR_UNUSED    = 0
+	NLMSGERR_ATTR_MSG       = 1
+	NLMSGERR_ATTR_OFFS      = 2
+	NLMSGERR_ATTR_COOKIE    = 3
+	NLMSGERR_ATTR_POLICY    = 4
+	NLMSGERR_ATTR_MISS_TYPE = 5
+	NLMSGERR_ATTR_MISS_NEST = 6
 )
 
 type NetlinkRequestData interface {
@@ -467,6 +482,23 @@ type NetlinkRequest struct {
 	Sockets map[int]*SocketHandle
 
 	RetryInterrupted bool
+
+	// Context, if set, is used by Execute and ExecuteIter to bound the
+	// request. ExecuteContext and ExecuteIterContext ignore it.
+	Context context.Context
+
+	// WarningCallback, if set, is called with the warning the kernel may
+	// attach to the acknowledgement of a successful request, e.g. about a
+	// deprecated attribute. This requires extended acks to be enabled, see
+	// EnableErrorMessageReporting. It must not call back into the netlink
+	// API.
+	WarningCallback func(warning *Error)
+
+	// Batch, if set, makes the Execute methods queue the request into it
+	// rather than sending it, if it is a rtnetlink modification that only
+	// expects an acknowledgement. Its outcome is then reported by
+	// Batch.Flush.
+	Batch *Batch
 }
 
 // Serialize the Netlink Request into a byte array
@@ -512,6 +544,17 @@ func (req *NetlinkRequest) AddRawData(data []byte) {
 // If the returned error is [ErrDumpInterrupted], results may be inconsistent
 // or incomplete.
 func (req *NetlinkRequest) Execute(sockType int, resType uint16) ([][]byte, error) {
+	return req.ExecuteContext(req.context(), sockType, resType)
+}
+
+// ExecuteContext works like Execute, but stops waiting for the kernel and
+// returns ctx.Err() as soon as ctx is done.
+func (req *NetlinkRequest) ExecuteContext(ctx context.Context, sockType int, resType uint16) ([][]byte, error) {
+	if req.batched(sockType, resType) {
+		req.Batch.Add(sockType, req)
+		return nil, nil
+	}
+
 	attempts := 1
 	if req.RetryInterrupted {
 		// Only retry if the Request is configured to do so for backwards compat.
@@ -521,7 +564,7 @@ func (req *NetlinkRequest) Execute(sockType int, resType uint16) ([][]byte, erro
 	var lastRes [][]byte
 	for range attempts {
 		var res [][]byte
-		err := req.executeIter(sockType, resType, func(msg []byte) bool {
+		err := req.executeIter(ctx, sockType, resType, func(msg []byte) bool {
 			res = append(res, msg)
 			return true
 		})
@@ -552,11 +595,23 @@ func (req *NetlinkRequest) Execute(sockType int, resType uint16) ([][]byte, erro
 // RetryInterrupted is enabled, messages are buffered until a complete dump is
 // received and then passed to the callback after the socket lock is released.
 func (req *NetlinkRequest) ExecuteIter(sockType int, resType uint16, f func(msg []byte) bool) error {
+	return req.ExecuteIterContext(req.context(), sockType, resType, f)
+}
+
+// ExecuteIterContext works like ExecuteIter, but stops waiting for the
+// kernel and returns ctx.Err() as soon as ctx is done. The replies left on
+// a socket of req.Sockets are then read and discarded before returning, as
+// the kernel does not run another dump on it until the current one is read.
+func (req *NetlinkRequest) ExecuteIterContext(ctx context.Context, sockType int, resType uint16, f func(msg []byte) bool) error {
+	if req.batched(sockType, resType) {
+		req.Batch.Add(sockType, req)
+		return nil
+	}
 	if !req.RetryInterrupted {
-		return req.executeIter(sockType, resType, f)
+		return req.executeIter(ctx, sockType, resType, f)
 	}
 
-	msgs, err := req.Execute(sockType, resType)
+	msgs, err := req.ExecuteContext(ctx, sockType, resType)
 	for _, msg := range msgs {
 		if cont := f(msg); !cont {
 			break
@@ -565,57 +620,67 @@ func (req *NetlinkRequest) ExecuteIter(sockType int, resType uint16, f func(msg
 	return err
 }
 
-func (req *NetlinkRequest) executeIter(sockType int, resType uint16, f func(msg []byte) bool) error {
-	var (
-		s   *NetlinkSocket
-		err error
-	)
+// batched reports whether req is to be queued into its Batch: that is a
+// request to create, delete or set a rtnetlink object which only expects an
+// acknowledgement. rtnetlink message types come in groups of four, the third
+// one of which is the GET one.
+func (req *NetlinkRequest) batched(sockType int, resType uint16) bool {
+	return req.Batch != nil &&
+		sockType == unix.NETLINK_ROUTE &&
+		resType == 0 &&
+		req.Flags&unix.NLM_F_ACK != 0 &&
+		req.Type >= unix.RTM_BASE &&
+		(req.Type-unix.RTM_BASE)%4 != 2
+}
 
-	if req.Sockets != nil {
-		if sh, ok := req.Sockets[sockType]; ok {
-			s = sh.Socket
-			req.Seq = atomic.AddUint32(&sh.Seq, 1)
-		}
+func (req *NetlinkRequest) context() context.Context {
+	if req.Context != nil {
+		return req.Context
 	}
-	sharedSocket := s != nil
-
-	if s == nil {
-		s, err = getNetlinkSocket(sockType)
-		if err != nil {
-			return err
-		}
+	return context.Background()
+}
 
-		if err := s.SetSendTimeout(&SocketTimeoutTv); err != nil {
-			return err
-		}
-		if err := s.SetReceiveTimeout(&SocketTimeoutTv); err != nil {
-			return err
-		}
-		if EnableErrorMessageReporting {
-			// ignore error, it's non-critical
-			_ = s.SetExtAck(true)
-		}
+func (req *NetlinkRequest) executeIter(ctx context.Context, sockType int, resType uint16, f func(msg []byte) bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		defer s.Close()
-	} else {
-		s.Lock()
-		defer s.Unlock()
+	t, sh, release, err := openTransport(req.Sockets, sockType)
+	if err != nil {
+		return err
+	}
+	defer release()
+	sharedSocket := sh != nil
+	if sharedSocket {
+		req.Seq = atomic.AddUint32(&sh.Seq, 1)
 	}
 
-	if err := s.Send(req); err != nil {
+	msg := req.Serialize()
+	if err := t.Send(ctx, msg); err != nil {
 		return err
 	}
 
-	pid, err := s.GetPid()
+	pid, err := t.Pid()
 	if err != nil {
 		return err
 	}
+	replied := false
+	if sharedSocket {
+		// The kernel answers EBUSY to the dumps requested on a socket until
+		// the running one is read to its end, so the replies left over when
+		// ctx is done are drained.
+		defer func() {
+			if !replied && ctx.Err() != nil {
+				drainReplies(t, req.Seq, pid)
+			}
+		}()
+	}
 
 	dumpIntr := false
 
 done:
 	for {
-		msgs, from, err := s.Receive()
+		msgs, from, err := t.Receive(ctx)
 		if err != nil {
 			return err
 		}
@@ -638,50 +703,20 @@ done:
 			}
 
 			if m.Header.Type == unix.NLMSG_DONE || m.Header.Type == unix.NLMSG_ERROR {
+				replied = true
 				// NLMSG_DONE might have no payload, if so assume no error.
 				if m.Header.Type == unix.NLMSG_DONE && len(m.Data) == 0 {
 					break done
 				}
 
-				native := NativeEndian()
-				errno := int32(native.Uint32(m.Data[0:4]))
-				if errno == 0 {
-					break done
+				err, warning := netlinkMessageError(m, msg, req.attrsOffset())
+				if err != nil {
+					return err
 				}
-				var err error
-				err = syscall.Errno(-errno)
-
-				unreadData := m.Data[4:]
-
-				if m.Header.Type == unix.NLMSG_ERROR {
-					if m.Header.Flags&unix.NLM_F_CAPPED != 0 {
-						// The request payload is capped, just skip the nlmsghdr
-						unreadData = unreadData[syscall.SizeofNlMsghdr:]
-					} else {
-						// Skip the entire request message
-						echoReqH := (*syscall.NlMsghdr)(unsafe.Pointer(&unreadData[0]))
-						unreadData = unreadData[nlmAlignOf(int(echoReqH.Len)):]
-					}
-				}
-
-				if m.Header.Flags&unix.NLM_F_ACK_TLVS != 0 && len(unreadData) > syscall.SizeofNlMsghdr {
-					// Annotate `err` using nlmsgerr attributes.
-					for len(unreadData) >= syscall.SizeofRtAttr {
-						attr := (*syscall.RtAttr)(unsafe.Pointer(&unreadData[0]))
-						attrData := unreadData[syscall.SizeofRtAttr:attr.Len]
-
-						switch attr.Type {
-						case NLMSGERR_ATTR_MSG:
-							err = fmt.Errorf("%w: %s", err, unix.ByteSliceToString(attrData))
-						default:
-							// TODO: handle other NLMSGERR_ATTR types
-						}
-
-						unreadData = unreadData[rtaAlignOf(int(attr.Len)):]
-					}
+				if warning != nil && req.WarningCallback != nil {
+					req.WarningCallback(warning)
 				}
-
-				return err
+				break done
 			}
 			if resType != 0 && m.Header.Type != resType {
 				continue
@@ -691,7 +726,10 @@ done:
 				// pass them to the iterator func.
 				f = dummyMsgIterFunc
 			}
-			if m.Header.Flags&unix.NLM_F_MULTI == 0 {
+			// Some generic netlink families do not set NLM_F_MULTI on dump
+			// replies, so dumps are only over on NLMSG_DONE.
+			if m.Header.Flags&unix.NLM_F_MULTI == 0 && req.Flags&unix.NLM_F_DUMP != unix.NLM_F_DUMP {
+				replied = true
 				break done
 			}
 		}
@@ -706,6 +744,26 @@ func dummyMsgIterFunc(msg []byte) bool {
 	return true
 }
 
+// drainReplies receives the replies to the request seq sent from pid over t,
+// up to the last one, and discards them.
+func drainReplies(t Transport, seq, pid uint32) {
+	for {
+		msgs, _, err := t.Receive(context.Background())
+		if err != nil {
+			return
+		}
+		for _, m := range msgs {
+			if m.Header.Seq != seq || m.Header.Pid != pid {
+				continue
+			}
+			if m.Header.Type == unix.NLMSG_DONE || m.Header.Type == unix.NLMSG_ERROR ||
+				m.Header.Flags&unix.NLM_F_MULTI == 0 {
+				return
+			}
+		}
+	}
+}
+
 // Create a new netlink request from proto and flags
 // Note the Len value will be inaccurate once data is added until
 // the message is serialized
@@ -826,7 +884,8 @@ func executeInNetns(newNs, curNs netns.NsHandle) (func(), error) {
 // Create a netlink socket with a given protocol (e.g. NETLINK_ROUTE)
 // and subscribe it to multicast groups passed in variable argument list.
 // Returns the netlink socket on which Receive() method can be called
-// to retrieve the messages from the kernel.
+// to retrieve the messages from the kernel. To receive the messages of all
+// the network namespaces with an id, call SetListenAllNsid and ReceiveNsid.
 func Subscribe(protocol int, groups ...uint) (*NetlinkSocket, error) {
 	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, protocol)
 	if err != nil {
@@ -854,6 +913,18 @@ func Subscribe(protocol int, groups ...uint) (*NetlinkSocket, error) {
 	return s, nil
 }
 
+// RunInNetns runs fn with the calling thread in the network namespace newNs,
+// if open. Then control goes back to curNs if open, otherwise to the netns at
+// the time this function was called.
+func RunInNetns(newNs, curNs netns.NsHandle, fn func() error) error {
+	c, err := executeInNetns(newNs, curNs)
+	if err != nil {
+		return err
+	}
+	defer c()
+	return fn()
+}
+
 // SubscribeAt works like Subscribe plus let's the caller choose the network
 // namespace in which the socket would be opened (newNs). Then control goes back
 // to curNs if open, otherwise to the netns at the time this function was called.
@@ -880,26 +951,37 @@ func (s *NetlinkSocket) GetTimeouts() (send, receive time.Duration) {
 }
 
 func (s *NetlinkSocket) Send(request *NetlinkRequest) error {
+	return s.SendContext(context.Background(), request)
+}
+
+// SendContext works like Send, but gives up and returns ctx.Err() as soon
+// as ctx is done.
+func (s *NetlinkSocket) SendContext(ctx context.Context, request *NetlinkRequest) error {
+	return s.send(ctx, request.Serialize())
+}
+
+// send sends the serialized netlink messages in b with a single sendmsg call.
+func (s *NetlinkSocket) send(ctx context.Context, b []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	rawConn, err := s.file.SyscallConn()
 	if err != nil {
 		return err
 	}
-	var (
-		deadline time.Time
-		innerErr error
-	)
-	sendTimeout := atomic.LoadInt64(&s.sendTimeout)
-	if sendTimeout != 0 {
-		deadline = time.Now().Add(time.Duration(sendTimeout))
-	}
+	var innerErr error
+	deadline, ctxDeadline := socketDeadline(ctx, atomic.LoadInt64(&s.sendTimeout))
 	if err := s.file.SetWriteDeadline(deadline); err != nil {
 		return err
 	}
-	serializedReq := request.Serialize()
+	defer interruptOnDone(ctx, s.file.SetWriteDeadline)()
 	err = rawConn.Write(func(fd uintptr) (done bool) {
-		innerErr = unix.Sendto(int(s.fd), serializedReq, 0, &s.lsa)
+		innerErr = unix.Sendto(int(s.fd), b, 0, &s.lsa)
 		return innerErr != unix.EWOULDBLOCK
 	})
+	if ctxErr := contextErr(ctx, err, ctxDeadline); ctxErr != nil {
+		return ctxErr
+	}
 	if innerErr != nil {
 		return innerErr
 	}
@@ -915,55 +997,153 @@ func (s *NetlinkSocket) Send(request *NetlinkRequest) error {
 }
 
 func (s *NetlinkSocket) Receive() ([]syscall.NetlinkMessage, *unix.SockaddrNetlink, error) {
+	return s.ReceiveContext(context.Background())
+}
+
+// ReceiveContext works like Receive, but stops waiting for messages and
+// returns ctx.Err() as soon as ctx is done.
+func (s *NetlinkSocket) ReceiveContext(ctx context.Context) ([]syscall.NetlinkMessage, *unix.SockaddrNetlink, error) {
+	msgs, from, _, err := s.receive(ctx, false)
+	return msgs, from, err
+}
+
+// ReceiveNsid works like Receive, but also returns the id, in the network
+// namespace of the socket, of the network namespace the messages originate
+// from. It is only known for the notifications of the other namespaces
+// received on a socket listening to all of them, see SetListenAllNsid.
+// Otherwise it is NETNSA_NSID_NOT_ASSIGNED.
+func (s *NetlinkSocket) ReceiveNsid() ([]syscall.NetlinkMessage, *unix.SockaddrNetlink, int, error) {
+	return s.ReceiveNsidContext(context.Background())
+}
+
+// ReceiveNsidContext works like ReceiveNsid, but stops waiting for messages
+// and returns ctx.Err() as soon as ctx is done.
+func (s *NetlinkSocket) ReceiveNsidContext(ctx context.Context) ([]syscall.NetlinkMessage, *unix.SockaddrNetlink, int, error) {
+	return s.receive(ctx, true)
+}
+
+func (s *NetlinkSocket) receive(ctx context.Context, withNsid bool) ([]syscall.NetlinkMessage, *unix.SockaddrNetlink, int, error) {
+	nsid := NETNSA_NSID_NOT_ASSIGNED
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nsid, err
+	}
 	rawConn, err := s.file.SyscallConn()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nsid, err
 	}
 	var (
-		deadline time.Time
 		fromAddr *unix.SockaddrNetlink
 		rb       [RECEIVE_BUFFER_SIZE]byte
-		nr       int
+		oob      []byte
+		nr, noob int
 		from     unix.Sockaddr
 		innerErr error
 	)
-	receiveTimeout := atomic.LoadInt64(&s.receiveTimeout)
-	if receiveTimeout != 0 {
-		deadline = time.Now().Add(time.Duration(receiveTimeout))
+	if withNsid {
+		oob = make([]byte, unix.CmsgSpace(4))
 	}
+	deadline, ctxDeadline := socketDeadline(ctx, atomic.LoadInt64(&s.receiveTimeout))
 	if err := s.file.SetReadDeadline(deadline); err != nil {
-		return nil, nil, err
+		return nil, nil, nsid, err
 	}
+	stop := interruptOnDone(ctx, s.file.SetReadDeadline)
 	err = rawConn.Read(func(fd uintptr) (done bool) {
-		nr, from, innerErr = unix.Recvfrom(int(fd), rb[:], 0)
+		nr, noob, _, from, innerErr = unix.Recvmsg(int(fd), rb[:], oob, 0)
 		return innerErr != unix.EWOULDBLOCK
 	})
+	stop()
+	if ctxErr := contextErr(ctx, err, ctxDeadline); ctxErr != nil {
+		return nil, nil, nsid, ctxErr
+	}
 	if innerErr != nil {
-		return nil, nil, innerErr
+		return nil, nil, nsid, innerErr
 	}
 	if err != nil {
 		// The timeout was previously implemented using SO_RCVTIMEO on a blocking
 		// socket. So, continue to return EAGAIN when the timeout is reached.
 		if errors.Is(err, os.ErrDeadlineExceeded) {
-			return nil, nil, unix.EAGAIN
+			return nil, nil, nsid, unix.EAGAIN
 		}
-		return nil, nil, err
+		return nil, nil, nsid, err
 	}
 	fromAddr, ok := from.(*unix.SockaddrNetlink)
 	if !ok {
-		return nil, nil, fmt.Errorf("Error converting to netlink sockaddr")
+		return nil, nil, nsid, fmt.Errorf("Error converting to netlink sockaddr")
 	}
 	if nr < unix.NLMSG_HDRLEN {
-		return nil, nil, fmt.Errorf("Got short response from netlink")
+		return nil, nil, nsid, fmt.Errorf("Got short response from netlink")
+	}
+	if noob > 0 {
+		cmsgs, err := unix.ParseSocketControlMessage(oob[:noob])
+		if err != nil {
+			return nil, nil, nsid, err
+		}
+		for _, cmsg := range cmsgs {
+			if cmsg.Header.Level == unix.SOL_NETLINK && cmsg.Header.Type == unix.NETLINK_LISTEN_ALL_NSID && len(cmsg.Data) >= 4 {
+				nsid = int(int32(NativeEndian().Uint32(cmsg.Data)))
+			}
+		}
 	}
 	msgLen := nlmAlignOf(nr)
 	rb2 := make([]byte, msgLen)
 	copy(rb2, rb[:msgLen])
 	nl, err := syscall.ParseNetlinkMessage(rb2)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nsid, err
 	}
-	return nl, fromAddr, nil
+	return nl, fromAddr, nsid, nil
+}
+
+// socketDeadline returns the deadline for an operation on a socket with the
+// given timeout, shortened to the deadline of ctx if there is one. It also
+// reports whether the deadline is the one of ctx.
+func socketDeadline(ctx context.Context, timeout int64) (time.Time, bool) {
+	var deadline time.Time
+	if timeout != 0 {
+		deadline = time.Now().Add(time.Duration(timeout))
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		return d, true
+	}
+	return deadline, false
+}
+
+// interruptOnDone makes a pending socket operation fail as soon as ctx is
+// done, by moving its deadline into the past with setDeadline. The returned
+// function must be called once the operation returned; it does not return
+// before any interruption in progress completed, so that the socket can be
+// safely reused afterwards.
+func interruptOnDone(ctx context.Context, setDeadline func(time.Time) error) func() {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(interrupted)
+		_ = setDeadline(time.Unix(1, 0))
+	})
+	return func() {
+		if !stop() {
+			<-interrupted
+		}
+	}
+}
+
+// contextErr returns the error of ctx if the socket operation failed with
+// err because ctx is done, and nil otherwise. ctxDeadline reports whether
+// the deadline of the operation is the one of ctx.
+func contextErr(ctx context.Context, err error, ctxDeadline bool) error {
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if ctxDeadline {
+		// The socket deadline may expire just before the context one.
+		return context.DeadlineExceeded
+	}
+	return nil
 }
 
 // SetSendTimeout allows to set a send timeout on the socket
@@ -997,6 +1177,40 @@ func (s *NetlinkSocket) SetExtAck(enable bool) error {
 	return unix.SetsockoptInt(int(s.fd), unix.SOL_NETLINK, unix.NETLINK_EXT_ACK, enableN)
 }
 
+// SetNoENOBUFS sets NETLINK_NO_ENOBUFS on the socket: the receive buffer
+// overruns, which make the kernel drop messages, are then no longer reported
+// by ENOBUFS errors.
+func (s *NetlinkSocket) SetNoENOBUFS(enable bool) error {
+	var enableN int
+	if enable {
+		enableN = 1
+	}
+	return unix.SetsockoptInt(int(s.fd), unix.SOL_NETLINK, unix.NETLINK_NO_ENOBUFS, enableN)
+}
+
+// SetListenAllNsid sets NETLINK_LISTEN_ALL_NSID on the socket, to also
+// receive the notifications of the multicast groups it joined sent in the
+// network namespaces which have an id in its own. ReceiveNsid returns the
+// namespace they originate from.
+func (s *NetlinkSocket) SetListenAllNsid(enable bool) error {
+	var enableN int
+	if enable {
+		enableN = 1
+	}
+	return unix.SetsockoptInt(int(s.fd), unix.SOL_NETLINK, unix.NETLINK_LISTEN_ALL_NSID, enableN)
+}
+
+// JoinGroup adds the socket to a multicast group. Unlike the groups passed
+// to Subscribe, the group may be above 32, as used by generic netlink.
+func (s *NetlinkSocket) JoinGroup(group uint32) error {
+	return unix.SetsockoptInt(int(s.fd), unix.SOL_NETLINK, unix.NETLINK_ADD_MEMBERSHIP, int(group))
+}
+
+// LeaveGroup removes the socket from a multicast group.
+func (s *NetlinkSocket) LeaveGroup(group uint32) error {
+	return unix.SetsockoptInt(int(s.fd), unix.SOL_NETLINK, unix.NETLINK_DROP_MEMBERSHIP, int(group))
+}
+
 func (s *NetlinkSocket) GetPid() (uint32, error) {
 	lsa, err := unix.Getsockname(int(s.fd))
 	if err != nil {
@@ -1117,10 +1331,18 @@ func netlinkRouteAttrAndValue(b []byte) (*unix.RtAttr, []byte, int, error) {
 type SocketHandle struct {
 	Seq    uint32
 	Socket *NetlinkSocket
+	// Transport, if set, is used instead of Socket to exchange messages.
+	Transport Transport
+
+	// mu serializes the requests over Transport.
+	mu sync.Mutex
 }
 
 // Close closes the netlink socket
 func (sh *SocketHandle) Close() error {
+	if sh.Transport != nil {
+		return sh.Transport.Close()
+	}
 	if sh.Socket != nil {
 		return sh.Socket.Close()
 	}
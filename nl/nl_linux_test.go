@@ -2,8 +2,10 @@ package nl
 
 import (
 	"bytes"
+	"context"
 	"crypto/rand"
 	"encoding/binary"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -160,6 +162,118 @@ func TestReceiveTimeout(t *testing.T) {
 	}
 }
 
+func TestReceiveContext(t *testing.T) {
+	nlSock, err := getNetlinkSocket(unix.NETLINK_ROUTE)
+	if err != nil {
+		t.Fatalf("Error creating the socket: %v", err)
+	}
+	defer nlSock.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, _, err := nlSock.ReceiveContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, got: %v", context.DeadlineExceeded, err)
+	}
+	if dur := time.Since(start); dur > time.Second {
+		t.Fatalf("Context deadline not honoured, receive took %v", dur)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	errC := make(chan error)
+	go func() {
+		_, _, err := nlSock.ReceiveContext(ctx)
+		errC <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-errC:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected %v, got: %v", context.Canceled, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Receive not interrupted by context cancellation")
+	}
+
+	// The socket remains usable once the context is gone.
+	req := NewNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP)
+	req.AddData(NewIfInfomsg(unix.AF_UNSPEC))
+	if err := nlSock.Send(req); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := nlSock.ReceiveContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecuteContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := NewNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_DUMP)
+	req.AddData(NewIfInfomsg(unix.AF_UNSPEC))
+	if _, err := req.ExecuteContext(ctx, unix.NETLINK_ROUTE, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected %v, got: %v", context.Canceled, err)
+	}
+	req.Context = ctx
+	if _, err := req.Execute(unix.NETLINK_ROUTE, 0); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected %v, got: %v", context.Canceled, err)
+	}
+}
+
+func TestExecuteIterContextCanceledDump(t *testing.T) {
+	setUpBatchTestNetns(t)
+
+	// Enough routes for their dump to take several reads.
+	b := &Batch{}
+	for i := 0; i < 2048; i++ {
+		req := NewNetlinkRequest(unix.RTM_NEWROUTE, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
+		msg := NewRtMsg()
+		msg.Family = unix.AF_INET
+		msg.Type = unix.RTN_BLACKHOLE
+		msg.Dst_len = 32
+		req.AddData(msg)
+		req.AddData(NewRtAttr(unix.RTA_DST, []byte{10, 0, byte(i >> 8), byte(i)}))
+		b.Add(unix.NETLINK_ROUTE, req)
+	}
+	if _, err := b.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := getNetlinkSocket(unix.NETLINK_ROUTE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	sockets := map[int]*SocketHandle{unix.NETLINK_ROUTE: {Socket: s}}
+	dump := func(ctx context.Context, f func(msg []byte) bool) error {
+		req := NewNetlinkRequest(unix.RTM_GETROUTE, unix.NLM_F_DUMP)
+		req.Sockets = sockets
+		req.AddData(&RtMsg{RtMsg: unix.RtMsg{Family: unix.AF_INET}})
+		return req.ExecuteIterContext(ctx, unix.NETLINK_ROUTE, unix.RTM_NEWROUTE, f)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := dump(ctx, func(msg []byte) bool {
+		cancel()
+		return true
+	}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected %v, got: %v", context.Canceled, err)
+	}
+
+	// The canceled dump does not keep the socket busy.
+	n := 0
+	if err := dump(context.Background(), func(msg []byte) bool {
+		n++
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n < 2048 {
+		t.Fatalf("Expected at least 2048 routes, got %d", n)
+	}
+}
+
 func (msg *CnMsgOp) write(b []byte) {
 	native := NativeEndian()
 	native.PutUint32(b[0:4], msg.ID.Idx)
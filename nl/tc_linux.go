@@ -881,6 +881,21 @@ const (
 	TCA_TUNNEL_KEY_MAX
 )
 
+const (
+	TCA_TUNNEL_KEY_ENC_OPTS_UNSPEC = iota
+	TCA_TUNNEL_KEY_ENC_OPTS_GENEVE
+	TCA_TUNNEL_KEY_ENC_OPTS_VXLAN
+	TCA_TUNNEL_KEY_ENC_OPTS_ERSPAN
+)
+
+const (
+	TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_UNSPEC = iota
+	TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_VER    /* u8 */
+	TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_INDEX  /* be32 */
+	TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_DIR    /* u8 */
+	TCA_TUNNEL_KEY_ENC_OPT_ERSPAN_HWID   /* u8 */
+)
+
 type TcTunnelKey struct {
 	TcGen
 	Action int32
@@ -1133,6 +1148,21 @@ const (
 	__TCA_FLOWER_MAX
 )
 
+const (
+	TCA_FLOWER_KEY_ENC_OPTS_UNSPEC = iota
+	TCA_FLOWER_KEY_ENC_OPTS_GENEVE
+	TCA_FLOWER_KEY_ENC_OPTS_VXLAN
+	TCA_FLOWER_KEY_ENC_OPTS_ERSPAN
+)
+
+const (
+	TCA_FLOWER_KEY_ENC_OPT_ERSPAN_UNSPEC = iota
+	TCA_FLOWER_KEY_ENC_OPT_ERSPAN_VER    /* u8 */
+	TCA_FLOWER_KEY_ENC_OPT_ERSPAN_INDEX  /* be32 */
+	TCA_FLOWER_KEY_ENC_OPT_ERSPAN_DIR    /* u8 */
+	TCA_FLOWER_KEY_ENC_OPT_ERSPAN_HWID   /* u8 */
+)
+
 const TCA_CLS_FLAGS_SKIP_HW = 1 << 0 /* don't offload filter to HW */
 const TCA_CLS_FLAGS_SKIP_SW = 1 << 1 /* don't use filter in SW */
 
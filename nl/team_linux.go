@@ -0,0 +1,78 @@
+package nl
+
+// All the following constants are coming from:
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/if_team.h
+
+const (
+	TEAM_GENL_NAME                     = "team"
+	TEAM_GENL_VERSION                  = 1
+	TEAM_GENL_CHANGE_EVENT_MC_GRP_NAME = "change_event"
+)
+
+const (
+	TEAM_CMD_NOOP = iota
+	TEAM_CMD_OPTIONS_SET
+	TEAM_CMD_OPTIONS_GET
+	TEAM_CMD_PORT_LIST_GET
+)
+
+const (
+	TEAM_ATTR_UNSPEC       = iota
+	TEAM_ATTR_TEAM_IFINDEX /* u32 */
+	TEAM_ATTR_LIST_OPTION  /* nest */
+	TEAM_ATTR_LIST_PORT    /* nest */
+)
+
+/* Nested layout of get/set msg:
+ *
+ *	[TEAM_ATTR_LIST_OPTION]
+ *		[TEAM_ATTR_ITEM_OPTION]
+ *			[TEAM_ATTR_OPTION_*], ...
+ *		[TEAM_ATTR_ITEM_OPTION]
+ *			[TEAM_ATTR_OPTION_*], ...
+ *		...
+ *	[TEAM_ATTR_LIST_PORT]
+ *		[TEAM_ATTR_ITEM_PORT]
+ *			[TEAM_ATTR_PORT_*], ...
+ *		...
+ */
+const (
+	TEAM_ATTR_ITEM_OPTION_UNSPEC = iota
+	TEAM_ATTR_ITEM_OPTION        /* nest */
+)
+
+const (
+	TEAM_ATTR_OPTION_UNSPEC       = iota
+	TEAM_ATTR_OPTION_NAME         /* string */
+	TEAM_ATTR_OPTION_CHANGED      /* flag */
+	TEAM_ATTR_OPTION_TYPE         /* u8 */
+	TEAM_ATTR_OPTION_DATA         /* dynamic */
+	TEAM_ATTR_OPTION_REMOVED      /* flag */
+	TEAM_ATTR_OPTION_PORT_IFINDEX /* u32 */ /* for per-port options */
+	TEAM_ATTR_OPTION_ARRAY_INDEX  /* u32 */ /* for array options */
+)
+
+const (
+	TEAM_ATTR_ITEM_PORT_UNSPEC = iota
+	TEAM_ATTR_ITEM_PORT        /* nest */
+)
+
+const (
+	TEAM_ATTR_PORT_UNSPEC  = iota
+	TEAM_ATTR_PORT_IFINDEX /* u32 */
+	TEAM_ATTR_PORT_CHANGED /* flag */
+	TEAM_ATTR_PORT_LINKUP  /* flag */
+	TEAM_ATTR_PORT_SPEED   /* u32 */
+	TEAM_ATTR_PORT_DUPLEX  /* u8 */
+	TEAM_ATTR_PORT_REMOVED /* flag */
+)
+
+// Netlink attribute types, used as TEAM_ATTR_OPTION_TYPE to describe the
+// type of TEAM_ATTR_OPTION_DATA.
+const (
+	NLA_U32    = 3
+	NLA_STRING = 5
+	NLA_FLAG   = 6
+	NLA_BINARY = 11
+	NLA_S32    = 14
+)
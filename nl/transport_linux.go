@@ -0,0 +1,323 @@
+package nl
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Transport exchanges netlink messages with the kernel over a netlink
+// socket, or with a fake of it. Set it on a [SocketHandle] to use it instead
+// of a netlink socket.
+type Transport interface {
+	// Send sends the serialized netlink messages in b at once.
+	Send(ctx context.Context, b []byte) error
+	// Receive returns the next netlink messages sent to the transport, and
+	// their sender.
+	Receive(ctx context.Context) ([]syscall.NetlinkMessage, *unix.SockaddrNetlink, error)
+	// Pid returns the port id of the transport, that the replies to its
+	// requests are addressed to.
+	Pid() (uint32, error)
+	// Close releases the transport.
+	Close() error
+}
+
+// NewSocketTransport returns a Transport over a new netlink socket of the
+// given protocol, in the current network namespace.
+func NewSocketTransport(protocol int) (Transport, error) {
+	s, err := getNetlinkSocket(protocol)
+	if err != nil {
+		return nil, err
+	}
+	return socketTransport{s}, nil
+}
+
+// socketTransport is a Transport over a netlink socket.
+type socketTransport struct {
+	s *NetlinkSocket
+}
+
+func (t socketTransport) Send(ctx context.Context, b []byte) error {
+	return t.s.send(ctx, b)
+}
+
+func (t socketTransport) Receive(ctx context.Context) ([]syscall.NetlinkMessage, *unix.SockaddrNetlink, error) {
+	return t.s.ReceiveContext(ctx)
+}
+
+func (t socketTransport) Pid() (uint32, error) {
+	return t.s.GetPid()
+}
+
+func (t socketTransport) Close() error {
+	return t.s.Close()
+}
+
+// openTransport returns the transport to send the requests of type sockType
+// on: the one of the matching socket handle in sockets, locked, or a new
+// netlink socket. release must be called once done with it. sh is nil in
+// the latter case.
+func openTransport(sockets map[int]*SocketHandle, sockType int) (t Transport, sh *SocketHandle, release func(), err error) {
+	if sh, ok := sockets[sockType]; ok {
+		if sh.Transport != nil {
+			sh.mu.Lock()
+			return sh.Transport, sh, sh.mu.Unlock, nil
+		}
+		sh.Socket.Lock()
+		return socketTransport{sh.Socket}, sh, sh.Socket.Unlock, nil
+	}
+
+	s, err := getNetlinkSocket(sockType)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.SetSendTimeout(&SocketTimeoutTv); err != nil {
+		s.Close()
+		return nil, nil, nil, err
+	}
+	if err := s.SetReceiveTimeout(&SocketTimeoutTv); err != nil {
+		s.Close()
+		return nil, nil, nil, err
+	}
+	return socketTransport{s}, nil, func() { s.Close() }, nil
+}
+
+// Record directions of the Recorder format.
+const (
+	recordSent     = 0
+	recordReceived = 1
+)
+
+// Recorder records the netlink messages exchanged over the transports it
+// creates, in a format a [Replayer] can serve them back from. This allows to
+// test code using a Handle without privileges, once its exchanges with the
+// kernel were recorded:
+//
+//	rec := nl.NewRecorder(f, nil)
+//	h, err := netlink.NewHandleWithOptions(netlink.HandleOptions{Transport: rec.Transport})
+//
+// Each record is made of the netlink family, the direction and the length
+// of the data, as little endian 32 bits integers, followed by the data:
+// the serialized messages sent or received at once.
+type Recorder struct {
+	mu           sync.Mutex
+	w            io.Writer
+	newTransport func(family int) (Transport, error)
+}
+
+// NewRecorder returns a Recorder writing to w the messages exchanged over
+// the transports returned by newTransport, or over netlink sockets in the
+// current network namespace if nil.
+func NewRecorder(w io.Writer, newTransport func(family int) (Transport, error)) *Recorder {
+	if newTransport == nil {
+		newTransport = NewSocketTransport
+	}
+	return &Recorder{w: w, newTransport: newTransport}
+}
+
+// Transport returns a recording transport for the given netlink family.
+func (r *Recorder) Transport(family int) (Transport, error) {
+	t, err := r.newTransport(family)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingTransport{Transport: t, r: r, family: family}, nil
+}
+
+func (r *Recorder) write(family int, direction uint32, b []byte) error {
+	var hdr [12]byte
+	binary.LittleEndian.PutUint32(hdr[0:4], uint32(family))
+	binary.LittleEndian.PutUint32(hdr[4:8], direction)
+	binary.LittleEndian.PutUint32(hdr[8:12], uint32(len(b)))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := r.w.Write(b)
+	return err
+}
+
+type recordingTransport struct {
+	Transport
+	r      *Recorder
+	family int
+}
+
+func (t *recordingTransport) Send(ctx context.Context, b []byte) error {
+	if err := t.Transport.Send(ctx, b); err != nil {
+		return err
+	}
+	return t.r.write(t.family, recordSent, b)
+}
+
+func (t *recordingTransport) Receive(ctx context.Context) ([]syscall.NetlinkMessage, *unix.SockaddrNetlink, error) {
+	msgs, from, err := t.Transport.Receive(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := t.r.write(t.family, recordReceived, serializeNetlinkMessages(msgs)); err != nil {
+		return nil, nil, err
+	}
+	return msgs, from, nil
+}
+
+// serializeNetlinkMessages serializes msgs back to their wire format.
+func serializeNetlinkMessages(msgs []syscall.NetlinkMessage) []byte {
+	var b []byte
+	for _, m := range msgs {
+		hdr := m.Header
+		hdr.Len = uint32(unix.SizeofNlMsghdr + len(m.Data))
+		b = append(b, (*(*[unix.SizeofNlMsghdr]byte)(unsafe.Pointer(&hdr)))[:]...)
+		b = append(b, m.Data...)
+		b = append(b, make([]byte, nlmAlignOf(len(b))-len(b))...)
+	}
+	return b
+}
+
+// replayPid is the port id of the replaying transports.
+const replayPid = 1
+
+// Replayer serves netlink exchanges recorded by a [Recorder] back, to fake
+// the kernel in tests:
+//
+//	rep, err := nl.NewReplayer(f)
+//	h, err := netlink.NewHandleWithOptions(netlink.HandleOptions{Transport: rep.Transport})
+//
+// The requests must match the recorded ones, but for their sequence
+// numbers, and are answered with the recorded replies. Sequence numbers and
+// port ids of the replies are rewritten to match the requests.
+type Replayer struct {
+	mu      sync.Mutex
+	records map[int][]replayRecord
+}
+
+type replayRecord struct {
+	direction uint32
+	data      []byte
+}
+
+// NewReplayer returns a Replayer serving the exchanges recorded in r.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	p := &Replayer{records: make(map[int][]replayRecord)}
+	for {
+		var hdr [12]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return p, nil
+			}
+			return nil, err
+		}
+		family := int(binary.LittleEndian.Uint32(hdr[0:4]))
+		rec := replayRecord{
+			direction: binary.LittleEndian.Uint32(hdr[4:8]),
+			data:      make([]byte, binary.LittleEndian.Uint32(hdr[8:12])),
+		}
+		if _, err := io.ReadFull(r, rec.data); err != nil {
+			return nil, err
+		}
+		p.records[family] = append(p.records[family], rec)
+	}
+}
+
+// Transport returns a transport serving the exchanges recorded for the
+// given netlink family. Only one transport should be used per family.
+func (p *Replayer) Transport(family int) (Transport, error) {
+	return &replayTransport{p: p, family: family}, nil
+}
+
+// next pops the next record of family, skipping the replies which were not
+// consumed if direction is recordSent.
+func (p *Replayer) next(family int, direction uint32) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	records := p.records[family]
+	for len(records) > 0 && direction == recordSent && records[0].direction == recordReceived {
+		records = records[1:]
+	}
+	if len(records) == 0 || records[0].direction != direction {
+		p.records[family] = records
+		if direction == recordSent {
+			return nil, fmt.Errorf("unexpected netlink request: none left in the recording")
+		}
+		return nil, fmt.Errorf("no netlink reply left in the recording")
+	}
+	p.records[family] = records[1:]
+	return records[0].data, nil
+}
+
+type replayTransport struct {
+	p      *Replayer
+	family int
+	// seqs maps the recorded sequence numbers to the ones of the requests.
+	seqs map[uint32]uint32
+}
+
+func (t *replayTransport) Send(ctx context.Context, b []byte) error {
+	recorded, err := t.p.next(t.family, recordSent)
+	if err != nil {
+		return err
+	}
+	msgs, err := syscall.ParseNetlinkMessage(b)
+	if err != nil {
+		return err
+	}
+	recordedMsgs, err := syscall.ParseNetlinkMessage(recorded)
+	if err != nil {
+		return err
+	}
+	if len(msgs) != len(recordedMsgs) {
+		return fmt.Errorf("netlink request does not match the recording: %d messages, expected %d", len(msgs), len(recordedMsgs))
+	}
+	t.seqs = make(map[uint32]uint32, len(msgs))
+	for i, m := range msgs {
+		rm := recordedMsgs[i]
+		t.seqs[rm.Header.Seq] = m.Header.Seq
+		m.Header.Seq, rm.Header.Seq = 0, 0
+		m.Header.Pid, rm.Header.Pid = 0, 0
+		if m.Header != rm.Header || !bytes.Equal(m.Data, rm.Data) {
+			return fmt.Errorf("netlink request does not match the recording: message %d of type %d, expected type %d", i, m.Header.Type, rm.Header.Type)
+		}
+	}
+	return nil
+}
+
+func (t *replayTransport) Receive(ctx context.Context) ([]syscall.NetlinkMessage, *unix.SockaddrNetlink, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	recorded, err := t.p.next(t.family, recordReceived)
+	if err != nil {
+		return nil, nil, err
+	}
+	msgs, err := syscall.ParseNetlinkMessage(recorded)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range msgs {
+		if seq, ok := t.seqs[msgs[i].Header.Seq]; ok {
+			msgs[i].Header.Seq = seq
+		}
+		if msgs[i].Header.Pid != PidKernel {
+			msgs[i].Header.Pid = replayPid
+		}
+	}
+	return msgs, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Pid: PidKernel}, nil
+}
+
+func (t *replayTransport) Pid() (uint32, error) {
+	return replayPid, nil
+}
+
+func (t *replayTransport) Close() error {
+	return nil
+}
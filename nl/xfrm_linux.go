@@ -92,6 +92,24 @@ const (
 
 const XFRMA_OUTPUT_MARK = XFRMA_SET_MARK
 
+// Attributes of XFRM_MSG_NEWSADINFO
+const (
+	XFRMA_SAD_UNSPEC = iota
+	XFRMA_SAD_CNT    /* __u32 */
+	XFRMA_SAD_HINFO  /* struct xfrmu_sadhinfo */
+	XFRMA_SAD_MAX    = iota - 1
+)
+
+// Attributes of XFRM_MSG_NEWSPDINFO
+const (
+	XFRMA_SPD_UNSPEC       = iota
+	XFRMA_SPD_INFO         /* struct xfrmu_spdinfo */
+	XFRMA_SPD_HINFO        /* struct xfrmu_spdhinfo */
+	XFRMA_SPD_IPV4_HTHRESH /* struct xfrmu_spdhthresh */
+	XFRMA_SPD_IPV6_HTHRESH /* struct xfrmu_spdhthresh */
+	XFRMA_SPD_MAX          = iota - 1
+)
+
 const (
 	SizeofXfrmAddress     = 0x10
 	SizeofXfrmSelector    = 0x38
@@ -99,6 +117,8 @@ const (
 	SizeofXfrmLifetimeCur = 0x20
 	SizeofXfrmId          = 0x18
 	SizeofXfrmMark        = 0x08
+	SizeofXfrmUserSecCtx  = 0x08
+	SizeofXfrmInfoFlags   = 0x04
 )
 
 // Netlink groups
@@ -316,3 +336,38 @@ func DeserializeXfrmMark(b []byte) *XfrmMark {
 func (msg *XfrmMark) Serialize() []byte {
 	return (*(*[SizeofXfrmMark]byte)(unsafe.Pointer(msg)))[:]
 }
+
+// XfrmInfoFlags is the __u32 heading the SAD and SPD info messages.
+type XfrmInfoFlags uint32
+
+func (msg *XfrmInfoFlags) Len() int {
+	return SizeofXfrmInfoFlags
+}
+
+func (msg *XfrmInfoFlags) Serialize() []byte {
+	return Uint32Attr(uint32(*msg))
+}
+
+// struct xfrm_user_sec_ctx {
+//   __u16     len;
+//   __u16     exttype;
+//   __u8      ctx_alg;  /* LSMs: e.g., selinux == 1 */
+//   __u8      ctx_doi;
+//   __u16     ctx_len;
+// };
+
+type XfrmUserSecCtx struct {
+	Len     uint16
+	Exttype uint16
+	CtxAlg  uint8
+	CtxDoi  uint8
+	CtxLen  uint16
+}
+
+func DeserializeXfrmUserSecCtx(b []byte) *XfrmUserSecCtx {
+	return (*XfrmUserSecCtx)(unsafe.Pointer(&b[0:SizeofXfrmUserSecCtx][0]))
+}
+
+func (msg *XfrmUserSecCtx) Serialize() []byte {
+	return (*(*[SizeofXfrmUserSecCtx]byte)(unsafe.Pointer(msg)))[:]
+}
@@ -30,3 +30,159 @@ func DeserializeXfrmUserExpire(b []byte) *XfrmUserExpire {
 func (msg *XfrmUserExpire) Serialize() []byte {
 	return (*(*[SizeofXfrmUserExpire]byte)(unsafe.Pointer(msg)))[:]
 }
+
+const (
+	SizeofXfrmUserPolexpire = 0xb0
+	SizeofXfrmUserAcquire   = 0x118
+	SizeofXfrmAeventId      = 0x30
+	SizeofXfrmUserReport    = 0x3c
+	SizeofXfrmUserMapping   = 0x40
+)
+
+// Flags of struct xfrm_aevent_id
+const (
+	XFRM_AE_UNSPEC = 0x0
+	XFRM_AE_RTHR   = 0x1  // replay threshold
+	XFRM_AE_RVAL   = 0x2  // replay value
+	XFRM_AE_LVAL   = 0x4  // lifetime value
+	XFRM_AE_ETHR   = 0x8  // expiry timer threshold
+	XFRM_AE_CR     = 0x10 // event cause is replay update
+	XFRM_AE_CE     = 0x20 // event cause is timer expiry
+	XFRM_AE_CU     = 0x40 // event cause is policy update
+)
+
+// struct xfrm_user_polexpire {
+// 	struct xfrm_userpolicy_info	pol;
+// 	__u8				hard;
+// };
+
+type XfrmUserPolexpire struct {
+	Pol  XfrmUserpolicyInfo
+	Hard uint8
+	Pad  [7]byte
+}
+
+func (msg *XfrmUserPolexpire) Len() int {
+	return SizeofXfrmUserPolexpire
+}
+
+func DeserializeXfrmUserPolexpire(b []byte) *XfrmUserPolexpire {
+	return (*XfrmUserPolexpire)(unsafe.Pointer(&b[0:SizeofXfrmUserPolexpire][0]))
+}
+
+func (msg *XfrmUserPolexpire) Serialize() []byte {
+	return (*(*[SizeofXfrmUserPolexpire]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_user_acquire {
+// 	struct xfrm_id			id;
+// 	xfrm_address_t			saddr;
+// 	struct xfrm_selector		sel;
+// 	struct xfrm_userpolicy_info	policy;
+// 	__u32				aalgos;
+// 	__u32				ealgos;
+// 	__u32				calgos;
+// 	__u32				seq;
+// };
+
+type XfrmUserAcquire struct {
+	Id     XfrmId
+	Saddr  XfrmAddress
+	Sel    XfrmSelector
+	Policy XfrmUserpolicyInfo
+	Aalgos uint32
+	Ealgos uint32
+	Calgos uint32
+	Seq    uint32
+}
+
+func (msg *XfrmUserAcquire) Len() int {
+	return SizeofXfrmUserAcquire
+}
+
+func DeserializeXfrmUserAcquire(b []byte) *XfrmUserAcquire {
+	return (*XfrmUserAcquire)(unsafe.Pointer(&b[0:SizeofXfrmUserAcquire][0]))
+}
+
+func (msg *XfrmUserAcquire) Serialize() []byte {
+	return (*(*[SizeofXfrmUserAcquire]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_aevent_id {
+// 	struct xfrm_usersa_id		sa_id;
+// 	xfrm_address_t			saddr;
+// 	__u32				flags;
+// 	__u32				reqid;
+// };
+
+type XfrmAeventId struct {
+	SaId  XfrmUsersaId
+	Saddr XfrmAddress
+	Flags uint32
+	Reqid uint32
+}
+
+func (msg *XfrmAeventId) Len() int {
+	return SizeofXfrmAeventId
+}
+
+func DeserializeXfrmAeventId(b []byte) *XfrmAeventId {
+	return (*XfrmAeventId)(unsafe.Pointer(&b[0:SizeofXfrmAeventId][0]))
+}
+
+func (msg *XfrmAeventId) Serialize() []byte {
+	return (*(*[SizeofXfrmAeventId]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_user_report {
+// 	__u8				proto;
+// 	struct xfrm_selector		sel;
+// };
+
+type XfrmUserReport struct {
+	Proto uint8
+	Pad   [3]byte
+	Sel   XfrmSelector
+}
+
+func (msg *XfrmUserReport) Len() int {
+	return SizeofXfrmUserReport
+}
+
+func DeserializeXfrmUserReport(b []byte) *XfrmUserReport {
+	return (*XfrmUserReport)(unsafe.Pointer(&b[0:SizeofXfrmUserReport][0]))
+}
+
+func (msg *XfrmUserReport) Serialize() []byte {
+	return (*(*[SizeofXfrmUserReport]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrm_user_mapping {
+// 	struct xfrm_usersa_id		id;
+// 	__u32				reqid;
+// 	xfrm_address_t			old_saddr;
+// 	xfrm_address_t			new_saddr;
+// 	__be16				old_sport;
+// 	__be16				new_sport;
+// };
+
+type XfrmUserMapping struct {
+	Id       XfrmUsersaId
+	Reqid    uint32
+	OldSaddr XfrmAddress
+	NewSaddr XfrmAddress
+	OldSport uint16 // big endian
+	NewSport uint16 // big endian
+}
+
+func (msg *XfrmUserMapping) Len() int {
+	return SizeofXfrmUserMapping
+}
+
+func DeserializeXfrmUserMapping(b []byte) *XfrmUserMapping {
+	return (*XfrmUserMapping)(unsafe.Pointer(&b[0:SizeofXfrmUserMapping][0]))
+}
+
+func (msg *XfrmUserMapping) Serialize() []byte {
+	return (*(*[SizeofXfrmUserMapping]byte)(unsafe.Pointer(msg)))[:]
+}
@@ -32,3 +32,151 @@ func TestXfrmUserExpireDeserializeSerialize(t *testing.T) {
 	msg := DeserializeXfrmUserExpire(orig)
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
+
+func (msg *XfrmUserPolexpire) write(b []byte) {
+	msg.Pol.write(b[0:SizeofXfrmUserpolicyInfo])
+	b[SizeofXfrmUserpolicyInfo] = msg.Hard
+	copy(b[SizeofXfrmUserpolicyInfo+1:SizeofXfrmUserPolexpire], msg.Pad[:])
+}
+
+func (msg *XfrmUserPolexpire) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserPolexpire)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserPolexpireSafe(b []byte) *XfrmUserPolexpire {
+	var msg = XfrmUserPolexpire{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserPolexpire]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserPolexpireDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserPolexpire)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserPolexpireSafe(orig)
+	msg := DeserializeXfrmUserPolexpire(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmUserAcquire) write(b []byte) {
+	const SaddrEnd = SizeofXfrmId + SizeofXfrmAddress
+	const SelEnd = SaddrEnd + SizeofXfrmSelector
+	const PolicyEnd = SelEnd + SizeofXfrmUserpolicyInfo
+	native := NativeEndian()
+	msg.Id.write(b[0:SizeofXfrmId])
+	msg.Saddr.write(b[SizeofXfrmId:SaddrEnd])
+	msg.Sel.write(b[SaddrEnd:SelEnd])
+	msg.Policy.write(b[SelEnd:PolicyEnd])
+	native.PutUint32(b[PolicyEnd:PolicyEnd+4], msg.Aalgos)
+	native.PutUint32(b[PolicyEnd+4:PolicyEnd+8], msg.Ealgos)
+	native.PutUint32(b[PolicyEnd+8:PolicyEnd+12], msg.Calgos)
+	native.PutUint32(b[PolicyEnd+12:PolicyEnd+16], msg.Seq)
+}
+
+func (msg *XfrmUserAcquire) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserAcquire)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserAcquireSafe(b []byte) *XfrmUserAcquire {
+	var msg = XfrmUserAcquire{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserAcquire]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserAcquireDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserAcquire)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserAcquireSafe(orig)
+	msg := DeserializeXfrmUserAcquire(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmAeventId) write(b []byte) {
+	const SaddrEnd = SizeofXfrmUsersaId + SizeofXfrmAddress
+	native := NativeEndian()
+	msg.SaId.write(b[0:SizeofXfrmUsersaId])
+	msg.Saddr.write(b[SizeofXfrmUsersaId:SaddrEnd])
+	native.PutUint32(b[SaddrEnd:SaddrEnd+4], msg.Flags)
+	native.PutUint32(b[SaddrEnd+4:SaddrEnd+8], msg.Reqid)
+}
+
+func (msg *XfrmAeventId) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmAeventId)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmAeventIdSafe(b []byte) *XfrmAeventId {
+	var msg = XfrmAeventId{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmAeventId]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmAeventIdDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmAeventId)
+	rand.Read(orig)
+	safemsg := deserializeXfrmAeventIdSafe(orig)
+	msg := DeserializeXfrmAeventId(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmUserReport) write(b []byte) {
+	b[0] = msg.Proto
+	copy(b[1:4], msg.Pad[:])
+	msg.Sel.write(b[4:SizeofXfrmUserReport])
+}
+
+func (msg *XfrmUserReport) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserReport)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserReportSafe(b []byte) *XfrmUserReport {
+	var msg = XfrmUserReport{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserReport]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserReportDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserReport)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserReportSafe(orig)
+	msg := DeserializeXfrmUserReport(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmUserMapping) write(b []byte) {
+	const OldEnd = SizeofXfrmUsersaId + 4 + SizeofXfrmAddress
+	const NewEnd = OldEnd + SizeofXfrmAddress
+	native := NativeEndian()
+	msg.Id.write(b[0:SizeofXfrmUsersaId])
+	native.PutUint32(b[SizeofXfrmUsersaId:SizeofXfrmUsersaId+4], msg.Reqid)
+	msg.OldSaddr.write(b[SizeofXfrmUsersaId+4 : OldEnd])
+	msg.NewSaddr.write(b[OldEnd:NewEnd])
+	native.PutUint16(b[NewEnd:NewEnd+2], msg.OldSport)
+	native.PutUint16(b[NewEnd+2:NewEnd+4], msg.NewSport)
+}
+
+func (msg *XfrmUserMapping) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserMapping)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserMappingSafe(b []byte) *XfrmUserMapping {
+	var msg = XfrmUserMapping{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserMapping]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserMappingDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserMapping)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserMappingSafe(orig)
+	msg := DeserializeXfrmUserMapping(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
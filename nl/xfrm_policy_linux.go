@@ -8,6 +8,27 @@ const (
 	SizeofXfrmUserpolicyId   = 0x40
 	SizeofXfrmUserpolicyInfo = 0xa8
 	SizeofXfrmUserTmpl       = 0x40
+	SizeofXfrmUserpolicyType = 0x06
+	SizeofXfrmuSpdInfo       = 0x18
+	SizeofXfrmuSpdHinfo      = 0x08
+	SizeofXfrmuSpdHthresh    = 0x02
+)
+
+const (
+	XFRM_POLICY_LOCALOK = 1
+	XFRM_POLICY_ICMP    = 2
+)
+
+const (
+	XFRM_POLICY_TYPE_MAIN = 0
+	XFRM_POLICY_TYPE_SUB  = 1
+)
+
+const (
+	XFRM_SHARE_ANY     = 0
+	XFRM_SHARE_SESSION = 1
+	XFRM_SHARE_USER    = 2
+	XFRM_SHARE_UNIQUE  = 3
 )
 
 // struct xfrm_userpolicy_id {
@@ -117,3 +138,103 @@ func DeserializeXfrmUserTmpl(b []byte) *XfrmUserTmpl {
 func (msg *XfrmUserTmpl) Serialize() []byte {
 	return (*(*[SizeofXfrmUserTmpl]byte)(unsafe.Pointer(msg)))[:]
 }
+
+// struct xfrm_userpolicy_type {
+//   __u8      type;
+//   __u16     reserved1;
+//   __u8      reserved2;
+// };
+
+type XfrmUserpolicyType struct {
+	Type      uint8
+	Pad1      byte
+	Reserved1 uint16
+	Reserved2 uint8
+	Pad2      byte
+}
+
+func (msg *XfrmUserpolicyType) Len() int {
+	return SizeofXfrmUserpolicyType
+}
+
+func DeserializeXfrmUserpolicyType(b []byte) *XfrmUserpolicyType {
+	return (*XfrmUserpolicyType)(unsafe.Pointer(&b[0:SizeofXfrmUserpolicyType][0]))
+}
+
+func (msg *XfrmUserpolicyType) Serialize() []byte {
+	return (*(*[SizeofXfrmUserpolicyType]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrmu_spdinfo {
+//   __u32 incnt;
+//   __u32 outcnt;
+//   __u32 fwdcnt;
+//   __u32 inscnt;
+//   __u32 outscnt;
+//   __u32 fwdscnt;
+// };
+
+type XfrmuSpdInfo struct {
+	Incnt   uint32
+	Outcnt  uint32
+	Fwdcnt  uint32
+	Inscnt  uint32
+	Outscnt uint32
+	Fwdscnt uint32
+}
+
+func (msg *XfrmuSpdInfo) Len() int {
+	return SizeofXfrmuSpdInfo
+}
+
+func DeserializeXfrmuSpdInfo(b []byte) *XfrmuSpdInfo {
+	return (*XfrmuSpdInfo)(unsafe.Pointer(&b[0:SizeofXfrmuSpdInfo][0]))
+}
+
+func (msg *XfrmuSpdInfo) Serialize() []byte {
+	return (*(*[SizeofXfrmuSpdInfo]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrmu_spdhinfo {
+//   __u32 spdhcnt;
+//   __u32 spdhmcnt;
+// };
+
+type XfrmuSpdHinfo struct {
+	Spdhcnt  uint32
+	Spdhmcnt uint32
+}
+
+func (msg *XfrmuSpdHinfo) Len() int {
+	return SizeofXfrmuSpdHinfo
+}
+
+func DeserializeXfrmuSpdHinfo(b []byte) *XfrmuSpdHinfo {
+	return (*XfrmuSpdHinfo)(unsafe.Pointer(&b[0:SizeofXfrmuSpdHinfo][0]))
+}
+
+func (msg *XfrmuSpdHinfo) Serialize() []byte {
+	return (*(*[SizeofXfrmuSpdHinfo]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrmu_spdhthresh {
+//   __u8 lbits;
+//   __u8 rbits;
+// };
+
+type XfrmuSpdHthresh struct {
+	Lbits uint8
+	Rbits uint8
+}
+
+func (msg *XfrmuSpdHthresh) Len() int {
+	return SizeofXfrmuSpdHthresh
+}
+
+func DeserializeXfrmuSpdHthresh(b []byte) *XfrmuSpdHthresh {
+	return (*XfrmuSpdHthresh)(unsafe.Pointer(&b[0:SizeofXfrmuSpdHthresh][0]))
+}
+
+func (msg *XfrmuSpdHthresh) Serialize() []byte {
+	return (*(*[SizeofXfrmuSpdHthresh]byte)(unsafe.Pointer(msg)))[:]
+}
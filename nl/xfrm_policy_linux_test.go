@@ -107,3 +107,113 @@ func TestXfrmUserTmplDeserializeSerialize(t *testing.T) {
 	msg := DeserializeXfrmUserTmpl(orig)
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
+
+func (msg *XfrmUserpolicyType) write(b []byte) {
+	native := NativeEndian()
+	b[0] = msg.Type
+	b[1] = msg.Pad1
+	native.PutUint16(b[2:4], msg.Reserved1)
+	b[4] = msg.Reserved2
+	b[5] = msg.Pad2
+}
+
+func (msg *XfrmUserpolicyType) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserpolicyType)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserpolicyTypeSafe(b []byte) *XfrmUserpolicyType {
+	var msg = XfrmUserpolicyType{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserpolicyType]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserpolicyTypeDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserpolicyType)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserpolicyTypeSafe(orig)
+	msg := DeserializeXfrmUserpolicyType(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmuSpdInfo) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Incnt)
+	native.PutUint32(b[4:8], msg.Outcnt)
+	native.PutUint32(b[8:12], msg.Fwdcnt)
+	native.PutUint32(b[12:16], msg.Inscnt)
+	native.PutUint32(b[16:20], msg.Outscnt)
+	native.PutUint32(b[20:24], msg.Fwdscnt)
+}
+
+func (msg *XfrmuSpdInfo) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmuSpdInfo)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmuSpdInfoSafe(b []byte) *XfrmuSpdInfo {
+	var msg = XfrmuSpdInfo{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmuSpdInfo]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmuSpdInfoDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmuSpdInfo)
+	rand.Read(orig)
+	safemsg := deserializeXfrmuSpdInfoSafe(orig)
+	msg := DeserializeXfrmuSpdInfo(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmuSpdHinfo) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Spdhcnt)
+	native.PutUint32(b[4:8], msg.Spdhmcnt)
+}
+
+func (msg *XfrmuSpdHinfo) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmuSpdHinfo)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmuSpdHinfoSafe(b []byte) *XfrmuSpdHinfo {
+	var msg = XfrmuSpdHinfo{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmuSpdHinfo]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmuSpdHinfoDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmuSpdHinfo)
+	rand.Read(orig)
+	safemsg := deserializeXfrmuSpdHinfoSafe(orig)
+	msg := DeserializeXfrmuSpdHinfo(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmuSpdHthresh) write(b []byte) {
+	b[0] = msg.Lbits
+	b[1] = msg.Rbits
+}
+
+func (msg *XfrmuSpdHthresh) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmuSpdHthresh)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmuSpdHthreshSafe(b []byte) *XfrmuSpdHthresh {
+	var msg = XfrmuSpdHthresh{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmuSpdHthresh]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmuSpdHthreshDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmuSpdHthresh)
+	rand.Read(orig)
+	safemsg := deserializeXfrmuSpdHthreshSafe(orig)
+	msg := DeserializeXfrmuSpdHthresh(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
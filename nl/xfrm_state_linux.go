@@ -16,6 +16,8 @@ const (
 	SizeofXfrmUsersaFlush    = 0x1
 	SizeofXfrmReplayStateEsn = 0x18
 	SizeofXfrmReplayState    = 0x0c
+	SizeofXfrmUserOffload    = 0x08
+	SizeofXfrmuSadHinfo      = 0x08
 )
 
 const (
@@ -34,6 +36,12 @@ const (
 	XFRM_SA_XFLAG_OSEQ_MAY_WRAP   = 2
 )
 
+const (
+	XFRM_OFFLOAD_IPV6    = 1
+	XFRM_OFFLOAD_INBOUND = 2
+	XFRM_OFFLOAD_PACKET  = 4
+)
+
 // struct xfrm_usersa_id {
 //   xfrm_address_t      daddr;
 //   __be32        spi;
@@ -335,9 +343,43 @@ type XfrmReplayStateEsn struct {
 	Bmp          []uint32
 }
 
+func (msg *XfrmReplayStateEsn) Len() int {
+	return SizeofXfrmReplayStateEsn + len(msg.Bmp)*4
+}
+
+func DeserializeXfrmReplayStateEsn(b []byte) *XfrmReplayStateEsn {
+	native := NativeEndian()
+	ret := XfrmReplayStateEsn{
+		BmpLen:       native.Uint32(b[0:4]),
+		OSeq:         native.Uint32(b[4:8]),
+		Seq:          native.Uint32(b[8:12]),
+		OSeqHi:       native.Uint32(b[12:16]),
+		SeqHi:        native.Uint32(b[16:20]),
+		ReplayWindow: native.Uint32(b[20:24]),
+	}
+	n := int(ret.BmpLen)
+	if max := (len(b) - SizeofXfrmReplayStateEsn) / 4; n > max {
+		n = max
+	}
+	ret.Bmp = make([]uint32, n)
+	for i := range ret.Bmp {
+		ret.Bmp[i] = native.Uint32(b[SizeofXfrmReplayStateEsn+i*4:])
+	}
+	return &ret
+}
+
 func (msg *XfrmReplayStateEsn) Serialize() []byte {
-	// We deliberately do not pass Bmp, as it gets set by the kernel.
-	return (*(*[SizeofXfrmReplayStateEsn]byte)(unsafe.Pointer(msg)))[:]
+	// Bmp is usually left empty, as it gets set by the kernel.
+	if len(msg.Bmp) == 0 {
+		return (*(*[SizeofXfrmReplayStateEsn]byte)(unsafe.Pointer(msg)))[:]
+	}
+	native := NativeEndian()
+	b := make([]byte, msg.Len())
+	copy(b, (*(*[SizeofXfrmReplayStateEsn]byte)(unsafe.Pointer(msg)))[:])
+	for i, v := range msg.Bmp {
+		native.PutUint32(b[SizeofXfrmReplayStateEsn+i*4:], v)
+	}
+	return b
 }
 
 // struct xfrm_replay_state {
@@ -359,3 +401,48 @@ func DeserializeXfrmReplayState(b []byte) *XfrmReplayState {
 func (msg *XfrmReplayState) Serialize() []byte {
 	return (*(*[SizeofXfrmReplayState]byte)(unsafe.Pointer(msg)))[:]
 }
+
+// struct xfrm_user_offload {
+//     int     ifindex;
+//     __u8    flags;
+// };
+
+type XfrmUserOffload struct {
+	Ifindex int32
+	Flags   uint8
+	Pad     [3]byte
+}
+
+func (msg *XfrmUserOffload) Len() int {
+	return SizeofXfrmUserOffload
+}
+
+func DeserializeXfrmUserOffload(b []byte) *XfrmUserOffload {
+	return (*XfrmUserOffload)(unsafe.Pointer(&b[0:SizeofXfrmUserOffload][0]))
+}
+
+func (msg *XfrmUserOffload) Serialize() []byte {
+	return (*(*[SizeofXfrmUserOffload]byte)(unsafe.Pointer(msg)))[:]
+}
+
+// struct xfrmu_sadhinfo {
+//     __u32 sadhcnt; /* current hash bkts */
+//     __u32 sadhmcnt; /* max allowed hash bkts */
+// };
+
+type XfrmuSadHinfo struct {
+	Sadhcnt  uint32
+	Sadhmcnt uint32
+}
+
+func (msg *XfrmuSadHinfo) Len() int {
+	return SizeofXfrmuSadHinfo
+}
+
+func DeserializeXfrmuSadHinfo(b []byte) *XfrmuSadHinfo {
+	return (*XfrmuSadHinfo)(unsafe.Pointer(&b[0:SizeofXfrmuSadHinfo][0]))
+}
+
+func (msg *XfrmuSadHinfo) Serialize() []byte {
+	return (*(*[SizeofXfrmuSadHinfo]byte)(unsafe.Pointer(msg)))[:]
+}
@@ -302,3 +302,105 @@ func TestXfrmXfrmAlgoAeadDeserializeSerialize(t *testing.T) {
 	msg := DeserializeXfrmAlgoAEAD(orig)
 	testDeserializeSerialize(t, orig, safemsg, msg)
 }
+
+func (msg *XfrmUserOffload) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], uint32(msg.Ifindex))
+	b[4] = msg.Flags
+	copy(b[5:8], msg.Pad[:])
+}
+
+func (msg *XfrmUserOffload) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmUserOffload)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmUserOffloadSafe(b []byte) *XfrmUserOffload {
+	var msg = XfrmUserOffload{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmUserOffload]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmUserOffloadDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmUserOffload)
+	rand.Read(orig)
+	safemsg := deserializeXfrmUserOffloadSafe(orig)
+	msg := DeserializeXfrmUserOffload(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
+
+func (msg *XfrmReplayStateEsn) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.BmpLen)
+	native.PutUint32(b[4:8], msg.OSeq)
+	native.PutUint32(b[8:12], msg.Seq)
+	native.PutUint32(b[12:16], msg.OSeqHi)
+	native.PutUint32(b[16:20], msg.SeqHi)
+	native.PutUint32(b[20:24], msg.ReplayWindow)
+	for i, v := range msg.Bmp {
+		native.PutUint32(b[24+i*4:28+i*4], v)
+	}
+}
+
+func (msg *XfrmReplayStateEsn) serializeSafe() []byte {
+	b := make([]byte, msg.Len())
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmReplayStateEsnSafe(b []byte) *XfrmReplayStateEsn {
+	var msg = XfrmReplayStateEsn{}
+	binary.Read(bytes.NewReader(b[0:4]), NativeEndian(), &msg.BmpLen)
+	binary.Read(bytes.NewReader(b[4:8]), NativeEndian(), &msg.OSeq)
+	binary.Read(bytes.NewReader(b[8:12]), NativeEndian(), &msg.Seq)
+	binary.Read(bytes.NewReader(b[12:16]), NativeEndian(), &msg.OSeqHi)
+	binary.Read(bytes.NewReader(b[16:20]), NativeEndian(), &msg.SeqHi)
+	binary.Read(bytes.NewReader(b[20:24]), NativeEndian(), &msg.ReplayWindow)
+	msg.Bmp = make([]uint32, msg.BmpLen)
+	binary.Read(bytes.NewReader(b[24:]), NativeEndian(), msg.Bmp)
+	return &msg
+}
+
+func TestXfrmReplayStateEsnDeserializeSerialize(t *testing.T) {
+	native := NativeEndian()
+	// use a 4 words bitmap
+	var orig = make([]byte, SizeofXfrmReplayStateEsn+16)
+	rand.Read(orig)
+	native.PutUint32(orig[0:4], 4)
+	safemsg := deserializeXfrmReplayStateEsnSafe(orig)
+	msg := DeserializeXfrmReplayStateEsn(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+
+	// A bitmap longer than the attribute is truncated.
+	native.PutUint32(orig[0:4], 5)
+	if msg := DeserializeXfrmReplayStateEsn(orig); len(msg.Bmp) != 4 {
+		t.Fatalf("expected a bitmap of 4 words, got %d", len(msg.Bmp))
+	}
+}
+
+func (msg *XfrmuSadHinfo) write(b []byte) {
+	native := NativeEndian()
+	native.PutUint32(b[0:4], msg.Sadhcnt)
+	native.PutUint32(b[4:8], msg.Sadhmcnt)
+}
+
+func (msg *XfrmuSadHinfo) serializeSafe() []byte {
+	b := make([]byte, SizeofXfrmuSadHinfo)
+	msg.write(b)
+	return b
+}
+
+func deserializeXfrmuSadHinfoSafe(b []byte) *XfrmuSadHinfo {
+	var msg = XfrmuSadHinfo{}
+	binary.Read(bytes.NewReader(b[0:SizeofXfrmuSadHinfo]), NativeEndian(), &msg)
+	return &msg
+}
+
+func TestXfrmuSadHinfoDeserializeSerialize(t *testing.T) {
+	var orig = make([]byte, SizeofXfrmuSadHinfo)
+	rand.Read(orig)
+	safemsg := deserializeXfrmuSadHinfoSafe(orig)
+	msg := DeserializeXfrmuSadHinfo(orig)
+	testDeserializeSerialize(t, orig, safemsg, msg)
+}
@@ -1,22 +1,91 @@
 package netlink
 
 import (
+	"fmt"
+	"net"
 	"strings"
 )
 
 // Protinfo represents bridge flags from netlink.
 type Protinfo struct {
-	Hairpin       bool
-	Guard         bool
-	FastLeave     bool
-	RootBlock     bool
-	Learning      bool
-	Flood         bool
-	ProxyArp      bool
-	ProxyArpWiFi  bool
-	Isolated      bool
-	NeighSuppress bool
-	VlanTunnel    bool
+	Hairpin           bool
+	Guard             bool
+	FastLeave         bool
+	RootBlock         bool
+	Learning          bool
+	Flood             bool
+	ProxyArp          bool
+	ProxyArpWiFi      bool
+	Isolated          bool
+	NeighSuppress     bool
+	VlanTunnel        bool
+	McastFlood        bool
+	BcastFlood        bool
+	McastToUcast      bool
+	Locked            bool
+	Mab               bool
+	NeighVlanSuppress bool
+
+	// Mask selects the boolean flags above applied by LinkSetBrportAttrs,
+	// the others being left unchanged. It is not set by LinkGetProtinfo.
+	Mask ProtinfoFlags
+
+	// Optional port settings. When applied with LinkSetBrportAttrs, nil
+	// values are left unchanged.
+	State           *uint8  // one of the nl.BR_STATE_* values
+	Priority        *uint16 // STP port priority
+	Cost            *uint32 // STP path cost
+	MulticastRouter *uint8  // 0 disabled, 1 temporary, 2 permanent
+	BackupPort      *uint32 // ifindex of the backup port, 0 to remove it
+	BackupNhId      *uint32 // nexthop group id of the backup, 0 to remove it
+
+	// Read-only STP port state, as reported by the kernel.
+	RootId            *BridgeId
+	BridgeId          *BridgeId
+	DesignatedPort    uint16
+	DesignatedCost    uint32
+	PortId            uint16
+	PortNo            uint16
+	TopologyChangeAck uint8
+	ConfigPending     uint8
+	// Timers are in hundredths of a second.
+	MessageAgeTimer   uint64
+	ForwardDelayTimer uint64
+	HoldTimer         uint64
+}
+
+// ProtinfoFlags selects boolean flags of Protinfo.
+type ProtinfoFlags uint32
+
+const (
+	PROTINFO_HAIRPIN ProtinfoFlags = 1 << iota
+	PROTINFO_GUARD
+	PROTINFO_FAST_LEAVE
+	PROTINFO_ROOT_BLOCK
+	PROTINFO_LEARNING
+	PROTINFO_FLOOD
+	PROTINFO_PROXY_ARP
+	PROTINFO_PROXY_ARP_WIFI
+	PROTINFO_ISOLATED
+	PROTINFO_NEIGH_SUPPRESS
+	PROTINFO_VLAN_TUNNEL
+	PROTINFO_MCAST_FLOOD
+	PROTINFO_BCAST_FLOOD
+	PROTINFO_MCAST_TO_UCAST
+	PROTINFO_LOCKED
+	PROTINFO_MAB
+	PROTINFO_NEIGH_VLAN_SUPPRESS
+)
+
+// BridgeId is an STP bridge identifier.
+type BridgeId struct {
+	Priority uint16
+	Addr     net.HardwareAddr
+}
+
+// String returns the bridge id in the format used by iproute2.
+func (id *BridgeId) String() string {
+	return fmt.Sprintf("%04x.%s", id.Priority, id.Addr)
 }
 
 // String returns a list of enabled flags
@@ -59,6 +128,24 @@ func (prot *Protinfo) String() string {
 	if prot.VlanTunnel {
 		boolStrings = append(boolStrings, "VlanTunnel")
 	}
+	if prot.McastFlood {
+		boolStrings = append(boolStrings, "McastFlood")
+	}
+	if prot.BcastFlood {
+		boolStrings = append(boolStrings, "BcastFlood")
+	}
+	if prot.McastToUcast {
+		boolStrings = append(boolStrings, "McastToUcast")
+	}
+	if prot.Locked {
+		boolStrings = append(boolStrings, "Locked")
+	}
+	if prot.Mab {
+		boolStrings = append(boolStrings, "Mab")
+	}
+	if prot.NeighVlanSuppress {
+		boolStrings = append(boolStrings, "NeighVlanSuppress")
+	}
 	return strings.Join(boolStrings, " ")
 }
 
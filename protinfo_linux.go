@@ -3,6 +3,7 @@ package netlink
 import (
 	"errors"
 	"fmt"
+	"net"
 	"syscall"
 
 	"github.com/vishvananda/netlink/nl"
@@ -79,8 +80,152 @@ func parseProtinfo(infos []syscall.NetlinkRouteAttr) (pi Protinfo) {
 			pi.NeighSuppress = byteToBool(info.Value[0])
 		case nl.IFLA_BRPORT_VLAN_TUNNEL:
 			pi.VlanTunnel = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_MCAST_FLOOD:
+			pi.McastFlood = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_BCAST_FLOOD:
+			pi.BcastFlood = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_MCAST_TO_UCAST:
+			pi.McastToUcast = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_LOCKED:
+			pi.Locked = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_MAB:
+			pi.Mab = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_NEIGH_VLAN_SUPPRESS:
+			pi.NeighVlanSuppress = byteToBool(info.Value[0])
+		case nl.IFLA_BRPORT_STATE:
+			state := info.Value[0]
+			pi.State = &state
+		case nl.IFLA_BRPORT_PRIORITY:
+			priority := native.Uint16(info.Value[0:2])
+			pi.Priority = &priority
+		case nl.IFLA_BRPORT_COST:
+			cost := native.Uint32(info.Value[0:4])
+			pi.Cost = &cost
+		case nl.IFLA_BRPORT_MULTICAST_ROUTER:
+			router := info.Value[0]
+			pi.MulticastRouter = &router
+		case nl.IFLA_BRPORT_BACKUP_PORT:
+			backupPort := native.Uint32(info.Value[0:4])
+			pi.BackupPort = &backupPort
+		case nl.IFLA_BRPORT_BACKUP_NHID:
+			backupNhId := native.Uint32(info.Value[0:4])
+			pi.BackupNhId = &backupNhId
+		case nl.IFLA_BRPORT_ROOT_ID:
+			pi.RootId = parseBridgeId(info.Value)
+		case nl.IFLA_BRPORT_BRIDGE_ID:
+			pi.BridgeId = parseBridgeId(info.Value)
+		case nl.IFLA_BRPORT_DESIGNATED_PORT:
+			pi.DesignatedPort = native.Uint16(info.Value[0:2])
+		case nl.IFLA_BRPORT_DESIGNATED_COST:
+			pi.DesignatedCost = native.Uint32(info.Value[0:4])
+		case nl.IFLA_BRPORT_ID:
+			pi.PortId = native.Uint16(info.Value[0:2])
+		case nl.IFLA_BRPORT_NO:
+			pi.PortNo = native.Uint16(info.Value[0:2])
+		case nl.IFLA_BRPORT_TOPOLOGY_CHANGE_ACK:
+			pi.TopologyChangeAck = info.Value[0]
+		case nl.IFLA_BRPORT_CONFIG_PENDING:
+			pi.ConfigPending = info.Value[0]
+		case nl.IFLA_BRPORT_MESSAGE_AGE_TIMER:
+			pi.MessageAgeTimer = native.Uint64(info.Value[0:8])
+		case nl.IFLA_BRPORT_FORWARD_DELAY_TIMER:
+			pi.ForwardDelayTimer = native.Uint64(info.Value[0:8])
+		case nl.IFLA_BRPORT_HOLD_TIMER:
+			pi.HoldTimer = native.Uint64(info.Value[0:8])
 		}
 
 	}
 	return
 }
+
+// parseBridgeId parses a struct ifla_bridge_id.
+func parseBridgeId(b []byte) *BridgeId {
+	if len(b) < 8 {
+		return nil
+	}
+	return &BridgeId{
+		Priority: uint16(b[0])<<8 | uint16(b[1]),
+		Addr:     net.HardwareAddr(append([]byte(nil), b[2:8]...)),
+	}
+}
+
+// LinkSetBrportAttrs applies the bridge port settings in pi to link in a
+// single request. Only the boolean flags selected by pi.Mask and the
+// optional settings that are not nil are applied, the others being left
+// unchanged. The read-only STP fields are ignored.
+// Equivalent to: `ip link set $link type bridge_slave ...`
+func LinkSetBrportAttrs(link Link, pi Protinfo) error {
+	return pkgHandle.LinkSetBrportAttrs(link, pi)
+}
+
+// LinkSetBrportAttrs applies the bridge port settings in pi to link in a
+// single request. Only the boolean flags selected by pi.Mask and the
+// optional settings that are not nil are applied, the others being left
+// unchanged. The read-only STP fields are ignored.
+// Equivalent to: `ip link set $link type bridge_slave ...`
+func (h *Handle) LinkSetBrportAttrs(link Link, pi Protinfo) error {
+	base := link.Attrs()
+	if err := h.ensureLinkIndex(base); err != nil {
+		return err
+	}
+	req := h.newNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
+
+	msg := nl.NewIfInfomsg(unix.AF_BRIDGE)
+	msg.Index = int32(base.Index)
+	req.AddData(msg)
+
+	br := nl.NewRtAttr(unix.IFLA_PROTINFO|unix.NLA_F_NESTED, nil)
+	addProtinfoAttrs(br, &pi)
+	req.AddData(br)
+
+	_, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	return err
+}
+
+func addProtinfoAttrs(br *nl.RtAttr, pi *Protinfo) {
+	for _, f := range []struct {
+		flag     ProtinfoFlags
+		attrType int
+		value    bool
+	}{
+		{PROTINFO_HAIRPIN, nl.IFLA_BRPORT_MODE, pi.Hairpin},
+		{PROTINFO_GUARD, nl.IFLA_BRPORT_GUARD, pi.Guard},
+		{PROTINFO_FAST_LEAVE, nl.IFLA_BRPORT_FAST_LEAVE, pi.FastLeave},
+		{PROTINFO_ROOT_BLOCK, nl.IFLA_BRPORT_PROTECT, pi.RootBlock},
+		{PROTINFO_LEARNING, nl.IFLA_BRPORT_LEARNING, pi.Learning},
+		{PROTINFO_FLOOD, nl.IFLA_BRPORT_UNICAST_FLOOD, pi.Flood},
+		{PROTINFO_PROXY_ARP, nl.IFLA_BRPORT_PROXYARP, pi.ProxyArp},
+		{PROTINFO_PROXY_ARP_WIFI, nl.IFLA_BRPORT_PROXYARP_WIFI, pi.ProxyArpWiFi},
+		{PROTINFO_ISOLATED, nl.IFLA_BRPORT_ISOLATED, pi.Isolated},
+		{PROTINFO_NEIGH_SUPPRESS, nl.IFLA_BRPORT_NEIGH_SUPPRESS, pi.NeighSuppress},
+		{PROTINFO_VLAN_TUNNEL, nl.IFLA_BRPORT_VLAN_TUNNEL, pi.VlanTunnel},
+		{PROTINFO_MCAST_FLOOD, nl.IFLA_BRPORT_MCAST_FLOOD, pi.McastFlood},
+		{PROTINFO_BCAST_FLOOD, nl.IFLA_BRPORT_BCAST_FLOOD, pi.BcastFlood},
+		{PROTINFO_MCAST_TO_UCAST, nl.IFLA_BRPORT_MCAST_TO_UCAST, pi.McastToUcast},
+		{PROTINFO_LOCKED, nl.IFLA_BRPORT_LOCKED, pi.Locked},
+		{PROTINFO_MAB, nl.IFLA_BRPORT_MAB, pi.Mab},
+		{PROTINFO_NEIGH_VLAN_SUPPRESS, nl.IFLA_BRPORT_NEIGH_VLAN_SUPPRESS, pi.NeighVlanSuppress},
+	} {
+		if pi.Mask&f.flag != 0 {
+			br.AddRtAttr(f.attrType, boolToByte(f.value))
+		}
+	}
+	if pi.State != nil {
+		br.AddRtAttr(nl.IFLA_BRPORT_STATE, nl.Uint8Attr(*pi.State))
+	}
+	if pi.Priority != nil {
+		br.AddRtAttr(nl.IFLA_BRPORT_PRIORITY, nl.Uint16Attr(*pi.Priority))
+	}
+	if pi.Cost != nil {
+		br.AddRtAttr(nl.IFLA_BRPORT_COST, nl.Uint32Attr(*pi.Cost))
+	}
+	if pi.MulticastRouter != nil {
+		br.AddRtAttr(nl.IFLA_BRPORT_MULTICAST_ROUTER, nl.Uint8Attr(*pi.MulticastRouter))
+	}
+	if pi.BackupPort != nil {
+		br.AddRtAttr(nl.IFLA_BRPORT_BACKUP_PORT, nl.Uint32Attr(*pi.BackupPort))
+	}
+	if pi.BackupNhId != nil {
+		br.AddRtAttr(nl.IFLA_BRPORT_BACKUP_NHID, nl.Uint32Attr(*pi.BackupNhId))
+	}
+}
@@ -196,3 +196,101 @@ func TestProtinfo(t *testing.T) {
 		t.Fatalf("Isolated mode is not enabled for %s, but should", iface1.Name)
 	}
 }
+
+func TestLinkSetBrportAttrs(t *testing.T) {
+	minKernelRequired(t, 4, 15)
+	t.Cleanup(setUpNetlinkTest(t))
+	master := &Bridge{LinkAttrs: LinkAttrs{Name: "foo"}}
+	if err := LinkAdd(master); err != nil {
+		t.Fatal(err)
+	}
+	port := &Veth{LinkAttrs: LinkAttrs{Name: "bar1", MasterIndex: master.Index}, PeerName: "bar2"}
+	if err := LinkAdd(port); err != nil {
+		t.Fatal(err)
+	}
+
+	pi, err := LinkGetProtinfo(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pi.State == nil || pi.Priority == nil || pi.Cost == nil {
+		t.Fatalf("expected port state, priority and cost to be reported, got %+v", pi)
+	}
+	if pi.BridgeId == nil || pi.RootId == nil {
+		t.Fatalf("expected STP bridge and root ids to be reported, got %+v", pi)
+	}
+	if pi.PortNo == 0 {
+		t.Fatalf("expected a non-zero port number, got %+v", pi)
+	}
+
+	oldLearning := pi.Learning
+	priority := uint16(10)
+	cost := uint32(42)
+	pi.Hairpin = true
+	pi.BcastFlood = false
+	pi.McastToUcast = true
+	pi.Priority = &priority
+	pi.Cost = &cost
+	pi.State = nil
+	pi.Mask = PROTINFO_HAIRPIN | PROTINFO_BCAST_FLOOD | PROTINFO_MCAST_TO_UCAST
+	if err := LinkSetBrportAttrs(port, pi); err != nil {
+		t.Fatal(err)
+	}
+
+	pi, err = LinkGetProtinfo(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pi.Hairpin {
+		t.Fatalf("Hairpin mode is not enabled for %s, but should", port.Name)
+	}
+	if pi.BcastFlood {
+		t.Fatalf("BcastFlood is enabled for %s, but shouldn't", port.Name)
+	}
+	if !pi.McastToUcast {
+		t.Fatalf("McastToUcast is not enabled for %s, but should", port.Name)
+	}
+	if pi.Learning != oldLearning {
+		t.Fatalf("Learning field was changed for %s but shouldn't", port.Name)
+	}
+	if *pi.Priority != priority {
+		t.Fatalf("expected priority %d for %s, got %d", priority, port.Name, *pi.Priority)
+	}
+	if *pi.Cost != cost {
+		t.Fatalf("expected cost %d for %s, got %d", cost, port.Name, *pi.Cost)
+	}
+}
+
+func TestLinkSetBrportAttrsPartial(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+	master := &Bridge{LinkAttrs: LinkAttrs{Name: "foo"}}
+	if err := LinkAdd(master); err != nil {
+		t.Fatal(err)
+	}
+	port := &Veth{LinkAttrs: LinkAttrs{Name: "bar1", MasterIndex: master.Index}, PeerName: "bar2"}
+	if err := LinkAdd(port); err != nil {
+		t.Fatal(err)
+	}
+	before, err := LinkGetProtinfo(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !before.Learning || !before.Flood {
+		t.Fatalf("expected learning and flooding to be enabled by default, got %s", before.String())
+	}
+
+	if err := LinkSetBrportAttrs(port, Protinfo{Isolated: true, Mask: PROTINFO_ISOLATED}); err != nil {
+		t.Fatal(err)
+	}
+	after, err := LinkGetProtinfo(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.Isolated {
+		t.Fatalf("Isolated mode is not enabled for %s, but should", port.Name)
+	}
+	after.Isolated = before.Isolated
+	if after.String() != before.String() {
+		t.Fatalf("flags other than isolated changed for %s: %s, expected %s", port.Name, after.String(), before.String())
+	}
+}
@@ -3,6 +3,7 @@ package netlink
 import (
 	"fmt"
 	"net"
+	"net/netip"
 	"strings"
 )
 
@@ -163,6 +164,38 @@ func (r Route) Equal(x Route) bool {
 		(r.NHID == x.NHID)
 }
 
+// DstPrefix returns the destination of the route as a netip.Prefix. It is
+// invalid for a default route.
+func (r Route) DstPrefix() netip.Prefix {
+	return NetipPrefix(r.Dst)
+}
+
+// SrcAddr returns the preferred source address of the route as a netip.Addr.
+func (r Route) SrcAddr() netip.Addr {
+	return NetipAddr(r.Src)
+}
+
+// GwAddr returns the gateway of the route as a netip.Addr.
+func (r Route) GwAddr() netip.Addr {
+	return NetipAddr(r.Gw)
+}
+
+// SetDstPrefix sets the destination of the route. An invalid prefix makes
+// it a default route.
+func (r *Route) SetDstPrefix(prefix netip.Prefix) {
+	r.Dst = NewIPNetFromPrefix(prefix)
+}
+
+// SetSrcAddr sets the preferred source address of the route.
+func (r *Route) SetSrcAddr(addr netip.Addr) {
+	r.Src = netIP(addr)
+}
+
+// SetGwAddr sets the gateway of the route.
+func (r *Route) SetGwAddr(addr netip.Addr) {
+	r.Gw = netIP(addr)
+}
+
 func (r *Route) SetFlag(flag NextHopFlag) {
 	r.Flags |= int(flag)
 }
@@ -176,6 +209,21 @@ type flagString struct {
 	s string
 }
 
+// RouteNetip is a compact view of a route using netip types, as returned by
+// RouteListNetipIter. Only the gateway of single path routes is reported.
+type RouteNetip struct {
+	Family    int
+	Table     int
+	Type      int
+	Protocol  RouteProtocol
+	Scope     Scope
+	Dst       netip.Prefix // invalid for a default route
+	Src       netip.Addr
+	Gw        netip.Addr
+	LinkIndex int
+	Priority  int
+}
+
 // RouteUpdate is sent when a route changes - type is RTM_NEWROUTE or RTM_DELROUTE
 
 // NlFlags is only non-zero for RTM_NEWROUTE, the following flags can be set:
@@ -187,6 +235,12 @@ type RouteUpdate struct {
 	Type    uint16
 	NlFlags uint16
 	Route
+	// NetnsID is the id of the network namespace the update originates
+	// from, see RouteSubscribeOptions.ListenAllNsid, or -1.
+	NetnsID int
+	// Resync marks the updates delimiting a resync, which carry nothing
+	// else.
+	Resync SubscriptionResync
 }
 
 type NexthopInfo struct {
@@ -217,6 +271,11 @@ func (n *NexthopInfo) String() string {
 	return fmt.Sprintf("{%s}", strings.Join(elems, " "))
 }
 
+// GwAddr returns the gateway of the nexthop as a netip.Addr.
+func (n NexthopInfo) GwAddr() netip.Addr {
+	return NetipAddr(n.Gw)
+}
+
 func (n NexthopInfo) Equal(x NexthopInfo) bool {
 	return n.LinkIndex == x.LinkIndex &&
 		n.Hops == x.Hops &&
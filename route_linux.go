@@ -2,10 +2,12 @@ package netlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"strconv"
 	"strings"
 	"syscall"
@@ -1349,6 +1351,83 @@ func (h *Handle) RouteListFilteredIter(family int, filter *Route, filterMask uin
 	return executeErr
 }
 
+// RouteListNetipIter passes each route of the table to f, as a RouteNetip
+// decoded without allocating. A table of RT_TABLE_UNSPEC means all tables.
+// Cloned routes are skipped. Iteration continues until all routes are
+// loaded or f returns false.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func RouteListNetipIter(family, table int, f func(RouteNetip) (cont bool)) error {
+	return pkgHandle.RouteListNetipIter(family, table, f)
+}
+
+// RouteListNetipIter passes each route of the table to f, as a RouteNetip
+// decoded without allocating. A table of RT_TABLE_UNSPEC means all tables.
+// Cloned routes are skipped. Iteration continues until all routes are
+// loaded or f returns false.
+//
+// If the returned error is [ErrDumpInterrupted], results may be inconsistent
+// or incomplete.
+func (h *Handle) RouteListNetipIter(family, table int, f func(RouteNetip) (cont bool)) error {
+	req := h.newNetlinkRequest(unix.RTM_GETROUTE, unix.NLM_F_DUMP)
+	rtmsg := &nl.RtMsg{}
+	rtmsg.Family = uint8(family)
+	req.AddData(rtmsg)
+	return req.ExecuteIter(unix.NETLINK_ROUTE, unix.RTM_NEWROUTE, func(m []byte) bool {
+		route, ok := parseRouteNetip(m)
+		if !ok {
+			return true
+		}
+		if family != FAMILY_ALL && route.Family != family {
+			return true
+		}
+		if table != unix.RT_TABLE_UNSPEC && route.Table != table {
+			return true
+		}
+		return f(route)
+	})
+}
+
+// parseRouteNetip decodes m into a RouteNetip. It returns false for cloned
+// routes and truncated messages.
+func parseRouteNetip(m []byte) (RouteNetip, bool) {
+	if len(m) < unix.SizeofRtMsg {
+		return RouteNetip{}, false
+	}
+	msg := nl.DeserializeRtMsg(m)
+	if msg.Flags&unix.RTM_F_CLONED != 0 {
+		return RouteNetip{}, false
+	}
+	route := RouteNetip{
+		Family:   int(msg.Family),
+		Table:    int(msg.Table),
+		Type:     int(msg.Type),
+		Protocol: RouteProtocol(msg.Protocol),
+		Scope:    Scope(msg.Scope),
+	}
+	ad := nl.NewAttributeDecoder(m[msg.Len():])
+	for ad.Next() {
+		switch ad.Type() {
+		case unix.RTA_DST:
+			if addr, ok := netip.AddrFromSlice(ad.Value()); ok {
+				route.Dst = netip.PrefixFrom(addr, int(msg.Dst_len))
+			}
+		case unix.RTA_PREFSRC:
+			route.Src, _ = netip.AddrFromSlice(ad.Value())
+		case unix.RTA_GATEWAY:
+			route.Gw, _ = netip.AddrFromSlice(ad.Value())
+		case unix.RTA_OIF:
+			route.LinkIndex = int(ad.Uint32())
+		case unix.RTA_PRIORITY:
+			route.Priority = int(ad.Uint32())
+		case unix.RTA_TABLE:
+			route.Table = int(ad.Uint32())
+		}
+	}
+	return route, true
+}
+
 // deserializeRouteCacheInfo decodes a RTA_CACHEINFO attribute into a RouteCacheInfo struct
 func deserializeRouteCacheInfo(b []byte) (*RouteCacheInfo, error) {
 	if len(b) != 32 {
@@ -1773,13 +1852,13 @@ func (h *Handle) RouteGet(destination net.IP) ([]Route, error) {
 // RouteSubscribe takes a chan down which notifications will be sent
 // when routes are added or deleted. Close the 'done' chan to stop subscription.
 func RouteSubscribe(ch chan<- RouteUpdate, done <-chan struct{}) error {
-	return routeSubscribeAt(netns.None(), netns.None(), ch, done, nil, false, 0, nil, false)
+	return routeSubscribeAt(netns.None(), netns.None(), ch, done, RouteSubscribeOptions{})
 }
 
 // RouteSubscribeAt works like RouteSubscribe plus it allows the caller
 // to choose the network namespace in which to subscribe (ns).
 func RouteSubscribeAt(ns netns.NsHandle, ch chan<- RouteUpdate, done <-chan struct{}) error {
-	return routeSubscribeAt(ns, netns.None(), ch, done, nil, false, 0, nil, false)
+	return routeSubscribeAt(ns, netns.None(), ch, done, RouteSubscribeOptions{})
 }
 
 // RouteSubscribeOptions contains a set of options to use with
@@ -1791,6 +1870,14 @@ type RouteSubscribeOptions struct {
 	ReceiveBufferSize      int
 	ReceiveBufferForceSize bool
 	ReceiveTimeout         *unix.Timeval
+	// Resync keeps the subscription going when the receive buffer overruns,
+	// as for LinkSubscribeOptions, dumping the existing routes again.
+	Resync bool
+	// NoENOBUFS sets NETLINK_NO_ENOBUFS on the socket.
+	NoENOBUFS bool
+	// ListenAllNsid sets NETLINK_LISTEN_ALL_NSID on the socket, as for
+	// LinkSubscribeOptions.
+	ListenAllNsid bool
 }
 
 // RouteSubscribeWithOptions work like RouteSubscribe but enable to
@@ -1805,94 +1892,56 @@ func RouteSubscribeWithOptions(ch chan<- RouteUpdate, done <-chan struct{}, opti
 		none := netns.None()
 		options.Namespace = &none
 	}
-	return routeSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback, options.ListExisting,
-		options.ReceiveBufferSize, options.ReceiveTimeout, options.ReceiveBufferForceSize)
+	return routeSubscribeAt(*options.Namespace, netns.None(), ch, done, options)
 }
 
-func routeSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- RouteUpdate, done <-chan struct{}, cberr func(error), listExisting bool,
-	rcvbuf int, rcvTimeout *unix.Timeval, rcvbufForce bool) error {
-	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_ROUTE, unix.RTNLGRP_IPV4_ROUTE, unix.RTNLGRP_IPV6_ROUTE)
-	if err != nil {
+// RouteSubscribeContext works like RouteSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func RouteSubscribeContext(ctx context.Context, ch chan<- RouteUpdate, options RouteSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
-	if rcvTimeout != nil {
-		if err := s.SetReceiveTimeout(rcvTimeout); err != nil {
-			return err
-		}
-	}
-	if rcvbuf != 0 {
-		err = s.SetReceiveBufferSize(rcvbuf, rcvbufForce)
-		if err != nil {
-			return err
-		}
-	}
-	if done != nil {
-		go func() {
-			<-done
-			s.Close()
-		}()
-	}
-	if listExisting {
-		req := pkgHandle.newNetlinkRequest(unix.RTM_GETROUTE,
-			unix.NLM_F_DUMP)
-		infmsg := nl.NewIfInfomsg(unix.AF_UNSPEC)
-		req.AddData(infmsg)
-		if err := s.Send(req); err != nil {
-			return err
-		}
-	}
-	go func() {
-		defer close(ch)
-		for {
-			msgs, from, err := s.Receive()
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return RouteSubscribeWithOptions(ch, ctx.Done(), options)
+}
+
+func routeSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- RouteUpdate, done <-chan struct{}, options RouteSubscribeOptions) error {
+	sub := &subscription{
+		dumps: []func() *nl.NetlinkRequest{func() *nl.NetlinkRequest {
+			req := pkgHandle.newNetlinkRequest(unix.RTM_GETROUTE, unix.NLM_F_DUMP)
+			req.AddData(nl.NewIfInfomsg(unix.AF_UNSPEC))
+			return req
+		}},
+		handle: func(m syscall.NetlinkMessage, nsid int) error {
+			route, err := deserializeRoute(m.Data)
 			if err != nil {
-				if cberr != nil {
-					cberr(fmt.Errorf("Receive failed: %v",
-						err))
-				}
-				return
+				return err
 			}
-			if from.Pid != nl.PidKernel {
-				if cberr != nil {
-					cberr(fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, nl.PidKernel))
-				}
-				continue
+			ch <- RouteUpdate{
+				Type:    m.Header.Type,
+				NlFlags: m.Header.Flags & (unix.NLM_F_REPLACE | unix.NLM_F_EXCL | unix.NLM_F_CREATE | unix.NLM_F_APPEND),
+				Route:   route,
+				NetnsID: nsid,
 			}
-			for _, m := range msgs {
-				if m.Header.Flags&unix.NLM_F_DUMP_INTR != 0 && cberr != nil {
-					cberr(ErrDumpInterrupted)
-				}
-				if m.Header.Type == unix.NLMSG_DONE {
-					continue
-				}
-				if m.Header.Type == unix.NLMSG_ERROR {
-					error := int32(native.Uint32(m.Data[0:4]))
-					if error == 0 {
-						continue
-					}
-					if cberr != nil {
-						cberr(fmt.Errorf("error message: %v",
-							syscall.Errno(-error)))
-					}
-					continue
-				}
-				route, err := deserializeRoute(m.Data)
-				if err != nil {
-					if cberr != nil {
-						cberr(err)
-					}
-					continue
-				}
-				ch <- RouteUpdate{
-					Type:    m.Header.Type,
-					NlFlags: m.Header.Flags & (unix.NLM_F_REPLACE | unix.NLM_F_EXCL | unix.NLM_F_CREATE | unix.NLM_F_APPEND),
-					Route:   route,
-				}
-			}
-		}
-	}()
-
-	return nil
+			return nil
+		},
+		mark: func(r SubscriptionResync) {
+			ch <- RouteUpdate{NetnsID: nl.NETNSA_NSID_NOT_ASSIGNED, Resync: r}
+		},
+		close: func() { close(ch) },
+	}
+	return sub.start(newNs, curNs, done, subscribeOptions{
+		cberr:         options.ErrorCallback,
+		listExisting:  options.ListExisting,
+		rcvbuf:        options.ReceiveBufferSize,
+		rcvbufForce:   options.ReceiveBufferForceSize,
+		rcvTimeout:    options.ReceiveTimeout,
+		resync:        options.Resync,
+		noENOBUFS:     options.NoENOBUFS,
+		listenAllNsid: options.ListenAllNsid,
+	}, unix.RTNLGRP_IPV4_ROUTE, unix.RTNLGRP_IPV6_ROUTE)
 }
 
 func (p RouteProtocol) String() string {
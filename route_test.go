@@ -5,6 +5,7 @@ package netlink
 
 import (
 	"net"
+	"net/netip"
 	"runtime"
 	"strconv"
 	"testing"
@@ -2977,3 +2978,108 @@ func TestRouteNHID(t *testing.T) {
 		t.Fatalf("Expected route NHID %d, got %d", nh.ID, routes[0].NHID)
 	}
 }
+
+func TestRouteNetip(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	link, err := LinkByName("lo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+
+	route := Route{LinkIndex: link.Attrs().Index, Priority: 10}
+	route.SetDstPrefix(netip.MustParsePrefix("192.168.0.0/24"))
+	route.SetSrcAddr(netip.MustParseAddr("127.1.1.1"))
+	if err := RouteAdd(&route); err != nil {
+		t.Fatal(err)
+	}
+
+	routes, err := RouteList(link, FAMILY_V4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 1 {
+		t.Fatal("Route not added properly")
+	}
+	if got := routes[0].DstPrefix(); got != netip.MustParsePrefix("192.168.0.0/24") {
+		t.Fatalf("unexpected dst prefix %s", got)
+	}
+	if got := routes[0].SrcAddr(); got != netip.MustParseAddr("127.1.1.1") {
+		t.Fatalf("unexpected src addr %s", got)
+	}
+
+	var found []RouteNetip
+	err = RouteListNetipIter(FAMILY_V4, unix.RT_TABLE_MAIN, func(r RouteNetip) bool {
+		found = append(found, r)
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected 1 route, got %+v", found)
+	}
+	expected := RouteNetip{
+		Family:    FAMILY_V4,
+		Table:     unix.RT_TABLE_MAIN,
+		Type:      unix.RTN_UNICAST,
+		Protocol:  unix.RTPROT_BOOT,
+		Scope:     SCOPE_UNIVERSE,
+		Dst:       netip.MustParsePrefix("192.168.0.0/24"),
+		Src:       netip.MustParseAddr("127.1.1.1"),
+		LinkIndex: link.Attrs().Index,
+		Priority:  10,
+	}
+	if found[0] != expected {
+		t.Fatalf("expected %+v, got %+v", expected, found[0])
+	}
+}
+
+func TestParseRouteNetipAllocs(t *testing.T) {
+	msg := nl.NewRtMsg()
+	msg.Family = unix.AF_INET6
+	msg.Dst_len = 64
+	b := msg.Serialize()
+	b = append(b, nl.NewRtAttr(unix.RTA_DST, net.ParseIP("2001:db8::").To16()).Serialize()...)
+	b = append(b, nl.NewRtAttr(unix.RTA_GATEWAY, net.ParseIP("fe80::1").To16()).Serialize()...)
+	b = append(b, nl.NewRtAttr(unix.RTA_OIF, nl.Uint32Attr(7)).Serialize()...)
+
+	route, ok := parseRouteNetip(b)
+	if !ok {
+		t.Fatal("route not parsed")
+	}
+	if route.Dst != netip.MustParsePrefix("2001:db8::/64") || route.Gw != netip.MustParseAddr("fe80::1") || route.LinkIndex != 7 {
+		t.Fatalf("unexpected route %+v", route)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		parseRouteNetip(b)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocation, got %v", allocs)
+	}
+}
+
+func TestNetipConversions(t *testing.T) {
+	for _, s := range []string{"10.0.0.1/8", "192.168.1.0/24", "2001:db8::1/64", "::/0"} {
+		ipNet, err := ParseIPNet(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prefix := NetipPrefix(ipNet)
+		if prefix != netip.MustParsePrefix(s) {
+			t.Fatalf("expected %s, got %s", s, prefix)
+		}
+		if back := NewIPNetFromPrefix(prefix); back.String() != ipNet.String() {
+			t.Fatalf("expected %s, got %s", ipNet, back)
+		}
+	}
+	if NetipPrefix(nil).IsValid() || NewIPNetFromPrefix(netip.Prefix{}) != nil {
+		t.Fatal("expected invalid conversions of empty values")
+	}
+	if addr := NetipAddr(net.IPv4(127, 0, 0, 1)); !addr.Is4() {
+		t.Fatalf("expected an unmapped IPv4 address, got %s", addr)
+	}
+}
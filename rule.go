@@ -3,6 +3,7 @@ package netlink
 import (
 	"fmt"
 	"net"
+	"net/netip"
 )
 
 // Rule represents a netlink rule.
@@ -46,6 +47,28 @@ func (r Rule) String() string {
 		r.Priority, from, to, r.Table, r.typeString())
 }
 
+// SrcPrefix returns the source selector of the rule as a netip.Prefix. It is
+// invalid when the rule matches all sources.
+func (r Rule) SrcPrefix() netip.Prefix {
+	return NetipPrefix(r.Src)
+}
+
+// DstPrefix returns the destination selector of the rule as a netip.Prefix.
+// It is invalid when the rule matches all destinations.
+func (r Rule) DstPrefix() netip.Prefix {
+	return NetipPrefix(r.Dst)
+}
+
+// SetSrcPrefix sets the source selector of the rule.
+func (r *Rule) SetSrcPrefix(prefix netip.Prefix) {
+	r.Src = NewIPNetFromPrefix(prefix)
+}
+
+// SetDstPrefix sets the destination selector of the rule.
+func (r *Rule) SetDstPrefix(prefix netip.Prefix) {
+	r.Dst = NewIPNetFromPrefix(prefix)
+}
+
 // NewRule return empty rules.
 func NewRule() *Rule {
 	return &Rule{
@@ -0,0 +1,32 @@
+package netlink
+
+// SubscriptionResync marks the updates of a subscription which delimit a
+// resync, when its options ask for them: the kernel dropped notifications
+// because the receive buffer of the subscription overran, so the existing
+// objects are dumped again.
+type SubscriptionResync uint8
+
+const (
+	// ResyncNone is the mark of the regular updates.
+	ResyncNone SubscriptionResync = iota
+	// ResyncStart marks the update sent once notifications were lost. The
+	// following updates, until the one marked ResyncDone, hold a fresh
+	// snapshot of the existing objects, interleaved with notifications.
+	ResyncStart
+	// ResyncDone marks the update sent once the snapshot is complete. The
+	// objects it did not hold no longer exist.
+	ResyncDone
+)
+
+func (r SubscriptionResync) String() string {
+	switch r {
+	case ResyncNone:
+		return "none"
+	case ResyncStart:
+		return "start"
+	case ResyncDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
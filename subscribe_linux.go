@@ -0,0 +1,220 @@
+package netlink
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// subscribeOptions holds the options shared by the rtnetlink subscriptions.
+type subscribeOptions struct {
+	cberr         func(error)
+	listExisting  bool
+	rcvbuf        int
+	rcvbufForce   bool
+	rcvTimeout    *unix.Timeval
+	resync        bool
+	noENOBUFS     bool
+	listenAllNsid bool
+}
+
+// subscription runs the receive loop shared by the rtnetlink and generic
+// netlink subscriptions. It dumps the existing objects when asked to, and
+// again on receive buffer overruns if resyncs are enabled.
+type subscription struct {
+	// protocol is the netlink protocol of the socket, NETLINK_ROUTE if
+	// unset.
+	protocol int
+	// dumps build the requests dumping the existing objects. They are sent
+	// one after the other, as a socket only runs one dump at a time.
+	dumps []func() *nl.NetlinkRequest
+	// handle sends the update carried by m, received from the network
+	// namespace nsid, down the channel of the subscription.
+	handle func(m syscall.NetlinkMessage, nsid int) error
+	// mark sends an update marked with r down the channel of the
+	// subscription.
+	mark func(r SubscriptionResync)
+	// close closes the channel of the subscription.
+	close func()
+
+	s       *nl.NetlinkSocket
+	opts    subscribeOptions
+	dump    int // index in dumps of the running dump, -1 if none
+	dumpSeq uint32
+	// resyncing is set from the overrun until the end of the dumps.
+	resyncing bool
+	// redump is set if the running dumps must be run again once done.
+	redump bool
+}
+
+// start opens the socket of sub in newNs, joined to groups, and starts
+// receiving from it until done is closed.
+func (sub *subscription) start(newNs, curNs netns.NsHandle, done <-chan struct{}, opts subscribeOptions, groups ...uint32) error {
+	s, err := nl.SubscribeAt(newNs, curNs, sub.protocol)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		if err := s.JoinGroup(g); err != nil {
+			s.Close()
+			return err
+		}
+	}
+	if err := configureSubscription(s, opts); err != nil {
+		s.Close()
+		return err
+	}
+	sub.s = s
+	sub.opts = opts
+	sub.dump = -1
+	if done != nil {
+		go func() {
+			<-done
+			s.Close()
+		}()
+	}
+	if opts.listExisting {
+		if err := sub.sendDump(0); err != nil {
+			s.Close()
+			return err
+		}
+	}
+	go sub.run()
+	return nil
+}
+
+func configureSubscription(s *nl.NetlinkSocket, opts subscribeOptions) error {
+	if opts.rcvTimeout != nil {
+		if err := s.SetReceiveTimeout(opts.rcvTimeout); err != nil {
+			return err
+		}
+	}
+	if opts.rcvbuf != 0 {
+		if err := s.SetReceiveBufferSize(opts.rcvbuf, opts.rcvbufForce); err != nil {
+			return err
+		}
+	}
+	if opts.noENOBUFS {
+		if err := s.SetNoENOBUFS(true); err != nil {
+			return err
+		}
+	}
+	if opts.listenAllNsid {
+		if err := s.SetListenAllNsid(true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sub *subscription) report(err error) {
+	if sub.opts.cberr != nil {
+		sub.opts.cberr(err)
+	}
+}
+
+func (sub *subscription) run() {
+	defer sub.close()
+	for {
+		msgs, from, nsid, err := sub.s.ReceiveNsid()
+		if err != nil {
+			if sub.opts.resync && errors.Is(err, unix.ENOBUFS) {
+				if err := sub.startResync(); err != nil {
+					sub.report(err)
+					return
+				}
+				continue
+			}
+			sub.report(fmt.Errorf("Receive failed: %w", err))
+			return
+		}
+		if from.Pid != nl.PidKernel {
+			sub.report(fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, nl.PidKernel))
+			continue
+		}
+		for _, m := range msgs {
+			if err := sub.receive(m, nsid); err != nil {
+				sub.report(err)
+				return
+			}
+		}
+	}
+}
+
+// receive processes m. It only returns the errors which stop the
+// subscription, and reports the others.
+func (sub *subscription) receive(m syscall.NetlinkMessage, nsid int) error {
+	dumpReply := sub.dump >= 0 && m.Header.Seq == sub.dumpSeq
+	if m.Header.Flags&unix.NLM_F_DUMP_INTR != 0 {
+		sub.report(ErrDumpInterrupted)
+		if dumpReply && sub.resyncing {
+			sub.redump = true
+		}
+	}
+	switch m.Header.Type {
+	case unix.NLMSG_DONE:
+		if dumpReply {
+			return sub.nextDump()
+		}
+		return nil
+	case unix.NLMSG_ERROR:
+		if len(m.Data) < 4 {
+			sub.report(fmt.Errorf("error message too short: %d bytes", len(m.Data)))
+			return nil
+		}
+		if errno := int32(native.Uint32(m.Data[0:4])); errno != 0 {
+			sub.report(fmt.Errorf("error message: %v", syscall.Errno(-errno)))
+			if dumpReply {
+				return sub.nextDump()
+			}
+		}
+		return nil
+	}
+	if err := sub.handle(m, nsid); err != nil {
+		sub.report(err)
+	}
+	return nil
+}
+
+// startResync marks the start of a resync, and dumps the existing objects
+// once the running dump, if any, is done.
+func (sub *subscription) startResync() error {
+	if !sub.resyncing {
+		sub.resyncing = true
+		sub.mark(ResyncStart)
+	}
+	if sub.dump >= 0 {
+		sub.redump = true
+		return nil
+	}
+	return sub.sendDump(0)
+}
+
+func (sub *subscription) nextDump() error {
+	if sub.dump+1 < len(sub.dumps) {
+		return sub.sendDump(sub.dump + 1)
+	}
+	sub.dump = -1
+	if sub.redump {
+		sub.redump = false
+		return sub.sendDump(0)
+	}
+	if sub.resyncing {
+		sub.resyncing = false
+		sub.mark(ResyncDone)
+	}
+	return nil
+}
+
+func (sub *subscription) sendDump(i int) error {
+	req := sub.dumps[i]()
+	if err := sub.s.Send(req); err != nil {
+		return err
+	}
+	sub.dump, sub.dumpSeq = i, req.Seq
+	return nil
+}
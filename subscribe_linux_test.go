@@ -0,0 +1,184 @@
+package netlink
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// overrunRouteSubscription adds routes until the receive buffer of the
+// route subscription, whose updates are not consumed meanwhile, overruns.
+// It returns the destinations of the routes.
+func overrunRouteSubscription(t *testing.T) map[string]bool {
+	link, err := LinkByName("lo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetUp(link); err != nil {
+		t.Fatal(err)
+	}
+	dsts := make(map[string]bool)
+	for i := 0; i < 256; i++ {
+		dst := &net.IPNet{IP: net.IPv4(10, 1, byte(i), 0), Mask: net.CIDRMask(24, 32)}
+		if err := RouteAdd(&Route{LinkIndex: link.Attrs().Index, Dst: dst}); err != nil {
+			t.Fatal(err)
+		}
+		dsts[dst.String()] = true
+	}
+	return dsts
+}
+
+func TestRouteSubscribeResync(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ch := make(chan RouteUpdate)
+	done := make(chan struct{})
+	defer close(done)
+	errs := make(chan error, 16)
+	if err := RouteSubscribeWithOptions(ch, done, RouteSubscribeOptions{
+		ReceiveBufferSize: 1,
+		Resync:            true,
+		ErrorCallback: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	dsts := overrunRouteSubscription(t)
+
+	resyncing := false
+	timeout := time.After(time.Minute)
+	for len(dsts) > 0 || resyncing {
+		select {
+		case update, ok := <-ch:
+			if !ok {
+				t.Fatalf("subscription closed: %v", <-errs)
+			}
+			switch update.Resync {
+			case ResyncStart:
+				if resyncing {
+					t.Fatal("unexpected nested resync start")
+				}
+				resyncing = true
+			case ResyncDone:
+				if !resyncing {
+					t.Fatal("unexpected resync done without start")
+				}
+				resyncing = false
+				if len(dsts) > 0 {
+					t.Fatalf("%d routes missing from the resync", len(dsts))
+				}
+			case ResyncNone:
+				if update.NetnsID != -1 {
+					t.Fatalf("unexpected netns id %d", update.NetnsID)
+				}
+				if resyncing && update.Dst != nil {
+					delete(dsts, update.Dst.String())
+				}
+			}
+		case <-timeout:
+			t.Fatalf("resync not received, %d routes missing", len(dsts))
+		}
+	}
+}
+
+func TestRouteSubscribeOverrun(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ch := make(chan RouteUpdate)
+	done := make(chan struct{})
+	defer close(done)
+	errs := make(chan error, 16)
+	if err := RouteSubscribeWithOptions(ch, done, RouteSubscribeOptions{
+		ReceiveBufferSize: 1,
+		ErrorCallback: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	overrunRouteSubscription(t)
+
+	timeout := time.After(time.Minute)
+	for closed := false; !closed; {
+		select {
+		case update, ok := <-ch:
+			closed = !ok
+			if ok && update.Resync != ResyncNone {
+				t.Fatal("unexpected resync")
+			}
+		case <-timeout:
+			t.Fatal("subscription not closed on overrun")
+		}
+	}
+	if err := <-errs; !errors.Is(err, unix.ENOBUFS) {
+		t.Fatalf("expected %v, got %v", unix.ENOBUFS, err)
+	}
+}
+
+func TestLinkSubscribeListenAllNsid(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ns, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+	peerNs, err := netns.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peerNs.Close()
+	if err := netns.Set(ns); err != nil {
+		t.Fatal(err)
+	}
+	const nsid = 7
+	if err := SetNetNsIdByFd(int(peerNs), nsid); err != nil {
+		t.Fatal(err)
+	}
+	peer, err := NewHandleAt(peerNs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	ch := make(chan LinkUpdate)
+	done := make(chan struct{})
+	defer close(done)
+	if err := LinkSubscribeWithOptions(ch, done, LinkSubscribeOptions{ListenAllNsid: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "foo"}, PeerName: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := peer.LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "baz"}, PeerName: "qux"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{"foo": -1, "baz": nsid}
+	timeout := time.After(time.Minute)
+	for len(expected) > 0 {
+		select {
+		case update := <-ch:
+			name := update.Link.Attrs().Name
+			if id, ok := expected[name]; ok {
+				if update.NetnsID != id {
+					t.Fatalf("expected netns id %d for %s, got %d", id, name, update.NetnsID)
+				}
+				delete(expected, name)
+			}
+		case <-timeout:
+			t.Fatalf("updates not received: %v", expected)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package netlink
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// TeamOption is an option of a team device, or of one of its ports when
+// PortIndex is set.
+type TeamOption struct {
+	Name string
+	// Value is one of uint32, int32, string, []byte or bool, depending on
+	// the option.
+	Value      interface{}
+	PortIndex  int
+	ArrayIndex *uint32
+	Changed    bool // Query only
+	Removed    bool // Query only
+}
+
+func (o TeamOption) String() string {
+	if o.PortIndex != 0 {
+		return fmt.Sprintf("{Name: %s, Value: %v, PortIndex: %d}", o.Name, o.Value, o.PortIndex)
+	}
+	return fmt.Sprintf("{Name: %s, Value: %v}", o.Name, o.Value)
+}
+
+// TeamPort is a port of a team device.
+type TeamPort struct {
+	Index   int
+	Linkup  bool
+	Speed   uint32 // in Mb/s
+	Duplex  uint8
+	Changed bool
+	Removed bool
+}
+
+// TeamUpdate is sent when the options or the ports of a team device change.
+type TeamUpdate struct {
+	TeamIndex int
+	Options   []TeamOption
+	Ports     []TeamPort
+}
+
+// TeamOptionList returns the options of the team device and of its ports.
+// Equivalent to: `teamnl $link options`
+func TeamOptionList(link Link) ([]TeamOption, error) {
+	return pkgHandle.TeamOptionList(link)
+}
+
+// TeamOptionList returns the options of the team device and of its ports.
+// Equivalent to: `teamnl $link options`
+func (h *Handle) TeamOptionList(link Link) ([]TeamOption, error) {
+	req, err := h.newTeamRequest(nl.TEAM_CMD_OPTIONS_GET, link)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	var options []TeamOption
+	for _, m := range msgs {
+		update, err := parseTeamMessage(m)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, update.Options...)
+	}
+	return options, nil
+}
+
+// TeamOptionSet sets options of the team device or of its ports. The type
+// of each option Value must match the type of the option.
+// Equivalent to: `teamnl $link setoption $name $value`
+func TeamOptionSet(link Link, options ...TeamOption) error {
+	return pkgHandle.TeamOptionSet(link, options...)
+}
+
+// TeamOptionSet sets options of the team device or of its ports. The type
+// of each option Value must match the type of the option.
+// Equivalent to: `teamnl $link setoption $name $value`
+func (h *Handle) TeamOptionSet(link Link, options ...TeamOption) error {
+	req, err := h.newTeamRequest(nl.TEAM_CMD_OPTIONS_SET, link)
+	if err != nil {
+		return err
+	}
+	list := nl.NewRtAttr(nl.TEAM_ATTR_LIST_OPTION|unix.NLA_F_NESTED, nil)
+	for _, option := range options {
+		item := list.AddRtAttr(nl.TEAM_ATTR_ITEM_OPTION|unix.NLA_F_NESTED, nil)
+		item.AddRtAttr(nl.TEAM_ATTR_OPTION_NAME, nl.ZeroTerminated(option.Name))
+		switch v := option.Value.(type) {
+		case uint32:
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_TYPE, nl.Uint8Attr(nl.NLA_U32))
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_DATA, nl.Uint32Attr(v))
+		case int32:
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_TYPE, nl.Uint8Attr(nl.NLA_S32))
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_DATA, nl.Uint32Attr(uint32(v)))
+		case string:
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_TYPE, nl.Uint8Attr(nl.NLA_STRING))
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_DATA, nl.ZeroTerminated(v))
+		case []byte:
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_TYPE, nl.Uint8Attr(nl.NLA_BINARY))
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_DATA, v)
+		case bool:
+			// A flag is true when present
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_TYPE, nl.Uint8Attr(nl.NLA_FLAG))
+			if v {
+				item.AddRtAttr(nl.TEAM_ATTR_OPTION_DATA, []byte{})
+			}
+		default:
+			return fmt.Errorf("unsupported value type %T for team option %s", option.Value, option.Name)
+		}
+		if option.PortIndex != 0 {
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_PORT_IFINDEX, nl.Uint32Attr(uint32(option.PortIndex)))
+		}
+		if option.ArrayIndex != nil {
+			item.AddRtAttr(nl.TEAM_ATTR_OPTION_ARRAY_INDEX, nl.Uint32Attr(*option.ArrayIndex))
+		}
+	}
+	req.AddData(list)
+
+	_, err = req.Execute(unix.NETLINK_GENERIC, 0)
+	return err
+}
+
+// TeamSetMode sets the mode of the team device, such as "roundrobin",
+// "activebackup", "loadbalance", "broadcast" or "random". The mode can only
+// be changed while the team has no ports.
+// Equivalent to: `teamnl $link setoption mode $mode`
+func TeamSetMode(link Link, mode string) error {
+	return pkgHandle.TeamSetMode(link, mode)
+}
+
+// TeamSetMode sets the mode of the team device, such as "roundrobin",
+// "activebackup", "loadbalance", "broadcast" or "random". The mode can only
+// be changed while the team has no ports.
+// Equivalent to: `teamnl $link setoption mode $mode`
+func (h *Handle) TeamSetMode(link Link, mode string) error {
+	return h.TeamOptionSet(link, TeamOption{Name: "mode", Value: mode})
+}
+
+// TeamPortList returns the ports of the team device.
+// Equivalent to: `teamnl $link ports`
+func TeamPortList(link Link) ([]TeamPort, error) {
+	return pkgHandle.TeamPortList(link)
+}
+
+// TeamPortList returns the ports of the team device.
+// Equivalent to: `teamnl $link ports`
+func (h *Handle) TeamPortList(link Link) ([]TeamPort, error) {
+	req, err := h.newTeamRequest(nl.TEAM_CMD_PORT_LIST_GET, link)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	var ports []TeamPort
+	for _, m := range msgs {
+		update, err := parseTeamMessage(m)
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, update.Ports...)
+	}
+	return ports, nil
+}
+
+func (h *Handle) newTeamRequest(cmd uint8, link Link) (*nl.NetlinkRequest, error) {
+	f, err := h.GenlFamilyGet(nl.TEAM_GENL_NAME)
+	if err != nil {
+		return nil, err
+	}
+	base := link.Attrs()
+	h.ensureIndex(base)
+
+	msg := &nl.Genlmsg{
+		Command: cmd,
+		Version: nl.TEAM_GENL_VERSION,
+	}
+	req := h.newNetlinkRequest(int(f.ID), unix.NLM_F_ACK)
+	req.AddData(msg)
+	req.AddData(nl.NewRtAttr(nl.TEAM_ATTR_TEAM_IFINDEX, nl.Uint32Attr(uint32(base.Index))))
+	return req, nil
+}
+
+func parseTeamMessage(m []byte) (*TeamUpdate, error) {
+	attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+	if err != nil {
+		return nil, err
+	}
+	update := &TeamUpdate{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.TEAM_ATTR_TEAM_IFINDEX:
+			update.TeamIndex = int(native.Uint32(attr.Value))
+		case nl.TEAM_ATTR_LIST_OPTION:
+			items, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				option, err := parseTeamOption(item.Value)
+				if err != nil {
+					return nil, err
+				}
+				update.Options = append(update.Options, option)
+			}
+		case nl.TEAM_ATTR_LIST_PORT:
+			items, err := nl.ParseRouteAttr(attr.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				port, err := parseTeamPort(item.Value)
+				if err != nil {
+					return nil, err
+				}
+				update.Ports = append(update.Ports, port)
+			}
+		}
+	}
+	return update, nil
+}
+
+func parseTeamOption(b []byte) (TeamOption, error) {
+	var option TeamOption
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return option, err
+	}
+	var optType uint8
+	var data []byte
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.TEAM_ATTR_OPTION_NAME:
+			option.Name = nl.BytesToString(attr.Value)
+		case nl.TEAM_ATTR_OPTION_CHANGED:
+			option.Changed = true
+		case nl.TEAM_ATTR_OPTION_TYPE:
+			optType = attr.Value[0]
+		case nl.TEAM_ATTR_OPTION_DATA:
+			data = attr.Value
+		case nl.TEAM_ATTR_OPTION_REMOVED:
+			option.Removed = true
+		case nl.TEAM_ATTR_OPTION_PORT_IFINDEX:
+			option.PortIndex = int(native.Uint32(attr.Value))
+		case nl.TEAM_ATTR_OPTION_ARRAY_INDEX:
+			index := native.Uint32(attr.Value)
+			option.ArrayIndex = &index
+		}
+	}
+	switch optType {
+	case nl.NLA_U32:
+		if len(data) >= 4 {
+			option.Value = native.Uint32(data)
+		}
+	case nl.NLA_S32:
+		if len(data) >= 4 {
+			option.Value = int32(native.Uint32(data))
+		}
+	case nl.NLA_STRING:
+		option.Value = nl.BytesToString(data)
+	case nl.NLA_BINARY:
+		option.Value = data
+	case nl.NLA_FLAG:
+		option.Value = data != nil
+	}
+	return option, nil
+}
+
+func parseTeamPort(b []byte) (TeamPort, error) {
+	var port TeamPort
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return port, err
+	}
+	for _, attr := range attrs {
+		switch attr.Attr.Type & nl.NLA_TYPE_MASK {
+		case nl.TEAM_ATTR_PORT_IFINDEX:
+			port.Index = int(native.Uint32(attr.Value))
+		case nl.TEAM_ATTR_PORT_CHANGED:
+			port.Changed = true
+		case nl.TEAM_ATTR_PORT_LINKUP:
+			port.Linkup = true
+		case nl.TEAM_ATTR_PORT_SPEED:
+			port.Speed = native.Uint32(attr.Value)
+		case nl.TEAM_ATTR_PORT_DUPLEX:
+			port.Duplex = attr.Value[0]
+		case nl.TEAM_ATTR_PORT_REMOVED:
+			port.Removed = true
+		}
+	}
+	return port, nil
+}
+
+// TeamSubscribe takes a chan down which notifications will be sent
+// when the options or ports of a team device change. Close the 'done'
+// chan to stop subscription.
+func TeamSubscribe(ch chan<- TeamUpdate, done <-chan struct{}) error {
+	return teamSubscribeAt(netns.None(), netns.None(), ch, done, nil)
+}
+
+// TeamSubscribeOptions contains a set of options to use with
+// TeamSubscribeWithOptions.
+type TeamSubscribeOptions struct {
+	Namespace     *netns.NsHandle
+	ErrorCallback func(error)
+}
+
+// TeamSubscribeWithOptions work like TeamSubscribe but enable to
+// provide additional options to modify the behavior. Currently, the
+// namespace can be provided as well as an error callback.
+func TeamSubscribeWithOptions(ch chan<- TeamUpdate, done <-chan struct{}, options TeamSubscribeOptions) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return teamSubscribeAt(*options.Namespace, netns.None(), ch, done, options.ErrorCallback)
+}
+
+// TeamSubscribeContext works like TeamSubscribeWithOptions, but the
+// subscription lasts until ctx is done rather than until a done channel is
+// closed. ch is then closed and options.ErrorCallback is called with
+// ctx.Err().
+func TeamSubscribeContext(ctx context.Context, ch chan<- TeamUpdate, options TeamSubscribeOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return TeamSubscribeWithOptions(ch, ctx.Done(), options)
+}
+
+func teamSubscribeAt(newNs, curNs netns.NsHandle, ch chan<- TeamUpdate, done <-chan struct{}, cberr func(error)) error {
+	return genlSubscribeGroups(newNs, curNs, nl.TEAM_GENL_NAME, []string{nl.TEAM_GENL_CHANGE_EVENT_MC_GRP_NAME}, done, cberr,
+		func(f *GenlFamily, m syscall.NetlinkMessage) error {
+			update, err := parseTeamMessage(m.Data)
+			if err != nil {
+				return fmt.Errorf("could not parse team message: %v", err)
+			}
+			ch <- *update
+			return nil
+		},
+		func() { close(ch) })
+}
@@ -0,0 +1,101 @@
+package netlink
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTeamOptionsAndPorts(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	if err := LinkAdd(&Team{LinkAttrs: LinkAttrs{Name: "team0"}}); err != nil {
+		t.Skipf("team not supported: %v", err)
+	}
+	team, err := LinkByName("team0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := team.(*Team); !ok {
+		t.Fatalf("unexpected link type %T", team)
+	}
+
+	ch := make(chan TeamUpdate, 16)
+	done := make(chan struct{})
+	defer close(done)
+	if err := TeamSubscribe(ch, done); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := TeamSetMode(team, "activebackup"); err != nil {
+		t.Fatal(err)
+	}
+	options, err := TeamOptionList(team)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, o := range options {
+		if o.Name == "mode" && o.PortIndex == 0 {
+			found = true
+			if o.Value != "activebackup" {
+				t.Fatalf("expected mode activebackup, got %v", o.Value)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("mode option not found")
+	}
+
+	timeout := time.After(10 * time.Second)
+	for found = false; !found; {
+		select {
+		case update := <-ch:
+			for _, o := range update.Options {
+				if update.TeamIndex == team.Attrs().Index && o.Name == "mode" && o.Changed {
+					found = true
+				}
+			}
+		case <-timeout:
+			t.Fatal("team option update not received")
+		}
+	}
+
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "v0"}, PeerName: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	port, err := LinkByName("v0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkSetMaster(port, team); err != nil {
+		t.Fatal(err)
+	}
+
+	ports, err := TeamPortList(team)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 1 || ports[0].Index != port.Attrs().Index {
+		t.Fatalf("unexpected team ports %+v", ports)
+	}
+
+	if err := TeamOptionSet(team, TeamOption{Name: "priority", Value: int32(10), PortIndex: port.Attrs().Index}); err != nil {
+		t.Fatal(err)
+	}
+	options, err = TeamOptionList(team)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found = false
+	for _, o := range options {
+		if o.Name == "priority" && o.PortIndex == port.Attrs().Index {
+			found = true
+			if o.Value != int32(10) {
+				t.Fatalf("expected port priority 10, got %v", o.Value)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("port priority option not found")
+	}
+}
@@ -0,0 +1,145 @@
+package netlink
+
+import (
+	"bytes"
+	"flag"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/cpu"
+	"golang.org/x/sys/unix"
+)
+
+var updateReplay bool
+
+func init() {
+	flag.BoolVar(&updateReplay, "update-replay", false, "rewrite "+replayFixture+" from the recording of TestHandleRecordReplay")
+}
+
+// replayFixture holds the recording of exerciseReplay, for the tests
+// running without privileges.
+const replayFixture = "testdata/handle_replay"
+
+// exerciseReplay makes the requests recorded and replayed by the tests.
+func exerciseReplay(h *Handle) (Link, []Route, error) {
+	lo, err := h.LinkByName("lo")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := h.LinkSetUp(lo); err != nil {
+		return nil, nil, err
+	}
+	dst := &net.IPNet{IP: net.IPv4(192, 168, 0, 0), Mask: net.CIDRMask(24, 32)}
+	if err := h.RouteAdd(&Route{LinkIndex: lo.Attrs().Index, Dst: dst}); err != nil {
+		return nil, nil, err
+	}
+	routes, err := h.RouteList(lo, FAMILY_V4)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lo, routes, nil
+}
+
+func TestHandleRecordReplay(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	var buf bytes.Buffer
+	rec := nl.NewRecorder(&buf, nil)
+	h, err := NewHandleWithOptions(HandleOptions{Transport: rec.Transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lo, routes, err := exerciseReplay(h)
+	h.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recording := buf.Bytes()
+	if updateReplay {
+		if err := os.WriteFile(replayFixture, recording, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rep, err := nl.NewReplayer(bytes.NewReader(recording))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err = NewHandleWithOptions(HandleOptions{Transport: rep.Transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	replayedLo, replayedRoutes, err := exerciseReplay(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayedLo.Attrs().Index != lo.Attrs().Index || replayedLo.Attrs().Name != "lo" {
+		t.Fatalf("replayed link %v, expected %v", replayedLo.Attrs(), lo.Attrs())
+	}
+	if len(replayedRoutes) != len(routes) || len(routes) == 0 {
+		t.Fatalf("replayed routes %v, expected %v", replayedRoutes, routes)
+	}
+	for i := range routes {
+		if !replayedRoutes[i].Equal(routes[i]) {
+			t.Fatalf("replayed route %v, expected %v", replayedRoutes[i], routes[i])
+		}
+	}
+	if _, err := h.LinkByName("lo"); err == nil {
+		t.Fatal("expected an error once the recording is exhausted")
+	}
+
+	// Requests must match the recorded ones.
+	rep, err = nl.NewReplayer(bytes.NewReader(recording))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err = NewHandleWithOptions(HandleOptions{Transport: rep.Transport}, unix.NETLINK_ROUTE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	if _, err := h.LinkByName("eth0"); err == nil {
+		t.Fatal("expected a mismatched request to fail")
+	}
+}
+
+func TestHandleReplayFixture(t *testing.T) {
+	if cpu.IsBigEndian {
+		t.Skip("testdata expect little-endian test executor")
+	}
+	f, err := os.Open(replayFixture)
+	if err != nil {
+		t.Fatalf("reading test fixture failed: %v", err)
+	}
+	defer f.Close()
+	rep, err := nl.NewReplayer(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewHandleWithOptions(HandleOptions{Transport: rep.Transport}, unix.NETLINK_ROUTE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	lo, routes, err := exerciseReplay(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lo.Attrs().Index != 1 || lo.Attrs().Name != "lo" || lo.Type() != "device" {
+		t.Fatalf("unexpected replayed link %v", lo.Attrs())
+	}
+	dst := &net.IPNet{IP: net.IPv4(192, 168, 0, 0).To4(), Mask: net.CIDRMask(24, 32)}
+	found := false
+	for _, route := range routes {
+		if route.Dst != nil && route.Dst.String() == dst.String() && route.LinkIndex == lo.Attrs().Index {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("route to %v not replayed: %v", dst, routes)
+	}
+}
@@ -2,7 +2,9 @@ package netlink
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/vishvananda/netlink/nl"
 	"golang.org/x/sys/unix"
 )
 
@@ -81,3 +83,45 @@ type XfrmMark struct {
 func (m *XfrmMark) String() string {
 	return fmt.Sprintf("(0x%x,0x%x)", m.Value, m.Mask)
 }
+
+// XfrmSecCtx represents the security context of the state or policy,
+// as set by the LSM (e.g. SELinux, whose Alg and Doi are 1).
+type XfrmSecCtx struct {
+	Alg uint8
+	Doi uint8
+	Ctx string
+}
+
+func (c *XfrmSecCtx) String() string {
+	return fmt.Sprintf("{Alg: %d, Doi: %d, Ctx: %s}", c.Alg, c.Doi, c.Ctx)
+}
+
+func writeSecCtx(c *XfrmSecCtx) []byte {
+	ctx := &nl.XfrmUserSecCtx{
+		Len:     uint16(nl.SizeofXfrmUserSecCtx + len(c.Ctx)),
+		Exttype: nl.XFRMA_SEC_CTX,
+		CtxAlg:  c.Alg,
+		CtxDoi:  c.Doi,
+		CtxLen:  uint16(len(c.Ctx)),
+	}
+	b := make([]byte, int(ctx.Len))
+	copy(b, ctx.Serialize())
+	copy(b[nl.SizeofXfrmUserSecCtx:], c.Ctx)
+	return b
+}
+
+func parseSecCtx(b []byte) (*XfrmSecCtx, error) {
+	if len(b) < nl.SizeofXfrmUserSecCtx {
+		return nil, fmt.Errorf("security context too short: %d bytes", len(b))
+	}
+	ctx := nl.DeserializeXfrmUserSecCtx(b)
+	if int(ctx.CtxLen) > len(b)-nl.SizeofXfrmUserSecCtx {
+		return nil, fmt.Errorf("security context length %d exceeds the attribute", ctx.CtxLen)
+	}
+	// The LSM may count the terminating null byte in the context length.
+	return &XfrmSecCtx{
+		Alg: ctx.CtxAlg,
+		Doi: ctx.CtxDoi,
+		Ctx: strings.TrimSuffix(string(b[nl.SizeofXfrmUserSecCtx:nl.SizeofXfrmUserSecCtx+int(ctx.CtxLen)]), "\x00"),
+	}, nil
+}
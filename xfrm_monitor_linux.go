@@ -1,7 +1,10 @@
 package netlink
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"syscall"
 
 	"github.com/vishvananda/netlink/nl"
 	"github.com/vishvananda/netns"
@@ -21,27 +24,464 @@ func (ue *XfrmMsgExpire) Type() nl.XfrmMsgType {
 	return nl.XFRM_MSG_EXPIRE
 }
 
-func parseXfrmMsgExpire(b []byte) *XfrmMsgExpire {
+func parseXfrmMsgExpire(b []byte) (*XfrmMsgExpire, error) {
 	var e XfrmMsgExpire
 
 	msg := nl.DeserializeXfrmUserExpire(b)
 	e.XfrmState = xfrmStateFromXfrmUsersaInfo(&msg.XfrmUsersaInfo)
 	e.Hard = msg.Hard == 1
 
-	return &e
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserExpire:])
+	if err != nil {
+		return nil, err
+	}
+	if err := parseXfrmStateAttrs(e.XfrmState, attrs); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+// XfrmMsgNewSA is the notification of the addition of a state.
+type XfrmMsgNewSA struct {
+	XfrmState *XfrmState
+}
+
+func (m *XfrmMsgNewSA) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_NEWSA
+}
+
+// XfrmMsgUpdSA is the notification of the update of a state.
+type XfrmMsgUpdSA struct {
+	XfrmState *XfrmState
+}
+
+func (m *XfrmMsgUpdSA) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_UPDSA
+}
+
+// XfrmMsgDelSA is the notification of the deletion of a state.
+type XfrmMsgDelSA struct {
+	XfrmState *XfrmState
+}
+
+func (m *XfrmMsgDelSA) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_DELSA
+}
+
+// parseXfrmMsgDelSA parses the deletion notification of a state, which
+// carries the xfrm_usersa_info in an XFRMA_SA attribute following the
+// xfrm_usersa_id of the state.
+func parseXfrmMsgDelSA(b []byte) (*XfrmMsgDelSA, error) {
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUsersaId:])
+	if err != nil {
+		return nil, err
+	}
+	var state *XfrmState
+	for _, attr := range attrs {
+		if attr.Attr.Type == nl.XFRMA_SA && len(attr.Value) >= nl.SizeofXfrmUsersaInfo {
+			state = xfrmStateFromXfrmUsersaInfo(nl.DeserializeXfrmUsersaInfo(attr.Value))
+		}
+	}
+	if state == nil {
+		return nil, fmt.Errorf("state missing from the deletion notification")
+	}
+	if err := parseXfrmStateAttrs(state, attrs); err != nil {
+		return nil, err
+	}
+	return &XfrmMsgDelSA{XfrmState: state}, nil
+}
+
+// XfrmMsgFlushSA is the notification of the flush of the states of
+// protocol Proto, 0 meaning any protocol.
+type XfrmMsgFlushSA struct {
+	Proto Proto
+}
+
+func (m *XfrmMsgFlushSA) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_FLUSHSA
+}
+
+// XfrmMsgNewPolicy is the notification of the addition of a policy.
+type XfrmMsgNewPolicy struct {
+	XfrmPolicy *XfrmPolicy
+}
+
+func (m *XfrmMsgNewPolicy) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_NEWPOLICY
+}
+
+// XfrmMsgUpdPolicy is the notification of the update of a policy.
+type XfrmMsgUpdPolicy struct {
+	XfrmPolicy *XfrmPolicy
+}
+
+func (m *XfrmMsgUpdPolicy) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_UPDPOLICY
+}
+
+// XfrmMsgDelPolicy is the notification of the deletion of a policy.
+type XfrmMsgDelPolicy struct {
+	XfrmPolicy *XfrmPolicy
+}
+
+func (m *XfrmMsgDelPolicy) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_DELPOLICY
+}
+
+// parseXfrmMsgDelPolicy parses the deletion notification of a policy,
+// which carries the xfrm_userpolicy_info in an XFRMA_POLICY attribute
+// following the xfrm_userpolicy_id of the policy.
+func parseXfrmMsgDelPolicy(b []byte) (*XfrmMsgDelPolicy, error) {
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserpolicyId:])
+	if err != nil {
+		return nil, err
+	}
+	var policy *XfrmPolicy
+	for _, attr := range attrs {
+		if attr.Attr.Type == nl.XFRMA_POLICY && len(attr.Value) >= nl.SizeofXfrmUserpolicyInfo {
+			policy = xfrmPolicyFromXfrmUserpolicyInfo(nl.DeserializeXfrmUserpolicyInfo(attr.Value), FAMILY_ALL)
+		}
+	}
+	if policy == nil {
+		return nil, fmt.Errorf("policy missing from the deletion notification")
+	}
+	if err := parseXfrmPolicyAttrs(policy, attrs); err != nil {
+		return nil, err
+	}
+	return &XfrmMsgDelPolicy{XfrmPolicy: policy}, nil
+}
+
+// XfrmMsgFlushPolicy is the notification of the flush of the policies of
+// type PolicyType.
+type XfrmMsgFlushPolicy struct {
+	PolicyType PolicyType
+}
+
+func (m *XfrmMsgFlushPolicy) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_FLUSHPOLICY
+}
+
+func parseXfrmMsgFlushPolicy(b []byte) (*XfrmMsgFlushPolicy, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+	var m XfrmMsgFlushPolicy
+	for _, attr := range attrs {
+		if attr.Attr.Type == nl.XFRMA_POLICY_TYPE {
+			if len(attr.Value) < nl.SizeofXfrmUserpolicyType {
+				return nil, fmt.Errorf("policy type attribute too short: %d bytes", len(attr.Value))
+			}
+			m.PolicyType = PolicyType(nl.DeserializeXfrmUserpolicyType(attr.Value[:]).Type)
+		}
+	}
+	return &m, nil
+}
+
+// XfrmMsgPolExpire is the notification of the soft or hard expiration of
+// the lifetime of a policy.
+type XfrmMsgPolExpire struct {
+	XfrmPolicy *XfrmPolicy
+	Hard       bool
+}
+
+func (m *XfrmMsgPolExpire) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_POLEXPIRE
+}
+
+func parseXfrmMsgPolExpire(b []byte) (*XfrmMsgPolExpire, error) {
+	msg := nl.DeserializeXfrmUserPolexpire(b)
+	policy := xfrmPolicyFromXfrmUserpolicyInfo(&msg.Pol, FAMILY_ALL)
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserPolexpire:])
+	if err != nil {
+		return nil, err
+	}
+	if err := parseXfrmPolicyAttrs(policy, attrs); err != nil {
+		return nil, err
+	}
+	return &XfrmMsgPolExpire{XfrmPolicy: policy, Hard: msg.Hard == 1}, nil
+}
+
+// XfrmMsgAcquire is the request of the kernel to the key manager to
+// negotiate the state of protocol Proto between Src and Dst, for the
+// packet matching Selector and the template of XfrmPolicy.
+type XfrmMsgAcquire struct {
+	Dst   net.IP
+	Src   net.IP
+	Proto Proto
+	Spi   int
+	// Selector is the selector of the packet which triggered the acquire.
+	Selector *XfrmPolicy
+	// XfrmPolicy is the policy requiring the state, with its templates.
+	XfrmPolicy *XfrmPolicy
+	// Aalgos, Ealgos and Calgos are the algorithms allowed by the
+	// template, 0 meaning any.
+	Aalgos uint32
+	Ealgos uint32
+	Calgos uint32
+	// Seq identifies the acquire, the negotiated state being expected to
+	// carry it.
+	Seq uint32
 }
 
+func (m *XfrmMsgAcquire) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_ACQUIRE
+}
+
+func parseXfrmMsgAcquire(b []byte) (*XfrmMsgAcquire, error) {
+	msg := nl.DeserializeXfrmUserAcquire(b)
+	policy := xfrmPolicyFromXfrmUserpolicyInfo(&msg.Policy, FAMILY_ALL)
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserAcquire:])
+	if err != nil {
+		return nil, err
+	}
+	if err := parseXfrmPolicyAttrs(policy, attrs); err != nil {
+		return nil, err
+	}
+	return &XfrmMsgAcquire{
+		Dst:        msg.Id.Daddr.ToIP(),
+		Src:        msg.Saddr.ToIP(),
+		Proto:      Proto(msg.Id.Proto),
+		Spi:        int(nl.Swap32(msg.Id.Spi)),
+		Selector:   policyFromSel(&msg.Sel),
+		XfrmPolicy: policy,
+		Aalgos:     algosFromKernel(msg.Aalgos),
+		Ealgos:     algosFromKernel(msg.Ealgos),
+		Calgos:     algosFromKernel(msg.Calgos),
+		Seq:        msg.Seq,
+	}, nil
+}
+
+// XfrmMsgNewAE is the notification of an asynchronous event of a state,
+// carrying its replay state and current lifetime. Only the identity of the
+// state and the fields of these attributes are set in XfrmState.
+type XfrmMsgNewAE struct {
+	XfrmState *XfrmState
+	// Flags are the nl.XFRM_AE_* flags, among which the cause of the event.
+	Flags uint32
+}
+
+func (m *XfrmMsgNewAE) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_NEWAE
+}
+
+func parseXfrmMsgNewAE(b []byte) (*XfrmMsgNewAE, error) {
+	msg := nl.DeserializeXfrmAeventId(b)
+	state := &XfrmState{
+		Dst:   msg.SaId.Daddr.ToIP(),
+		Src:   msg.Saddr.ToIP(),
+		Proto: Proto(msg.SaId.Proto),
+		Spi:   int(nl.Swap32(msg.SaId.Spi)),
+		Reqid: int(msg.Reqid),
+	}
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmAeventId:])
+	if err != nil {
+		return nil, err
+	}
+	if err := parseXfrmStateAttrs(state, attrs); err != nil {
+		return nil, err
+	}
+	return &XfrmMsgNewAE{XfrmState: state, Flags: msg.Flags}, nil
+}
+
+// XfrmMsgReport is the report of an event about the packets of protocol
+// Proto matching Selector, such as a Mobile IPv6 binding error.
+type XfrmMsgReport struct {
+	Proto    Proto
+	Selector *XfrmPolicy
+	Coaddr   net.IP
+}
+
+func (m *XfrmMsgReport) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_REPORT
+}
+
+func parseXfrmMsgReport(b []byte) (*XfrmMsgReport, error) {
+	msg := nl.DeserializeXfrmUserReport(b)
+	attrs, err := nl.ParseRouteAttr(b[nl.SizeofXfrmUserReport:])
+	if err != nil {
+		return nil, err
+	}
+	m := &XfrmMsgReport{
+		Proto:    Proto(msg.Proto),
+		Selector: policyFromSel(&msg.Sel),
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type == nl.XFRMA_COADDR {
+			if len(attr.Value) < nl.SizeofXfrmAddress {
+				return nil, fmt.Errorf("coaddr attribute too short: %d bytes", len(attr.Value))
+			}
+			m.Coaddr = nl.DeserializeXfrmAddress(attr.Value[:]).ToIP()
+		}
+	}
+	return m, nil
+}
+
+// XfrmMsgMapping is the notification of the change of the source address
+// or port of the UDP encapsulated packets received for a state, as when
+// the NAT in front of the peer changes its mapping.
+type XfrmMsgMapping struct {
+	Dst        net.IP
+	Proto      Proto
+	Spi        int
+	Reqid      int
+	OldSrc     net.IP
+	NewSrc     net.IP
+	OldSrcPort int
+	NewSrcPort int
+}
+
+func (m *XfrmMsgMapping) Type() nl.XfrmMsgType {
+	return nl.XFRM_MSG_MAPPING
+}
+
+func parseXfrmMsgMapping(b []byte) *XfrmMsgMapping {
+	msg := nl.DeserializeXfrmUserMapping(b)
+	return &XfrmMsgMapping{
+		Dst:        msg.Id.Daddr.ToIP(),
+		Proto:      Proto(msg.Id.Proto),
+		Spi:        int(nl.Swap32(msg.Id.Spi)),
+		Reqid:      int(msg.Reqid),
+		OldSrc:     msg.OldSaddr.ToIP(),
+		NewSrc:     msg.NewSaddr.ToIP(),
+		OldSrcPort: int(nl.Swap16(msg.OldSport)),
+		NewSrcPort: int(nl.Swap16(msg.NewSport)),
+	}
+}
+
+// xfrmMsgSizes are the minimum sizes of the messages, which start with a
+// fixed size header.
+var xfrmMsgSizes = map[nl.XfrmMsgType]int{
+	nl.XFRM_MSG_NEWSA:     nl.SizeofXfrmUsersaInfo,
+	nl.XFRM_MSG_UPDSA:     nl.SizeofXfrmUsersaInfo,
+	nl.XFRM_MSG_DELSA:     nl.SizeofXfrmUsersaId,
+	nl.XFRM_MSG_EXPIRE:    nl.SizeofXfrmUserExpire,
+	nl.XFRM_MSG_FLUSHSA:   nl.SizeofXfrmUsersaFlush,
+	nl.XFRM_MSG_NEWPOLICY: nl.SizeofXfrmUserpolicyInfo,
+	nl.XFRM_MSG_UPDPOLICY: nl.SizeofXfrmUserpolicyInfo,
+	nl.XFRM_MSG_DELPOLICY: nl.SizeofXfrmUserpolicyId,
+	nl.XFRM_MSG_POLEXPIRE: nl.SizeofXfrmUserPolexpire,
+	nl.XFRM_MSG_ACQUIRE:   nl.SizeofXfrmUserAcquire,
+	nl.XFRM_MSG_NEWAE:     nl.SizeofXfrmAeventId,
+	nl.XFRM_MSG_REPORT:    nl.SizeofXfrmUserReport,
+	nl.XFRM_MSG_MAPPING:   nl.SizeofXfrmUserMapping,
+}
+
+func parseXfrmMsg(m syscall.NetlinkMessage) (XfrmMsg, error) {
+	t := nl.XfrmMsgType(m.Header.Type)
+	if len(m.Data) < xfrmMsgSizes[t] {
+		return nil, fmt.Errorf("xfrm msg type %x too short: %d bytes", t, len(m.Data))
+	}
+	switch t {
+	case nl.XFRM_MSG_NEWSA, nl.XFRM_MSG_UPDSA:
+		state, err := parseXfrmState(m.Data, FAMILY_ALL)
+		if err != nil {
+			return nil, err
+		}
+		if t == nl.XFRM_MSG_UPDSA {
+			return &XfrmMsgUpdSA{XfrmState: state}, nil
+		}
+		return &XfrmMsgNewSA{XfrmState: state}, nil
+	case nl.XFRM_MSG_DELSA:
+		return parseXfrmMsgDelSA(m.Data)
+	case nl.XFRM_MSG_EXPIRE:
+		return parseXfrmMsgExpire(m.Data)
+	case nl.XFRM_MSG_FLUSHSA:
+		return &XfrmMsgFlushSA{Proto: Proto(nl.DeserializeXfrmUsersaFlush(m.Data).Proto)}, nil
+	case nl.XFRM_MSG_NEWPOLICY, nl.XFRM_MSG_UPDPOLICY:
+		policy, err := parseXfrmPolicy(m.Data, FAMILY_ALL)
+		if err != nil {
+			return nil, err
+		}
+		if t == nl.XFRM_MSG_UPDPOLICY {
+			return &XfrmMsgUpdPolicy{XfrmPolicy: policy}, nil
+		}
+		return &XfrmMsgNewPolicy{XfrmPolicy: policy}, nil
+	case nl.XFRM_MSG_DELPOLICY:
+		return parseXfrmMsgDelPolicy(m.Data)
+	case nl.XFRM_MSG_FLUSHPOLICY:
+		return parseXfrmMsgFlushPolicy(m.Data)
+	case nl.XFRM_MSG_POLEXPIRE:
+		return parseXfrmMsgPolExpire(m.Data)
+	case nl.XFRM_MSG_ACQUIRE:
+		return parseXfrmMsgAcquire(m.Data)
+	case nl.XFRM_MSG_NEWAE:
+		return parseXfrmMsgNewAE(m.Data)
+	case nl.XFRM_MSG_REPORT:
+		return parseXfrmMsgReport(m.Data)
+	case nl.XFRM_MSG_MAPPING:
+		return parseXfrmMsgMapping(m.Data), nil
+	}
+	return nil, fmt.Errorf("unsupported msg type: %x", t)
+}
+
+// XfrmMonitor sends the messages of the given types down ch until done is
+// closed, and the errors down errorChan.
 func XfrmMonitor(ch chan<- XfrmMsg, done <-chan struct{}, errorChan chan<- error,
 	types ...nl.XfrmMsgType) error {
+	var cberr func(error)
+	if errorChan != nil {
+		cberr = func(err error) {
+			errorChan <- err
+		}
+	}
+	return xfrmMonitorAt(netns.None(), netns.None(), ch, done, XfrmMonitorOptions{ErrorCallback: cberr}, types...)
+}
+
+// XfrmMonitorOptions contains a set of options to use with
+// XfrmMonitorWithOptions.
+type XfrmMonitorOptions struct {
+	Namespace              *netns.NsHandle
+	ErrorCallback          func(error)
+	ReceiveBufferSize      int
+	ReceiveBufferForceSize bool
+	ReceiveTimeout         *unix.Timeval
+}
+
+// XfrmMonitorWithOptions work like XfrmMonitor but enable to provide
+// additional options to modify the behavior. Currently, the namespace can
+// be provided as well as an error callback.
+func XfrmMonitorWithOptions(ch chan<- XfrmMsg, done <-chan struct{}, options XfrmMonitorOptions,
+	types ...nl.XfrmMsgType) error {
+	if options.Namespace == nil {
+		none := netns.None()
+		options.Namespace = &none
+	}
+	return xfrmMonitorAt(*options.Namespace, netns.None(), ch, done, options, types...)
+}
 
+// XfrmMonitorContext works like XfrmMonitorWithOptions, but the monitor
+// lasts until ctx is done rather than until a done channel is closed. ch is
+// then closed and options.ErrorCallback is called with ctx.Err().
+func XfrmMonitorContext(ctx context.Context, ch chan<- XfrmMsg, options XfrmMonitorOptions,
+	types ...nl.XfrmMsgType) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	options.ErrorCallback = contextErrorCallback(ctx, options.ErrorCallback)
+	return XfrmMonitorWithOptions(ch, ctx.Done(), options, types...)
+}
+
+func xfrmMonitorAt(newNs, curNs netns.NsHandle, ch chan<- XfrmMsg, done <-chan struct{}, options XfrmMonitorOptions,
+	types ...nl.XfrmMsgType) error {
+	cberr := options.ErrorCallback
 	groups, err := xfrmMcastGroups(types)
 	if err != nil {
-		return nil
+		return err
 	}
-	s, err := nl.SubscribeAt(netns.None(), netns.None(), unix.NETLINK_XFRM, groups...)
+	s, err := nl.SubscribeAt(newNs, curNs, unix.NETLINK_XFRM, groups...)
 	if err != nil {
 		return err
 	}
+	if err := configureSubscription(s, subscribeOptions{
+		rcvbuf:      options.ReceiveBufferSize,
+		rcvbufForce: options.ReceiveBufferForceSize,
+		rcvTimeout:  options.ReceiveTimeout,
+	}); err != nil {
+		s.Close()
+		return err
+	}
 
 	if done != nil {
 		go func() {
@@ -51,25 +491,41 @@ func XfrmMonitor(ch chan<- XfrmMsg, done <-chan struct{}, errorChan chan<- error
 
 	}
 
+	// The groups carry several types of messages, only the given ones are
+	// sent down ch.
+	wanted := make(map[nl.XfrmMsgType]bool, len(types))
+	for _, t := range types {
+		wanted[t] = true
+	}
+
 	go func() {
 		defer close(ch)
 		for {
 			msgs, from, err := s.Receive()
 			if err != nil {
-				errorChan <- err
+				if cberr != nil {
+					cberr(err)
+				}
 				return
 			}
 			if from.Pid != nl.PidKernel {
-				errorChan <- fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, nl.PidKernel)
-				return
+				if cberr != nil {
+					cberr(fmt.Errorf("Wrong sender portid %d, expected %d", from.Pid, nl.PidKernel))
+				}
+				continue
 			}
 			for _, m := range msgs {
-				switch m.Header.Type {
-				case nl.XFRM_MSG_EXPIRE:
-					ch <- parseXfrmMsgExpire(m.Data)
-				default:
-					errorChan <- fmt.Errorf("unsupported msg type: %x", m.Header.Type)
+				if !wanted[nl.XfrmMsgType(m.Header.Type)] {
+					continue
+				}
+				msg, err := parseXfrmMsg(m)
+				if err != nil {
+					if cberr != nil {
+						cberr(err)
+					}
+					continue
 				}
+				ch <- msg
 			}
 		}
 	}()
@@ -88,8 +544,20 @@ func xfrmMcastGroups(types []nl.XfrmMsgType) ([]uint, error) {
 		var group uint
 
 		switch t {
-		case nl.XFRM_MSG_EXPIRE:
+		case nl.XFRM_MSG_EXPIRE, nl.XFRM_MSG_POLEXPIRE:
 			group = nl.XFRMNLGRP_EXPIRE
+		case nl.XFRM_MSG_NEWSA, nl.XFRM_MSG_UPDSA, nl.XFRM_MSG_DELSA, nl.XFRM_MSG_FLUSHSA:
+			group = nl.XFRMNLGRP_SA
+		case nl.XFRM_MSG_NEWPOLICY, nl.XFRM_MSG_UPDPOLICY, nl.XFRM_MSG_DELPOLICY, nl.XFRM_MSG_FLUSHPOLICY:
+			group = nl.XFRMNLGRP_POLICY
+		case nl.XFRM_MSG_ACQUIRE:
+			group = nl.XFRMNLGRP_ACQUIRE
+		case nl.XFRM_MSG_NEWAE:
+			group = nl.XFRMNLGRP_AEVENTS
+		case nl.XFRM_MSG_REPORT:
+			group = nl.XFRMNLGRP_REPORT
+		case nl.XFRM_MSG_MAPPING:
+			group = nl.XFRMNLGRP_MAPPING
 		default:
 			return nil, fmt.Errorf("unsupported group: %x", t)
 		}
@@ -1,10 +1,16 @@
 package netlink
 
 import (
+	"context"
+	"errors"
+	"net"
 	"os"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
 )
 
 func TestXfrmMonitorExpire(t *testing.T) {
@@ -50,3 +56,280 @@ func TestXfrmMonitorExpire(t *testing.T) {
 		t.Fatal("Missing expire msg: hard found:", hardFound, "soft found:", softFound)
 	}
 }
+
+func receiveXfrmMsg(t *testing.T, ch <-chan XfrmMsg) XfrmMsg {
+	t.Helper()
+	select {
+	case msg, ok := <-ch:
+		if !ok {
+			t.Fatal("xfrm monitor closed")
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("xfrm msg not received")
+	}
+	return nil
+}
+
+func TestXfrmMonitorPolicy(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ns, err := netns.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ns.Close()
+
+	ch := make(chan XfrmMsg, 16)
+	done := make(chan struct{})
+	defer close(done)
+	errs := make(chan error, 16)
+	if err := XfrmMonitorWithOptions(ch, done, XfrmMonitorOptions{
+		Namespace: &ns,
+		ErrorCallback: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	}, nl.XFRM_MSG_NEWPOLICY, nl.XFRM_MSG_UPDPOLICY, nl.XFRM_MSG_DELPOLICY, nl.XFRM_MSG_FLUSHPOLICY); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := getPolicy()
+	if err := XfrmPolicyAdd(policy); err != nil {
+		t.Fatal(err)
+	}
+	newMsg, ok := receiveXfrmMsg(t, ch).(*XfrmMsgNewPolicy)
+	if !ok {
+		t.Fatal("expected a policy addition")
+	}
+	if !comparePolicies(policy, newMsg.XfrmPolicy) {
+		t.Fatalf("unexpected policy added: %v, expected %v", newMsg.XfrmPolicy, policy)
+	}
+
+	policy.Priority = 20
+	if err := XfrmPolicyUpdate(policy); err != nil {
+		t.Fatal(err)
+	}
+	updMsg, ok := receiveXfrmMsg(t, ch).(*XfrmMsgUpdPolicy)
+	if !ok {
+		t.Fatal("expected a policy update")
+	}
+	if updMsg.XfrmPolicy.Priority != policy.Priority {
+		t.Fatalf("unexpected policy priority updated: %d, expected %d", updMsg.XfrmPolicy.Priority, policy.Priority)
+	}
+
+	if err := XfrmPolicyDel(policy); err != nil {
+		t.Fatal(err)
+	}
+	delMsg, ok := receiveXfrmMsg(t, ch).(*XfrmMsgDelPolicy)
+	if !ok {
+		t.Fatal("expected a policy deletion")
+	}
+	if !comparePolicies(policy, delMsg.XfrmPolicy) {
+		t.Fatalf("unexpected policy deleted: %v, expected %v", delMsg.XfrmPolicy, policy)
+	}
+
+	// The flush of an empty database is not notified.
+	if err := XfrmPolicyAdd(policy); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := receiveXfrmMsg(t, ch).(*XfrmMsgNewPolicy); !ok {
+		t.Fatal("expected a policy addition")
+	}
+	if err := XfrmPolicyFlush(); err != nil {
+		t.Fatal(err)
+	}
+	flushMsg, ok := receiveXfrmMsg(t, ch).(*XfrmMsgFlushPolicy)
+	if !ok {
+		t.Fatal("expected a policy flush")
+	}
+	if flushMsg.PolicyType != XFRM_POLICY_TYPE_MAIN {
+		t.Fatalf("unexpected policy type flushed: %v", flushMsg.PolicyType)
+	}
+
+	select {
+	case err := <-errs:
+		t.Fatal(err)
+	default:
+	}
+}
+
+func TestXfrmMonitorAcquire(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	// No policy applies to the packets routed through the loopback.
+	if err := LinkAdd(&Veth{LinkAttrs: LinkAttrs{Name: "foo"}, PeerName: "bar"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"foo", "bar"} {
+		link, err := LinkByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := LinkSetUp(link); err != nil {
+			t.Fatal(err)
+		}
+	}
+	foo, err := LinkByName("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := ParseAddr("10.0.0.1/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AddrAdd(foo, addr); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan XfrmMsg, 16)
+	done := make(chan struct{})
+	defer close(done)
+	if err := XfrmMonitor(ch, done, nil, nl.XFRM_MSG_ACQUIRE); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := getPolicy()
+	policy.Src, _ = ParseIPNet("10.0.0.1/32")
+	policy.Dst, _ = ParseIPNet("10.0.0.2/32")
+	policy.Mark = nil
+	policy.Tmpls[0].Src = net.ParseIP("10.0.0.1")
+	policy.Tmpls[0].Dst = net.ParseIP("10.0.0.2")
+	policy.Tmpls[0].Spi = 0
+	if err := XfrmPolicyAdd(policy); err != nil {
+		t.Fatal(err)
+	}
+
+	// The packet matching the policy, for which no state exists, triggers
+	// the acquire.
+	conn, err := net.DialUDP("udp", &net.UDPAddr{IP: policy.Src.IP, Port: policy.SrcPort},
+		&net.UDPAddr{IP: policy.Dst.IP, Port: policy.DstPort})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("acquire"))
+
+	msg, ok := receiveXfrmMsg(t, ch).(*XfrmMsgAcquire)
+	if !ok {
+		t.Fatal("expected an acquire")
+	}
+	tmpl := policy.Tmpls[0]
+	if !msg.Dst.Equal(tmpl.Dst) || !msg.Src.Equal(tmpl.Src) || msg.Proto != tmpl.Proto {
+		t.Fatalf("unexpected acquire of %v from %v to %v", msg.Proto, msg.Src, msg.Dst)
+	}
+	if !msg.Selector.Dst.IP.Equal(policy.Dst.IP) || msg.Selector.DstPort != policy.DstPort ||
+		msg.Selector.Proto != policy.Proto {
+		t.Fatalf("unexpected acquire selector %v", msg.Selector)
+	}
+	if msg.XfrmPolicy.Dir != policy.Dir || !compareTemplates(policy.Tmpls, msg.XfrmPolicy.Tmpls) {
+		t.Fatalf("unexpected acquire policy: %v, expected %v", msg.XfrmPolicy, policy)
+	}
+	if msg.Seq == 0 {
+		t.Fatal("expected an acquire sequence number")
+	}
+}
+
+func TestParseXfrmMsgMapping(t *testing.T) {
+	mapping := nl.XfrmUserMapping{
+		Reqid:    7,
+		OldSport: nl.Swap16(4500),
+		NewSport: nl.Swap16(4501),
+	}
+	mapping.Id.Daddr.FromIP(net.ParseIP("192.0.2.1"))
+	mapping.Id.Spi = nl.Swap32(0x1234)
+	mapping.Id.Proto = uint8(XFRM_PROTO_ESP)
+	mapping.OldSaddr.FromIP(net.ParseIP("198.51.100.1"))
+	mapping.NewSaddr.FromIP(net.ParseIP("198.51.100.2"))
+	m := syscall.NetlinkMessage{
+		Header: syscall.NlMsghdr{Type: nl.XFRM_MSG_MAPPING},
+		Data:   mapping.Serialize(),
+	}
+
+	msg, err := parseXfrmMsg(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &XfrmMsgMapping{
+		Dst:        net.ParseIP("192.0.2.1"),
+		Proto:      XFRM_PROTO_ESP,
+		Spi:        0x1234,
+		Reqid:      7,
+		OldSrc:     net.ParseIP("198.51.100.1"),
+		NewSrc:     net.ParseIP("198.51.100.2"),
+		OldSrcPort: 4500,
+		NewSrcPort: 4501,
+	}
+	got, ok := msg.(*XfrmMsgMapping)
+	if !ok || !got.Dst.Equal(expected.Dst) || got.Proto != expected.Proto || got.Spi != expected.Spi ||
+		got.Reqid != expected.Reqid || !got.OldSrc.Equal(expected.OldSrc) || !got.NewSrc.Equal(expected.NewSrc) ||
+		got.OldSrcPort != expected.OldSrcPort || got.NewSrcPort != expected.NewSrcPort {
+		t.Fatalf("unexpected mapping %+v, expected %+v", msg, expected)
+	}
+
+	m.Data = m.Data[:nl.SizeofXfrmUserMapping-1]
+	if _, err := parseXfrmMsg(m); err == nil {
+		t.Fatal("expected an error parsing a truncated mapping")
+	}
+}
+
+func TestParseXfrmMsgTruncatedAttrs(t *testing.T) {
+	report := make([]byte, nl.SizeofXfrmUserReport)
+	for _, m := range []syscall.NetlinkMessage{
+		{
+			Header: syscall.NlMsghdr{Type: nl.XFRM_MSG_FLUSHPOLICY},
+			Data:   nl.NewRtAttr(nl.XFRMA_POLICY_TYPE, []byte{0}).Serialize(),
+		},
+		{
+			Header: syscall.NlMsghdr{Type: nl.XFRM_MSG_REPORT},
+			Data:   append(report, nl.NewRtAttr(nl.XFRMA_COADDR, []byte{192, 0, 2, 1}).Serialize()...),
+		},
+	} {
+		if _, err := parseXfrmMsg(m); err == nil {
+			t.Fatalf("expected an error parsing xfrm msg type %x with a truncated attribute", m.Header.Type)
+		}
+	}
+}
+
+func TestXfrmMonitorContext(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan XfrmMsg, 16)
+	errs := make(chan error, 16)
+	if err := XfrmMonitorContext(ctx, ch, XfrmMonitorOptions{
+		ReceiveBufferSize: 1 << 16,
+		ErrorCallback: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	}, nl.XFRM_MSG_NEWPOLICY); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := getPolicy()
+	if err := XfrmPolicyAdd(policy); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := receiveXfrmMsg(t, ch).(*XfrmMsgNewPolicy); !ok {
+		t.Fatal("expected a policy addition")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatal("unexpected xfrm msg")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("xfrm monitor not closed")
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
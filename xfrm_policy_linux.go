@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"syscall"
 
 	"github.com/vishvananda/netlink/nl"
 	"golang.org/x/sys/unix"
@@ -58,6 +59,52 @@ func (a PolicyAction) String() string {
 	}
 }
 
+// PolicyType is an enum representing an ipsec policy type. The sub
+// policies are looked up before the main ones.
+type PolicyType uint8
+
+const (
+	XFRM_POLICY_TYPE_MAIN PolicyType = nl.XFRM_POLICY_TYPE_MAIN
+	XFRM_POLICY_TYPE_SUB  PolicyType = nl.XFRM_POLICY_TYPE_SUB
+)
+
+func (t PolicyType) String() string {
+	switch t {
+	case XFRM_POLICY_TYPE_MAIN:
+		return "main"
+	case XFRM_POLICY_TYPE_SUB:
+		return "sub"
+	default:
+		return fmt.Sprintf("type %d", t)
+	}
+}
+
+// Share is an enum representing the sharing of the states of an ipsec
+// policy or template.
+type Share uint8
+
+const (
+	XFRM_SHARE_ANY     Share = nl.XFRM_SHARE_ANY
+	XFRM_SHARE_SESSION Share = nl.XFRM_SHARE_SESSION
+	XFRM_SHARE_USER    Share = nl.XFRM_SHARE_USER
+	XFRM_SHARE_UNIQUE  Share = nl.XFRM_SHARE_UNIQUE
+)
+
+func (s Share) String() string {
+	switch s {
+	case XFRM_SHARE_ANY:
+		return "any"
+	case XFRM_SHARE_SESSION:
+		return "session"
+	case XFRM_SHARE_USER:
+		return "user"
+	case XFRM_SHARE_UNIQUE:
+		return "unique"
+	default:
+		return fmt.Sprintf("share %d", s)
+	}
+}
+
 // XfrmPolicyTmpl encapsulates a rule for the base addresses of an ipsec
 // policy. These rules are matched with XfrmState to determine encryption
 // and authentication algorithms.
@@ -69,11 +116,28 @@ type XfrmPolicyTmpl struct {
 	Spi      int
 	Reqid    int
 	Optional int
+	Share    Share
+	// Aalgos, Ealgos and Calgos are the masks of the authentication,
+	// encryption and compression algorithms allowed, by SADB id. Zero
+	// allows any of them.
+	Aalgos uint32
+	Ealgos uint32
+	Calgos uint32
 }
 
 func (t XfrmPolicyTmpl) String() string {
-	return fmt.Sprintf("{Dst: %v, Src: %v, Proto: %s, Mode: %s, Spi: 0x%x, Reqid: 0x%x}",
-		t.Dst, t.Src, t.Proto, t.Mode, t.Spi, t.Reqid)
+	return fmt.Sprintf("{Dst: %v, Src: %v, Proto: %s, Mode: %s, Spi: 0x%x, Reqid: 0x%x, Optional: %d, Share: %s, Aalgos: 0x%x, Ealgos: 0x%x, Calgos: 0x%x}",
+		t.Dst, t.Src, t.Proto, t.Mode, t.Spi, t.Reqid, t.Optional, t.Share, t.Aalgos, t.Ealgos, t.Calgos)
+}
+
+// XfrmPolicyStats represents the current number of bytes/packets
+// processed by this Policy, and the Policy's installation and last use
+// time.
+type XfrmPolicyStats struct {
+	Bytes   uint64
+	Packets uint64
+	AddTime uint64
+	UseTime uint64
 }
 
 // XfrmPolicy represents an ipsec policy. It represents the overlay network
@@ -93,11 +157,44 @@ type XfrmPolicy struct {
 	Ifid     int
 	Mark     *XfrmMark
 	Tmpls    []XfrmPolicyTmpl
+	Type     PolicyType
+	// Share is reported as XFRM_SHARE_ANY by the kernel, which does not
+	// keep it.
+	Share Share
+	// LocalOK allows the sockets to override the policy, and ICMP
+	// extends the selector to the ICMP errors about the matching packets.
+	LocalOK    bool
+	ICMP       bool
+	Limits     XfrmStateLimits
+	Statistics XfrmPolicyStats
+	SecCtx     *XfrmSecCtx
 }
 
 func (p XfrmPolicy) String() string {
-	return fmt.Sprintf("{Dst: %v, Src: %v, Proto: %s, DstPort: %d, SrcPort: %d, Dir: %s, Priority: %d, Index: %d, Action: %s, Ifindex: %d, Ifid: %d, Mark: %s, Tmpls: %s}",
-		p.Dst, p.Src, p.Proto, p.DstPort, p.SrcPort, p.Dir, p.Priority, p.Index, p.Action, p.Ifindex, p.Ifid, p.Mark, p.Tmpls)
+	return fmt.Sprintf("{Dst: %v, Src: %v, Proto: %s, DstPort: %d, SrcPort: %d, Dir: %s, Priority: %d, Index: %d, Action: %s, Ifindex: %d, Ifid: %d, Mark: %s, Tmpls: %s, "+
+		"Type: %s, Share: %s, LocalOK: %t, ICMP: %t, SecCtx: %v}",
+		p.Dst, p.Src, p.Proto, p.DstPort, p.SrcPort, p.Dir, p.Priority, p.Index, p.Action, p.Ifindex, p.Ifid, p.Mark, p.Tmpls,
+		p.Type, p.Share, p.LocalOK, p.ICMP, p.SecCtx)
+}
+
+func writePolicyType(t PolicyType) []byte {
+	return (&nl.XfrmUserpolicyType{Type: uint8(t)}).Serialize()
+}
+
+// The templates allow any algorithm with a zero mask, which the kernel
+// represents with all the bits set.
+func algosToKernel(algos uint32) uint32 {
+	if algos == 0 {
+		return ^uint32(0)
+	}
+	return algos
+}
+
+func algosFromKernel(algos uint32) uint32 {
+	if algos == ^uint32(0) {
+		return 0
+	}
+	return algos
 }
 
 func selFromPolicy(sel *nl.XfrmSelector, policy *XfrmPolicy) {
@@ -125,6 +222,17 @@ func selFromPolicy(sel *nl.XfrmSelector, policy *XfrmPolicy) {
 	sel.Ifindex = int32(policy.Ifindex)
 }
 
+func policyFromSel(sel *nl.XfrmSelector) *XfrmPolicy {
+	return &XfrmPolicy{
+		Dst:     sel.Daddr.ToIPNet(sel.PrefixlenD, sel.Family),
+		Src:     sel.Saddr.ToIPNet(sel.PrefixlenS, sel.Family),
+		Proto:   Proto(sel.Proto),
+		DstPort: int(nl.Swap16(sel.Dport)),
+		SrcPort: int(nl.Swap16(sel.Sport)),
+		Ifindex: int(sel.Ifindex),
+	}
+}
+
 // XfrmPolicyAdd will add an xfrm policy to the system.
 // Equivalent to: `ip xfrm policy add $policy`
 func XfrmPolicyAdd(policy *XfrmPolicy) error {
@@ -158,10 +266,14 @@ func (h *Handle) xfrmPolicyAddOrUpdate(policy *XfrmPolicy, nlProto int) error {
 	msg.Index = uint32(policy.Index)
 	msg.Dir = uint8(policy.Dir)
 	msg.Action = uint8(policy.Action)
-	msg.Lft.SoftByteLimit = nl.XFRM_INF
-	msg.Lft.HardByteLimit = nl.XFRM_INF
-	msg.Lft.SoftPacketLimit = nl.XFRM_INF
-	msg.Lft.HardPacketLimit = nl.XFRM_INF
+	msg.Share = uint8(policy.Share)
+	if policy.LocalOK {
+		msg.Flags |= nl.XFRM_POLICY_LOCALOK
+	}
+	if policy.ICMP {
+		msg.Flags |= nl.XFRM_POLICY_ICMP
+	}
+	limitsToLft(policy.Limits, &msg.Lft)
 	req.AddData(msg)
 
 	tmplData := make([]byte, nl.SizeofXfrmUserTmpl*len(policy.Tmpls))
@@ -176,9 +288,10 @@ func (h *Handle) xfrmPolicyAddOrUpdate(policy *XfrmPolicy, nlProto int) error {
 		userTmpl.Mode = uint8(tmpl.Mode)
 		userTmpl.Reqid = uint32(tmpl.Reqid)
 		userTmpl.Optional = uint8(tmpl.Optional)
-		userTmpl.Aalgos = ^uint32(0)
-		userTmpl.Ealgos = ^uint32(0)
-		userTmpl.Calgos = ^uint32(0)
+		userTmpl.Share = uint8(tmpl.Share)
+		userTmpl.Aalgos = algosToKernel(tmpl.Aalgos)
+		userTmpl.Ealgos = algosToKernel(tmpl.Ealgos)
+		userTmpl.Calgos = algosToKernel(tmpl.Calgos)
 	}
 	if len(tmplData) > 0 {
 		tmpls := nl.NewRtAttr(nl.XFRMA_TMPL, tmplData)
@@ -194,6 +307,15 @@ func (h *Handle) xfrmPolicyAddOrUpdate(policy *XfrmPolicy, nlProto int) error {
 		req.AddData(ifId)
 	}
 
+	if policy.Type != XFRM_POLICY_TYPE_MAIN {
+		out := nl.NewRtAttr(nl.XFRMA_POLICY_TYPE, writePolicyType(policy.Type))
+		req.AddData(out)
+	}
+	if policy.SecCtx != nil {
+		out := nl.NewRtAttr(nl.XFRMA_SEC_CTX, writeSecCtx(policy.SecCtx))
+		req.AddData(out)
+	}
+
 	_, err := req.Execute(unix.NETLINK_XFRM, 0)
 	return err
 }
@@ -298,6 +420,15 @@ func (h *Handle) xfrmPolicyGetOrDelete(policy *XfrmPolicy, nlProto int) (*XfrmPo
 		req.AddData(ifId)
 	}
 
+	if policy.Type != XFRM_POLICY_TYPE_MAIN {
+		out := nl.NewRtAttr(nl.XFRMA_POLICY_TYPE, writePolicyType(policy.Type))
+		req.AddData(out)
+	}
+	if policy.SecCtx != nil {
+		out := nl.NewRtAttr(nl.XFRMA_SEC_CTX, writeSecCtx(policy.SecCtx))
+		req.AddData(out)
+	}
+
 	resType := nl.XFRM_MSG_NEWPOLICY
 	if nlProto == nl.XFRM_MSG_DELPOLICY {
 		resType = 0
@@ -323,6 +454,19 @@ func parseXfrmPolicy(m []byte, family int) (*XfrmPolicy, error) {
 		return nil, familyError
 	}
 
+	policy := xfrmPolicyFromXfrmUserpolicyInfo(msg, family)
+	attrs, err := nl.ParseRouteAttr(m[msg.Len():])
+	if err != nil {
+		return nil, err
+	}
+	if err := parseXfrmPolicyAttrs(policy, attrs); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func xfrmPolicyFromXfrmUserpolicyInfo(msg *nl.XfrmUserpolicyInfo, family int) *XfrmPolicy {
 	var policy XfrmPolicy
 
 	policy.Dst = msg.Sel.Daddr.ToIPNet(msg.Sel.PrefixlenD, uint16(family))
@@ -335,12 +479,23 @@ func parseXfrmPolicy(m []byte, family int) (*XfrmPolicy, error) {
 	policy.Index = int(msg.Index)
 	policy.Dir = Dir(msg.Dir)
 	policy.Action = PolicyAction(msg.Action)
-
-	attrs, err := nl.ParseRouteAttr(m[msg.Len():])
-	if err != nil {
-		return nil, err
+	policy.Share = Share(msg.Share)
+	policy.LocalOK = msg.Flags&nl.XFRM_POLICY_LOCALOK != 0
+	policy.ICMP = msg.Flags&nl.XFRM_POLICY_ICMP != 0
+	lftToLimits(&msg.Lft, &policy.Limits)
+	policy.Statistics = XfrmPolicyStats{
+		Bytes:   msg.Curlft.Bytes,
+		Packets: msg.Curlft.Packets,
+		AddTime: msg.Curlft.AddTime,
+		UseTime: msg.Curlft.UseTime,
 	}
 
+	return &policy
+}
+
+// parseXfrmPolicyAttrs fills policy from the attributes following the
+// xfrm_userpolicy_info, or the header of the notification carrying it.
+func parseXfrmPolicyAttrs(policy *XfrmPolicy, attrs []syscall.NetlinkRouteAttr) error {
 	for _, attr := range attrs {
 		switch attr.Attr.Type {
 		case nl.XFRMA_TMPL:
@@ -355,6 +510,10 @@ func parseXfrmPolicy(m []byte, family int) (*XfrmPolicy, error) {
 				resTmpl.Spi = int(nl.Swap32(tmpl.XfrmId.Spi))
 				resTmpl.Reqid = int(tmpl.Reqid)
 				resTmpl.Optional = int(tmpl.Optional)
+				resTmpl.Share = Share(tmpl.Share)
+				resTmpl.Aalgos = algosFromKernel(tmpl.Aalgos)
+				resTmpl.Ealgos = algosFromKernel(tmpl.Ealgos)
+				resTmpl.Calgos = algosFromKernel(tmpl.Calgos)
 				policy.Tmpls = append(policy.Tmpls, resTmpl)
 			}
 		case nl.XFRMA_MARK:
@@ -364,8 +523,119 @@ func parseXfrmPolicy(m []byte, family int) (*XfrmPolicy, error) {
 			policy.Mark.Mask = mark.Mask
 		case nl.XFRMA_IF_ID:
 			policy.Ifid = int(native.Uint32(attr.Value))
+		case nl.XFRMA_POLICY_TYPE:
+			policy.Type = PolicyType(nl.DeserializeXfrmUserpolicyType(attr.Value[:]).Type)
+		case nl.XFRMA_SEC_CTX:
+			secCtx, err := parseSecCtx(attr.Value)
+			if err != nil {
+				return err
+			}
+			policy.SecCtx = secCtx
 		}
 	}
 
-	return &policy, nil
+	return nil
+}
+
+// XfrmSPDHashThresh represents the prefix lengths of the local and remote
+// addresses of the selectors from which the policies are hashed, the
+// others being looked up in a list.
+type XfrmSPDHashThresh struct {
+	LBits uint8
+	RBits uint8
+}
+
+// XfrmSPDInfo represents the number of policies of the security policy
+// database, per direction and for the sockets, and its hash table.
+type XfrmSPDInfo struct {
+	InCount      uint32
+	OutCount     uint32
+	FwdCount     uint32
+	InSockCount  uint32
+	OutSockCount uint32
+	FwdSockCount uint32
+	HashCount    uint32
+	HashMax      uint32
+	IPv4Thresh   XfrmSPDHashThresh
+	IPv6Thresh   XfrmSPDHashThresh
+}
+
+// XfrmSPDInfoGet gets the counters and the hash thresholds of the
+// security policy database.
+// Equivalent to: `ip xfrm policy count`
+func XfrmSPDInfoGet() (*XfrmSPDInfo, error) {
+	return pkgHandle.XfrmSPDInfoGet()
+}
+
+// XfrmSPDInfoGet gets the counters and the hash thresholds of the
+// security policy database.
+// Equivalent to: `ip xfrm policy count`
+func (h *Handle) XfrmSPDInfoGet() (*XfrmSPDInfo, error) {
+	req := h.newNetlinkRequest(nl.XFRM_MSG_GETSPDINFO, 0)
+	flags := nl.XfrmInfoFlags(^uint32(0))
+	req.AddData(&flags)
+
+	msgs, err := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWSPDINFO)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := nl.ParseRouteAttr(msgs[0][nl.SizeofXfrmInfoFlags:])
+	if err != nil {
+		return nil, err
+	}
+
+	var info XfrmSPDInfo
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.XFRMA_SPD_INFO:
+			spdInfo := nl.DeserializeXfrmuSpdInfo(attr.Value[:])
+			info.InCount = spdInfo.Incnt
+			info.OutCount = spdInfo.Outcnt
+			info.FwdCount = spdInfo.Fwdcnt
+			info.InSockCount = spdInfo.Inscnt
+			info.OutSockCount = spdInfo.Outscnt
+			info.FwdSockCount = spdInfo.Fwdscnt
+		case nl.XFRMA_SPD_HINFO:
+			hinfo := nl.DeserializeXfrmuSpdHinfo(attr.Value[:])
+			info.HashCount = hinfo.Spdhcnt
+			info.HashMax = hinfo.Spdhmcnt
+		case nl.XFRMA_SPD_IPV4_HTHRESH:
+			thresh := nl.DeserializeXfrmuSpdHthresh(attr.Value[:])
+			info.IPv4Thresh = XfrmSPDHashThresh{LBits: thresh.Lbits, RBits: thresh.Rbits}
+		case nl.XFRMA_SPD_IPV6_HTHRESH:
+			thresh := nl.DeserializeXfrmuSpdHthresh(attr.Value[:])
+			info.IPv6Thresh = XfrmSPDHashThresh{LBits: thresh.Lbits, RBits: thresh.Rbits}
+		}
+	}
+	return &info, nil
+}
+
+// XfrmSPDHashThreshSet sets the hash thresholds of the security policy
+// database for IPv4 and IPv6, and rebuilds its hash table. A nil threshold
+// is left unchanged.
+// Equivalent to: `ip xfrm policy set hthresh4 LBITS RBITS hthresh6 LBITS RBITS`
+func XfrmSPDHashThreshSet(ipv4, ipv6 *XfrmSPDHashThresh) error {
+	return pkgHandle.XfrmSPDHashThreshSet(ipv4, ipv6)
+}
+
+// XfrmSPDHashThreshSet sets the hash thresholds of the security policy
+// database for IPv4 and IPv6, and rebuilds its hash table. A nil threshold
+// is left unchanged.
+// Equivalent to: `ip xfrm policy set hthresh4 LBITS RBITS hthresh6 LBITS RBITS`
+func (h *Handle) XfrmSPDHashThreshSet(ipv4, ipv6 *XfrmSPDHashThresh) error {
+	req := h.newNetlinkRequest(nl.XFRM_MSG_NEWSPDINFO, unix.NLM_F_ACK)
+	flags := nl.XfrmInfoFlags(^uint32(0))
+	req.AddData(&flags)
+
+	if ipv4 != nil {
+		thresh := &nl.XfrmuSpdHthresh{Lbits: ipv4.LBits, Rbits: ipv4.RBits}
+		req.AddData(nl.NewRtAttr(nl.XFRMA_SPD_IPV4_HTHRESH, thresh.Serialize()))
+	}
+	if ipv6 != nil {
+		thresh := &nl.XfrmuSpdHthresh{Lbits: ipv6.LBits, Rbits: ipv6.RBits}
+		req.AddData(nl.NewRtAttr(nl.XFRMA_SPD_IPV6_HTHRESH, thresh.Serialize()))
+	}
+
+	_, err := req.Execute(unix.NETLINK_XFRM, 0)
+	return err
 }
@@ -213,6 +213,130 @@ func TestXfrmPolicyWithOptional(t *testing.T) {
 	}
 }
 
+func TestXfrmPolicyWithFlagsAndLimits(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	pol := getPolicy()
+	pol.LocalOK = true
+	pol.ICMP = true
+	pol.Limits = XfrmStateLimits{
+		ByteSoft:   1 << 20,
+		PacketHard: 1000,
+		TimeSoft:   60,
+		TimeHard:   120,
+	}
+	pol.Tmpls[0].Share = XFRM_SHARE_USER
+	pol.Tmpls[0].Ealgos = 1 << 12
+	if err := XfrmPolicyAdd(pol); err != nil {
+		t.Fatal(err)
+	}
+	sp, err := XfrmPolicyGet(pol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !comparePolicies(pol, sp) {
+		t.Fatalf("unexpected policy returned.\nExpected: %v.\nGot %v", pol, sp)
+	}
+	if sp.Limits.ByteSoft != pol.Limits.ByteSoft || sp.Limits.ByteHard != ^uint64(0) ||
+		sp.Limits.PacketHard != pol.Limits.PacketHard || sp.Limits.TimeSoft != pol.Limits.TimeSoft ||
+		sp.Limits.TimeHard != pol.Limits.TimeHard {
+		t.Fatalf("unexpected limits returned: %+v", sp.Limits)
+	}
+	if sp.Statistics.AddTime == 0 || sp.Statistics.Packets != 0 {
+		t.Fatalf("unexpected statistics returned: %+v", sp.Statistics)
+	}
+	if err = XfrmPolicyDel(sp); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXfrmPolicySubType(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	pol := getPolicy()
+	pol.Type = XFRM_POLICY_TYPE_SUB
+	if err := XfrmPolicyAdd(pol); err != nil {
+		t.Skipf("sub policies not supported: %v", err)
+	}
+	// The main policy of the same selector is distinct from the sub one.
+	main := getPolicy()
+	main.Priority = 20
+	if err := XfrmPolicyAdd(main); err != nil {
+		t.Fatal(err)
+	}
+	sp, err := XfrmPolicyGet(pol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !comparePolicies(pol, sp) {
+		t.Fatalf("unexpected policy returned.\nExpected: %v.\nGot %v", pol, sp)
+	}
+	if err = XfrmPolicyDel(pol); err != nil {
+		t.Fatal(err)
+	}
+	policies, err := XfrmPolicyList(FAMILY_ALL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != 1 || !comparePolicies(main, &policies[0]) {
+		t.Fatalf("unexpected policies left: %v", policies)
+	}
+}
+
+func TestXfrmPolicyWithSecCtx(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	pol := getPolicy()
+	pol.SecCtx = &XfrmSecCtx{Alg: 1, Doi: 1, Ctx: "system_u:object_r:ipsec_spd_t:s0"}
+	if err := XfrmPolicyAdd(pol); err != nil {
+		t.Skipf("security context rejected by the LSM: %v", err)
+	}
+	sp, err := XfrmPolicyGet(pol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sp.SecCtx == nil {
+		t.Skip("security context ignored without LSM")
+	}
+	if *sp.SecCtx != *pol.SecCtx {
+		t.Fatalf("unexpected security context returned: %v", sp.SecCtx)
+	}
+	if err = XfrmPolicyDel(pol); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXfrmSPDInfo(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	if err := XfrmPolicyAdd(getPolicy()); err != nil {
+		t.Fatal(err)
+	}
+	info, err := XfrmSPDInfoGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.OutCount != 1 || info.InCount != 0 || info.FwdCount != 0 || info.HashMax == 0 {
+		t.Fatalf("unexpected SPD info: %+v", info)
+	}
+
+	ipv4 := XfrmSPDHashThresh{LBits: 24, RBits: 16}
+	ipv6 := XfrmSPDHashThresh{LBits: 64, RBits: 48}
+	if err := XfrmSPDHashThreshSet(&ipv4, &ipv6); err != nil {
+		t.Fatal(err)
+	}
+	if err := XfrmSPDHashThreshSet(&XfrmSPDHashThresh{LBits: 33}, nil); err == nil {
+		t.Fatal("expected an error for an IPv4 threshold above 32")
+	}
+	info, err = XfrmSPDInfoGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.IPv4Thresh != ipv4 || info.IPv6Thresh != ipv6 {
+		t.Fatalf("unexpected hash thresholds: %+v %+v", info.IPv4Thresh, info.IPv6Thresh)
+	}
+}
+
 func comparePolicies(a, b *XfrmPolicy) bool {
 	if a == b {
 		return true
@@ -225,7 +349,9 @@ func comparePolicies(a, b *XfrmPolicy) bool {
 		compareIPNet(a.Src, b.Src) && compareIPNet(a.Dst, b.Dst) &&
 		a.Action == b.Action && a.Ifindex == b.Ifindex &&
 		a.Mark.Value == b.Mark.Value && a.Mark.Mask == b.Mark.Mask &&
-		a.Ifid == b.Ifid && compareTemplates(a.Tmpls, b.Tmpls)
+		a.Ifid == b.Ifid && compareTemplates(a.Tmpls, b.Tmpls) &&
+		a.Type == b.Type && a.Share == b.Share &&
+		a.LocalOK == b.LocalOK && a.ICMP == b.ICMP
 }
 
 func compareTemplates(a, b []XfrmPolicyTmpl) bool {
@@ -236,7 +362,8 @@ func compareTemplates(a, b []XfrmPolicyTmpl) bool {
 		tb := b[i]
 		if !ta.Dst.Equal(tb.Dst) || !ta.Src.Equal(tb.Src) || ta.Spi != tb.Spi ||
 			ta.Mode != tb.Mode || ta.Reqid != tb.Reqid || ta.Proto != tb.Proto ||
-			ta.Optional != tb.Optional {
+			ta.Optional != tb.Optional || ta.Share != tb.Share ||
+			ta.Aalgos != tb.Aalgos || ta.Ealgos != tb.Ealgos || ta.Calgos != tb.Calgos {
 			return false
 		}
 	}
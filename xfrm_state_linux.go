@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"syscall"
 	"time"
 	"unsafe"
 
@@ -99,6 +100,35 @@ func (r XfrmReplayState) String() string {
 		r.OSeq, r.Seq, r.BitMap)
 }
 
+// XfrmReplayStateEsn represents the sequence number states for the
+// extended sequence numbers anti-replay mode. BitMap holds the window of
+// the already received sequence numbers, in blocks of 32.
+type XfrmReplayStateEsn struct {
+	OSeq         uint32
+	Seq          uint32
+	OSeqHi       uint32
+	SeqHi        uint32
+	ReplayWindow uint32
+	BitMap       []uint32
+}
+
+func (r XfrmReplayStateEsn) String() string {
+	return fmt.Sprintf("{OSeq: 0x%x, Seq: 0x%x, OSeqHi: 0x%x, SeqHi: 0x%x, ReplayWindow: %d, BitMap: %x}",
+		r.OSeq, r.Seq, r.OSeqHi, r.SeqHi, r.ReplayWindow, r.BitMap)
+}
+
+// XfrmStateOffload represents the offload of the state to a device.
+// Without Packet, only the crypto operations are offloaded.
+type XfrmStateOffload struct {
+	Ifindex int
+	Inbound bool
+	Packet  bool
+}
+
+func (o XfrmStateOffload) String() string {
+	return fmt.Sprintf("{Ifindex: %d, Inbound: %t, Packet: %t}", o.Ifindex, o.Inbound, o.Packet)
+}
+
 // XfrmState represents the state of an ipsec policy. It optionally
 // contains an XfrmStateAlgo for encryption and one for authentication.
 type XfrmState struct {
@@ -124,7 +154,29 @@ type XfrmState struct {
 	DontEncapDSCP bool
 	OSeqMayWrap   bool
 	Replay        *XfrmReplayState
-	Selector      *XfrmPolicy
+	// ReplayEsn holds the sequence number states with ESN. When adding a
+	// state, it is only used with ESN, whose window is ReplayWindow.
+	ReplayEsn *XfrmReplayStateEsn
+	Selector  *XfrmPolicy
+	Offload   *XfrmStateOffload
+	SecCtx    *XfrmSecCtx
+	Coaddr    net.IP
+	// Tfcpad is the length the ESP payloads are padded to for traffic
+	// flow confidentiality.
+	Tfcpad int
+	// NatKeepaliveInterval is the interval, in seconds, of the NAT
+	// keepalive packets sent on an outbound state with encapsulation.
+	NatKeepaliveInterval int
+	// MtimerThresh is the age, in seconds, from which a change of the
+	// encapsulation source of an inbound state is notified again.
+	MtimerThresh int
+	// ReplayThresh and EtimerThresh are the sequence number difference
+	// and the time, in units of 100ms, triggering async events.
+	ReplayThresh int
+	EtimerThresh int
+	// LastUsed is the time, in seconds since the epoch, the state was
+	// last used.
+	LastUsed uint64
 }
 
 func (sa XfrmState) String() string {
@@ -132,8 +184,10 @@ func (sa XfrmState) String() string {
 	if sa.Pcpunum != nil {
 		pcpu = fmt.Sprintf("%d", *sa.Pcpunum)
 	}
-	return fmt.Sprintf("Dst: %v, Src: %v, Proto: %s, Mode: %s, SPI: 0x%x, ReqID: 0x%x, ReplayWindow: %d, Mark: %v, OutputMark: %v, SADir: %d, Ifid: %d, Pcpunum: %s, Auth: %v, Crypt: %v, Aead: %v, Encap: %v, ESN: %t, DontEncapDSCP: %t, OSeqMayWrap: %t, Replay: %v",
-		sa.Dst, sa.Src, sa.Proto, sa.Mode, sa.Spi, sa.Reqid, sa.ReplayWindow, sa.Mark, sa.OutputMark, sa.SADir, sa.Ifid, pcpu, sa.Auth, sa.Crypt, sa.Aead, sa.Encap, sa.ESN, sa.DontEncapDSCP, sa.OSeqMayWrap, sa.Replay)
+	return fmt.Sprintf("Dst: %v, Src: %v, Proto: %s, Mode: %s, SPI: 0x%x, ReqID: 0x%x, ReplayWindow: %d, Mark: %v, OutputMark: %v, SADir: %d, Ifid: %d, Pcpunum: %s, Auth: %v, Crypt: %v, Aead: %v, Encap: %v, ESN: %t, DontEncapDSCP: %t, OSeqMayWrap: %t, Replay: %v, ReplayEsn: %v, "+
+		"Offload: %v, SecCtx: %v, Coaddr: %v, Tfcpad: %d, NatKeepaliveInterval: %d, MtimerThresh: %d, ReplayThresh: %d, EtimerThresh: %d",
+		sa.Dst, sa.Src, sa.Proto, sa.Mode, sa.Spi, sa.Reqid, sa.ReplayWindow, sa.Mark, sa.OutputMark, sa.SADir, sa.Ifid, pcpu, sa.Auth, sa.Crypt, sa.Aead, sa.Encap, sa.ESN, sa.DontEncapDSCP, sa.OSeqMayWrap, sa.Replay, sa.ReplayEsn,
+		sa.Offload, sa.SecCtx, sa.Coaddr, sa.Tfcpad, sa.NatKeepaliveInterval, sa.MtimerThresh, sa.ReplayThresh, sa.EtimerThresh)
 }
 func (sa XfrmState) Print(stats bool) string {
 	if !stats {
@@ -144,10 +198,14 @@ func (sa XfrmState) Print(stats bool) string {
 	if sa.Statistics.UseTime > 0 {
 		ut = time.Unix(int64(sa.Statistics.UseTime), 0).Format(time.UnixDate)
 	}
+	lu := "-"
+	if sa.LastUsed > 0 {
+		lu = time.Unix(int64(sa.LastUsed), 0).Format(time.UnixDate)
+	}
 	return fmt.Sprintf("%s, ByteSoft: %s, ByteHard: %s, PacketSoft: %s, PacketHard: %s, TimeSoft: %d, TimeHard: %d, TimeUseSoft: %d, TimeUseHard: %d, Bytes: %d, Packets: %d, "+
-		"AddTime: %s, UseTime: %s, ReplayWindow: %d, Replay: %d, Failed: %d",
+		"AddTime: %s, UseTime: %s, LastUsed: %s, ReplayWindow: %d, Replay: %d, Failed: %d",
 		sa.String(), printLimit(sa.Limits.ByteSoft), printLimit(sa.Limits.ByteHard), printLimit(sa.Limits.PacketSoft), printLimit(sa.Limits.PacketHard),
-		sa.Limits.TimeSoft, sa.Limits.TimeHard, sa.Limits.TimeUseSoft, sa.Limits.TimeUseHard, sa.Statistics.Bytes, sa.Statistics.Packets, at, ut,
+		sa.Limits.TimeSoft, sa.Limits.TimeHard, sa.Limits.TimeUseSoft, sa.Limits.TimeUseHard, sa.Statistics.Bytes, sa.Statistics.Packets, at, ut, lu,
 		sa.Statistics.ReplayWindow, sa.Statistics.Replay, sa.Statistics.Failed)
 }
 
@@ -209,7 +267,7 @@ func writeMark(m *XfrmMark) []byte {
 	return mark.Serialize()
 }
 
-func writeReplayEsn(replayWindow int) []byte {
+func writeReplayEsn(replayWindow int, r *XfrmReplayStateEsn) []byte {
 	replayEsn := &nl.XfrmReplayStateEsn{
 		OSeq:         0,
 		Seq:          0,
@@ -223,9 +281,34 @@ func writeReplayEsn(replayWindow int) []byte {
 	bytesPerElem := int(unsafe.Sizeof(replayEsn.BmpLen)) // Any uint32 variable is good for this
 	replayEsn.BmpLen = uint32((replayWindow + (bytesPerElem * 8) - 1) / (bytesPerElem * 8))
 
+	if r != nil {
+		replayEsn.OSeq = r.OSeq
+		replayEsn.Seq = r.Seq
+		replayEsn.OSeqHi = r.OSeqHi
+		replayEsn.SeqHi = r.SeqHi
+		// The kernel only takes a bitmap of the full length.
+		if len(r.BitMap) > 0 {
+			replayEsn.Bmp = make([]uint32, replayEsn.BmpLen)
+			copy(replayEsn.Bmp, r.BitMap)
+		}
+	}
+
 	return replayEsn.Serialize()
 }
 
+func writeOffload(o *XfrmStateOffload) []byte {
+	offload := &nl.XfrmUserOffload{
+		Ifindex: int32(o.Ifindex),
+	}
+	if o.Inbound {
+		offload.Flags |= nl.XFRM_OFFLOAD_INBOUND
+	}
+	if o.Packet {
+		offload.Flags |= nl.XFRM_OFFLOAD_PACKET
+	}
+	return offload.Serialize()
+}
+
 func writeReplay(r *XfrmReplayState) []byte {
 	return (&nl.XfrmReplayState{
 		OSeq:   r.OSeq,
@@ -312,7 +395,7 @@ func (h *Handle) xfrmStateAddOrUpdate(state *XfrmState, nlProto int) error {
 		req.AddData(out)
 	}
 	if state.ESN {
-		out := nl.NewRtAttr(nl.XFRMA_REPLAY_ESN_VAL, writeReplayEsn(state.ReplayWindow))
+		out := nl.NewRtAttr(nl.XFRMA_REPLAY_ESN_VAL, writeReplayEsn(state.ReplayWindow, state.ReplayEsn))
 		req.AddData(out)
 	}
 	if state.OutputMark != nil {
@@ -354,6 +437,45 @@ func (h *Handle) xfrmStateAddOrUpdate(state *XfrmState, nlProto int) error {
 		req.AddData(pcpuNum)
 	}
 
+	if state.Offload != nil {
+		out := nl.NewRtAttr(nl.XFRMA_OFFLOAD_DEV, writeOffload(state.Offload))
+		req.AddData(out)
+	}
+	if state.SecCtx != nil {
+		out := nl.NewRtAttr(nl.XFRMA_SEC_CTX, writeSecCtx(state.SecCtx))
+		req.AddData(out)
+	}
+	if state.Coaddr != nil {
+		var coaddr nl.XfrmAddress
+		coaddr.FromIP(state.Coaddr)
+		out := nl.NewRtAttr(nl.XFRMA_COADDR, coaddr.Serialize())
+		req.AddData(out)
+	}
+	if state.Tfcpad != 0 {
+		out := nl.NewRtAttr(nl.XFRMA_TFCPAD, nl.Uint32Attr(uint32(state.Tfcpad)))
+		req.AddData(out)
+	}
+	if state.NatKeepaliveInterval != 0 {
+		out := nl.NewRtAttr(nl.XFRMA_NAT_KEEPALIVE_INTERVAL, nl.Uint32Attr(uint32(state.NatKeepaliveInterval)))
+		req.AddData(out)
+	}
+	if state.MtimerThresh != 0 {
+		out := nl.NewRtAttr(nl.XFRMA_MTIMER_THRESH, nl.Uint32Attr(uint32(state.MtimerThresh)))
+		req.AddData(out)
+	}
+	if state.ReplayThresh != 0 {
+		out := nl.NewRtAttr(nl.XFRMA_REPLAY_THRESH, nl.Uint32Attr(uint32(state.ReplayThresh)))
+		req.AddData(out)
+	}
+	if state.EtimerThresh != 0 {
+		out := nl.NewRtAttr(nl.XFRMA_ETIMER_THRESH, nl.Uint32Attr(uint32(state.EtimerThresh)))
+		req.AddData(out)
+	}
+	if state.LastUsed != 0 {
+		out := nl.NewRtAttr(nl.XFRMA_LASTUSED, nl.Uint64Attr(state.LastUsed))
+		req.AddData(out)
+	}
+
 	_, err := req.Execute(unix.NETLINK_XFRM, 0)
 	return err
 }
@@ -508,16 +630,10 @@ func xfrmStateFromXfrmUsersaInfo(msg *nl.XfrmUsersaInfo) *XfrmState {
 	state.Spi = int(nl.Swap32(msg.Id.Spi))
 	state.Reqid = int(msg.Reqid)
 	state.ReplayWindow = int(msg.ReplayWindow)
+	state.ESN = msg.Flags&nl.XFRM_STATE_ESN != 0
 	lftToLimits(&msg.Lft, &state.Limits)
 	curToStats(&msg.Curlft, &msg.Stats, &state.Statistics)
-	state.Selector = &XfrmPolicy{
-		Dst:     msg.Sel.Daddr.ToIPNet(msg.Sel.PrefixlenD, msg.Sel.Family),
-		Src:     msg.Sel.Saddr.ToIPNet(msg.Sel.PrefixlenS, msg.Sel.Family),
-		Proto:   Proto(msg.Sel.Proto),
-		DstPort: int(nl.Swap16(msg.Sel.Dport)),
-		SrcPort: int(nl.Swap16(msg.Sel.Sport)),
-		Ifindex: int(msg.Sel.Ifindex),
-	}
+	state.Selector = policyFromSel(&msg.Sel)
 
 	return &state
 }
@@ -533,7 +649,16 @@ func parseXfrmState(m []byte, family int) (*XfrmState, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := parseXfrmStateAttrs(state, attrs); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
 
+// parseXfrmStateAttrs fills state from the attributes following the
+// xfrm_usersa_info, or the header of the notification carrying it.
+func parseXfrmStateAttrs(state *XfrmState, attrs []syscall.NetlinkRouteAttr) error {
 	for _, attr := range attrs {
 		switch attr.Attr.Type {
 		case nl.XFRMA_ALG_AUTH, nl.XFRMA_ALG_CRYPT:
@@ -612,10 +737,59 @@ func parseXfrmState(m []byte, family int) (*XfrmState, error) {
 			state.Replay.OSeq = replay.OSeq
 			state.Replay.Seq = replay.Seq
 			state.Replay.BitMap = replay.BitMap
+		case nl.XFRMA_REPLAY_ESN_VAL:
+			if len(attr.Value) < nl.SizeofXfrmReplayStateEsn {
+				return fmt.Errorf("replay esn attribute too short: %d bytes", len(attr.Value))
+			}
+			replayEsn := nl.DeserializeXfrmReplayStateEsn(attr.Value[:])
+			state.ReplayEsn = &XfrmReplayStateEsn{
+				OSeq:         replayEsn.OSeq,
+				Seq:          replayEsn.Seq,
+				OSeqHi:       replayEsn.OSeqHi,
+				SeqHi:        replayEsn.SeqHi,
+				ReplayWindow: replayEsn.ReplayWindow,
+				BitMap:       replayEsn.Bmp,
+			}
+			if state.ReplayWindow == 0 {
+				state.ReplayWindow = int(replayEsn.ReplayWindow)
+			}
+		case nl.XFRMA_OFFLOAD_DEV:
+			offload := nl.DeserializeXfrmUserOffload(attr.Value[:])
+			state.Offload = &XfrmStateOffload{
+				Ifindex: int(offload.Ifindex),
+				Inbound: offload.Flags&nl.XFRM_OFFLOAD_INBOUND != 0,
+				Packet:  offload.Flags&nl.XFRM_OFFLOAD_PACKET != 0,
+			}
+		case nl.XFRMA_SEC_CTX:
+			secCtx, err := parseSecCtx(attr.Value)
+			if err != nil {
+				return err
+			}
+			state.SecCtx = secCtx
+		case nl.XFRMA_COADDR:
+			state.Coaddr = nl.DeserializeXfrmAddress(attr.Value[:]).ToIP()
+		case nl.XFRMA_TFCPAD:
+			state.Tfcpad = int(native.Uint32(attr.Value))
+		case nl.XFRMA_NAT_KEEPALIVE_INTERVAL:
+			state.NatKeepaliveInterval = int(native.Uint32(attr.Value))
+		case nl.XFRMA_MTIMER_THRESH:
+			state.MtimerThresh = int(native.Uint32(attr.Value))
+		case nl.XFRMA_REPLAY_THRESH:
+			state.ReplayThresh = int(native.Uint32(attr.Value))
+		case nl.XFRMA_ETIMER_THRESH:
+			state.EtimerThresh = int(native.Uint32(attr.Value))
+		case nl.XFRMA_LASTUSED:
+			state.LastUsed = native.Uint64(attr.Value)
+		case nl.XFRMA_LTIME_VAL:
+			cur := nl.DeserializeXfrmLifetimeCur(attr.Value[:])
+			state.Statistics.Bytes = cur.Bytes
+			state.Statistics.Packets = cur.Packets
+			state.Statistics.AddTime = cur.AddTime
+			state.Statistics.UseTime = cur.UseTime
 		}
 	}
 
-	return state, nil
+	return nil
 }
 
 // XfrmStateFlush will flush the xfrm state on the system.
@@ -694,3 +868,47 @@ func xfrmUsersaInfoFromXfrmState(state *XfrmState) *nl.XfrmUsersaInfo {
 	}
 	return msg
 }
+
+// XfrmSADInfo represents the number of states of the security
+// association database and its hash table.
+type XfrmSADInfo struct {
+	Count     uint32
+	HashCount uint32
+	HashMax   uint32
+}
+
+// XfrmSADInfoGet gets the counters of the security association database.
+// Equivalent to: `ip xfrm state count`
+func XfrmSADInfoGet() (*XfrmSADInfo, error) {
+	return pkgHandle.XfrmSADInfoGet()
+}
+
+// XfrmSADInfoGet gets the counters of the security association database.
+// Equivalent to: `ip xfrm state count`
+func (h *Handle) XfrmSADInfoGet() (*XfrmSADInfo, error) {
+	req := h.newNetlinkRequest(nl.XFRM_MSG_GETSADINFO, 0)
+	flags := nl.XfrmInfoFlags(^uint32(0))
+	req.AddData(&flags)
+
+	msgs, err := req.Execute(unix.NETLINK_XFRM, nl.XFRM_MSG_NEWSADINFO)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := nl.ParseRouteAttr(msgs[0][nl.SizeofXfrmInfoFlags:])
+	if err != nil {
+		return nil, err
+	}
+
+	var info XfrmSADInfo
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case nl.XFRMA_SAD_CNT:
+			info.Count = native.Uint32(attr.Value)
+		case nl.XFRMA_SAD_HINFO:
+			hinfo := nl.DeserializeXfrmuSadHinfo(attr.Value[:])
+			info.HashCount = hinfo.Sadhcnt
+			info.HashMax = hinfo.Sadhmcnt
+		}
+	}
+	return &info, nil
+}
@@ -3,7 +3,10 @@ package netlink
 import (
 	"bytes"
 	"encoding/hex"
+	"math/rand"
 	"net"
+	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -358,6 +361,183 @@ func TestXfrmStateWithOutputMarkAndMask(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestXfrmStateWithTfcpadAndLastUsed(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	state := getBaseState()
+	state.Tfcpad = 1000
+	state.LastUsed = uint64(time.Now().Add(-time.Minute).Unix())
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+	s, err := XfrmStateGet(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareStates(state, s) || s.Tfcpad != state.Tfcpad || s.LastUsed != state.LastUsed {
+		t.Fatalf("unexpected state returned.\nExpected: %v.\nGot %v", state.Print(true), s.Print(true))
+	}
+	if err = XfrmStateDel(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXfrmStateWithReplayEsn(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	state := getBaseState()
+	state.ESN = true
+	state.ReplayWindow = 128
+	state.ReplayEsn = &XfrmReplayStateEsn{
+		OSeq:   0x10,
+		Seq:    0x20,
+		SeqHi:  0x1,
+		BitMap: []uint32{0x1f},
+	}
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+	s, err := XfrmStateGet(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareStates(state, s) || !s.ESN || s.ReplayWindow != state.ReplayWindow || s.ReplayEsn == nil {
+		t.Fatalf("unexpected state returned.\nExpected: %v.\nGot %v", state, s)
+	}
+	r := s.ReplayEsn
+	if r.OSeq != 0x10 || r.Seq != 0x20 || r.SeqHi != 0x1 || r.ReplayWindow != 128 ||
+		len(r.BitMap) != 4 || r.BitMap[0] != 0x1f {
+		t.Fatalf("unexpected replay state returned: %v", r)
+	}
+	if err = XfrmStateDel(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXfrmStateWithMtimerThresh(t *testing.T) {
+	minKernelRequired(t, 5, 18)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	state := getBaseState()
+	state.Encap = &XfrmStateEncap{
+		Type:            XFRM_ENCAP_ESPINUDP,
+		SrcPort:         4500,
+		DstPort:         4500,
+		OriginalAddress: net.IPv4zero,
+	}
+	state.MtimerThresh = 30
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+	s, err := XfrmStateGet(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareStates(state, s) || s.MtimerThresh != state.MtimerThresh {
+		t.Fatalf("unexpected state returned.\nExpected: %v.\nGot %v", state, s)
+	}
+	if err = XfrmStateDel(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXfrmStateWithNatKeepaliveInterval(t *testing.T) {
+	minKernelRequired(t, 6, 10)
+	t.Cleanup(setUpNetlinkTest(t))
+
+	state := getBaseState()
+	state.SADir = XFRM_SA_DIR_OUT
+	state.Encap = &XfrmStateEncap{
+		Type:            XFRM_ENCAP_ESPINUDP,
+		SrcPort:         4500,
+		DstPort:         4500,
+		OriginalAddress: net.IPv4zero,
+	}
+	state.NatKeepaliveInterval = 20
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+	s, err := XfrmStateGet(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareStates(state, s) || s.NatKeepaliveInterval != state.NatKeepaliveInterval {
+		t.Fatalf("unexpected state returned.\nExpected: %v.\nGot %v", state, s)
+	}
+	if err = XfrmStateDel(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// The crypto offload is exercised with a netdevsim port, which only
+// offloads rfc4106(gcm(aes)) states with a 128 bits ICV.
+func TestXfrmStateWithOffload(t *testing.T) {
+	skipUnlessRoot(t)
+	if _, err := os.Stat("/sys/bus/netdevsim"); err != nil {
+		t.Skip("netdevsim is not available")
+	}
+	t.Cleanup(setUpNetlinkTest(t))
+
+	id := strconv.Itoa(1000 + rand.Intn(1000))
+	if err := os.WriteFile("/sys/bus/netdevsim/new_device", []byte(id+" 1"), 0200); err != nil {
+		t.Fatal(err)
+	}
+	defer os.WriteFile("/sys/bus/netdevsim/del_device", []byte(id), 0200)
+	links, err := LinkList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var link Link
+	for _, l := range links {
+		if l.Attrs().Flags&net.FlagLoopback == 0 {
+			link = l
+		}
+	}
+	if link == nil {
+		t.Fatal("netdevsim port not found")
+	}
+
+	state := getAeadState()
+	state.Aead.ICVLen = 128
+	state.Offload = &XfrmStateOffload{Ifindex: link.Attrs().Index, Inbound: true}
+	if err := XfrmStateAdd(state); err != nil {
+		t.Fatal(err)
+	}
+	s, err := XfrmStateGet(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !compareStates(state, s) || s.Offload == nil || *s.Offload != *state.Offload {
+		t.Fatalf("unexpected state returned.\nExpected: %v.\nGot %v", state, s)
+	}
+	if err = XfrmStateDel(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXfrmSADInfo(t *testing.T) {
+	t.Cleanup(setUpNetlinkTest(t))
+
+	info, err := XfrmSADInfoGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Count != 0 || info.HashMax == 0 {
+		t.Fatalf("unexpected SAD info: %+v", info)
+	}
+	if _, err := XfrmStateAllocSpi(getBaseState()); err != nil {
+		t.Fatal(err)
+	}
+	info, err = XfrmSADInfoGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Count != 1 {
+		t.Fatalf("unexpected SAD info: %+v", info)
+	}
+}
+
 func genStateSelectorForV6Payload() *XfrmPolicy {
 	_, wildcardV6Net, _ := net.ParseCIDR("::/0")
 	return &XfrmPolicy{